@@ -0,0 +1,33 @@
+package main
+
+import (
+	"hotaku-api/config"
+	"hotaku-api/internal/repo"
+	"hotaku-api/internal/server"
+	"hotaku-api/internal/service"
+	"hotaku-api/internal/usecase"
+	"log"
+
+	"github.com/joho/godotenv"
+)
+
+func main() {
+	if err := godotenv.Load(); err != nil {
+		log.Println("No .env file found, using environment variables")
+	}
+
+	appConfig := config.LoadConfig()
+
+	config.ConnectDatabase()
+	minioService := server.InitializeMinioService(appConfig)
+
+	fileUseCase := usecase.NewFileUseCase(repo.NewUserRepository(config.DB), repo.NewGroupRepository(config.DB), repo.NewStorageObjectRepository(config.DB), minioService, appConfig.Quota.UserBytes, appConfig.Quota.GroupBytes)
+	imageURLService := service.NewImageURLService(minioService, appConfig.MinIO.PrivateBucket, appConfig.ImageURL.BaseURL, appConfig.ImageURL.Secret, appConfig.ImageURL.TTL)
+	coverUseCase := usecase.NewMangaCoverUseCase(repo.NewUserRepository(config.DB), repo.NewGroupRepository(config.DB), repo.NewMangaRepository(config.DB), repo.NewMangaCoverRepository(config.DB), fileUseCase, minioService, imageURLService)
+
+	mangas, covers, err := coverUseCase.BackfillLegacyCovers()
+	if err != nil {
+		log.Fatal("Cover backfill failed:", err)
+	}
+	log.Printf("Recorded %d covers of %d mangas", covers, mangas)
+}
@@ -0,0 +1,35 @@
+package main
+
+import (
+	"hotaku-api/config"
+	"hotaku-api/internal/repo"
+	"hotaku-api/internal/server"
+	"hotaku-api/internal/usecase"
+	"log"
+
+	"github.com/joho/godotenv"
+)
+
+func main() {
+	if err := godotenv.Load(); err != nil {
+		log.Println("No .env file found, using environment variables")
+	}
+
+	appConfig := config.LoadConfig()
+
+	config.ConnectDatabase()
+	minioService := server.InitializeMinioService(appConfig)
+
+	chapterRepo := repo.NewChapterRepository(config.DB)
+	chapterPageRepo := repo.NewChapterPageRepository(config.DB)
+	userRepo := repo.NewUserRepository(config.DB)
+	groupRepo := repo.NewGroupRepository(config.DB)
+	fileUseCase := usecase.NewFileUseCase(userRepo, groupRepo, repo.NewStorageObjectRepository(config.DB), minioService, appConfig.Quota.UserBytes, appConfig.Quota.GroupBytes)
+	pageUseCase := usecase.NewChapterPageUseCase(userRepo, groupRepo, chapterRepo, chapterPageRepo, repo.NewPageDuplicateRepository(config.DB), fileUseCase, minioService, appConfig.Upload.MaxPages, appConfig.Upload.Concurrency)
+
+	chapters, pages, err := pageUseCase.BackfillLegacyPages()
+	if err != nil {
+		log.Fatal("Page backfill failed:", err)
+	}
+	log.Printf("Recorded %d pages of %d chapters", pages, chapters)
+}
@@ -0,0 +1,36 @@
+package main
+
+import (
+	"flag"
+	"hotaku-api/config"
+	"hotaku-api/internal/repo"
+	"log"
+	"time"
+
+	"github.com/joho/godotenv"
+)
+
+func main() {
+	if err := godotenv.Load(); err != nil {
+		log.Println("No .env file found, using environment variables")
+	}
+
+	appConfig := config.LoadConfig()
+
+	path := flag.String("path", appConfig.Search.IndexPath, "File the search index snapshot is written to")
+	flag.Parse()
+
+	config.ConnectDatabase()
+
+	startedAt := time.Now()
+	index := repo.NewMangaSearchIndex(config.DB)
+	stats, err := index.Rebuild()
+	if err != nil {
+		log.Fatal("Search index rebuild failed:", err)
+	}
+	if err := index.Save(*path); err != nil {
+		log.Fatal("Failed to save search index:", err)
+	}
+
+	log.Printf("Indexed %d mangas and %d terms in %s, snapshot written to %s", stats.Documents, stats.Terms, time.Since(startedAt).Round(time.Millisecond), *path)
+}
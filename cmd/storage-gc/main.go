@@ -0,0 +1,56 @@
+package main
+
+import (
+	"flag"
+	"hotaku-api/config"
+	"hotaku-api/internal/repo"
+	"hotaku-api/internal/server"
+	"hotaku-api/internal/usecase"
+	"log"
+
+	"github.com/joho/godotenv"
+)
+
+func main() {
+	if err := godotenv.Load(); err != nil {
+		log.Println("No .env file found, using environment variables")
+	}
+
+	appConfig := config.LoadConfig()
+
+	var (
+		dryRun      = flag.Bool("dry-run", true, "Only report orphaned objects without deleting them")
+		gracePeriod = flag.Duration("grace", appConfig.StorageGC.GracePeriod, "Minimum age of an object before it can be collected")
+	)
+	flag.Parse()
+
+	config.ConnectDatabase()
+	minioService := server.InitializeMinioService(appConfig)
+
+	gcUseCase := usecase.NewStorageGCUseCase(
+		repo.NewChapterRepository(config.DB),
+		repo.NewChapterPageRepository(config.DB),
+		repo.NewMangaCoverRepository(config.DB),
+		repo.NewStorageObjectRepository(config.DB),
+		repo.NewGroupWatermarkRepository(config.DB),
+		minioService,
+	)
+
+	report, err := gcUseCase.CollectGarbage(*dryRun, *gracePeriod)
+	if err != nil {
+		log.Fatal("Garbage collection failed:", err)
+	}
+
+	for _, orphan := range report.Orphans {
+		log.Printf("orphan: %s (%d bytes, last modified %s)", orphan.ObjectName, orphan.Size, orphan.LastModified.Format("2006-01-02 15:04:05"))
+	}
+	for _, failure := range report.Failed {
+		log.Printf("failed to delete %s: %s", failure.Filename, failure.Error)
+	}
+
+	if report.DryRun {
+		log.Printf("Dry run: scanned %d objects, found %d orphans", report.Scanned, len(report.Orphans))
+		return
+	}
+	log.Printf("Scanned %d objects, deleted %d of %d orphans, freed %d bytes", report.Scanned, report.Deleted, len(report.Orphans), report.FreedBytes)
+}
@@ -5,14 +5,24 @@ import (
 	"log"
 	"os"
 	"strconv"
+	"strings"
+	"time"
 )
 
 // Config holds all configuration for the application
 type Config struct {
-	Database DatabaseConfig
-	Server   ServerConfig
-	App      AppConfig
-	MinIO    MinIOConfig
+	Database  DatabaseConfig
+	Server    ServerConfig
+	App       AppConfig
+	MinIO     MinIOConfig
+	Download  DownloadConfig
+	StorageGC StorageGCConfig
+	Quota     QuotaConfig
+	Image     ImageConfig
+	ImageURL  ImageURLConfig
+	Upload    UploadConfig
+	Search    SearchConfig
+	Watermark WatermarkConfig
 }
 
 // DatabaseConfig holds database configuration
@@ -45,6 +55,74 @@ type MinIOConfig struct {
 	UseSSL          bool
 	BucketName      string
 	PublicURL       string
+	PrivateBucket   bool
+}
+
+// DownloadConfig holds chapter download configuration
+type DownloadConfig struct {
+	RateLimit  int
+	RateWindow time.Duration
+}
+
+// StorageGCConfig holds orphaned object garbage collection configuration
+type StorageGCConfig struct {
+	Enabled     bool
+	Interval    time.Duration
+	GracePeriod time.Duration
+	DryRun      bool
+}
+
+// QuotaConfig holds storage quota configuration, zero meaning unlimited
+type QuotaConfig struct {
+	UserBytes  int64
+	GroupBytes int64
+}
+
+// ImageConfig holds uploaded image normalization configuration, zero dimensions meaning no cap
+type ImageConfig struct {
+	MaxWidth    int
+	MaxHeight   int
+	JPEGQuality int
+}
+
+// ImageURLConfig holds the configuration of image URLs served through the API
+type ImageURLConfig struct {
+	BaseURL string
+	Secret  string
+	TTL     time.Duration
+}
+
+// UploadConfig holds the limits of multi-page uploads
+type UploadConfig struct {
+	MaxRequestBytes int64
+	MaxPages        int
+	Concurrency     int
+	// SessionSweepInterval is how often expired resumable and presigned uploads are discarded
+	SessionSweepInterval time.Duration
+}
+
+// Search backends selectable with SEARCH_BACKEND
+const (
+	SearchBackendMySQL = "mysql"
+	SearchBackendIndex = "index"
+)
+
+// SearchConfig holds manga search configuration
+type SearchConfig struct {
+	Backend      string
+	IndexPath    string
+	SyncInterval time.Duration
+	// SuggestRefreshInterval is how often suggestions check whether what they are built from changed
+	SuggestRefreshInterval time.Duration
+	// PopularityRefreshInterval is how often the popularity listings are sorted by is recomputed
+	PopularityRefreshInterval time.Duration
+}
+
+// WatermarkConfig holds the configuration of the background rendering of watermarked pages
+type WatermarkConfig struct {
+	RenderInterval time.Duration
+	// RenderBatch caps how many pages a single run renders
+	RenderBatch int
 }
 
 // LoadConfig loads configuration from environment variables with defaults
@@ -73,6 +151,48 @@ func LoadConfig() *Config {
 			UseSSL:          getEnvAsBool("MINIO_USE_SSL", false),
 			BucketName:      getEnv("MINIO_BUCKET_NAME", "manga-images"),
 			PublicURL:       getEnv("MINIO_PUBLIC_URL", "localhost:9000"),
+			PrivateBucket:   getEnvAsBool("MINIO_PRIVATE_BUCKET", false),
+		},
+		Download: DownloadConfig{
+			RateLimit:  getEnvAsInt("DOWNLOAD_RATE_LIMIT", 20),
+			RateWindow: getEnvAsDuration("DOWNLOAD_RATE_WINDOW", time.Hour),
+		},
+		StorageGC: StorageGCConfig{
+			Enabled:     getEnvAsBool("STORAGE_GC_ENABLED", false),
+			Interval:    getEnvAsDuration("STORAGE_GC_INTERVAL", 24*time.Hour),
+			GracePeriod: getEnvAsDuration("STORAGE_GC_GRACE_PERIOD", 72*time.Hour),
+			DryRun:      getEnvAsBool("STORAGE_GC_DRY_RUN", true),
+		},
+		Quota: QuotaConfig{
+			UserBytes:  int64(getEnvAsInt("STORAGE_QUOTA_USER_MB", 5120)) * 1024 * 1024,
+			GroupBytes: int64(getEnvAsInt("STORAGE_QUOTA_GROUP_MB", 51200)) * 1024 * 1024,
+		},
+		Image: ImageConfig{
+			MaxWidth:    getEnvAsInt("IMAGE_MAX_WIDTH", 0),
+			MaxHeight:   getEnvAsInt("IMAGE_MAX_HEIGHT", 0),
+			JPEGQuality: getEnvAsInt("IMAGE_JPEG_QUALITY", 90),
+		},
+		ImageURL: ImageURLConfig{
+			BaseURL: strings.TrimSuffix(getEnv("API_PUBLIC_URL", ""), "/"),
+			Secret:  getEnv("IMAGE_URL_SECRET", ""),
+			TTL:     getEnvAsDuration("IMAGE_URL_TTL", time.Hour),
+		},
+		Upload: UploadConfig{
+			MaxRequestBytes:      int64(getEnvAsInt("UPLOAD_MAX_REQUEST_MB", 200)) * 1024 * 1024,
+			MaxPages:             getEnvAsInt("UPLOAD_MAX_PAGES", 200),
+			Concurrency:          getEnvAsInt("UPLOAD_CONCURRENCY", 4),
+			SessionSweepInterval: getEnvAsDuration("UPLOAD_SESSION_SWEEP_INTERVAL", time.Hour),
+		},
+		Search: SearchConfig{
+			Backend:                   getEnv("SEARCH_BACKEND", SearchBackendMySQL),
+			IndexPath:                 getEnv("SEARCH_INDEX_PATH", "data/search-index.gob"),
+			SyncInterval:              getEnvAsDuration("SEARCH_INDEX_SYNC_INTERVAL", time.Minute),
+			SuggestRefreshInterval:    getEnvAsDuration("SUGGEST_REFRESH_INTERVAL", 30*time.Second),
+			PopularityRefreshInterval: getEnvAsDuration("POPULARITY_REFRESH_INTERVAL", 10*time.Minute),
+		},
+		Watermark: WatermarkConfig{
+			RenderInterval: getEnvAsDuration("WATERMARK_RENDER_INTERVAL", time.Minute),
+			RenderBatch:    getEnvAsInt("WATERMARK_RENDER_BATCH", 100),
 		},
 	}
 
@@ -107,6 +227,66 @@ func (c *Config) Validate() error {
 	if c.MinIO.BucketName == "" {
 		return fmt.Errorf("MinIO bucket name is required (MINIO_BUCKET_NAME)")
 	}
+	if c.Download.RateLimit < 1 {
+		return fmt.Errorf("download rate limit must be positive (DOWNLOAD_RATE_LIMIT)")
+	}
+	if c.Download.RateWindow <= 0 {
+		return fmt.Errorf("download rate window must be positive (DOWNLOAD_RATE_WINDOW)")
+	}
+	if c.StorageGC.Interval <= 0 {
+		return fmt.Errorf("storage GC interval must be positive (STORAGE_GC_INTERVAL)")
+	}
+	if c.StorageGC.GracePeriod < time.Hour {
+		return fmt.Errorf("storage GC grace period must be at least 1h (STORAGE_GC_GRACE_PERIOD)")
+	}
+	if c.Quota.UserBytes < 0 {
+		return fmt.Errorf("user storage quota must not be negative (STORAGE_QUOTA_USER_MB)")
+	}
+	if c.Quota.GroupBytes < 0 {
+		return fmt.Errorf("group storage quota must not be negative (STORAGE_QUOTA_GROUP_MB)")
+	}
+	if c.Image.MaxWidth < 0 || c.Image.MaxHeight < 0 {
+		return fmt.Errorf("image dimension caps must not be negative (IMAGE_MAX_WIDTH, IMAGE_MAX_HEIGHT)")
+	}
+	if c.Image.JPEGQuality < 1 || c.Image.JPEGQuality > 100 {
+		return fmt.Errorf("JPEG quality must be between 1 and 100 (IMAGE_JPEG_QUALITY)")
+	}
+	if c.MinIO.PrivateBucket && len(c.ImageURL.Secret) < 32 {
+		return fmt.Errorf("image URL secret of at least 32 characters is required with a private bucket (IMAGE_URL_SECRET)")
+	}
+	if c.ImageURL.TTL < time.Minute {
+		return fmt.Errorf("image URL TTL must be at least 1m (IMAGE_URL_TTL)")
+	}
+	if c.Upload.MaxRequestBytes < 1 {
+		return fmt.Errorf("upload request size limit must be positive (UPLOAD_MAX_REQUEST_MB)")
+	}
+	if c.Upload.MaxPages < 1 {
+		return fmt.Errorf("upload page limit must be positive (UPLOAD_MAX_PAGES)")
+	}
+	if c.Upload.Concurrency < 1 {
+		return fmt.Errorf("upload concurrency must be positive (UPLOAD_CONCURRENCY)")
+	}
+	if c.Upload.SessionSweepInterval <= 0 {
+		return fmt.Errorf("upload session sweep interval must be positive (UPLOAD_SESSION_SWEEP_INTERVAL)")
+	}
+	if c.Search.Backend != SearchBackendMySQL && c.Search.Backend != SearchBackendIndex {
+		return fmt.Errorf("search backend must be %q or %q (SEARCH_BACKEND)", SearchBackendMySQL, SearchBackendIndex)
+	}
+	if c.Search.SyncInterval < time.Second {
+		return fmt.Errorf("search index sync interval must be at least 1s (SEARCH_INDEX_SYNC_INTERVAL)")
+	}
+	if c.Search.SuggestRefreshInterval < time.Second {
+		return fmt.Errorf("suggestion refresh interval must be at least 1s (SUGGEST_REFRESH_INTERVAL)")
+	}
+	if c.Search.PopularityRefreshInterval < time.Minute {
+		return fmt.Errorf("popularity refresh interval must be at least 1m (POPULARITY_REFRESH_INTERVAL)")
+	}
+	if c.Watermark.RenderInterval < time.Second {
+		return fmt.Errorf("watermark render interval must be at least 1s (WATERMARK_RENDER_INTERVAL)")
+	}
+	if c.Watermark.RenderBatch < 1 {
+		return fmt.Errorf("watermark render batch must be positive (WATERMARK_RENDER_BATCH)")
+	}
 	return nil
 }
 
@@ -139,3 +319,14 @@ func getEnvAsBool(key string, defaultValue bool) bool {
 	}
 	return defaultValue
 }
+
+// getEnvAsDuration gets environment variable as duration with fallback to default value
+func getEnvAsDuration(key string, defaultValue time.Duration) time.Duration {
+	if value := os.Getenv(key); value != "" {
+		if durationValue, err := time.ParseDuration(value); err == nil {
+			return durationValue
+		}
+		log.Printf("Warning: Invalid duration value for %s: %s, using default: %s", key, value, defaultValue)
+	}
+	return defaultValue
+}
@@ -0,0 +1,3 @@
+SET FOREIGN_KEY_CHECKS = 0;
+DROP TABLE IF EXISTS `upload_intents`;
+SET FOREIGN_KEY_CHECKS = 1;
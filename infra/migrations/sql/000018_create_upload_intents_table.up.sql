@@ -0,0 +1,19 @@
+CREATE TABLE `upload_intents` (
+    upload_id CHAR(36) NOT NULL,
+    user_id CHAR(36) NOT NULL,
+    purpose VARCHAR(20) NOT NULL,
+    manga_id CHAR(36) NOT NULL,
+    chapter_id CHAR(36),
+    object_name VARCHAR(1024) NOT NULL,
+    content_type VARCHAR(100) NOT NULL,
+    size BIGINT UNSIGNED NOT NULL,
+    expires_at TIMESTAMP NOT NULL,
+    finalized_at TIMESTAMP NULL DEFAULT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (upload_id),
+    INDEX idx_upload_intents_user_id (user_id),
+    INDEX idx_upload_intents_expires_at (expires_at),
+    CONSTRAINT fk_upload_intents_users FOREIGN KEY (user_id) REFERENCES users(user_id) ON DELETE CASCADE ON UPDATE CASCADE,
+    CONSTRAINT fk_upload_intents_mangas FOREIGN KEY (manga_id) REFERENCES mangas(manga_id) ON DELETE CASCADE ON UPDATE CASCADE,
+    CONSTRAINT fk_upload_intents_manga_chapters FOREIGN KEY (chapter_id) REFERENCES manga_chapters(chapter_id) ON DELETE CASCADE ON UPDATE CASCADE
+) ENGINE = InnoDB DEFAULT CHARSET = utf8mb4;
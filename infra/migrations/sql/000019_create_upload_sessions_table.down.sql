@@ -0,0 +1,4 @@
+SET FOREIGN_KEY_CHECKS = 0;
+DROP TABLE IF EXISTS `upload_session_parts`;
+DROP TABLE IF EXISTS `upload_sessions`;
+SET FOREIGN_KEY_CHECKS = 1;
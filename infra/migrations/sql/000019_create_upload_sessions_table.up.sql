@@ -0,0 +1,32 @@
+CREATE TABLE `upload_sessions` (
+    session_id CHAR(36) NOT NULL,
+    user_id CHAR(36) NOT NULL,
+    kind VARCHAR(20) NOT NULL,
+    manga_id CHAR(36) NOT NULL,
+    chapter_id CHAR(36) NOT NULL,
+    filename VARCHAR(255) NOT NULL,
+    content_type VARCHAR(100) NOT NULL,
+    total_size BIGINT UNSIGNED NOT NULL,
+    upload_offset BIGINT UNSIGNED NOT NULL DEFAULT 0,
+    object_name VARCHAR(1024) NOT NULL,
+    storage_upload_id VARCHAR(255) NOT NULL,
+    expires_at TIMESTAMP NOT NULL,
+    completed_at TIMESTAMP NULL DEFAULT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    PRIMARY KEY (session_id),
+    INDEX idx_upload_sessions_user_id (user_id),
+    INDEX idx_upload_sessions_expires_at (expires_at),
+    CONSTRAINT fk_upload_sessions_users FOREIGN KEY (user_id) REFERENCES users(user_id) ON DELETE CASCADE ON UPDATE CASCADE,
+    CONSTRAINT fk_upload_sessions_mangas FOREIGN KEY (manga_id) REFERENCES mangas(manga_id) ON DELETE CASCADE ON UPDATE CASCADE,
+    CONSTRAINT fk_upload_sessions_manga_chapters FOREIGN KEY (chapter_id) REFERENCES manga_chapters(chapter_id) ON DELETE CASCADE ON UPDATE CASCADE
+) ENGINE = InnoDB DEFAULT CHARSET = utf8mb4;
+
+CREATE TABLE `upload_session_parts` (
+    session_id CHAR(36) NOT NULL,
+    part_number INT UNSIGNED NOT NULL CHECK (part_number > 0),
+    etag VARCHAR(255) NOT NULL,
+    size BIGINT UNSIGNED NOT NULL,
+    PRIMARY KEY (session_id, part_number),
+    CONSTRAINT fk_upload_session_parts_upload_sessions FOREIGN KEY (session_id) REFERENCES upload_sessions(session_id) ON DELETE CASCADE ON UPDATE CASCADE
+) ENGINE = InnoDB DEFAULT CHARSET = utf8mb4;
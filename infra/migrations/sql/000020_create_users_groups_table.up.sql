@@ -0,0 +1,9 @@
+CREATE TABLE `users_groups` (
+    user_id CHAR(36) NOT NULL,
+    group_id CHAR(36) NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (user_id, group_id),
+    INDEX idx_users_groups_group_id (group_id),
+    CONSTRAINT fk_users_groups_users FOREIGN KEY (user_id) REFERENCES users(user_id) ON DELETE CASCADE,
+    CONSTRAINT fk_users_groups_groups FOREIGN KEY (group_id) REFERENCES `groups`(group_id) ON DELETE CASCADE
+) ENGINE = InnoDB DEFAULT CHARSET = utf8mb4;
@@ -0,0 +1,3 @@
+SET FOREIGN_KEY_CHECKS = 0;
+DROP TABLE IF EXISTS `storage_objects`;
+SET FOREIGN_KEY_CHECKS = 1;
@@ -0,0 +1,16 @@
+CREATE TABLE `storage_objects` (
+    object_name VARCHAR(512) NOT NULL,
+    manga_id CHAR(36) NOT NULL,
+    uploaded_by CHAR(36),
+    group_id CHAR(36),
+    content_type VARCHAR(100) NOT NULL,
+    size BIGINT UNSIGNED NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (object_name),
+    INDEX idx_storage_objects_manga_id (manga_id),
+    INDEX idx_storage_objects_uploaded_by (uploaded_by),
+    INDEX idx_storage_objects_group_id (group_id),
+    CONSTRAINT fk_storage_objects_mangas FOREIGN KEY (manga_id) REFERENCES mangas(manga_id) ON DELETE CASCADE ON UPDATE CASCADE,
+    CONSTRAINT fk_storage_objects_users FOREIGN KEY (uploaded_by) REFERENCES users(user_id) ON DELETE SET NULL ON UPDATE CASCADE,
+    CONSTRAINT fk_storage_objects_groups FOREIGN KEY (group_id) REFERENCES `groups`(group_id) ON DELETE SET NULL ON UPDATE CASCADE
+) ENGINE = InnoDB DEFAULT CHARSET = utf8mb4;
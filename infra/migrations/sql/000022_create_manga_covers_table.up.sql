@@ -0,0 +1,17 @@
+CREATE TABLE `manga_covers` (
+    cover_id CHAR(36) NOT NULL,
+    manga_id CHAR(36) NOT NULL,
+    image_url VARCHAR(2048) NOT NULL,
+    volume INT UNSIGNED,
+    locale VARCHAR(35),
+    uploaded_by CHAR(36),
+    is_primary BOOLEAN NOT NULL DEFAULT FALSE,
+    -- Only set for the primary cover, so the unique key allows a single primary per manga
+    primary_manga_id CHAR(36) AS (IF(is_primary, manga_id, NULL)) STORED,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (cover_id),
+    UNIQUE KEY uq_manga_covers_primary_manga_id (primary_manga_id),
+    INDEX idx_manga_covers_manga_id (manga_id),
+    CONSTRAINT fk_manga_covers_mangas FOREIGN KEY (manga_id) REFERENCES mangas(manga_id) ON DELETE CASCADE ON UPDATE CASCADE,
+    CONSTRAINT fk_manga_covers_users FOREIGN KEY (uploaded_by) REFERENCES users(user_id) ON DELETE SET NULL ON UPDATE CASCADE
+) ENGINE = InnoDB DEFAULT CHARSET = utf8mb4;
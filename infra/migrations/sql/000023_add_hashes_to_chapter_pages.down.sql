@@ -0,0 +1,4 @@
+ALTER TABLE `chapter_pages`
+    DROP INDEX idx_chapter_pages_content_sha256,
+    DROP COLUMN perceptual_hash,
+    DROP COLUMN content_sha256;
@@ -0,0 +1,4 @@
+ALTER TABLE `chapter_pages`
+    ADD COLUMN content_sha256 CHAR(64) NULL AFTER image_url,
+    ADD COLUMN perceptual_hash BIGINT UNSIGNED NULL AFTER content_sha256,
+    ADD INDEX idx_chapter_pages_content_sha256 (content_sha256);
@@ -0,0 +1,19 @@
+CREATE TABLE `page_duplicate_flags` (
+    flag_id CHAR(36) NOT NULL,
+    manga_id CHAR(36) NOT NULL,
+    page_id CHAR(36) NOT NULL,
+    duplicate_page_id CHAR(36) NOT NULL,
+    distance TINYINT UNSIGNED NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    reviewed_by CHAR(36),
+    reviewed_at TIMESTAMP NULL DEFAULT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (flag_id),
+    UNIQUE KEY uq_page_duplicate_flags_pair (page_id, duplicate_page_id),
+    INDEX idx_page_duplicate_flags_status (status, created_at),
+    INDEX idx_page_duplicate_flags_manga_id (manga_id),
+    CONSTRAINT fk_page_duplicate_flags_mangas FOREIGN KEY (manga_id) REFERENCES mangas(manga_id) ON DELETE CASCADE ON UPDATE CASCADE,
+    CONSTRAINT fk_page_duplicate_flags_page FOREIGN KEY (page_id) REFERENCES chapter_pages(page_id) ON DELETE CASCADE ON UPDATE CASCADE,
+    CONSTRAINT fk_page_duplicate_flags_duplicate_page FOREIGN KEY (duplicate_page_id) REFERENCES chapter_pages(page_id) ON DELETE CASCADE ON UPDATE CASCADE,
+    CONSTRAINT fk_page_duplicate_flags_users FOREIGN KEY (reviewed_by) REFERENCES users(user_id) ON DELETE SET NULL ON UPDATE CASCADE
+) ENGINE = InnoDB DEFAULT CHARSET = utf8mb4;
@@ -0,0 +1,2 @@
+ALTER TABLE `mangas`
+    DROP COLUMN visibility;
@@ -0,0 +1,2 @@
+ALTER TABLE `mangas`
+    ADD COLUMN visibility VARCHAR(20) NOT NULL DEFAULT 'public' AFTER description;
@@ -0,0 +1,3 @@
+SET FOREIGN_KEY_CHECKS = 0;
+DROP TABLE IF EXISTS `group_watermarks`;
+SET FOREIGN_KEY_CHECKS = 1;
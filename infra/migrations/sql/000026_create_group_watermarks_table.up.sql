@@ -0,0 +1,13 @@
+CREATE TABLE `group_watermarks` (
+    group_id CHAR(36) NOT NULL,
+    kind VARCHAR(10) NOT NULL DEFAULT 'text',
+    text VARCHAR(64),
+    image_object VARCHAR(512),
+    position VARCHAR(20) NOT NULL DEFAULT 'bottom-right',
+    opacity TINYINT UNSIGNED NOT NULL DEFAULT 50,
+    scale TINYINT UNSIGNED NOT NULL DEFAULT 25,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    PRIMARY KEY (group_id),
+    CONSTRAINT fk_group_watermarks_groups FOREIGN KEY (group_id) REFERENCES `groups`(group_id) ON DELETE CASCADE ON UPDATE CASCADE
+) ENGINE = InnoDB DEFAULT CHARSET = utf8mb4;
@@ -0,0 +1,3 @@
+ALTER TABLE `manga_chapters`
+    DROP FOREIGN KEY fk_manga_chapters_watermark_groups,
+    DROP COLUMN watermark_group_id;
@@ -0,0 +1,3 @@
+ALTER TABLE `manga_chapters`
+    ADD COLUMN watermark_group_id CHAR(36) AFTER title,
+    ADD CONSTRAINT fk_manga_chapters_watermark_groups FOREIGN KEY (watermark_group_id) REFERENCES `groups`(group_id) ON DELETE SET NULL ON UPDATE CASCADE;
@@ -0,0 +1,2 @@
+ALTER TABLE `mangas`
+    DROP INDEX ft_mangas_title_description;
@@ -0,0 +1,2 @@
+ALTER TABLE `mangas`
+    ADD FULLTEXT KEY ft_mangas_title_description (title, description);
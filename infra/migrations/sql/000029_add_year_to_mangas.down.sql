@@ -0,0 +1,3 @@
+ALTER TABLE `mangas`
+    DROP INDEX idx_mangas_year,
+    DROP COLUMN year;
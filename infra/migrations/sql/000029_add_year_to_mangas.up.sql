@@ -0,0 +1,3 @@
+ALTER TABLE `mangas`
+    ADD COLUMN year SMALLINT UNSIGNED NULL AFTER description,
+    ADD INDEX idx_mangas_year (year);
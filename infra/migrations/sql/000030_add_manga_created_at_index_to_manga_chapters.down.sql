@@ -0,0 +1,2 @@
+ALTER TABLE `manga_chapters`
+    DROP INDEX idx_manga_chapters_manga_id_created_at;
@@ -0,0 +1,2 @@
+ALTER TABLE `manga_chapters`
+    ADD INDEX idx_manga_chapters_manga_id_created_at (manga_id, created_at);
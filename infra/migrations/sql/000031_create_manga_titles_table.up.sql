@@ -0,0 +1,16 @@
+CREATE TABLE `manga_titles` (
+    title_id CHAR(36) NOT NULL,
+    manga_id CHAR(36) NOT NULL,
+    locale VARCHAR(35) NOT NULL,
+    title VARCHAR(255) NOT NULL,
+    is_primary BOOLEAN NOT NULL DEFAULT FALSE,
+    -- Only set for primary titles, so the unique key allows a single primary title per manga and locale
+    primary_manga_id CHAR(36) AS (IF(is_primary, manga_id, NULL)) STORED,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    PRIMARY KEY (title_id),
+    UNIQUE KEY uq_manga_titles_manga_id_locale_title (manga_id, locale, title),
+    UNIQUE KEY uq_manga_titles_primary_manga_id_locale (primary_manga_id, locale),
+    FULLTEXT KEY ft_manga_titles_title (title),
+    CONSTRAINT fk_manga_titles_mangas FOREIGN KEY (manga_id) REFERENCES mangas(manga_id) ON DELETE CASCADE ON UPDATE CASCADE
+) ENGINE = InnoDB DEFAULT CHARSET = utf8mb4;
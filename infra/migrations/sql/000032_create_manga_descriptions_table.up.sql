@@ -0,0 +1,10 @@
+CREATE TABLE `manga_descriptions` (
+    manga_id CHAR(36) NOT NULL,
+    locale VARCHAR(35) NOT NULL,
+    description TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    PRIMARY KEY (manga_id, locale),
+    FULLTEXT KEY ft_manga_descriptions_description (description),
+    CONSTRAINT fk_manga_descriptions_mangas FOREIGN KEY (manga_id) REFERENCES mangas(manga_id) ON DELETE CASCADE ON UPDATE CASCADE
+) ENGINE = InnoDB DEFAULT CHARSET = utf8mb4;
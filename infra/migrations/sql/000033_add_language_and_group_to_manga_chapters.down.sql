@@ -0,0 +1,7 @@
+ALTER TABLE `manga_chapters`
+    ADD UNIQUE KEY uq_manga_chapters_manga_id_chapter_number (manga_id, chapter_number),
+    DROP INDEX uq_manga_chapters_manga_id_chapter_number_language_group_key,
+    DROP FOREIGN KEY fk_manga_chapters_groups,
+    DROP COLUMN group_key,
+    DROP COLUMN group_id,
+    DROP COLUMN language;
@@ -0,0 +1,9 @@
+ALTER TABLE `manga_chapters`
+    ADD COLUMN language VARCHAR(35) NOT NULL DEFAULT 'und' AFTER title,
+    ADD COLUMN group_id CHAR(36) AFTER language,
+    -- NULLs never collide in a unique key, so versions without a group are keyed by an empty group instead
+    ADD COLUMN group_key CHAR(36) AS (COALESCE(group_id, '')) VIRTUAL AFTER group_id,
+    ADD CONSTRAINT fk_manga_chapters_groups FOREIGN KEY (group_id) REFERENCES `groups`(group_id) ON DELETE SET NULL ON UPDATE CASCADE,
+    -- A chapter number may be hosted once per language and group
+    ADD UNIQUE KEY uq_manga_chapters_manga_id_chapter_number_language_group_key (manga_id, chapter_number, language, group_key),
+    DROP INDEX uq_manga_chapters_manga_id_chapter_number;
@@ -0,0 +1,8 @@
+ALTER TABLE `manga_chapters`
+    DROP FOREIGN KEY fk_manga_chapters_manga_volumes,
+    DROP INDEX idx_manga_chapters_volume_id,
+    DROP COLUMN volume_id;
+
+SET FOREIGN_KEY_CHECKS = 0;
+DROP TABLE IF EXISTS `manga_volumes`;
+SET FOREIGN_KEY_CHECKS = 1;
@@ -0,0 +1,19 @@
+CREATE TABLE `manga_volumes` (
+    volume_id CHAR(36) NOT NULL,
+    manga_id CHAR(36) NOT NULL,
+    volume_number INT NOT NULL,
+    title VARCHAR(255),
+    -- The cover shown for the volume, falling back to a cover uploaded for its number when unset
+    cover_id CHAR(36),
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    PRIMARY KEY (volume_id),
+    UNIQUE KEY uq_manga_volumes_manga_id_volume_number (manga_id, volume_number),
+    CONSTRAINT fk_manga_volumes_mangas FOREIGN KEY (manga_id) REFERENCES mangas(manga_id) ON DELETE CASCADE ON UPDATE CASCADE,
+    CONSTRAINT fk_manga_volumes_manga_covers FOREIGN KEY (cover_id) REFERENCES manga_covers(cover_id) ON DELETE SET NULL ON UPDATE CASCADE
+) ENGINE = InnoDB DEFAULT CHARSET = utf8mb4;
+
+ALTER TABLE `manga_chapters`
+    ADD COLUMN volume_id CHAR(36) AFTER group_id,
+    ADD INDEX idx_manga_chapters_volume_id (volume_id),
+    ADD CONSTRAINT fk_manga_chapters_manga_volumes FOREIGN KEY (volume_id) REFERENCES manga_volumes(volume_id) ON DELETE SET NULL ON UPDATE CASCADE;
@@ -0,0 +1,15 @@
+CREATE TABLE `user_chapter_positions` (
+    user_id CHAR(36) NOT NULL,
+    chapter_id CHAR(36) NOT NULL,
+    page_number INT NOT NULL,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    PRIMARY KEY (user_id, chapter_id),
+    INDEX idx_user_chapter_positions_chapter_id (chapter_id),
+    INDEX idx_user_chapter_positions_user_id_updated_at (user_id, updated_at),
+    CONSTRAINT fk_user_chapter_positions_user_id
+        FOREIGN KEY (user_id) REFERENCES users(user_id)
+        ON DELETE CASCADE ON UPDATE CASCADE,
+    CONSTRAINT fk_user_chapter_positions_chapter_id
+        FOREIGN KEY (chapter_id) REFERENCES manga_chapters(chapter_id)
+        ON DELETE CASCADE ON UPDATE CASCADE
+) ENGINE = InnoDB DEFAULT CHARSET = utf8mb4;
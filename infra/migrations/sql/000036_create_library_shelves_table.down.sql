@@ -0,0 +1,11 @@
+ALTER TABLE `users`
+    DROP COLUMN library_public;
+
+ALTER TABLE `user_favorite_mangas`
+    DROP FOREIGN KEY fk_user_favorite_mangas_library_shelves,
+    DROP INDEX idx_user_favorite_mangas_shelf_id,
+    DROP COLUMN shelf_id;
+
+SET FOREIGN_KEY_CHECKS = 0;
+DROP TABLE IF EXISTS `library_shelves`;
+SET FOREIGN_KEY_CHECKS = 1;
@@ -0,0 +1,23 @@
+CREATE TABLE `library_shelves` (
+    shelf_id CHAR(36) NOT NULL,
+    user_id CHAR(36) NOT NULL,
+    name VARCHAR(64) NOT NULL,
+    -- reading, plan_to_read, dropped and completed are the shelves every library starts with, custom the others
+    kind VARCHAR(16) NOT NULL DEFAULT 'custom',
+    -- Only set for built-in shelves, so the unique key allows a single shelf of each kind per user
+    builtin_kind VARCHAR(16) AS (IF(kind = 'custom', NULL, kind)) STORED,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    PRIMARY KEY (shelf_id),
+    UNIQUE KEY uq_library_shelves_user_id_name (user_id, name),
+    UNIQUE KEY uq_library_shelves_user_id_builtin_kind (user_id, builtin_kind),
+    CONSTRAINT fk_library_shelves_users FOREIGN KEY (user_id) REFERENCES users(user_id) ON DELETE CASCADE ON UPDATE CASCADE
+) ENGINE = InnoDB DEFAULT CHARSET = utf8mb4;
+
+ALTER TABLE `user_favorite_mangas`
+    ADD COLUMN shelf_id CHAR(36) AFTER manga_id,
+    ADD INDEX idx_user_favorite_mangas_shelf_id (shelf_id),
+    ADD CONSTRAINT fk_user_favorite_mangas_library_shelves FOREIGN KEY (shelf_id) REFERENCES library_shelves(shelf_id) ON DELETE SET NULL ON UPDATE CASCADE;
+
+ALTER TABLE `users`
+    ADD COLUMN library_public BOOLEAN NOT NULL DEFAULT FALSE;
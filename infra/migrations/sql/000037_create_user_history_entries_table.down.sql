@@ -0,0 +1,6 @@
+ALTER TABLE `users`
+    DROP COLUMN history_paused;
+
+SET FOREIGN_KEY_CHECKS = 0;
+DROP TABLE IF EXISTS `user_history_entries`;
+SET FOREIGN_KEY_CHECKS = 1;
@@ -0,0 +1,25 @@
+CREATE TABLE `user_history_entries` (
+    entry_id CHAR(36) NOT NULL,
+    user_id CHAR(36) NOT NULL,
+    manga_id CHAR(36) NOT NULL,
+    chapter_id CHAR(36) NOT NULL,
+    -- The last page the user was on while the chapter was open, NULL when only marked read
+    page_number INT,
+    opened_at DATETIME(3) NOT NULL,
+    PRIMARY KEY (entry_id),
+    INDEX idx_user_history_entries_user_id_opened_at (user_id, opened_at),
+    INDEX idx_user_history_entries_manga_id (manga_id),
+    INDEX idx_user_history_entries_chapter_id (chapter_id),
+    CONSTRAINT fk_user_history_entries_users
+        FOREIGN KEY (user_id) REFERENCES users(user_id)
+        ON DELETE CASCADE ON UPDATE CASCADE,
+    CONSTRAINT fk_user_history_entries_mangas
+        FOREIGN KEY (manga_id) REFERENCES mangas(manga_id)
+        ON DELETE CASCADE ON UPDATE CASCADE,
+    CONSTRAINT fk_user_history_entries_manga_chapters
+        FOREIGN KEY (chapter_id) REFERENCES manga_chapters(chapter_id)
+        ON DELETE CASCADE ON UPDATE CASCADE
+) ENGINE = InnoDB DEFAULT CHARSET = utf8mb4;
+
+ALTER TABLE `users`
+    ADD COLUMN history_paused BOOLEAN NOT NULL DEFAULT FALSE;
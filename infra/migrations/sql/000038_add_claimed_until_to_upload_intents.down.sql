@@ -0,0 +1,2 @@
+ALTER TABLE `upload_intents`
+    DROP COLUMN claimed_until;
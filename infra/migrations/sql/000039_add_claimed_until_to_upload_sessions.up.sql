@@ -0,0 +1,2 @@
+ALTER TABLE `upload_sessions`
+    ADD COLUMN claimed_until TIMESTAMP NULL DEFAULT NULL AFTER expires_at;
@@ -0,0 +1,3 @@
+ALTER TABLE `chapter_pages`
+    DROP FOREIGN KEY fk_chapter_pages_users,
+    DROP COLUMN uploaded_by;
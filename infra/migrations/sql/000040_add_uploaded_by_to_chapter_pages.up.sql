@@ -0,0 +1,12 @@
+-- Pages sharing a deduplicated object each record who added them, so deleting the file only removes the
+-- deleting user's pages. Pages added before are attributed to the object's uploader
+ALTER TABLE `chapter_pages`
+    ADD COLUMN uploaded_by CHAR(36) NULL AFTER perceptual_hash,
+    ADD CONSTRAINT fk_chapter_pages_users FOREIGN KEY (uploaded_by) REFERENCES users(user_id) ON DELETE SET NULL ON UPDATE CASCADE;
+-- Page URLs end with the object key, whatever storage endpoint they were recorded with
+UPDATE `chapter_pages` AS p
+    JOIN `manga_chapters` AS c ON c.chapter_id = p.chapter_id
+    JOIN `storage_objects` AS o ON o.manga_id = c.manga_id
+        AND RIGHT(p.image_url, CHAR_LENGTH(o.object_name) + 1) = CONCAT('/', o.object_name)
+SET p.uploaded_by = o.uploaded_by
+WHERE o.uploaded_by IS NOT NULL;
@@ -0,0 +1 @@
+ALTER TABLE `group_watermarks` DROP COLUMN variant_key;
@@ -0,0 +1,5 @@
+-- Watermarked variants are stored under a random key so their URLs can't be derived from the original pages.
+-- The key changes with every watermark update, replacing the update time as the variant version
+ALTER TABLE `group_watermarks` ADD COLUMN variant_key CHAR(32) NULL AFTER scale;
+UPDATE `group_watermarks` SET variant_key = LEFT(SHA2(CONCAT(UUID(), RAND()), 256), 32);
+ALTER TABLE `group_watermarks` MODIFY variant_key CHAR(32) NOT NULL;
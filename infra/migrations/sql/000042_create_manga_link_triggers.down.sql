@@ -0,0 +1,12 @@
+DROP TRIGGER IF EXISTS `trg_mangas_authors_ai_touch_manga`;
+DROP TRIGGER IF EXISTS `trg_mangas_authors_ad_touch_manga`;
+DROP TRIGGER IF EXISTS `trg_mangas_authors_au_touch_manga`;
+DROP TRIGGER IF EXISTS `trg_authors_bd_touch_mangas`;
+DROP TRIGGER IF EXISTS `trg_mangas_categories_ai_touch_manga`;
+DROP TRIGGER IF EXISTS `trg_mangas_categories_ad_touch_manga`;
+DROP TRIGGER IF EXISTS `trg_mangas_categories_au_touch_manga`;
+DROP TRIGGER IF EXISTS `trg_categories_bd_touch_mangas`;
+DROP TRIGGER IF EXISTS `trg_mangas_groups_ai_touch_manga`;
+DROP TRIGGER IF EXISTS `trg_mangas_groups_ad_touch_manga`;
+DROP TRIGGER IF EXISTS `trg_mangas_groups_au_touch_manga`;
+DROP TRIGGER IF EXISTS `trg_groups_bd_touch_mangas`;
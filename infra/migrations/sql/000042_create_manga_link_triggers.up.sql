@@ -0,0 +1,81 @@
+-- Linking a manga to an author, category or group, or unlinking it, bumps the manga's updated_at so the search
+-- index and suggestions pick the change up from timestamps alone. Cascaded deletes don't fire triggers, so
+-- deleting an author, category or group bumps its mangas before the links are removed
+-- DELIMITER not required because migrations run with multiStatements=true
+DROP TRIGGER IF EXISTS `trg_mangas_authors_ai_touch_manga`;
+CREATE TRIGGER `trg_mangas_authors_ai_touch_manga`
+AFTER INSERT ON `mangas_authors`
+FOR EACH ROW
+UPDATE `mangas` SET updated_at = CURRENT_TIMESTAMP WHERE manga_id = NEW.manga_id;
+
+DROP TRIGGER IF EXISTS `trg_mangas_authors_ad_touch_manga`;
+CREATE TRIGGER `trg_mangas_authors_ad_touch_manga`
+AFTER DELETE ON `mangas_authors`
+FOR EACH ROW
+UPDATE `mangas` SET updated_at = CURRENT_TIMESTAMP WHERE manga_id = OLD.manga_id;
+
+DROP TRIGGER IF EXISTS `trg_mangas_authors_au_touch_manga`;
+CREATE TRIGGER `trg_mangas_authors_au_touch_manga`
+AFTER UPDATE ON `mangas_authors`
+FOR EACH ROW
+BEGIN
+    UPDATE `mangas` SET updated_at = CURRENT_TIMESTAMP WHERE manga_id IN (OLD.manga_id, NEW.manga_id);
+END;
+
+DROP TRIGGER IF EXISTS `trg_authors_bd_touch_mangas`;
+CREATE TRIGGER `trg_authors_bd_touch_mangas`
+BEFORE DELETE ON `authors`
+FOR EACH ROW
+UPDATE `mangas` SET updated_at = CURRENT_TIMESTAMP WHERE manga_id IN (SELECT manga_id FROM `mangas_authors` WHERE author_id = OLD.author_id);
+
+DROP TRIGGER IF EXISTS `trg_mangas_categories_ai_touch_manga`;
+CREATE TRIGGER `trg_mangas_categories_ai_touch_manga`
+AFTER INSERT ON `mangas_categories`
+FOR EACH ROW
+UPDATE `mangas` SET updated_at = CURRENT_TIMESTAMP WHERE manga_id = NEW.manga_id;
+
+DROP TRIGGER IF EXISTS `trg_mangas_categories_ad_touch_manga`;
+CREATE TRIGGER `trg_mangas_categories_ad_touch_manga`
+AFTER DELETE ON `mangas_categories`
+FOR EACH ROW
+UPDATE `mangas` SET updated_at = CURRENT_TIMESTAMP WHERE manga_id = OLD.manga_id;
+
+DROP TRIGGER IF EXISTS `trg_mangas_categories_au_touch_manga`;
+CREATE TRIGGER `trg_mangas_categories_au_touch_manga`
+AFTER UPDATE ON `mangas_categories`
+FOR EACH ROW
+BEGIN
+    UPDATE `mangas` SET updated_at = CURRENT_TIMESTAMP WHERE manga_id IN (OLD.manga_id, NEW.manga_id);
+END;
+
+DROP TRIGGER IF EXISTS `trg_categories_bd_touch_mangas`;
+CREATE TRIGGER `trg_categories_bd_touch_mangas`
+BEFORE DELETE ON `categories`
+FOR EACH ROW
+UPDATE `mangas` SET updated_at = CURRENT_TIMESTAMP WHERE manga_id IN (SELECT manga_id FROM `mangas_categories` WHERE category_id = OLD.category_id);
+
+DROP TRIGGER IF EXISTS `trg_mangas_groups_ai_touch_manga`;
+CREATE TRIGGER `trg_mangas_groups_ai_touch_manga`
+AFTER INSERT ON `mangas_groups`
+FOR EACH ROW
+UPDATE `mangas` SET updated_at = CURRENT_TIMESTAMP WHERE manga_id = NEW.manga_id;
+
+DROP TRIGGER IF EXISTS `trg_mangas_groups_ad_touch_manga`;
+CREATE TRIGGER `trg_mangas_groups_ad_touch_manga`
+AFTER DELETE ON `mangas_groups`
+FOR EACH ROW
+UPDATE `mangas` SET updated_at = CURRENT_TIMESTAMP WHERE manga_id = OLD.manga_id;
+
+DROP TRIGGER IF EXISTS `trg_mangas_groups_au_touch_manga`;
+CREATE TRIGGER `trg_mangas_groups_au_touch_manga`
+AFTER UPDATE ON `mangas_groups`
+FOR EACH ROW
+BEGIN
+    UPDATE `mangas` SET updated_at = CURRENT_TIMESTAMP WHERE manga_id IN (OLD.manga_id, NEW.manga_id);
+END;
+
+DROP TRIGGER IF EXISTS `trg_groups_bd_touch_mangas`;
+CREATE TRIGGER `trg_groups_bd_touch_mangas`
+BEFORE DELETE ON `groups`
+FOR EACH ROW
+UPDATE `mangas` SET updated_at = CURRENT_TIMESTAMP WHERE manga_id IN (SELECT manga_id FROM `mangas_groups` WHERE group_id = OLD.group_id);
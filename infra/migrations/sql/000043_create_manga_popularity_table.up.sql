@@ -0,0 +1,12 @@
+-- How popular each manga is, refreshed in the background so sorting listings by popularity reads a column
+-- rather than counting readers and favorites of every listed manga
+CREATE TABLE `manga_popularity` (
+    manga_id CHAR(36) NOT NULL,
+    readers INT UNSIGNED NOT NULL DEFAULT 0,
+    favorites INT UNSIGNED NOT NULL DEFAULT 0,
+    score INT UNSIGNED NOT NULL DEFAULT 0,
+    refreshed_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    PRIMARY KEY (manga_id),
+    INDEX idx_manga_popularity_score (score),
+    CONSTRAINT fk_manga_popularity_mangas FOREIGN KEY (manga_id) REFERENCES mangas(manga_id) ON DELETE CASCADE ON UPDATE CASCADE
+) ENGINE = InnoDB DEFAULT CHARSET = utf8mb4;
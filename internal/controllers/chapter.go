@@ -0,0 +1,175 @@
+package controllers
+
+import (
+	"errors"
+	"log"
+	"mime"
+	"net/http"
+
+	"hotaku-api/internal/domain/request"
+	"hotaku-api/internal/domain/response"
+	"hotaku-api/internal/usecaseinf"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ChapterController handles chapter-related HTTP requests
+type ChapterController struct {
+	chapterUseCase usecaseinf.ChapterUseCase
+}
+
+// NewChapterController creates a new instance of ChapterController
+func NewChapterController(chapterUseCase usecaseinf.ChapterUseCase) *ChapterController {
+	return &ChapterController{
+		chapterUseCase: chapterUseCase,
+	}
+}
+
+// GetManifest returns the pages of a chapter with the URLs to load them from
+func (cc *ChapterController) GetManifest(c *gin.Context) {
+	manifest, err := cc.chapterUseCase.GetManifest(c.GetString("user_id"), c.Param("id"))
+	if err != nil {
+		if errors.Is(err, usecaseinf.ErrMangaRestricted) {
+			c.JSON(http.StatusForbidden, response.ErrorResponse(http.StatusForbidden, "Chapter is restricted", err.Error()))
+			return
+		}
+		// Watermarked chapters are never served without their watermark
+		if errors.Is(err, usecaseinf.ErrWatermarkPending) {
+			c.Header("Retry-After", "60")
+			c.JSON(http.StatusServiceUnavailable, response.ErrorResponse(http.StatusServiceUnavailable, "Chapter pages are being watermarked", err.Error()))
+			return
+		}
+		if errors.Is(err, usecaseinf.ErrWatermarkFailed) || errors.Is(err, usecaseinf.ErrWatermarkNotFound) {
+			c.JSON(http.StatusUnprocessableEntity, response.ErrorResponse(http.StatusUnprocessableEntity, "Chapter pages could not be watermarked", err.Error()))
+			return
+		}
+		c.JSON(http.StatusNotFound, response.ErrorResponse(http.StatusNotFound, "Chapter not found", err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, response.SuccessResponse(http.StatusOK, "Chapter pages retrieved successfully", manifest))
+}
+
+// ListChapters returns the chapters of a manga, filtered by language and group
+func (cc *ChapterController) ListChapters(c *gin.Context) {
+	var req request.ListChaptersRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, response.ErrorResponse(http.StatusBadRequest, "Invalid request data", err.Error()))
+		return
+	}
+
+	chapters, err := cc.chapterUseCase.ListChapters(c.Param("id"), &req, preferredLanguages(c))
+	if err != nil {
+		status := chapterErrorStatus(err)
+		c.JSON(status, response.ErrorResponse(status, "Failed to list chapters", err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, response.SuccessResponse(http.StatusOK, "Chapters retrieved successfully", chapters))
+}
+
+// SetAttribution credits a chapter to a language and a scanlation group
+func (cc *ChapterController) SetAttribution(c *gin.Context) {
+	userID := c.GetString("user_id")
+
+	var req request.SetChapterAttributionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, response.ErrorResponse(http.StatusBadRequest, "Invalid request data", err.Error()))
+		return
+	}
+
+	chapter, err := cc.chapterUseCase.SetAttribution(userID, c.Param("id"), &req)
+	if err != nil {
+		status := chapterErrorStatus(err)
+		c.JSON(status, response.ErrorResponse(status, "Failed to update chapter attribution", err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, response.SuccessResponse(http.StatusOK, "Chapter attribution updated successfully", chapter))
+}
+
+// SetWatermark turns a chapter's watermark on with a group's watermark, or off when no group is given
+func (cc *ChapterController) SetWatermark(c *gin.Context) {
+	userID := c.GetString("user_id")
+
+	var req request.SetChapterWatermarkRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, response.ErrorResponse(http.StatusBadRequest, "Invalid request data", err.Error()))
+		return
+	}
+
+	result, err := cc.chapterUseCase.SetWatermark(userID, c.Param("id"), req.GroupID)
+	if err != nil {
+		status := watermarkErrorStatus(err)
+		if errors.Is(err, usecaseinf.ErrChapterNotFound) {
+			status = http.StatusNotFound
+		}
+		c.JSON(status, response.ErrorResponse(status, "Failed to update chapter watermark", err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, response.SuccessResponse(http.StatusOK, "Chapter watermark updated successfully", result))
+}
+
+// DownloadChapter streams a chapter as a CBZ archive
+func (cc *ChapterController) DownloadChapter(c *gin.Context) {
+	chapterID := c.Param("id")
+	if chapterID == "" {
+		c.JSON(http.StatusBadRequest, response.ErrorResponse(http.StatusBadRequest, "Chapter ID is required", nil))
+		return
+	}
+
+	download, err := cc.chapterUseCase.PrepareDownload(c.GetString("user_id"), chapterID)
+	if err != nil {
+		status := chapterDownloadErrorStatus(err)
+		if status == http.StatusServiceUnavailable {
+			c.Header("Retry-After", "60")
+		}
+		c.JSON(status, response.ErrorResponse(status, "Chapter not available for download", err.Error()))
+		return
+	}
+
+	c.Header("Content-Type", "application/vnd.comicbook+zip")
+	c.Header("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": download.Filename}))
+	c.Status(http.StatusOK)
+
+	// Headers are already sent, so a failure can only cut the stream short
+	if err := cc.chapterUseCase.WriteArchive(download, c.Writer); err != nil {
+		log.Printf("Failed to stream chapter %s: %v", chapterID, err)
+		c.Abort()
+	}
+}
+
+// chapterErrorStatus maps chapter listing and attribution errors to HTTP status codes
+func chapterErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, usecaseinf.ErrMangaNotFound), errors.Is(err, usecaseinf.ErrChapterNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, usecaseinf.ErrInvalidChapterFilter), errors.Is(err, usecaseinf.ErrInvalidLocale),
+		errors.Is(err, usecaseinf.ErrGroupNotCredited):
+		return http.StatusBadRequest
+	case errors.Is(err, usecaseinf.ErrChapterForbidden):
+		return http.StatusForbidden
+	case errors.Is(err, usecaseinf.ErrChapterVersionExists):
+		return http.StatusConflict
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// chapterDownloadErrorStatus maps chapter download errors to HTTP status codes
+func chapterDownloadErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, usecaseinf.ErrMangaRestricted):
+		return http.StatusForbidden
+	case errors.Is(err, usecaseinf.ErrChapterNotFound), errors.Is(err, usecaseinf.ErrChapterEmpty):
+		return http.StatusNotFound
+	case errors.Is(err, usecaseinf.ErrWatermarkPending):
+		// Watermarked chapters are never served without their watermark
+		return http.StatusServiceUnavailable
+	case errors.Is(err, usecaseinf.ErrWatermarkFailed), errors.Is(err, usecaseinf.ErrWatermarkNotFound):
+		return http.StatusUnprocessableEntity
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -0,0 +1,96 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+
+	"hotaku-api/internal/domain/request"
+	"hotaku-api/internal/domain/response"
+	"hotaku-api/internal/usecaseinf"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GroupController handles group-related HTTP requests
+type GroupController struct {
+	watermarkUseCase usecaseinf.WatermarkUseCase
+}
+
+// NewGroupController creates a new instance of GroupController
+func NewGroupController(watermarkUseCase usecaseinf.WatermarkUseCase) *GroupController {
+	return &GroupController{
+		watermarkUseCase: watermarkUseCase,
+	}
+}
+
+// GetWatermark returns the watermark of a group
+func (gc *GroupController) GetWatermark(c *gin.Context) {
+	userID := c.GetString("user_id")
+
+	watermark, err := gc.watermarkUseCase.GetGroupWatermark(userID, c.Param("id"))
+	if err != nil {
+		status := watermarkErrorStatus(err)
+		c.JSON(status, response.ErrorResponse(status, "Failed to retrieve watermark", err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, response.SuccessResponse(http.StatusOK, "Watermark retrieved successfully", watermark))
+}
+
+// SetWatermark creates or replaces the watermark of a group from a multipart form,
+// with the PNG overlay of image watermarks in the "image" field
+func (gc *GroupController) SetWatermark(c *gin.Context) {
+	userID := c.GetString("user_id")
+
+	var req request.SetGroupWatermarkRequest
+	if err := c.ShouldBind(&req); err != nil {
+		c.JSON(http.StatusBadRequest, response.ErrorResponse(http.StatusBadRequest, "Invalid request data", err.Error()))
+		return
+	}
+
+	// The overlay is optional when an image watermark already has one
+	overlay, err := c.FormFile("image")
+	if err != nil && !errors.Is(err, http.ErrMissingFile) {
+		c.JSON(http.StatusBadRequest, response.ErrorResponse(http.StatusBadRequest, "Invalid overlay image", err.Error()))
+		return
+	}
+
+	watermark, err := gc.watermarkUseCase.SetGroupWatermark(userID, c.Param("id"), &req, overlay)
+	if err != nil {
+		status := watermarkErrorStatus(err)
+		c.JSON(status, response.ErrorResponse(status, "Failed to save watermark", err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, response.SuccessResponse(http.StatusOK, "Watermark saved successfully", watermark))
+}
+
+// DeleteWatermark removes the watermark of a group
+func (gc *GroupController) DeleteWatermark(c *gin.Context) {
+	userID := c.GetString("user_id")
+
+	if err := gc.watermarkUseCase.DeleteGroupWatermark(userID, c.Param("id")); err != nil {
+		status := watermarkErrorStatus(err)
+		c.JSON(status, response.ErrorResponse(status, "Failed to delete watermark", err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, response.SuccessResponse(http.StatusOK, "Watermark deleted successfully", nil))
+}
+
+// watermarkErrorStatus maps watermark errors to HTTP status codes
+func watermarkErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, usecaseinf.ErrWatermarkForbidden):
+		return http.StatusForbidden
+	case errors.Is(err, usecaseinf.ErrWatermarkNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, usecaseinf.ErrInvalidWatermark), errors.Is(err, usecaseinf.ErrGroupNotCredited),
+		errors.Is(err, usecaseinf.ErrWatermarkNeedsPrivateBucket):
+		return http.StatusBadRequest
+	case errors.Is(err, usecaseinf.ErrWatermarkFailed):
+		return http.StatusUnprocessableEntity
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -0,0 +1,192 @@
+package controllers
+
+import (
+	"encoding/csv"
+	"errors"
+	"io"
+	"log"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"hotaku-api/internal/domain/dto"
+	"hotaku-api/internal/domain/request"
+	"hotaku-api/internal/domain/response"
+	"hotaku-api/internal/usecaseinf"
+
+	"github.com/gin-gonic/gin"
+)
+
+// HistoryController handles reading history-related HTTP requests
+type HistoryController struct {
+	historyUseCase usecaseinf.HistoryUseCase
+}
+
+// NewHistoryController creates a new instance of HistoryController
+func NewHistoryController(historyUseCase usecaseinf.HistoryUseCase) *HistoryController {
+	return &HistoryController{
+		historyUseCase: historyUseCase,
+	}
+}
+
+// ListHistory returns the chapters the current user opened, the latest first
+func (hc *HistoryController) ListHistory(c *gin.Context) {
+	userID := c.GetString("user_id")
+
+	var req request.ListHistoryRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, response.ErrorResponse(http.StatusBadRequest, "Invalid request data", err.Error()))
+		return
+	}
+
+	history, err := hc.historyUseCase.ListHistory(userID, &req, preferredLanguages(c))
+	if err != nil {
+		status := historyErrorStatus(err)
+		c.JSON(status, response.ErrorResponse(status, "Failed to retrieve reading history", err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, response.SuccessResponse(http.StatusOK, "Reading history retrieved successfully", history))
+}
+
+// ExportHistory downloads the whole reading history of the current user as JSON or CSV
+func (hc *HistoryController) ExportHistory(c *gin.Context) {
+	userID := c.GetString("user_id")
+
+	var req request.ExportHistoryRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, response.ErrorResponse(http.StatusBadRequest, "Invalid request data", err.Error()))
+		return
+	}
+
+	entries, err := hc.historyUseCase.ExportHistory(userID, preferredLanguages(c))
+	if err != nil {
+		status := historyErrorStatus(err)
+		c.JSON(status, response.ErrorResponse(status, "Failed to export reading history", err.Error()))
+		return
+	}
+
+	filename := "reading-history-" + time.Now().UTC().Format("2006-01-02")
+	if req.Format != "csv" {
+		c.Header("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": filename + ".json"}))
+		c.JSON(http.StatusOK, entries)
+		return
+	}
+
+	c.Header("Content-Type", "text/csv; charset=utf-8")
+	c.Header("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": filename + ".csv"}))
+	c.Status(http.StatusOK)
+
+	// Headers are already sent, so a failure can only cut the file short
+	if err := writeHistoryCSV(c.Writer, entries); err != nil {
+		log.Printf("Failed to export reading history of user %s: %v", userID, err)
+		c.Abort()
+	}
+}
+
+// DeleteEntry removes an entry from the current user's reading history
+func (hc *HistoryController) DeleteEntry(c *gin.Context) {
+	userID := c.GetString("user_id")
+
+	if err := hc.historyUseCase.DeleteEntry(userID, c.Param("entry_id")); err != nil {
+		status := historyErrorStatus(err)
+		c.JSON(status, response.ErrorResponse(status, "Failed to delete history entry", err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, response.SuccessResponse(http.StatusOK, "History entry deleted successfully", nil))
+}
+
+// ClearHistory removes the current user's reading history, or only its entries of a manga
+func (hc *HistoryController) ClearHistory(c *gin.Context) {
+	userID := c.GetString("user_id")
+
+	var req request.ClearHistoryRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, response.ErrorResponse(http.StatusBadRequest, "Invalid request data", err.Error()))
+		return
+	}
+
+	cleared, err := hc.historyUseCase.ClearHistory(userID, &req)
+	if err != nil {
+		status := historyErrorStatus(err)
+		c.JSON(status, response.ErrorResponse(status, "Failed to clear reading history", err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, response.SuccessResponse(http.StatusOK, "Reading history cleared successfully", cleared))
+}
+
+// SetPaused pauses or resumes recording the chapters the current user opens
+func (hc *HistoryController) SetPaused(c *gin.Context) {
+	userID := c.GetString("user_id")
+
+	var req request.SetHistoryPauseRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, response.ErrorResponse(http.StatusBadRequest, "Invalid request data", err.Error()))
+		return
+	}
+
+	paused, err := hc.historyUseCase.SetPaused(userID, &req)
+	if err != nil {
+		status := historyErrorStatus(err)
+		c.JSON(status, response.ErrorResponse(status, "Failed to update reading history pause", err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, response.SuccessResponse(http.StatusOK, "Reading history pause updated successfully", paused))
+}
+
+// writeHistoryCSV writes history entries as CSV, a header row first
+func writeHistoryCSV(w io.Writer, entries []dto.HistoryEntryResponse) error {
+	writer := csv.NewWriter(w)
+	header := []string{"opened_at", "manga_id", "manga_title", "chapter_id", "chapter_number", "chapter_title", "language", "page_number"}
+	if err := writer.Write(header); err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		var chapterTitle, pageNumber string
+		if entry.ChapterTitle != nil {
+			chapterTitle = *entry.ChapterTitle
+		}
+		if entry.PageNumber != nil {
+			pageNumber = strconv.Itoa(*entry.PageNumber)
+		}
+		record := []string{
+			entry.OpenedAt.UTC().Format(time.RFC3339),
+			entry.MangaID,
+			csvSafe(entry.MangaTitle),
+			entry.ChapterID,
+			strconv.FormatFloat(entry.ChapterNumber, 'f', -1, 64),
+			csvSafe(chapterTitle),
+			entry.Language,
+			pageNumber,
+		}
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// csvSafe keeps a user supplied cell from being run as a formula by spreadsheets, by prefixing the cells starting
+// like one with a quote
+func csvSafe(value string) string {
+	if value != "" && strings.ContainsRune("=+-@\t\r", rune(value[0])) {
+		return "'" + value
+	}
+	return value
+}
+
+// historyErrorStatus maps reading history errors to HTTP status codes
+func historyErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, usecaseinf.ErrHistoryEntryNotFound):
+		return http.StatusNotFound
+	default:
+		return http.StatusInternalServerError
+	}
+}
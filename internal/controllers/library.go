@@ -0,0 +1,197 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+
+	"hotaku-api/internal/domain/request"
+	"hotaku-api/internal/domain/response"
+	"hotaku-api/internal/usecaseinf"
+
+	"github.com/gin-gonic/gin"
+)
+
+// LibraryController handles user library-related HTTP requests
+type LibraryController struct {
+	libraryUseCase usecaseinf.LibraryUseCase
+}
+
+// NewLibraryController creates a new instance of LibraryController
+func NewLibraryController(libraryUseCase usecaseinf.LibraryUseCase) *LibraryController {
+	return &LibraryController{
+		libraryUseCase: libraryUseCase,
+	}
+}
+
+// GetLibrary returns the current user's library
+func (lc *LibraryController) GetLibrary(c *gin.Context) {
+	userID := c.GetString("user_id")
+
+	var req request.ListLibraryRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, response.ErrorResponse(http.StatusBadRequest, "Invalid request data", err.Error()))
+		return
+	}
+
+	library, err := lc.libraryUseCase.GetLibrary(userID, &req, preferredLanguages(c))
+	if err != nil {
+		status := libraryErrorStatus(err)
+		c.JSON(status, response.ErrorResponse(status, "Failed to retrieve library", err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, response.SuccessResponse(http.StatusOK, "Library retrieved successfully", library))
+}
+
+// GetPublicLibrary returns the library of a user who shares it
+func (lc *LibraryController) GetPublicLibrary(c *gin.Context) {
+	var req request.ListLibraryRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, response.ErrorResponse(http.StatusBadRequest, "Invalid request data", err.Error()))
+		return
+	}
+
+	library, err := lc.libraryUseCase.GetPublicLibrary(c.GetString("user_id"), c.Param("id"), &req, preferredLanguages(c))
+	if err != nil {
+		status := libraryErrorStatus(err)
+		c.JSON(status, response.ErrorResponse(status, "Failed to retrieve library", err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, response.SuccessResponse(http.StatusOK, "Library retrieved successfully", library))
+}
+
+// SaveFavorite adds a manga to the current user's library or moves it to another shelf
+func (lc *LibraryController) SaveFavorite(c *gin.Context) {
+	userID := c.GetString("user_id")
+
+	var req request.SaveFavoriteRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, response.ErrorResponse(http.StatusBadRequest, "Invalid request data", err.Error()))
+		return
+	}
+
+	favorite, err := lc.libraryUseCase.SaveFavorite(userID, c.Param("manga_id"), &req)
+	if err != nil {
+		status := libraryErrorStatus(err)
+		c.JSON(status, response.ErrorResponse(status, "Failed to save manga to library", err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, response.SuccessResponse(http.StatusOK, "Manga saved to library successfully", favorite))
+}
+
+// RemoveFavorite removes a manga from the current user's library
+func (lc *LibraryController) RemoveFavorite(c *gin.Context) {
+	userID := c.GetString("user_id")
+
+	if err := lc.libraryUseCase.RemoveFavorite(userID, c.Param("manga_id")); err != nil {
+		status := libraryErrorStatus(err)
+		c.JSON(status, response.ErrorResponse(status, "Failed to remove manga from library", err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, response.SuccessResponse(http.StatusOK, "Manga removed from library successfully", nil))
+}
+
+// ListShelves returns the shelves of the current user's library
+func (lc *LibraryController) ListShelves(c *gin.Context) {
+	userID := c.GetString("user_id")
+
+	shelves, err := lc.libraryUseCase.ListShelves(userID)
+	if err != nil {
+		status := libraryErrorStatus(err)
+		c.JSON(status, response.ErrorResponse(status, "Failed to list shelves", err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, response.SuccessResponse(http.StatusOK, "Shelves retrieved successfully", shelves))
+}
+
+// CreateShelf adds a custom shelf to the current user's library
+func (lc *LibraryController) CreateShelf(c *gin.Context) {
+	userID := c.GetString("user_id")
+
+	var req request.SaveShelfRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, response.ErrorResponse(http.StatusBadRequest, "Invalid request data", err.Error()))
+		return
+	}
+
+	shelf, err := lc.libraryUseCase.CreateShelf(userID, &req)
+	if err != nil {
+		status := libraryErrorStatus(err)
+		c.JSON(status, response.ErrorResponse(status, "Failed to create shelf", err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, response.SuccessResponse(http.StatusOK, "Shelf created successfully", shelf))
+}
+
+// RenameShelf changes the name of a shelf of the current user's library
+func (lc *LibraryController) RenameShelf(c *gin.Context) {
+	userID := c.GetString("user_id")
+
+	var req request.SaveShelfRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, response.ErrorResponse(http.StatusBadRequest, "Invalid request data", err.Error()))
+		return
+	}
+
+	shelf, err := lc.libraryUseCase.RenameShelf(userID, c.Param("shelf_id"), &req)
+	if err != nil {
+		status := libraryErrorStatus(err)
+		c.JSON(status, response.ErrorResponse(status, "Failed to rename shelf", err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, response.SuccessResponse(http.StatusOK, "Shelf renamed successfully", shelf))
+}
+
+// DeleteShelf removes a custom shelf of the current user's library
+func (lc *LibraryController) DeleteShelf(c *gin.Context) {
+	userID := c.GetString("user_id")
+
+	if err := lc.libraryUseCase.DeleteShelf(userID, c.Param("shelf_id")); err != nil {
+		status := libraryErrorStatus(err)
+		c.JSON(status, response.ErrorResponse(status, "Failed to delete shelf", err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, response.SuccessResponse(http.StatusOK, "Shelf deleted successfully", nil))
+}
+
+// SetVisibility changes whether the current user's library is shared
+func (lc *LibraryController) SetVisibility(c *gin.Context) {
+	userID := c.GetString("user_id")
+
+	var req request.SetLibraryVisibilityRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, response.ErrorResponse(http.StatusBadRequest, "Invalid request data", err.Error()))
+		return
+	}
+
+	visibility, err := lc.libraryUseCase.SetVisibility(userID, &req)
+	if err != nil {
+		status := libraryErrorStatus(err)
+		c.JSON(status, response.ErrorResponse(status, "Failed to update library visibility", err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, response.SuccessResponse(http.StatusOK, "Library visibility updated successfully", visibility))
+}
+
+// libraryErrorStatus maps library errors to HTTP status codes
+func libraryErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, usecaseinf.ErrLibraryNotFound), errors.Is(err, usecaseinf.ErrMangaNotFound),
+		errors.Is(err, usecaseinf.ErrFavoriteNotFound), errors.Is(err, usecaseinf.ErrShelfNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, usecaseinf.ErrShelfExists):
+		return http.StatusConflict
+	case errors.Is(err, usecaseinf.ErrShelfBuiltin), errors.Is(err, usecaseinf.ErrInvalidLibraryFilter):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
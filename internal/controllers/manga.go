@@ -0,0 +1,305 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+
+	"hotaku-api/internal/domain/request"
+	"hotaku-api/internal/domain/response"
+	"hotaku-api/internal/usecaseinf"
+	"hotaku-api/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// MangaController handles manga-related HTTP requests
+type MangaController struct {
+	mangaUseCase      usecaseinf.MangaUseCase
+	mangaCoverUseCase usecaseinf.MangaCoverUseCase
+	mangaTitleUseCase usecaseinf.MangaTitleUseCase
+	quotaUseCase      usecaseinf.QuotaUseCase
+}
+
+// NewMangaController creates a new instance of MangaController
+func NewMangaController(mangaUseCase usecaseinf.MangaUseCase, mangaCoverUseCase usecaseinf.MangaCoverUseCase, mangaTitleUseCase usecaseinf.MangaTitleUseCase, quotaUseCase usecaseinf.QuotaUseCase) *MangaController {
+	return &MangaController{
+		mangaUseCase:      mangaUseCase,
+		mangaCoverUseCase: mangaCoverUseCase,
+		mangaTitleUseCase: mangaTitleUseCase,
+		quotaUseCase:      quotaUseCase,
+	}
+}
+
+// GetManga returns a manga with its primary cover, titled in the language the request asks for
+func (mc *MangaController) GetManga(c *gin.Context) {
+	manga, err := mc.mangaUseCase.GetManga(c.GetString("user_id"), c.Param("id"), preferredLanguages(c))
+	if err != nil {
+		c.JSON(http.StatusNotFound, response.ErrorResponse(http.StatusNotFound, "Manga not found", err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, response.SuccessResponse(http.StatusOK, "Manga retrieved successfully", manga))
+}
+
+// ListMangas returns a page of mangas matching the catalog filters
+func (mc *MangaController) ListMangas(c *gin.Context) {
+	var req request.ListMangasRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, response.ErrorResponse(http.StatusBadRequest, "Invalid request data", err.Error()))
+		return
+	}
+
+	mangas, err := mc.mangaUseCase.ListMangas(c.GetString("user_id"), &req, preferredLanguages(c))
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, usecaseinf.ErrInvalidMangaFilter) {
+			status = http.StatusBadRequest
+		}
+		c.JSON(status, response.ErrorResponse(status, "Failed to list mangas", err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, response.SuccessResponse(http.StatusOK, "Mangas retrieved successfully", mangas))
+}
+
+// SetVisibility changes whether a manga's images are served through signed URLs only
+func (mc *MangaController) SetVisibility(c *gin.Context) {
+	userID := c.GetString("user_id")
+
+	var req request.SetVisibilityRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, response.ErrorResponse(http.StatusBadRequest, "Invalid request data", err.Error()))
+		return
+	}
+
+	manga, err := mc.mangaUseCase.SetVisibility(userID, c.Param("id"), req.Visibility)
+	if err != nil {
+		status := http.StatusNotFound
+		switch {
+		case errors.Is(err, usecaseinf.ErrModeratorOnly):
+			status = http.StatusForbidden
+		case errors.Is(err, usecaseinf.ErrRestrictedNeedsPrivateBucket):
+			status = http.StatusBadRequest
+		}
+		c.JSON(status, response.ErrorResponse(status, "Failed to update manga visibility", err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, response.SuccessResponse(http.StatusOK, "Manga visibility updated successfully", manga))
+}
+
+// SetYear sets the year a manga was first published
+func (mc *MangaController) SetYear(c *gin.Context) {
+	userID := c.GetString("user_id")
+
+	var req request.SetYearRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, response.ErrorResponse(http.StatusBadRequest, "Invalid request data", err.Error()))
+		return
+	}
+
+	manga, err := mc.mangaUseCase.SetYear(userID, c.Param("id"), req.Year)
+	if err != nil {
+		status := http.StatusNotFound
+		if errors.Is(err, usecaseinf.ErrModeratorOnly) {
+			status = http.StatusForbidden
+		}
+		c.JSON(status, response.ErrorResponse(status, "Failed to update manga year", err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, response.SuccessResponse(http.StatusOK, "Manga year updated successfully", manga))
+}
+
+// ListCovers returns all covers of a manga
+func (mc *MangaController) ListCovers(c *gin.Context) {
+	covers, err := mc.mangaCoverUseCase.ListCovers(c.GetString("user_id"), c.Param("id"))
+	if err != nil {
+		if errors.Is(err, usecaseinf.ErrMangaRestricted) {
+			c.JSON(http.StatusForbidden, response.ErrorResponse(http.StatusForbidden, "Manga is restricted", err.Error()))
+			return
+		}
+		c.JSON(http.StatusNotFound, response.ErrorResponse(http.StatusNotFound, "Manga not found", err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, response.SuccessResponse(http.StatusOK, "Covers retrieved successfully", covers))
+}
+
+// UploadCover adds a new cover to a manga
+func (mc *MangaController) UploadCover(c *gin.Context) {
+	userID := c.GetString("user_id")
+
+	var req request.UploadCoverRequest
+	if err := c.ShouldBind(&req); err != nil {
+		c.JSON(http.StatusBadRequest, response.ErrorResponse(http.StatusBadRequest, "Invalid request data", err.Error()))
+		return
+	}
+
+	file, err := c.FormFile("image")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.ErrorResponse(http.StatusBadRequest, "No image file found in the request. Key 'image' required.", nil))
+		return
+	}
+
+	if !checkQuota(c, mc.quotaUseCase, c.Param("id"), file.Size) {
+		return
+	}
+
+	cover, err := mc.mangaCoverUseCase.UploadCover(userID, c.Param("id"), file, &req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.ErrorResponse(http.StatusBadRequest, "Failed to upload cover", err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, response.SuccessResponse(http.StatusOK, "Cover uploaded successfully", cover))
+}
+
+// SetPrimaryCover makes a cover the one shown for the manga
+func (mc *MangaController) SetPrimaryCover(c *gin.Context) {
+	userID := c.GetString("user_id")
+
+	cover, err := mc.mangaCoverUseCase.SetPrimaryCover(userID, c.Param("id"), c.Param("cover_id"))
+	if err != nil {
+		status := coverErrorStatus(err)
+		c.JSON(status, response.ErrorResponse(status, "Failed to set primary cover", err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, response.SuccessResponse(http.StatusOK, "Primary cover updated successfully", cover))
+}
+
+// DeleteCover removes a cover and its image
+func (mc *MangaController) DeleteCover(c *gin.Context) {
+	userID := c.GetString("user_id")
+
+	if err := mc.mangaCoverUseCase.DeleteCover(userID, c.Param("id"), c.Param("cover_id")); err != nil {
+		status := coverErrorStatus(err)
+		c.JSON(status, response.ErrorResponse(status, "Failed to delete cover", err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, response.SuccessResponse(http.StatusOK, "Cover deleted successfully", nil))
+}
+
+// ListTitles returns all localized titles of a manga
+func (mc *MangaController) ListTitles(c *gin.Context) {
+	titles, err := mc.mangaTitleUseCase.ListTitles(c.Param("id"))
+	if err != nil {
+		status := titleErrorStatus(err)
+		c.JSON(status, response.ErrorResponse(status, "Failed to retrieve titles", err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, response.SuccessResponse(http.StatusOK, "Titles retrieved successfully", titles))
+}
+
+// AddTitle adds a localized title to a manga
+func (mc *MangaController) AddTitle(c *gin.Context) {
+	userID := c.GetString("user_id")
+
+	var req request.AddMangaTitleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, response.ErrorResponse(http.StatusBadRequest, "Invalid request data", err.Error()))
+		return
+	}
+
+	title, err := mc.mangaTitleUseCase.AddTitle(userID, c.Param("id"), &req)
+	if err != nil {
+		status := titleErrorStatus(err)
+		c.JSON(status, response.ErrorResponse(status, "Failed to add title", err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, response.SuccessResponse(http.StatusOK, "Title added successfully", title))
+}
+
+// DeleteTitle removes a localized title of a manga
+func (mc *MangaController) DeleteTitle(c *gin.Context) {
+	userID := c.GetString("user_id")
+
+	if err := mc.mangaTitleUseCase.DeleteTitle(userID, c.Param("id"), c.Param("title_id")); err != nil {
+		status := titleErrorStatus(err)
+		c.JSON(status, response.ErrorResponse(status, "Failed to delete title", err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, response.SuccessResponse(http.StatusOK, "Title deleted successfully", nil))
+}
+
+// ListDescriptions returns all localized descriptions of a manga
+func (mc *MangaController) ListDescriptions(c *gin.Context) {
+	descriptions, err := mc.mangaTitleUseCase.ListDescriptions(c.Param("id"))
+	if err != nil {
+		status := titleErrorStatus(err)
+		c.JSON(status, response.ErrorResponse(status, "Failed to retrieve descriptions", err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, response.SuccessResponse(http.StatusOK, "Descriptions retrieved successfully", descriptions))
+}
+
+// SetDescription creates or replaces the description of a manga in a locale
+func (mc *MangaController) SetDescription(c *gin.Context) {
+	userID := c.GetString("user_id")
+
+	var req request.SetMangaDescriptionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, response.ErrorResponse(http.StatusBadRequest, "Invalid request data", err.Error()))
+		return
+	}
+
+	description, err := mc.mangaTitleUseCase.SetDescription(userID, c.Param("id"), c.Param("locale"), &req)
+	if err != nil {
+		status := titleErrorStatus(err)
+		c.JSON(status, response.ErrorResponse(status, "Failed to save description", err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, response.SuccessResponse(http.StatusOK, "Description saved successfully", description))
+}
+
+// DeleteDescription removes the description of a manga in a locale
+func (mc *MangaController) DeleteDescription(c *gin.Context) {
+	userID := c.GetString("user_id")
+
+	if err := mc.mangaTitleUseCase.DeleteDescription(userID, c.Param("id"), c.Param("locale")); err != nil {
+		status := titleErrorStatus(err)
+		c.JSON(status, response.ErrorResponse(status, "Failed to delete description", err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, response.SuccessResponse(http.StatusOK, "Description deleted successfully", nil))
+}
+
+// preferredLanguages returns the languages a request asks for through the lang parameter and the Accept-Language
+// header, most preferred first, and marks the response as depending on the header
+func preferredLanguages(c *gin.Context) []string {
+	c.Header("Vary", "Accept-Language")
+	return utils.PreferredLanguages(c.Query("lang"), c.GetHeader("Accept-Language"))
+}
+
+// titleErrorStatus maps localized title and description errors to HTTP status codes
+func titleErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, usecaseinf.ErrModeratorOnly):
+		return http.StatusForbidden
+	case errors.Is(err, usecaseinf.ErrMangaNotFound), errors.Is(err, usecaseinf.ErrMangaTitleNotFound),
+		errors.Is(err, usecaseinf.ErrMangaDescriptionNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, usecaseinf.ErrMangaTitleExists):
+		return http.StatusConflict
+	case errors.Is(err, usecaseinf.ErrInvalidLocale):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// coverErrorStatus maps cover errors to HTTP status codes
+func coverErrorStatus(err error) int {
+	if errors.Is(err, usecaseinf.ErrCoverNotFound) {
+		return http.StatusNotFound
+	}
+	return uploadErrorStatus(err, fileErrorStatus(err))
+}
@@ -0,0 +1,76 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+
+	"hotaku-api/internal/domain/request"
+	"hotaku-api/internal/domain/response"
+	"hotaku-api/internal/usecaseinf"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ModerationController handles moderation queue requests
+type ModerationController struct {
+	moderationUseCase usecaseinf.ModerationUseCase
+}
+
+// NewModerationController creates a new instance of ModerationController
+func NewModerationController(moderationUseCase usecaseinf.ModerationUseCase) *ModerationController {
+	return &ModerationController{
+		moderationUseCase: moderationUseCase,
+	}
+}
+
+// ListDuplicates returns the pages flagged as possible duplicates
+func (mc *ModerationController) ListDuplicates(c *gin.Context) {
+	userID := c.GetString("user_id")
+
+	var req request.ListDuplicateFlagsRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, response.ErrorResponse(http.StatusBadRequest, "Invalid request data", err.Error()))
+		return
+	}
+
+	flags, err := mc.moderationUseCase.ListDuplicateFlags(userID, &req)
+	if err != nil {
+		status := moderationErrorStatus(err)
+		c.JSON(status, response.ErrorResponse(status, "Failed to retrieve duplicate flags", err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, response.SuccessResponse(http.StatusOK, "Duplicate flags retrieved successfully", flags))
+}
+
+// ReviewDuplicate records a moderator's decision on a duplicate flag
+func (mc *ModerationController) ReviewDuplicate(c *gin.Context) {
+	userID := c.GetString("user_id")
+
+	var req request.ReviewDuplicateFlagRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, response.ErrorResponse(http.StatusBadRequest, "Invalid request data", err.Error()))
+		return
+	}
+
+	flag, err := mc.moderationUseCase.ReviewDuplicateFlag(userID, c.Param("flag_id"), req.Status)
+	if err != nil {
+		status := moderationErrorStatus(err)
+		c.JSON(status, response.ErrorResponse(status, "Failed to review duplicate flag", err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, response.SuccessResponse(http.StatusOK, "Duplicate flag reviewed successfully", flag))
+}
+
+// moderationErrorStatus maps moderation errors to HTTP status codes
+func moderationErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, usecaseinf.ErrModeratorOnly):
+		return http.StatusForbidden
+	case errors.Is(err, usecaseinf.ErrDuplicateFlagNotFound):
+		return http.StatusNotFound
+	default:
+		return http.StatusInternalServerError
+	}
+}
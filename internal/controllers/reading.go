@@ -0,0 +1,145 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"hotaku-api/internal/domain/request"
+	"hotaku-api/internal/domain/response"
+	"hotaku-api/internal/usecaseinf"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ReadingController handles reading progress-related HTTP requests
+type ReadingController struct {
+	readingUseCase usecaseinf.ReadingUseCase
+}
+
+// NewReadingController creates a new instance of ReadingController
+func NewReadingController(readingUseCase usecaseinf.ReadingUseCase) *ReadingController {
+	return &ReadingController{
+		readingUseCase: readingUseCase,
+	}
+}
+
+// MarkRead marks a chapter read by the current user
+func (rc *ReadingController) MarkRead(c *gin.Context) {
+	userID := c.GetString("user_id")
+
+	progress, err := rc.readingUseCase.MarkRead(userID, c.Param("id"), incognito(c), preferredLanguages(c))
+	if err != nil {
+		status := readingErrorStatus(err)
+		c.JSON(status, response.ErrorResponse(status, "Failed to mark chapter read", err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, response.SuccessResponse(http.StatusOK, "Chapter marked read successfully", progress))
+}
+
+// MarkUnread marks a chapter unread by the current user
+func (rc *ReadingController) MarkUnread(c *gin.Context) {
+	userID := c.GetString("user_id")
+
+	progress, err := rc.readingUseCase.MarkUnread(userID, c.Param("id"), preferredLanguages(c))
+	if err != nil {
+		status := readingErrorStatus(err)
+		c.JSON(status, response.ErrorResponse(status, "Failed to mark chapter unread", err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, response.SuccessResponse(http.StatusOK, "Chapter marked unread successfully", progress))
+}
+
+// MarkReadUpTo marks every chapter of a manga up to a chapter number read by the current user
+func (rc *ReadingController) MarkReadUpTo(c *gin.Context) {
+	userID := c.GetString("user_id")
+
+	var req request.MarkReadUpToRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, response.ErrorResponse(http.StatusBadRequest, "Invalid request data", err.Error()))
+		return
+	}
+
+	progress, err := rc.readingUseCase.MarkReadUpTo(userID, c.Param("id"), &req, preferredLanguages(c))
+	if err != nil {
+		status := readingErrorStatus(err)
+		c.JSON(status, response.ErrorResponse(status, "Failed to mark chapters read", err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, response.SuccessResponse(http.StatusOK, "Chapters marked read successfully", progress))
+}
+
+// SavePosition records the page of a chapter the current user is on
+func (rc *ReadingController) SavePosition(c *gin.Context) {
+	userID := c.GetString("user_id")
+
+	var req request.SaveReadingPositionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, response.ErrorResponse(http.StatusBadRequest, "Invalid request data", err.Error()))
+		return
+	}
+
+	progress, err := rc.readingUseCase.SavePosition(userID, c.Param("id"), &req, incognito(c), preferredLanguages(c))
+	if err != nil {
+		status := readingErrorStatus(err)
+		c.JSON(status, response.ErrorResponse(status, "Failed to save reading position", err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, response.SuccessResponse(http.StatusOK, "Reading position saved successfully", progress))
+}
+
+// GetProgress summarizes how much of a manga the current user read and where to continue
+func (rc *ReadingController) GetProgress(c *gin.Context) {
+	userID := c.GetString("user_id")
+
+	progress, err := rc.readingUseCase.GetProgress(userID, c.Param("id"), preferredLanguages(c))
+	if err != nil {
+		status := readingErrorStatus(err)
+		c.JSON(status, response.ErrorResponse(status, "Failed to retrieve reading progress", err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, response.SuccessResponse(http.StatusOK, "Reading progress retrieved successfully", progress))
+}
+
+// ContinueReading returns the mangas the current user has been reading, the most recently read first
+func (rc *ReadingController) ContinueReading(c *gin.Context) {
+	userID := c.GetString("user_id")
+
+	var req request.ContinueReadingRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, response.ErrorResponse(http.StatusBadRequest, "Invalid request data", err.Error()))
+		return
+	}
+
+	feed, err := rc.readingUseCase.ContinueReading(userID, &req, preferredLanguages(c))
+	if err != nil {
+		status := readingErrorStatus(err)
+		c.JSON(status, response.ErrorResponse(status, "Failed to retrieve continue reading feed", err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, response.SuccessResponse(http.StatusOK, "Continue reading feed retrieved successfully", feed))
+}
+
+// incognito tells whether the request asks to read without recording anything, with incognito=true
+func incognito(c *gin.Context) bool {
+	enabled, _ := strconv.ParseBool(c.Query("incognito"))
+	return enabled
+}
+
+// readingErrorStatus maps reading progress errors to HTTP status codes
+func readingErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, usecaseinf.ErrMangaNotFound), errors.Is(err, usecaseinf.ErrChapterNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, usecaseinf.ErrInvalidPageNumber):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
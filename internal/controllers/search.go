@@ -0,0 +1,88 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+
+	"hotaku-api/internal/domain/request"
+	"hotaku-api/internal/domain/response"
+	"hotaku-api/internal/usecaseinf"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SearchController handles search and search suggestion requests
+type SearchController struct {
+	searchUseCase  usecaseinf.SearchUseCase
+	suggestUseCase usecaseinf.SuggestUseCase
+}
+
+// NewSearchController creates a new instance of SearchController
+func NewSearchController(searchUseCase usecaseinf.SearchUseCase, suggestUseCase usecaseinf.SuggestUseCase) *SearchController {
+	return &SearchController{
+		searchUseCase:  searchUseCase,
+		suggestUseCase: suggestUseCase,
+	}
+}
+
+// SearchMangas returns the mangas matching a full-text query
+func (sc *SearchController) SearchMangas(c *gin.Context) {
+	var req request.SearchMangasRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, response.ErrorResponse(http.StatusBadRequest, "Invalid request data", err.Error()))
+		return
+	}
+
+	results, err := sc.searchUseCase.SearchMangas(c.GetString("user_id"), &req, preferredLanguages(c))
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, usecaseinf.ErrInvalidSearchQuery) {
+			status = http.StatusBadRequest
+		}
+		c.JSON(status, response.ErrorResponse(status, "Failed to search mangas", err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, response.SuccessResponse(http.StatusOK, "Search completed successfully", results))
+}
+
+// RebuildIndex reindexes every manga in the embedded search index
+func (sc *SearchController) RebuildIndex(c *gin.Context) {
+	userID := c.GetString("user_id")
+
+	index, err := sc.searchUseCase.RebuildIndex(userID)
+	if err != nil {
+		status := http.StatusInternalServerError
+		switch {
+		case errors.Is(err, usecaseinf.ErrModeratorOnly):
+			status = http.StatusForbidden
+		case errors.Is(err, usecaseinf.ErrSearchIndexDisabled):
+			status = http.StatusConflict
+		}
+		c.JSON(status, response.ErrorResponse(status, "Failed to rebuild search index", err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, response.SuccessResponse(http.StatusOK, "Search index rebuilt successfully", index))
+}
+
+// Suggest returns the mangas, authors, groups and categories matching what is being typed
+func (sc *SearchController) Suggest(c *gin.Context) {
+	var req request.SuggestRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, response.ErrorResponse(http.StatusBadRequest, "Invalid request data", err.Error()))
+		return
+	}
+
+	suggestions, err := sc.suggestUseCase.Suggest(&req)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, usecaseinf.ErrInvalidSuggestionType) {
+			status = http.StatusBadRequest
+		}
+		c.JSON(status, response.ErrorResponse(status, "Failed to retrieve suggestions", err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, response.SuccessResponse(http.StatusOK, "Suggestions retrieved successfully", suggestions))
+}
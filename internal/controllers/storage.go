@@ -0,0 +1,61 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+
+	"hotaku-api/internal/domain/response"
+	"hotaku-api/internal/usecaseinf"
+
+	"github.com/gin-gonic/gin"
+)
+
+// StorageController handles storage usage requests
+type StorageController struct {
+	quotaUseCase usecaseinf.QuotaUseCase
+}
+
+// NewStorageController creates a new instance of StorageController
+func NewStorageController(quotaUseCase usecaseinf.QuotaUseCase) *StorageController {
+	return &StorageController{
+		quotaUseCase: quotaUseCase,
+	}
+}
+
+// GetUsage returns the storage usage of the current user and their groups
+func (sc *StorageController) GetUsage(c *gin.Context) {
+	userID := c.GetString("user_id")
+
+	usage, err := sc.quotaUseCase.GetUsage(userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, response.ErrorResponse(http.StatusInternalServerError, "Failed to retrieve storage usage", err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, response.SuccessResponse(http.StatusOK, "Storage usage retrieved successfully", usage))
+}
+
+// uploadErrorStatus maps an error storing an upload to an HTTP status code, fallback unless the upload went
+// over quota when it was recorded
+func uploadErrorStatus(err error, fallback int) int {
+	if errors.Is(err, usecaseinf.ErrQuotaExceeded) {
+		return http.StatusRequestEntityTooLarge
+	}
+	return fallback
+}
+
+// checkQuota verifies that the current user may store size more bytes for the manga,
+// writing the error response and returning false when they may not
+func checkQuota(c *gin.Context, quotaUseCase usecaseinf.QuotaUseCase, mangaID string, size int64) bool {
+	err := quotaUseCase.CheckUpload(c.GetString("user_id"), mangaID, size)
+	if err == nil {
+		return true
+	}
+
+	if errors.Is(err, usecaseinf.ErrQuotaExceeded) {
+		c.JSON(http.StatusRequestEntityTooLarge, response.ErrorResponse(http.StatusRequestEntityTooLarge, "Storage quota exceeded", err.Error()))
+	} else {
+		c.JSON(http.StatusInternalServerError, response.ErrorResponse(http.StatusInternalServerError, "Failed to check storage quota", err.Error()))
+	}
+	return false
+}
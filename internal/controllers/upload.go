@@ -1,16 +1,23 @@
 package controllers
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"io"
 	"mime/multipart"
 	"net/http"
+	"os"
 	"path/filepath"
 	"strconv"
 	"strings"
+	"time"
 
 	"hotaku-api/internal/domain/dto"
+	"hotaku-api/internal/domain/request"
 	"hotaku-api/internal/domain/response"
 	"hotaku-api/internal/service"
+	"hotaku-api/internal/usecaseinf"
 
 	"github.com/gin-gonic/gin"
 )
@@ -21,13 +28,37 @@ const (
 
 // UploadController handles file upload operations
 type UploadController struct {
-	minioService *service.MinIOService
+	minioService        *service.MinIOService
+	chapterPageUseCase  usecaseinf.ChapterPageUseCase
+	directUploadUseCase usecaseinf.DirectUploadUseCase
+	fileUseCase         usecaseinf.FileUseCase
+	mangaCoverUseCase   usecaseinf.MangaCoverUseCase
+	quotaUseCase        usecaseinf.QuotaUseCase
+	imageUseCase        usecaseinf.ImageUseCase
+	// maxPagesRequestSize caps the whole body of a chapter pages upload
+	maxPagesRequestSize int64
 }
 
 // NewUploadController creates a new upload controller
-func NewUploadController(minioService *service.MinIOService) *UploadController {
+func NewUploadController(
+	minioService *service.MinIOService,
+	chapterPageUseCase usecaseinf.ChapterPageUseCase,
+	directUploadUseCase usecaseinf.DirectUploadUseCase,
+	fileUseCase usecaseinf.FileUseCase,
+	mangaCoverUseCase usecaseinf.MangaCoverUseCase,
+	quotaUseCase usecaseinf.QuotaUseCase,
+	imageUseCase usecaseinf.ImageUseCase,
+	maxPagesRequestSize int64,
+) *UploadController {
 	return &UploadController{
-		minioService: minioService,
+		minioService:        minioService,
+		chapterPageUseCase:  chapterPageUseCase,
+		directUploadUseCase: directUploadUseCase,
+		fileUseCase:         fileUseCase,
+		mangaCoverUseCase:   mangaCoverUseCase,
+		quotaUseCase:        quotaUseCase,
+		imageUseCase:        imageUseCase,
+		maxPagesRequestSize: maxPagesRequestSize,
 	}
 }
 
@@ -51,21 +82,30 @@ func (c *UploadController) UploadMangaImage(ctx *gin.Context) {
 		return
 	}
 
-	// Upload to MinIO
-	fileURL, err := c.minioService.UploadMangaImage(file, mangaID)
+	if !checkQuota(ctx, c.quotaUseCase, mangaID, file.Size) {
+		return
+	}
+
+	// The uploaded image becomes the manga's primary cover
+	cover, err := c.mangaCoverUseCase.UploadCover(ctx.GetString("user_id"), mangaID, file, &request.UploadCoverRequest{Primary: true})
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, response.ErrorResponse(http.StatusInternalServerError, "Failed to upload file", nil))
+		status := uploadErrorStatus(err, http.StatusInternalServerError)
+		ctx.JSON(status, response.ErrorResponse(status, "Failed to upload file", nil))
 		return
 	}
 
 	ctx.JSON(http.StatusOK, response.SuccessResponse(http.StatusOK, "File uploaded successfully", dto.UploadResponse{
-		URL:      fileURL,
-		Filename: file.Filename,
-		Size:     file.Size,
+		URL:          cover.URL,
+		Filename:     file.Filename,
+		Size:         cover.Size,
+		OriginalSize: cover.OriginalSize,
 	}))
 }
 
-// UploadChapterPages handles chapter pages upload
+// UploadChapterPages handles chapter pages upload. The multipart body is streamed: each "pages"
+// part is validated and queued for storage as soon as it is received, so at most a few pages are
+// held in memory and nothing is spooled to disk. By default the upload is all-or-nothing;
+// with ?mode=partial the pages that could be stored are kept and every page gets a status
 func (c *UploadController) UploadChapterPages(ctx *gin.Context) {
 	mangaID := ctx.Param("manga_id")
 	chapterID := ctx.Param("chapter_id")
@@ -75,61 +115,257 @@ func (c *UploadController) UploadChapterPages(ctx *gin.Context) {
 		return
 	}
 
-	// Get the uploaded files
-	form, err := ctx.MultipartForm()
+	mode := ctx.DefaultQuery("mode", "atomic")
+	if mode != "atomic" && mode != "partial" {
+		ctx.JSON(http.StatusBadRequest, response.ErrorResponse(http.StatusBadRequest, "Mode must be atomic or partial", nil))
+		return
+	}
+
+	ctx.Request.Body = http.MaxBytesReader(ctx.Writer, ctx.Request.Body, c.maxPagesRequestSize)
+	reader, err := ctx.Request.MultipartReader()
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, response.ErrorResponse(http.StatusBadRequest, "Request must be multipart/form-data", nil))
+		return
+	}
+
+	// The pages aren't known before they are stored, so the declared body size is checked instead. A body of unknown
+	// size, such as a chunked one, is left to the quota check made as each page is recorded
+	if requestSize := min(ctx.Request.ContentLength, c.maxPagesRequestSize); requestSize >= 0 {
+		if !checkQuota(ctx, c.quotaUseCase, mangaID, requestSize) {
+			return
+		}
+	}
+
+	// Storage writes stop as soon as the client disconnects
+	batch, err := c.chapterPageUseCase.BeginPageUpload(ctx.Request.Context(), ctx.GetString("user_id"), mangaID, chapterID, mode == "partial")
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, response.ErrorResponse(http.StatusBadRequest, "Failed to get uploaded files", nil))
+		ctx.JSON(http.StatusBadRequest, response.ErrorResponse(http.StatusBadRequest, "Failed to upload pages", err.Error()))
 		return
 	}
 
-	files := form.File["pages"]
-	if len(files) == 0 {
+	// Pages are appended unless a 1-based insert position is given, in any part of the form
+	position := 0
+	pageCount := 0
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			batch.Abort()
+			pagesRequestError(ctx, err)
+			return
+		}
+
+		switch part.FormName() {
+		case "position":
+			value, err := io.ReadAll(io.LimitReader(part, 32))
+			if err == nil {
+				position, err = strconv.Atoi(strings.TrimSpace(string(value)))
+			}
+			if err != nil || position <= 0 {
+				part.Close()
+				batch.Abort()
+				ctx.JSON(http.StatusBadRequest, response.ErrorResponse(http.StatusBadRequest, "Position must be a positive integer", nil))
+				return
+			}
+		case "pages":
+			if err := c.addPagePart(batch, part); err != nil {
+				part.Close()
+				batch.Abort()
+				pagesRequestError(ctx, err)
+				return
+			}
+			pageCount++
+		}
+		part.Close()
+	}
+
+	if pageCount == 0 {
+		batch.Abort()
 		ctx.JSON(http.StatusBadRequest, response.ErrorResponse(http.StatusBadRequest, "No files uploaded", nil))
 		return
 	}
 
-	// List existing files in chapter to determine max page number
-	prefix := fmt.Sprintf("manga/%s/chapters/%s/", mangaID, chapterID)
-	existingFiles, err := c.minioService.ListFiles(prefix)
+	result, err := batch.Commit(position)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, response.ErrorResponse(http.StatusInternalServerError, "Failed to list existing files", nil))
+		pagesRequestError(ctx, err)
 		return
 	}
 
-	// Find max existing page number
-	maxPage := 0
-	_, fileName := filepath.Split(existingFiles[len(existingFiles)-1])
-	baseName := strings.TrimSuffix(fileName, filepath.Ext(fileName))
-	var pageNum int
-	if n, err := fmt.Sscanf(baseName, "page_%03d", &pageNum); err == nil && n == 1 && pageNum > maxPage {
-		maxPage = pageNum
+	switch {
+	case mode == "atomic":
+		pages := make([]dto.PageUploadResponse, 0, len(result.Pages))
+		for _, status := range result.Pages {
+			pages = append(pages, *status.Page)
+		}
+		ctx.JSON(http.StatusOK, response.SuccessResponse(http.StatusOK, "Files uploaded successfully", pages))
+	case result.Uploaded == 0:
+		ctx.JSON(http.StatusBadRequest, response.ErrorResponse(http.StatusBadRequest, "No pages could be uploaded", result))
+	case result.Failed > 0:
+		ctx.JSON(http.StatusMultiStatus, response.SuccessResponse(http.StatusMultiStatus, "Some files failed to upload", result))
+	default:
+		ctx.JSON(http.StatusOK, response.SuccessResponse(http.StatusOK, "Files uploaded successfully", result))
 	}
+}
 
-	var uploadResponses []dto.UploadResponse
+// addPagePart validates a streamed page and hands it to the upload batch. Invalid pages are
+// rejected through the batch, so a partial upload reports them and goes on
+func (c *UploadController) addPagePart(batch usecaseinf.PageUploadBatch, part *multipart.Part) error {
+	filename := part.FileName()
+	if !isValidImageFile(filename) {
+		return batch.Reject(filename, fmt.Errorf("%s: invalid file type. Only image files (jpg, jpeg, png, gif, webp) are allowed", filename))
+	}
 
-	// Upload each new file, continuing the page numbering
-	for i, file := range files {
-		pageNumber := maxPage + i + 1
+	// Read one byte past the limit to tell a page at the limit from an oversized one
+	data, err := io.ReadAll(io.LimitReader(part, MaxFileSize+1))
+	if err != nil {
+		return err
+	}
+	if len(data) > MaxFileSize {
+		return batch.Reject(filename, fmt.Errorf("%s: file size exceeds maximum allowed size %d", filename, MaxFileSize))
+	}
 
-		if err := c.validateImageFile(file); err != nil {
-			ctx.JSON(http.StatusBadRequest, response.ErrorResponse(http.StatusBadRequest, err.Error(), nil))
-			return
-		}
+	contentType := part.Header.Get("Content-Type")
+	if contentType == "" || contentType == "application/octet-stream" {
+		contentType = http.DetectContentType(data)
+	}
+	return batch.Add(filename, contentType, data)
+}
 
-		fileURL, err := c.minioService.UploadChapterPage(file, mangaID, chapterID, pageNumber)
+// pagesRequestError responds to a streamed page upload that was cut short by the request itself
+func pagesRequestError(ctx *gin.Context, err error) {
+	var maxBytesErr *http.MaxBytesError
+	switch {
+	case errors.As(err, &maxBytesErr):
+		ctx.JSON(http.StatusRequestEntityTooLarge, response.ErrorResponse(http.StatusRequestEntityTooLarge, "Request body too large", fmt.Sprintf("maximum allowed size is %d bytes", maxBytesErr.Limit)))
+	case errors.Is(err, usecaseinf.ErrTooManyPages):
+		ctx.JSON(http.StatusRequestEntityTooLarge, response.ErrorResponse(http.StatusRequestEntityTooLarge, "Too many pages", err.Error()))
+	case errors.Is(err, usecaseinf.ErrQuotaExceeded):
+		ctx.JSON(http.StatusRequestEntityTooLarge, response.ErrorResponse(http.StatusRequestEntityTooLarge, "Storage quota exceeded", err.Error()))
+	case errors.Is(err, context.Canceled):
+		// The client is gone, there is no one left to answer
+		ctx.Abort()
+	default:
+		ctx.JSON(http.StatusBadRequest, response.ErrorResponse(http.StatusBadRequest, "Failed to upload pages", err.Error()))
+	}
+}
+
+// UploadChapterArchive handles importing chapter pages from a CBZ/ZIP archive
+func (c *UploadController) UploadChapterArchive(ctx *gin.Context) {
+	mangaID := ctx.Param("manga_id")
+	chapterID := ctx.Param("chapter_id")
+
+	if mangaID == "" || chapterID == "" {
+		ctx.JSON(http.StatusBadRequest, response.ErrorResponse(http.StatusBadRequest, "Manga ID and Chapter ID are required", nil))
+		return
+	}
+
+	// Stream the multipart body instead of letting Gin buffer the whole form
+	ctx.Request.Body = http.MaxBytesReader(ctx.Writer, ctx.Request.Body, usecaseinf.MaxArchiveSize)
+	reader, err := ctx.Request.MultipartReader()
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, response.ErrorResponse(http.StatusBadRequest, "Request must be multipart/form-data", nil))
+		return
+	}
+
+	var part *multipart.Part
+	for {
+		part, err = reader.NextPart()
 		if err != nil {
-			ctx.JSON(http.StatusInternalServerError, response.ErrorResponse(http.StatusInternalServerError, "Failed to upload file "+file.Filename, nil))
+			ctx.JSON(http.StatusBadRequest, response.ErrorResponse(http.StatusBadRequest, "No archive found in the request. Key 'archive' required.", nil))
 			return
 		}
+		if part.FormName() == "archive" {
+			break
+		}
+		part.Close()
+	}
+	defer part.Close()
 
-		uploadResponses = append(uploadResponses, dto.UploadResponse{
-			URL:      fileURL,
-			Filename: file.Filename,
-			Size:     file.Size,
-		})
+	if !isValidArchiveFile(part.FileName()) {
+		ctx.JSON(http.StatusBadRequest, response.ErrorResponse(http.StatusBadRequest, "Invalid file type. Only archive files (cbz, zip) are allowed", nil))
+		return
 	}
 
-	ctx.JSON(http.StatusOK, response.SuccessResponse(http.StatusOK, "Files uploaded successfully", uploadResponses))
+	// ZIP needs random access to its central directory, so spool to disk rather than memory
+	tmpFile, err := os.CreateTemp("", "hotaku-archive-*.zip")
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, response.ErrorResponse(http.StatusInternalServerError, "Failed to store uploaded archive", nil))
+		return
+	}
+	defer func() {
+		tmpFile.Close()
+		os.Remove(tmpFile.Name())
+	}()
+
+	size, err := io.Copy(tmpFile, part)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, response.ErrorResponse(http.StatusBadRequest, "Failed to read uploaded archive", err.Error()))
+		return
+	}
+
+	// Pages are stored as-is, so the archive size is a close estimate of the space they take
+	if !checkQuota(ctx, c.quotaUseCase, mangaID, size) {
+		return
+	}
+
+	result, err := c.chapterPageUseCase.ImportArchive(ctx.GetString("user_id"), mangaID, chapterID, tmpFile, size)
+	if err != nil {
+		status := uploadErrorStatus(err, http.StatusBadRequest)
+		ctx.JSON(status, response.ErrorResponse(status, "Failed to import archive", err.Error()))
+		return
+	}
+
+	if len(result.Imported) == 0 {
+		ctx.JSON(http.StatusBadRequest, response.ErrorResponse(http.StatusBadRequest, "No valid pages found in archive", result))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response.SuccessResponse(http.StatusOK, "Archive imported successfully", result))
+}
+
+// PresignUpload issues a presigned URL for uploading a file directly to storage
+func (c *UploadController) PresignUpload(ctx *gin.Context) {
+	userID := ctx.GetString("user_id")
+
+	var req request.PresignUploadRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, response.ErrorResponse(http.StatusBadRequest, "Invalid request data", err.Error()))
+		return
+	}
+
+	if !checkQuota(ctx, c.quotaUseCase, req.MangaID, req.Size) {
+		return
+	}
+
+	result, err := c.directUploadUseCase.Presign(userID, &req)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, response.ErrorResponse(http.StatusBadRequest, "Failed to create upload", err.Error()))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response.SuccessResponse(http.StatusOK, "Upload URL created successfully", result))
+}
+
+// FinalizeUpload verifies a direct upload and registers it as a cover or page
+func (c *UploadController) FinalizeUpload(ctx *gin.Context) {
+	userID := ctx.GetString("user_id")
+
+	uploadID := ctx.Param("upload_id")
+	if uploadID == "" {
+		ctx.JSON(http.StatusBadRequest, response.ErrorResponse(http.StatusBadRequest, "Upload ID is required", nil))
+		return
+	}
+
+	result, err := c.directUploadUseCase.Finalize(userID, uploadID)
+	if err != nil {
+		status := uploadErrorStatus(err, http.StatusBadRequest)
+		ctx.JSON(status, response.ErrorResponse(status, "Failed to finalize upload", err.Error()))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response.SuccessResponse(http.StatusOK, "Upload finalized successfully", result))
 }
 
 // ReplacePage handles replace specific page
@@ -153,11 +389,6 @@ func (c *UploadController) ReplacePage(ctx *gin.Context) {
 		return
 	}
 
-	if page > 999 {
-		ctx.JSON(http.StatusBadRequest, response.ErrorResponse(http.StatusBadRequest, "Page number exceeds maximum allowed value", nil))
-		return
-	}
-
 	// Get the uploaded file
 	file, err := ctx.FormFile("image")
 	if err != nil {
@@ -170,31 +401,62 @@ func (c *UploadController) ReplacePage(ctx *gin.Context) {
 		return
 	}
 
-	// Upload to MinIO
-	fileURL, err := c.minioService.UploadChapterPage(file, mangaID, chapterID, page)
+	if !checkQuota(ctx, c.quotaUseCase, mangaID, file.Size) {
+		return
+	}
+
+	result, err := c.chapterPageUseCase.ReplacePage(ctx.GetString("user_id"), mangaID, chapterID, page, file)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, response.ErrorResponse(http.StatusInternalServerError, "Failed to upload file", nil))
+		status := uploadErrorStatus(err, http.StatusBadRequest)
+		ctx.JSON(status, response.ErrorResponse(status, "Failed to replace page", err.Error()))
 		return
 	}
 
-	ctx.JSON(http.StatusOK, response.SuccessResponse(http.StatusOK, "File uploaded successfully", dto.UploadResponse{
-		URL:      fileURL,
-		Filename: file.Filename,
-		Size:     file.Size,
-	}))
+	ctx.JSON(http.StatusOK, response.SuccessResponse(http.StatusOK, "File uploaded successfully", result))
+}
+
+// ReorderPages renumbers the pages of a chapter in the given order
+func (c *UploadController) ReorderPages(ctx *gin.Context) {
+	mangaID := ctx.Param("manga_id")
+	chapterID := ctx.Param("chapter_id")
+
+	if mangaID == "" || chapterID == "" {
+		ctx.JSON(http.StatusBadRequest, response.ErrorResponse(http.StatusBadRequest, "Manga ID and Chapter ID are required", nil))
+		return
+	}
+
+	var req request.ReorderPagesRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, response.ErrorResponse(http.StatusBadRequest, "Invalid request data", err.Error()))
+		return
+	}
+
+	pages, err := c.chapterPageUseCase.ReorderPages(ctx.GetString("user_id"), mangaID, chapterID, req.PageIDs)
+	if err != nil {
+		status := http.StatusBadRequest
+		if errors.Is(err, usecaseinf.ErrPageForbidden) {
+			status = http.StatusForbidden
+		}
+		ctx.JSON(status, response.ErrorResponse(status, "Failed to reorder pages", err.Error()))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response.SuccessResponse(http.StatusOK, "Pages reordered successfully", pages))
 }
 
 // DeleteFile handles file deletion
 func (c *UploadController) DeleteFile(ctx *gin.Context) {
+	userID := ctx.GetString("user_id")
+
 	objectName := ctx.Param("object_name")
 	if objectName == "" {
 		ctx.JSON(http.StatusBadRequest, response.ErrorResponse(http.StatusBadRequest, "Object name is required", nil))
 		return
 	}
 
-	err := c.minioService.DeleteFile(objectName)
-	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, response.ErrorResponse(http.StatusInternalServerError, "Failed to delete file", nil))
+	if err := c.fileUseCase.Delete(userID, objectName); err != nil {
+		status := fileErrorStatus(err)
+		ctx.JSON(status, response.ErrorResponse(status, "Failed to delete file", err.Error()))
 		return
 	}
 
@@ -203,6 +465,8 @@ func (c *UploadController) DeleteFile(ctx *gin.Context) {
 
 // GetFileInfo gets file information
 func (c *UploadController) GetFileInfo(ctx *gin.Context) {
+	userID := ctx.GetString("user_id")
+
 	objectName := ctx.Param("object_name")
 	if objectName == "" {
 		ctx.JSON(http.StatusBadRequest, response.ErrorResponse(http.StatusBadRequest, "Object name is required", nil))
@@ -212,21 +476,14 @@ func (c *UploadController) GetFileInfo(ctx *gin.Context) {
 	// Remove /info suffix to get the actual object name
 	objectName = strings.TrimSuffix(objectName, "/info")
 
-	// Trim leading slash for wildcard parameter
-	if len(objectName) > 0 && objectName[0] == '/' {
-		objectName = objectName[1:]
-	}
-
-	size, err := c.minioService.GetFileSize(objectName)
+	info, err := c.fileUseCase.GetInfo(userID, objectName)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, response.ErrorResponse(http.StatusInternalServerError, "Failed to get file info", nil))
+		status := fileErrorStatus(err)
+		ctx.JSON(status, response.ErrorResponse(status, "Failed to get file info", err.Error()))
 		return
 	}
 
-	ctx.JSON(http.StatusOK, response.SuccessResponse(http.StatusOK, "File info retrieved successfully", dto.FileInfoResponse{
-		ObjectName: objectName,
-		Size:       size,
-	}))
+	ctx.JSON(http.StatusOK, response.SuccessResponse(http.StatusOK, "File info retrieved successfully", info))
 }
 
 // GetImage retrieves and serves an image file
@@ -237,12 +494,29 @@ func (c *UploadController) GetImage(ctx *gin.Context) {
 		return
 	}
 
+	// Only serve manga content, never staging uploads or paths escaping the namespace
+	objectName, err := c.minioService.NormalizeObjectName(objectName)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, response.ErrorResponse(http.StatusBadRequest, "Invalid object name", nil))
+		return
+	}
+
 	// Validate that the requested file is an image
 	if !isValidImageFile(objectName) {
 		ctx.JSON(http.StatusBadRequest, response.ErrorResponse(http.StatusBadRequest, "Invalid file type. Only image files (jpg, jpeg, png, gif, webp) are allowed", nil))
 		return
 	}
 
+	// Restricted mangas are only served through signed, expiring URLs
+	if err := c.imageUseCase.CheckAccess(objectName, ctx.Query("expires"), ctx.Query("signature")); err != nil {
+		status := fileErrorStatus(err)
+		if errors.Is(err, usecaseinf.ErrImageAccessDenied) {
+			status = http.StatusForbidden
+		}
+		ctx.JSON(status, response.ErrorResponse(status, "Failed to retrieve image", err.Error()))
+		return
+	}
+
 	// Get the object from MinIO
 	obj, err := c.minioService.GetObject(objectName)
 	if err != nil {
@@ -267,13 +541,32 @@ func (c *UploadController) GetImage(ctx *gin.Context) {
 	// Set appropriate headers
 	ctx.Header("Content-Type", contentType)
 	ctx.Header("Content-Length", fmt.Sprintf("%d", objInfo.Size))
-	ctx.Header("Cache-Control", "public, max-age=31536000") // Cache for 1 year
+	if expires, err := strconv.ParseInt(ctx.Query("expires"), 10, 64); err == nil && ctx.Query("signature") != "" {
+		// Shared caches must not keep serving a restricted image once its URL expires
+		ctx.Header("Cache-Control", fmt.Sprintf("private, max-age=%d", max(expires-time.Now().Unix(), 0)))
+	} else {
+		ctx.Header("Cache-Control", "public, max-age=31536000") // Cache for 1 year
+	}
 	ctx.Header("Access-Control-Allow-Origin", "*")
 
 	// Stream the file to the response
 	ctx.DataFromReader(http.StatusOK, objInfo.Size, contentType, obj, nil)
 }
 
+// fileErrorStatus maps file ownership errors to HTTP status codes
+func fileErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, usecaseinf.ErrInvalidObjectName):
+		return http.StatusBadRequest
+	case errors.Is(err, usecaseinf.ErrFileForbidden):
+		return http.StatusForbidden
+	case errors.Is(err, usecaseinf.ErrFileNotFound):
+		return http.StatusNotFound
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
 // isValidImageFile checks if the file is a valid image
 func isValidImageFile(filename string) bool {
 	validExtensions := map[string]bool{
@@ -288,6 +581,12 @@ func isValidImageFile(filename string) bool {
 	return validExtensions[ext]
 }
 
+// isValidArchiveFile checks if the file is a supported chapter archive
+func isValidArchiveFile(filename string) bool {
+	ext := strings.ToLower(filepath.Ext(filename))
+	return ext == ".cbz" || ext == ".zip"
+}
+
 // getImageContentType determines the MIME type based on file extension
 func getImageContentType(filename string) string {
 	ext := strings.ToLower(filepath.Ext(filename))
@@ -0,0 +1,123 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"hotaku-api/internal/domain/request"
+	"hotaku-api/internal/domain/response"
+	"hotaku-api/internal/usecaseinf"
+
+	"github.com/gin-gonic/gin"
+)
+
+// UploadSessionController handles resumable chunked uploads
+type UploadSessionController struct {
+	uploadSessionUseCase usecaseinf.UploadSessionUseCase
+	quotaUseCase         usecaseinf.QuotaUseCase
+}
+
+// NewUploadSessionController creates a new instance of UploadSessionController
+func NewUploadSessionController(uploadSessionUseCase usecaseinf.UploadSessionUseCase, quotaUseCase usecaseinf.QuotaUseCase) *UploadSessionController {
+	return &UploadSessionController{
+		uploadSessionUseCase: uploadSessionUseCase,
+		quotaUseCase:         quotaUseCase,
+	}
+}
+
+// CreateSession starts a resumable upload
+func (uc *UploadSessionController) CreateSession(c *gin.Context) {
+	userID := c.GetString("user_id")
+
+	var req request.CreateUploadSessionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, response.ErrorResponse(http.StatusBadRequest, "Invalid request data", err.Error()))
+		return
+	}
+
+	if !checkQuota(c, uc.quotaUseCase, req.MangaID, req.Size) {
+		return
+	}
+
+	body, err := uc.uploadSessionUseCase.Create(userID, &req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.ErrorResponse(http.StatusBadRequest, "Failed to create upload session", err.Error()))
+		return
+	}
+
+	c.Header("Upload-Offset", strconv.FormatInt(body.Offset, 10))
+	c.Header("Upload-Length", strconv.FormatInt(body.Size, 10))
+	c.JSON(http.StatusOK, response.SuccessResponse(http.StatusOK, "Upload session created successfully", body))
+}
+
+// GetSession returns the current offset of a resumable upload
+func (uc *UploadSessionController) GetSession(c *gin.Context) {
+	userID := c.GetString("user_id")
+
+	body, err := uc.uploadSessionUseCase.Get(userID, c.Param("session_id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, response.ErrorResponse(http.StatusNotFound, "Upload session not found", err.Error()))
+		return
+	}
+
+	c.Header("Upload-Offset", strconv.FormatInt(body.Offset, 10))
+	c.Header("Upload-Length", strconv.FormatInt(body.Size, 10))
+	c.Header("Cache-Control", "no-store")
+	c.JSON(http.StatusOK, response.SuccessResponse(http.StatusOK, "Upload session retrieved successfully", body))
+}
+
+// UploadChunk appends a chunk to a resumable upload at the offset given by the Upload-Offset header
+func (uc *UploadSessionController) UploadChunk(c *gin.Context) {
+	userID := c.GetString("user_id")
+
+	offset, err := strconv.ParseInt(c.GetHeader("Upload-Offset"), 10, 64)
+	if err != nil || offset < 0 {
+		c.JSON(http.StatusBadRequest, response.ErrorResponse(http.StatusBadRequest, "Upload-Offset header must be a non-negative integer", nil))
+		return
+	}
+
+	if c.Request.ContentLength <= 0 {
+		c.JSON(http.StatusLengthRequired, response.ErrorResponse(http.StatusLengthRequired, "Content-Length header is required", nil))
+		return
+	}
+
+	body, err := uc.uploadSessionUseCase.AppendChunk(userID, c.Param("session_id"), offset, c.Request.Body, c.Request.ContentLength)
+	if err != nil {
+		if errors.Is(err, usecaseinf.ErrUploadOffsetMismatch) {
+			c.JSON(http.StatusConflict, response.ErrorResponse(http.StatusConflict, "Upload offset does not match, fetch the session to resume", err.Error()))
+			return
+		}
+		c.JSON(http.StatusBadRequest, response.ErrorResponse(http.StatusBadRequest, "Failed to upload chunk", err.Error()))
+		return
+	}
+
+	c.Header("Upload-Offset", strconv.FormatInt(body.Offset, 10))
+	c.JSON(http.StatusOK, response.SuccessResponse(http.StatusOK, "Chunk uploaded successfully", body))
+}
+
+// FinalizeSession assembles a completed upload and registers its pages
+func (uc *UploadSessionController) FinalizeSession(c *gin.Context) {
+	userID := c.GetString("user_id")
+
+	body, err := uc.uploadSessionUseCase.Finalize(userID, c.Param("session_id"))
+	if err != nil {
+		status := uploadErrorStatus(err, http.StatusBadRequest)
+		c.JSON(status, response.ErrorResponse(status, "Failed to finalize upload session", err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, response.SuccessResponse(http.StatusOK, "Upload session finalized successfully", body))
+}
+
+// CancelSession aborts a resumable upload
+func (uc *UploadSessionController) CancelSession(c *gin.Context) {
+	userID := c.GetString("user_id")
+
+	if err := uc.uploadSessionUseCase.Cancel(userID, c.Param("session_id")); err != nil {
+		c.JSON(http.StatusBadRequest, response.ErrorResponse(http.StatusBadRequest, "Failed to cancel upload session", err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, response.SuccessResponse(http.StatusOK, "Upload session cancelled successfully", nil))
+}
@@ -0,0 +1,171 @@
+package controllers
+
+import (
+	"errors"
+	"log"
+	"mime"
+	"net/http"
+
+	"hotaku-api/internal/domain/request"
+	"hotaku-api/internal/domain/response"
+	"hotaku-api/internal/usecaseinf"
+
+	"github.com/gin-gonic/gin"
+)
+
+// VolumeController handles manga volume-related HTTP requests
+type VolumeController struct {
+	volumeUseCase  usecaseinf.VolumeUseCase
+	chapterUseCase usecaseinf.ChapterUseCase
+}
+
+// NewVolumeController creates a new instance of VolumeController
+func NewVolumeController(volumeUseCase usecaseinf.VolumeUseCase, chapterUseCase usecaseinf.ChapterUseCase) *VolumeController {
+	return &VolumeController{
+		volumeUseCase:  volumeUseCase,
+		chapterUseCase: chapterUseCase,
+	}
+}
+
+// ListVolumes returns all volumes of a manga
+func (vc *VolumeController) ListVolumes(c *gin.Context) {
+	volumes, err := vc.volumeUseCase.ListVolumes(c.GetString("user_id"), c.Param("id"))
+	if err != nil {
+		status := volumeErrorStatus(err)
+		c.JSON(status, response.ErrorResponse(status, "Failed to list volumes", err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, response.SuccessResponse(http.StatusOK, "Volumes retrieved successfully", volumes))
+}
+
+// CreateVolume adds a volume to a manga
+func (vc *VolumeController) CreateVolume(c *gin.Context) {
+	userID := c.GetString("user_id")
+
+	var req request.CreateVolumeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, response.ErrorResponse(http.StatusBadRequest, "Invalid request data", err.Error()))
+		return
+	}
+
+	volume, err := vc.volumeUseCase.CreateVolume(userID, c.Param("id"), &req)
+	if err != nil {
+		status := volumeErrorStatus(err)
+		c.JSON(status, response.ErrorResponse(status, "Failed to create volume", err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, response.SuccessResponse(http.StatusOK, "Volume created successfully", volume))
+}
+
+// UpdateVolume changes the number, title or cover of a volume
+func (vc *VolumeController) UpdateVolume(c *gin.Context) {
+	userID := c.GetString("user_id")
+
+	var req request.UpdateVolumeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, response.ErrorResponse(http.StatusBadRequest, "Invalid request data", err.Error()))
+		return
+	}
+
+	volume, err := vc.volumeUseCase.UpdateVolume(userID, c.Param("id"), c.Param("volume_id"), &req)
+	if err != nil {
+		status := volumeErrorStatus(err)
+		c.JSON(status, response.ErrorResponse(status, "Failed to update volume", err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, response.SuccessResponse(http.StatusOK, "Volume updated successfully", volume))
+}
+
+// DeleteVolume removes a volume, leaving its chapters without a volume
+func (vc *VolumeController) DeleteVolume(c *gin.Context) {
+	userID := c.GetString("user_id")
+
+	if err := vc.volumeUseCase.DeleteVolume(userID, c.Param("id"), c.Param("volume_id")); err != nil {
+		status := volumeErrorStatus(err)
+		c.JSON(status, response.ErrorResponse(status, "Failed to delete volume", err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, response.SuccessResponse(http.StatusOK, "Volume deleted successfully", nil))
+}
+
+// SetChapters replaces the chapters of a volume
+func (vc *VolumeController) SetChapters(c *gin.Context) {
+	userID := c.GetString("user_id")
+
+	var req request.SetVolumeChaptersRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, response.ErrorResponse(http.StatusBadRequest, "Invalid request data", err.Error()))
+		return
+	}
+
+	volume, err := vc.volumeUseCase.SetChapters(userID, c.Param("id"), c.Param("volume_id"), &req)
+	if err != nil {
+		status := volumeErrorStatus(err)
+		c.JSON(status, response.ErrorResponse(status, "Failed to update volume chapters", err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, response.SuccessResponse(http.StatusOK, "Volume chapters updated successfully", volume))
+}
+
+// GetProgress summarizes how much of each volume of a manga the current user read
+func (vc *VolumeController) GetProgress(c *gin.Context) {
+	userID := c.GetString("user_id")
+
+	progress, err := vc.volumeUseCase.GetProgress(userID, c.Param("id"))
+	if err != nil {
+		status := volumeErrorStatus(err)
+		c.JSON(status, response.ErrorResponse(status, "Failed to retrieve reading progress", err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, response.SuccessResponse(http.StatusOK, "Reading progress retrieved successfully", progress))
+}
+
+// DownloadVolume streams a volume as a CBZ archive, in the languages the request asks for
+func (vc *VolumeController) DownloadVolume(c *gin.Context) {
+	volumeID := c.Param("id")
+
+	download, err := vc.chapterUseCase.PrepareVolumeDownload(c.GetString("user_id"), volumeID, preferredLanguages(c))
+	if err != nil {
+		status := volumeErrorStatus(err)
+		if status == http.StatusServiceUnavailable {
+			c.Header("Retry-After", "60")
+		}
+		c.JSON(status, response.ErrorResponse(status, "Volume not available for download", err.Error()))
+		return
+	}
+
+	c.Header("Content-Type", "application/vnd.comicbook+zip")
+	c.Header("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": download.Filename}))
+	c.Status(http.StatusOK)
+
+	// Headers are already sent, so a failure can only cut the stream short
+	if err := vc.chapterUseCase.WriteArchive(download, c.Writer); err != nil {
+		log.Printf("Failed to stream volume %s: %v", volumeID, err)
+		c.Abort()
+	}
+}
+
+// volumeErrorStatus maps volume errors to HTTP status codes
+func volumeErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, usecaseinf.ErrModeratorOnly), errors.Is(err, usecaseinf.ErrMangaRestricted):
+		return http.StatusForbidden
+	case errors.Is(err, usecaseinf.ErrMangaNotFound), errors.Is(err, usecaseinf.ErrVolumeNotFound), errors.Is(err, usecaseinf.ErrVolumeEmpty):
+		return http.StatusNotFound
+	case errors.Is(err, usecaseinf.ErrVolumeExists):
+		return http.StatusConflict
+	case errors.Is(err, usecaseinf.ErrCoverNotFound), errors.Is(err, usecaseinf.ErrInvalidVolumeChapters):
+		return http.StatusBadRequest
+	case errors.Is(err, usecaseinf.ErrWatermarkPending):
+		// Watermarked chapters are never served without their watermark
+		return http.StatusServiceUnavailable
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -0,0 +1,66 @@
+package dto
+
+import (
+	"encoding/xml"
+	"time"
+)
+
+// ComicInfo represents the ComicInfo.xml metadata embedded in CBZ archives, its fields in schema order
+type ComicInfo struct {
+	XMLName     xml.Name `xml:"ComicInfo"`
+	Title       string   `xml:"Title,omitempty"`
+	Series      string   `xml:"Series"`
+	Number      string   `xml:"Number,omitempty"`
+	Volume      *int     `xml:"Volume,omitempty"`
+	Summary     string   `xml:"Summary,omitempty"`
+	Writer      string   `xml:"Writer,omitempty"`
+	Translator  string   `xml:"Translator,omitempty"`
+	PageCount   int      `xml:"PageCount"`
+	LanguageISO string   `xml:"LanguageISO,omitempty"`
+	Manga       string   `xml:"Manga"`
+}
+
+// ChapterDownload describes the contents of a chapter archive before it is streamed
+type ChapterDownload struct {
+	Filename string
+	Objects  []string
+	// Names are the archive entry names of the objects, pages being named by position when empty
+	Names     []string
+	ComicInfo ComicInfo
+}
+
+// ManifestPage represents a page of a chapter manifest
+type ManifestPage struct {
+	PageNumber int    `json:"page_number"`
+	URL        string `json:"url"`
+}
+
+// ChapterManifestResponse lists the pages of a chapter in reading order
+type ChapterManifestResponse struct {
+	ChapterID string         `json:"chapter_id"`
+	MangaID   string         `json:"manga_id"`
+	Pages     []ManifestPage `json:"pages"`
+}
+
+// ChapterResponse represents a version of a chapter in API responses
+type ChapterResponse struct {
+	ChapterID     string    `json:"chapter_id"`
+	MangaID       string    `json:"manga_id"`
+	ChapterNumber float64   `json:"chapter_number"`
+	Title         *string   `json:"title"`
+	Language      string    `json:"language"`
+	GroupID       *string   `json:"group_id"`
+	GroupName     *string   `json:"group_name"`
+	VolumeID      *string   `json:"volume_id"`
+	VolumeNumber  *int      `json:"volume_number"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+// ChapterListResponse represents a page of a manga's chapters
+type ChapterListResponse struct {
+	Items []ChapterResponse `json:"items"`
+	Total int64             `json:"total"`
+	Page  int               `json:"page"`
+	Limit int               `json:"limit"`
+}
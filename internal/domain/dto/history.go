@@ -0,0 +1,36 @@
+package dto
+
+import "time"
+
+// HistoryEntryResponse represents a chapter in a user's reading history
+type HistoryEntryResponse struct {
+	EntryID       string    `json:"entry_id"`
+	MangaID       string    `json:"manga_id"`
+	MangaTitle    string    `json:"manga_title"`
+	ChapterID     string    `json:"chapter_id"`
+	ChapterNumber float64   `json:"chapter_number"`
+	ChapterTitle  *string   `json:"chapter_title"`
+	Language      string    `json:"language"`
+	PageNumber    *int      `json:"page_number"`
+	OpenedAt      time.Time `json:"opened_at"`
+}
+
+// HistoryResponse represents a page of a user's reading history
+type HistoryResponse struct {
+	// Paused tells whether the chapters the user opens are left out of the history
+	Paused bool                   `json:"paused"`
+	Items  []HistoryEntryResponse `json:"items"`
+	Total  int64                  `json:"total"`
+	Page   int                    `json:"page"`
+	Limit  int                    `json:"limit"`
+}
+
+// HistoryPauseResponse represents whether a user's reading history is paused
+type HistoryPauseResponse struct {
+	Paused bool `json:"paused"`
+}
+
+// HistoryClearResponse represents how many entries clearing a reading history deleted
+type HistoryClearResponse struct {
+	Deleted int64 `json:"deleted"`
+}
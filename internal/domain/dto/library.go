@@ -0,0 +1,45 @@
+package dto
+
+import "time"
+
+// LibraryShelfResponse represents a shelf of a user's library in API responses
+type LibraryShelfResponse struct {
+	ShelfID    string    `json:"shelf_id"`
+	Name       string    `json:"name"`
+	Kind       string    `json:"kind"`
+	MangaCount int64     `json:"manga_count"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// FavoriteResponse represents a manga in a user's library in API responses
+type FavoriteResponse struct {
+	FavoriteID string    `json:"favorite_id"`
+	MangaID    string    `json:"manga_id"`
+	ShelfID    *string   `json:"shelf_id"`
+	AddedAt    time.Time `json:"added_at"`
+}
+
+// LibraryItemResponse represents a listed favorite along with its manga's chapter counts. Versions of a chapter in
+// several languages or by several groups count once
+type LibraryItemResponse struct {
+	FavoriteResponse
+	Manga           MangaResponse `json:"manga"`
+	ChapterCount    int64         `json:"chapter_count"`
+	UnreadCount     int64         `json:"unread_count"`
+	LatestChapterAt *time.Time    `json:"latest_chapter_at"`
+}
+
+// LibraryResponse represents a page of a user's library
+type LibraryResponse struct {
+	UserID string                `json:"user_id"`
+	Public bool                  `json:"public"`
+	Items  []LibraryItemResponse `json:"items"`
+	Total  int64                 `json:"total"`
+	Page   int                   `json:"page"`
+	Limit  int                   `json:"limit"`
+}
+
+// LibraryVisibilityResponse represents whether a user's library is shared
+type LibraryVisibilityResponse struct {
+	Public bool `json:"public"`
+}
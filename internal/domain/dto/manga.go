@@ -0,0 +1,67 @@
+package dto
+
+import "time"
+
+// MangaResponse represents a manga in API responses
+type MangaResponse struct {
+	MangaID string `json:"manga_id"`
+	// Title and Description are the ones best matching the languages of the request, their locale being nil
+	// for the default ones
+	Title             string    `json:"title"`
+	TitleLocale       *string   `json:"title_locale"`
+	Description       *string   `json:"description"`
+	DescriptionLocale *string   `json:"description_locale"`
+	Year              *int      `json:"year"`
+	StatusID          uint      `json:"status_id"`
+	Visibility        string    `json:"visibility"`
+	Authors           []string  `json:"authors"`
+	CoverURL          *string   `json:"cover_url"`
+	CreatedAt         time.Time `json:"created_at"`
+	UpdatedAt         time.Time `json:"updated_at"`
+	// AltTitles lists every localized title, only when a single manga is retrieved
+	AltTitles []MangaTitleResponse `json:"alt_titles,omitempty"`
+}
+
+// MangaTitleResponse represents a localized title of a manga in API responses
+type MangaTitleResponse struct {
+	TitleID   string `json:"title_id"`
+	Locale    string `json:"locale"`
+	Title     string `json:"title"`
+	IsPrimary bool   `json:"is_primary"`
+}
+
+// MangaDescriptionResponse represents a localized description of a manga in API responses
+type MangaDescriptionResponse struct {
+	Locale      string    `json:"locale"`
+	Description string    `json:"description"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// MangaCoverResponse represents a manga cover in API responses
+type MangaCoverResponse struct {
+	CoverID    string    `json:"cover_id"`
+	URL        string    `json:"url"`
+	Volume     *int      `json:"volume"`
+	Locale     *string   `json:"locale"`
+	UploadedBy *string   `json:"uploaded_by"`
+	IsPrimary  bool      `json:"is_primary"`
+	CreatedAt  time.Time `json:"created_at"`
+	// Size and OriginalSize are only reported when the cover was just uploaded
+	Size         int64 `json:"size,omitempty"`
+	OriginalSize int64 `json:"original_size,omitempty"`
+}
+
+// MangaListItemResponse represents a listed manga along with its chapter count and latest chapter
+type MangaListItemResponse struct {
+	MangaResponse
+	ChapterCount    int64      `json:"chapter_count"`
+	LatestChapterAt *time.Time `json:"latest_chapter_at"`
+}
+
+// MangaListResponse represents a page of listed mangas
+type MangaListResponse struct {
+	Items []MangaListItemResponse `json:"items"`
+	Total int64                   `json:"total"`
+	Page  int                     `json:"page"`
+	Limit int                     `json:"limit"`
+}
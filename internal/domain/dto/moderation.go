@@ -0,0 +1,32 @@
+package dto
+
+import "time"
+
+// DuplicatePageResponse represents one side of a duplicate page flag
+type DuplicatePageResponse struct {
+	PageID     string `json:"page_id"`
+	ChapterID  string `json:"chapter_id"`
+	PageNumber int    `json:"page_number"`
+	URL        string `json:"url"`
+}
+
+// DuplicateFlagResponse represents a pair of pages flagged as possible duplicates
+type DuplicateFlagResponse struct {
+	FlagID        string                 `json:"flag_id"`
+	MangaID       string                 `json:"manga_id"`
+	Page          *DuplicatePageResponse `json:"page"`
+	DuplicatePage *DuplicatePageResponse `json:"duplicate_page"`
+	Distance      int                    `json:"distance"`
+	Status        string                 `json:"status"`
+	ReviewedBy    *string                `json:"reviewed_by,omitempty"`
+	ReviewedAt    *time.Time             `json:"reviewed_at,omitempty"`
+	CreatedAt     time.Time              `json:"created_at"`
+}
+
+// DuplicateFlagListResponse represents a page of duplicate page flags
+type DuplicateFlagListResponse struct {
+	Items []DuplicateFlagResponse `json:"items"`
+	Total int64                   `json:"total"`
+	Page  int                     `json:"page"`
+	Limit int                     `json:"limit"`
+}
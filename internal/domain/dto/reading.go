@@ -0,0 +1,38 @@
+package dto
+
+import "time"
+
+// ContinueReadingChapter represents where a user picks a manga back up
+type ContinueReadingChapter struct {
+	ChapterID     string  `json:"chapter_id"`
+	ChapterNumber float64 `json:"chapter_number"`
+	Title         *string `json:"title"`
+	Language      string  `json:"language"`
+	PageNumber    int     `json:"page_number"`
+}
+
+// MangaProgressResponse summarizes how much of a manga a user read. Versions of a chapter in several languages or by
+// several groups count once, read when any of them is
+type MangaProgressResponse struct {
+	MangaID      string     `json:"manga_id"`
+	ChapterCount int        `json:"chapter_count"`
+	ReadCount    int        `json:"read_count"`
+	Completed    bool       `json:"completed"`
+	LastReadAt   *time.Time `json:"last_read_at"`
+	// Continue is where to pick the manga back up, nil once every chapter is read
+	Continue *ContinueReadingChapter `json:"continue"`
+}
+
+// ContinueReadingItem represents a manga of the continue reading feed along with the user's progress
+type ContinueReadingItem struct {
+	Manga    MangaResponse         `json:"manga"`
+	Progress MangaProgressResponse `json:"progress"`
+}
+
+// ContinueReadingResponse represents a page of the continue reading feed
+type ContinueReadingResponse struct {
+	Items []ContinueReadingItem `json:"items"`
+	Total int64                 `json:"total"`
+	Page  int                   `json:"page"`
+	Limit int                   `json:"limit"`
+}
@@ -0,0 +1,50 @@
+package dto
+
+import "time"
+
+// MangaSearchHighlights holds the matched fields of a search hit, HTML escaped with the matching words in <mark> tags
+type MangaSearchHighlights struct {
+	Title string `json:"title"`
+	// AltTitle is the other title of the manga that matched, left out when the shown title matches
+	AltTitle *string `json:"alt_title,omitempty"`
+	// Description is an excerpt around the first match, left out when the description doesn't match
+	Description *string `json:"description,omitempty"`
+}
+
+// MangaSearchHitResponse represents a manga matching a search
+type MangaSearchHitResponse struct {
+	MangaResponse
+	Score      float64               `json:"score"`
+	Highlights MangaSearchHighlights `json:"highlights"`
+}
+
+// MangaSearchResponse represents a page of manga search results
+type MangaSearchResponse struct {
+	Items  []MangaSearchHitResponse  `json:"items"`
+	Total  int64                     `json:"total"`
+	Page   int                       `json:"page"`
+	Limit  int                       `json:"limit"`
+	Facets MangaSearchFacetsResponse `json:"facets"`
+}
+
+// SearchFacetResponse represents a facet value along with how many matching mangas have it
+type SearchFacetResponse struct {
+	Value string `json:"value"`
+	Name  string `json:"name"`
+	Count int64  `json:"count"`
+}
+
+// MangaSearchFacetsResponse represents the most frequent statuses, categories, authors and groups of the matching mangas
+type MangaSearchFacetsResponse struct {
+	Statuses   []SearchFacetResponse `json:"statuses"`
+	Categories []SearchFacetResponse `json:"categories"`
+	Authors    []SearchFacetResponse `json:"authors"`
+	Groups     []SearchFacetResponse `json:"groups"`
+}
+
+// SearchIndexResponse represents the content of the embedded search index
+type SearchIndexResponse struct {
+	Documents int       `json:"documents"`
+	Terms     int       `json:"terms"`
+	SyncedAt  time.Time `json:"synced_at"`
+}
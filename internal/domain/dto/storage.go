@@ -0,0 +1,40 @@
+package dto
+
+import "time"
+
+// OrphanObject represents a stored object no longer referenced by any page or cover
+type OrphanObject struct {
+	ObjectName   string    `json:"object_name"`
+	Size         int64     `json:"size"`
+	LastModified time.Time `json:"last_modified"`
+}
+
+// GarbageCollectionReport summarizes a storage garbage collection run
+type GarbageCollectionReport struct {
+	DryRun     bool                `json:"dry_run"`
+	Scanned    int                 `json:"scanned"`
+	Orphans    []OrphanObject      `json:"orphans"`
+	Deleted    int                 `json:"deleted"`
+	FreedBytes int64               `json:"freed_bytes"`
+	Failed     []FileErrorResponse `json:"failed"`
+}
+
+// StorageUsage represents how much storage an uploader uses against its quota
+type StorageUsage struct {
+	UsedBytes  int64 `json:"used_bytes"`
+	QuotaBytes int64 `json:"quota_bytes"`
+	Objects    int64 `json:"objects"`
+}
+
+// GroupStorageUsage represents the storage usage of a scanlation group
+type GroupStorageUsage struct {
+	GroupID   string `json:"group_id"`
+	GroupName string `json:"group_name"`
+	StorageUsage
+}
+
+// StorageUsageResponse represents the storage usage of a user and their groups
+type StorageUsageResponse struct {
+	User   StorageUsage        `json:"user"`
+	Groups []GroupStorageUsage `json:"groups"`
+}
@@ -0,0 +1,11 @@
+package dto
+
+// SuggestionResponse represents a manga, author, group or category suggested while typing a search
+type SuggestionResponse struct {
+	Type string `json:"type"`
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	// MangaCount is how many mangas an author, group or category has, left out for mangas
+	MangaCount int   `json:"manga_count,omitempty"`
+	Popularity int64 `json:"popularity"`
+}
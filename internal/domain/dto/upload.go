@@ -1,14 +1,108 @@
 package dto
 
+import "time"
+
 // UploadResponse represents the response for file uploads
 type UploadResponse struct {
 	URL      string `json:"url"`
 	Filename string `json:"filename"`
-	Size     int64  `json:"size"`
+	// Size is the stored size after metadata stripping and normalization, OriginalSize the uploaded one
+	Size         int64 `json:"size"`
+	OriginalSize int64 `json:"original_size"`
 }
 
 // FileInfoResponse represents file information
 type FileInfoResponse struct {
-	ObjectName string `json:"object_name"`
-	Size       int64  `json:"size"`
+	ObjectName  string  `json:"object_name"`
+	Size        int64   `json:"size"`
+	ContentType string  `json:"content_type,omitempty"`
+	MangaID     string  `json:"manga_id,omitempty"`
+	UploadedBy  *string `json:"uploaded_by,omitempty"`
+	GroupID     *string `json:"group_id,omitempty"`
+}
+
+// PageUploadResponse represents an uploaded chapter page
+type PageUploadResponse struct {
+	PageID     string `json:"page_id"`
+	PageNumber int    `json:"page_number"`
+	URL        string `json:"url"`
+	Filename   string `json:"filename"`
+	// Size is the stored size after metadata stripping and normalization, OriginalSize the uploaded one
+	Size         int64 `json:"size"`
+	OriginalSize int64 `json:"original_size"`
+	// Deduplicated is set when the image matched one already stored for the manga and was not stored again
+	Deduplicated bool `json:"deduplicated"`
+}
+
+// ChapterPageResponse represents a page of a chapter
+type ChapterPageResponse struct {
+	PageID     string `json:"page_id"`
+	PageNumber int    `json:"page_number"`
+	URL        string `json:"url"`
+}
+
+// FileErrorResponse represents a file that could not be processed
+type FileErrorResponse struct {
+	Filename string `json:"filename"`
+	Error    string `json:"error"`
+}
+
+// ArchiveImportResponse represents the result of importing a chapter archive
+type ArchiveImportResponse struct {
+	Imported []PageUploadResponse `json:"imported"`
+	Failed   []FileErrorResponse  `json:"failed"`
+	Skipped  []string             `json:"skipped"`
+}
+
+// PageUploadStatus represents the outcome of one page of a multi-page upload, in upload order
+type PageUploadStatus struct {
+	Index    int                 `json:"index"`
+	Filename string              `json:"filename"`
+	Status   string              `json:"status"`
+	Error    string              `json:"error,omitempty"`
+	Page     *PageUploadResponse `json:"page,omitempty"`
+}
+
+// PageBatchUploadResponse represents the result of a multi-page upload
+type PageBatchUploadResponse struct {
+	Pages    []PageUploadStatus `json:"pages"`
+	Uploaded int                `json:"uploaded"`
+	Failed   int                `json:"failed"`
+}
+
+// PresignedUploadResponse represents a presigned direct-to-storage upload
+type PresignedUploadResponse struct {
+	UploadID  string `json:"upload_id"`
+	UploadURL string `json:"upload_url"`
+	Method    string `json:"method"`
+	// Fields are the form fields to send, in a multipart/form-data body, before the file field
+	Fields    map[string]string `json:"fields"`
+	ExpiresAt time.Time         `json:"expires_at"`
+}
+
+// FinalizeUploadResponse represents a finalized direct-to-storage upload
+type FinalizeUploadResponse struct {
+	Purpose string              `json:"purpose"`
+	Cover   *MangaCoverResponse `json:"cover,omitempty"`
+	Page    *PageUploadResponse `json:"page,omitempty"`
+}
+
+// UploadSessionResponse represents the state of a resumable upload
+type UploadSessionResponse struct {
+	SessionID    string     `json:"session_id"`
+	Kind         string     `json:"kind"`
+	Filename     string     `json:"filename"`
+	Size         int64      `json:"size"`
+	Offset       int64      `json:"offset"`
+	MinChunkSize int64      `json:"min_chunk_size"`
+	MaxChunkSize int64      `json:"max_chunk_size"`
+	ExpiresAt    time.Time  `json:"expires_at"`
+	CompletedAt  *time.Time `json:"completed_at,omitempty"`
+}
+
+// FinalizeSessionResponse represents a finalized resumable upload
+type FinalizeSessionResponse struct {
+	Kind    string                 `json:"kind"`
+	Page    *PageUploadResponse    `json:"page,omitempty"`
+	Archive *ArchiveImportResponse `json:"archive,omitempty"`
 }
@@ -0,0 +1,31 @@
+package dto
+
+import "time"
+
+// MangaVolumeResponse represents a volume of a manga in API responses
+type MangaVolumeResponse struct {
+	VolumeID     string  `json:"volume_id"`
+	MangaID      string  `json:"manga_id"`
+	VolumeNumber int     `json:"volume_number"`
+	Title        *string `json:"title"`
+	CoverID      *string `json:"cover_id"`
+	// CoverURL is the volume's cover, or else the cover uploaded for its number, nil when there is none
+	CoverURL     *string   `json:"cover_url"`
+	ChapterCount int       `json:"chapter_count"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+// VolumeProgressResponse summarizes how much of a volume a user read. Versions of a chapter in several languages or
+// by several groups count once, read when any of them is
+type VolumeProgressResponse struct {
+	// VolumeID and VolumeNumber are nil for the chapters not in any volume
+	VolumeID     *string    `json:"volume_id"`
+	VolumeNumber *int       `json:"volume_number"`
+	ChapterCount int        `json:"chapter_count"`
+	ReadCount    int        `json:"read_count"`
+	Completed    bool       `json:"completed"`
+	LastReadAt   *time.Time `json:"last_read_at"`
+	// NextChapterID is the first chapter of the volume the user hasn't read yet, nil once it is completed
+	NextChapterID *string `json:"next_chapter_id"`
+}
@@ -0,0 +1,22 @@
+package dto
+
+import "time"
+
+// GroupWatermarkResponse represents the watermark of a group
+type GroupWatermarkResponse struct {
+	GroupID    string    `json:"group_id"`
+	Kind       string    `json:"kind"`
+	Text       *string   `json:"text,omitempty"`
+	HasOverlay bool      `json:"has_overlay"`
+	Position   string    `json:"position"`
+	Opacity    int       `json:"opacity"`
+	Scale      int       `json:"scale"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+// ChapterWatermarkResponse represents the watermark state of a chapter
+type ChapterWatermarkResponse struct {
+	ChapterID        string  `json:"chapter_id"`
+	WatermarkGroupID *string `json:"watermark_group_id"`
+	Pages            int     `json:"pages"`
+}
@@ -0,0 +1,13 @@
+package entities
+
+import "time"
+
+// Author represents the author entity in the domain layer
+type Author struct {
+	AuthorID   string    `json:"author_id" gorm:"type:char(36);primaryKey"`
+	ExternalID string    `json:"external_id" gorm:"type:char(36);unique;not null"`
+	AuthorName string    `json:"author_name" gorm:"unique;not null"`
+	AuthorBio  *string   `json:"author_bio"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
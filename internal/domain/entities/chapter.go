@@ -0,0 +1,29 @@
+package entities
+
+import "time"
+
+// ChapterLanguageUnknown is the language of chapters nobody attributed a language to yet
+const ChapterLanguageUnknown = "und"
+
+// MangaChapter represents the manga chapter entity in the domain layer
+type MangaChapter struct {
+	ChapterID     string  `json:"chapter_id" gorm:"type:char(36);primaryKey"`
+	ExternalID    string  `json:"external_id" gorm:"type:char(36);unique;not null"`
+	MangaID       string  `json:"manga_id" gorm:"type:char(36);not null"`
+	ChapterNumber float64 `json:"chapter_number" gorm:"type:decimal(6,3);not null"`
+	Title         *string `json:"title"`
+	// Language is the BCP 47 tag of the language the chapter is in, "und" when unknown
+	Language string `json:"language" gorm:"type:varchar(35);not null;default:und"`
+	// GroupID is the scanlation group credited for this version of the chapter
+	GroupID *string `json:"group_id" gorm:"type:char(36)"`
+	// VolumeID is the volume the chapter is grouped in, nil when it isn't in any volume yet
+	VolumeID *string `json:"volume_id" gorm:"type:char(36)"`
+	// WatermarkGroupID is the group whose watermark is drawn on the pages, nil when unwatermarked
+	WatermarkGroupID *string   `json:"watermark_group_id" gorm:"type:char(36)"`
+	CreatedAt        time.Time `json:"created_at"`
+	UpdatedAt        time.Time `json:"updated_at"`
+
+	// Relationships
+	Group  *Group       `json:"group,omitempty" gorm:"foreignKey:GroupID;references:GroupID"`
+	Volume *MangaVolume `json:"volume,omitempty" gorm:"foreignKey:VolumeID;references:VolumeID"`
+}
@@ -0,0 +1,27 @@
+package entities
+
+// ChapterListQuery describes a listing of the chapters of a manga along with the filters narrowing it. Languages are
+// given by their base language subtag, such as "en" for "en-US", and match every region and script of it
+type ChapterListQuery struct {
+	MangaID   string
+	Languages []string
+	GroupIDs  []string
+	// VolumeID keeps the chapters of a volume, and NoVolume the chapters not in any volume
+	VolumeID string
+	NoVolume bool
+	// BestVersions keeps a single version of each chapter number: the one in the earliest of RankLanguages, then
+	// in the earliest group of GroupIDs, then the latest uploaded
+	BestVersions  bool
+	RankLanguages []string
+	// ByVolume orders the chapters by volume number first
+	ByVolume   bool
+	Descending bool
+	Offset     int
+	Limit      int
+}
+
+// ChapterListResult is a page of listed chapters along with the total count of chapters matching the filters
+type ChapterListResult struct {
+	Items []MangaChapter
+	Total int64
+}
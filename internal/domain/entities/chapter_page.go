@@ -0,0 +1,14 @@
+package entities
+
+// ChapterPage represents a single page image of a manga chapter
+type ChapterPage struct {
+	PageID         string  `json:"page_id" gorm:"type:char(36);primaryKey"`
+	ExternalID     string  `json:"external_id" gorm:"type:char(36);unique;not null"`
+	ChapterID      string  `json:"chapter_id" gorm:"type:char(36);not null"`
+	PageNumber     int     `json:"page_number" gorm:"not null"`
+	ImageURL       string  `json:"image_url" gorm:"type:varchar(2048);not null"`
+	ContentSHA256  *string `json:"content_sha256" gorm:"column:content_sha256;type:char(64)"`
+	PerceptualHash *uint64 `json:"perceptual_hash" gorm:"column:perceptual_hash"`
+	// UploadedBy is who added the page, which may differ from who uploaded a deduplicated image
+	UploadedBy *string `json:"uploaded_by" gorm:"type:char(36)"`
+}
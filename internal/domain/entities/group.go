@@ -0,0 +1,12 @@
+package entities
+
+import "time"
+
+// Group represents a scanlation group in the domain layer
+type Group struct {
+	GroupID    string    `json:"group_id" gorm:"type:char(36);primaryKey"`
+	ExternalID string    `json:"external_id" gorm:"type:char(36);unique;not null"`
+	GroupName  string    `json:"group_name" gorm:"unique;not null"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
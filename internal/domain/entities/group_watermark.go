@@ -0,0 +1,34 @@
+package entities
+
+import "time"
+
+const (
+	// WatermarkKindText draws the watermark text with the built-in font
+	WatermarkKindText = "text"
+	// WatermarkKindImage draws an uploaded PNG overlay
+	WatermarkKindImage = "image"
+)
+
+// Positions a watermark can be anchored to on a page
+const (
+	WatermarkPositionTopLeft     = "top-left"
+	WatermarkPositionTopRight    = "top-right"
+	WatermarkPositionBottomLeft  = "bottom-left"
+	WatermarkPositionBottomRight = "bottom-right"
+	WatermarkPositionCenter      = "center"
+)
+
+// GroupWatermark represents the watermark a group draws on the pages of its early-access chapters
+type GroupWatermark struct {
+	GroupID     string    `json:"group_id" gorm:"type:char(36);primaryKey"`
+	Kind        string    `json:"kind" gorm:"type:varchar(10);not null;default:text"`
+	Text        *string   `json:"text" gorm:"type:varchar(64)"`
+	ImageObject *string   `json:"image_object" gorm:"type:varchar(512)"`
+	Position    string    `json:"position" gorm:"type:varchar(20);not null;default:bottom-right"`
+	Opacity     int       `json:"opacity" gorm:"not null;default:50"`
+	Scale       int       `json:"scale" gorm:"not null;default:25"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+	// VariantKey names the storage directory of the rendered variants and changes with every update
+	VariantKey string `json:"-" gorm:"type:char(32);not null"`
+}
@@ -0,0 +1,35 @@
+package entities
+
+import "time"
+
+// UserHistoryEntry records that a user opened a chapter. Going on reading the same chapter moves its entry
+// rather than adding one
+type UserHistoryEntry struct {
+	EntryID   string `json:"entry_id" gorm:"type:char(36);primaryKey"`
+	UserID    string `json:"user_id" gorm:"type:char(36);not null"`
+	MangaID   string `json:"manga_id" gorm:"type:char(36);not null"`
+	ChapterID string `json:"chapter_id" gorm:"type:char(36);not null"`
+	// PageNumber is the last page the user was on, nil when they only marked the chapter read
+	PageNumber *int      `json:"page_number"`
+	OpenedAt   time.Time `json:"opened_at" gorm:"type:datetime(3);not null"`
+
+	// Relationships
+	Manga   *Manga        `json:"manga,omitempty" gorm:"foreignKey:MangaID;references:MangaID"`
+	Chapter *MangaChapter `json:"chapter,omitempty" gorm:"foreignKey:ChapterID;references:ChapterID"`
+}
+
+// HistoryQuery selects the reading history of a user, the latest opened first
+type HistoryQuery struct {
+	UserID string
+	// MangaID keeps the entries of a single manga when set
+	MangaID *string
+	Offset  int
+	// Limit caps how many entries are listed, 0 for all of them
+	Limit int
+}
+
+// HistoryPage represents a page of a user's reading history along with how many entries there are
+type HistoryPage struct {
+	Items []UserHistoryEntry
+	Total int64
+}
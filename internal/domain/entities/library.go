@@ -0,0 +1,77 @@
+package entities
+
+import "time"
+
+const (
+	// ShelfKindReading is the built-in shelf of the mangas being read
+	ShelfKindReading = "reading"
+	// ShelfKindPlanToRead is the built-in shelf of the mangas to read later
+	ShelfKindPlanToRead = "plan_to_read"
+	// ShelfKindDropped is the built-in shelf of the mangas given up on
+	ShelfKindDropped = "dropped"
+	// ShelfKindCompleted is the built-in shelf of the mangas read to the end
+	ShelfKindCompleted = "completed"
+	// ShelfKindCustom is the kind of the shelves users create
+	ShelfKindCustom = "custom"
+)
+
+const (
+	// LibrarySortAdded lists favorites by when they were added to the library
+	LibrarySortAdded = "added"
+	// LibrarySortUpdated lists favorites by when their latest chapter was added, mangas without chapters last
+	LibrarySortUpdated = "updated"
+)
+
+// LibraryShelf represents a shelf of a user's library, either built-in or created by the user
+type LibraryShelf struct {
+	ShelfID   string    `json:"shelf_id" gorm:"type:char(36);primaryKey"`
+	UserID    string    `json:"user_id" gorm:"type:char(36);not null"`
+	Name      string    `json:"name" gorm:"type:varchar(64);not null"`
+	Kind      string    `json:"kind" gorm:"type:varchar(16);not null;default:custom"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// IsBuiltin checks if the shelf is one every library starts with
+func (s *LibraryShelf) IsBuiltin() bool {
+	return s.Kind != ShelfKindCustom
+}
+
+// UserFavoriteManga represents a manga in a user's library
+type UserFavoriteManga struct {
+	FavoriteID string `json:"favorite_id" gorm:"type:char(36);primaryKey"`
+	ExternalID string `json:"external_id" gorm:"type:char(36);unique;not null"`
+	UserID     string `json:"user_id" gorm:"type:char(36);not null"`
+	MangaID    string `json:"manga_id" gorm:"type:char(36);not null"`
+	// ShelfID is the shelf the manga is on, nil when it isn't on any shelf
+	ShelfID *string   `json:"shelf_id" gorm:"type:char(36)"`
+	AddedAt time.Time `json:"added_at" gorm:"autoCreateTime"`
+}
+
+// LibraryQuery selects a page of a user's library
+type LibraryQuery struct {
+	UserID string
+	// ShelfID keeps the favorites on a shelf, NoShelf those on none
+	ShelfID    *string
+	NoShelf    bool
+	Sort       string
+	Descending bool
+	Offset     int
+	Limit      int
+}
+
+// LibraryItem is a favorite along with its manga's chapter count, how many of them the user hasn't read and when
+// its latest chapter was added. Versions of a chapter in several languages or by several groups count once
+type LibraryItem struct {
+	Favorite        UserFavoriteManga
+	Manga           Manga
+	ChapterCount    int64
+	UnreadCount     int64
+	LatestChapterAt *time.Time
+}
+
+// LibraryResult is a page of a user's library along with how many favorites match the query
+type LibraryResult struct {
+	Items []LibraryItem
+	Total int64
+}
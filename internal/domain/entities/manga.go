@@ -0,0 +1,37 @@
+package entities
+
+import "time"
+
+const (
+	// MangaVisibilityPublic lets anyone with a page URL view the manga's images
+	MangaVisibilityPublic = "public"
+	// MangaVisibilityRestricted only serves the manga's images through signed, expiring URLs
+	MangaVisibilityRestricted = "restricted"
+
+	// MangaFavoritePopularity is how many readers a favorite counts for in the popularity of a manga
+	MangaFavoritePopularity = 3
+)
+
+// Manga represents the manga entity in the domain layer
+type Manga struct {
+	MangaID     string    `json:"manga_id" gorm:"type:char(36);primaryKey"`
+	ExternalID  string    `json:"external_id" gorm:"type:char(36);unique;not null"`
+	StatusID    uint      `json:"status_id" gorm:"not null"`
+	Title       string    `json:"title" gorm:"not null"`
+	Description *string   `json:"description"`
+	Year        *int      `json:"year"`
+	Visibility  string    `json:"visibility" gorm:"type:varchar(20);not null;default:public"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+
+	// Relationships
+	Authors      []Author           `json:"authors,omitempty" gorm:"many2many:mangas_authors;foreignKey:MangaID;joinForeignKey:MangaID;references:AuthorID;joinReferences:AuthorID"`
+	PrimaryCover *MangaCover        `json:"primary_cover,omitempty" gorm:"foreignKey:MangaID;references:MangaID"`
+	Titles       []MangaTitle       `json:"titles,omitempty" gorm:"foreignKey:MangaID;references:MangaID"`
+	Descriptions []MangaDescription `json:"descriptions,omitempty" gorm:"foreignKey:MangaID;references:MangaID"`
+}
+
+// IsRestricted reports whether the manga's images may only be served through signed URLs
+func (m *Manga) IsRestricted() bool {
+	return m.Visibility == MangaVisibilityRestricted
+}
@@ -0,0 +1,15 @@
+package entities
+
+import "time"
+
+// MangaCover represents a cover image of a manga, optionally tied to a volume and locale
+type MangaCover struct {
+	CoverID    string    `json:"cover_id" gorm:"type:char(36);primaryKey"`
+	MangaID    string    `json:"manga_id" gorm:"type:char(36);not null"`
+	ImageURL   string    `json:"image_url" gorm:"type:varchar(2048);not null"`
+	Volume     *int      `json:"volume"`
+	Locale     *string   `json:"locale" gorm:"type:varchar(35)"`
+	UploadedBy *string   `json:"uploaded_by" gorm:"type:char(36)"`
+	IsPrimary  bool      `json:"is_primary" gorm:"not null;default:false"`
+	CreatedAt  time.Time `json:"created_at"`
+}
@@ -0,0 +1,54 @@
+package entities
+
+import "time"
+
+const (
+	// MangaListSortLatestChapter lists the mangas by when their latest chapter was added, mangas without chapters last
+	MangaListSortLatestChapter = "latest_chapter"
+	// MangaListSortCreated lists the mangas by when they were added
+	MangaListSortCreated = "created"
+	// MangaListSortTitle lists the mangas by title
+	MangaListSortTitle = "title"
+	// MangaListSortPopularity lists the mangas by readers plus favorites
+	MangaListSortPopularity = "popularity"
+)
+
+// MangaRefFilter narrows a manga listing to the mangas linked, or not linked, to authors, groups or categories
+type MangaRefFilter struct {
+	Include []string
+	Exclude []string
+	// MatchAll requires every included ID instead of any of them
+	MatchAll bool
+}
+
+// MangaListQuery describes a manga listing along with the filters narrowing it. Nil bounds are left open
+type MangaListQuery struct {
+	Categories        MangaRefFilter
+	Authors           MangaRefFilter
+	Groups            MangaRefFilter
+	StatusIDs         []uint
+	ExcludedStatusIDs []uint
+	YearFrom          *int
+	YearTo            *int
+	ChaptersMin       *int
+	ChaptersMax       *int
+	// UpdatedSince keeps the mangas with a chapter added since then
+	UpdatedSince *time.Time
+	Sort         string
+	Descending   bool
+	Offset       int
+	Limit        int
+}
+
+// MangaListItem is a listed manga along with its chapter count and when its latest chapter was added
+type MangaListItem struct {
+	Manga           Manga
+	ChapterCount    int64
+	LatestChapterAt *time.Time
+}
+
+// MangaListResult is a page of listed mangas along with the total count of mangas matching the filters
+type MangaListResult struct {
+	Items []MangaListItem
+	Total int64
+}
@@ -0,0 +1,65 @@
+package entities
+
+import "time"
+
+const (
+	// MangaSearchModeNatural ranks mangas by how well they match the words of the query
+	MangaSearchModeNatural = "natural"
+	// MangaSearchModeBoolean matches the query as an expression with required, excluded and prefix terms
+	MangaSearchModeBoolean = "boolean"
+
+	// MangaSearchSortRelevance lists the best matches first
+	MangaSearchSortRelevance = "relevance"
+	// MangaSearchSortRecent lists the most recently added mangas first
+	MangaSearchSortRecent = "recent"
+)
+
+// MangaSearchQuery describes a full-text manga search along with the filters narrowing it
+type MangaSearchQuery struct {
+	Text string
+	Mode string
+	// WithDescription matches the description as well as the title
+	WithDescription bool
+	StatusID        uint
+	CategoryID      string
+	AuthorID        string
+	GroupID         string
+	Sort            string
+	Offset          int
+	Limit           int
+}
+
+// MangaSearchHit is a manga matching a search along with its relevance score
+type MangaSearchHit struct {
+	Manga Manga
+	Score float64
+}
+
+// MangaSearchFacet is a value of a facet along with how many matching mangas have it
+type MangaSearchFacet struct {
+	Value string
+	Name  string
+	Count int64
+}
+
+// MangaSearchFacets counts the matching mangas by status, category, author and group
+type MangaSearchFacets struct {
+	Statuses   []MangaSearchFacet
+	Categories []MangaSearchFacet
+	Authors    []MangaSearchFacet
+	Groups     []MangaSearchFacet
+}
+
+// MangaSearchResult is a page of mangas matching a search, along with the total count and facets of all matches
+type MangaSearchResult struct {
+	Hits   []MangaSearchHit
+	Total  int64
+	Facets MangaSearchFacets
+}
+
+// MangaSearchIndexStats describes the content of an embedded search index
+type MangaSearchIndexStats struct {
+	Documents int
+	Terms     int
+	SyncedAt  time.Time
+}
@@ -0,0 +1,24 @@
+package entities
+
+import "time"
+
+// MangaTitle represents a title of a manga in a locale, such as its romaji or English name. A locale may hold
+// several titles, the primary one being shown
+type MangaTitle struct {
+	TitleID   string    `json:"title_id" gorm:"type:char(36);primaryKey"`
+	MangaID   string    `json:"manga_id" gorm:"type:char(36);not null"`
+	Locale    string    `json:"locale" gorm:"type:varchar(35);not null"`
+	Title     string    `json:"title" gorm:"not null"`
+	IsPrimary bool      `json:"is_primary" gorm:"not null;default:false"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// MangaDescription represents the description of a manga in a locale
+type MangaDescription struct {
+	MangaID     string    `json:"manga_id" gorm:"type:char(36);primaryKey"`
+	Locale      string    `json:"locale" gorm:"type:varchar(35);primaryKey"`
+	Description string    `json:"description" gorm:"type:text;not null"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
@@ -0,0 +1,18 @@
+package entities
+
+import "time"
+
+// MangaVolume represents a volume of a manga grouping some of its chapters
+type MangaVolume struct {
+	VolumeID     string  `json:"volume_id" gorm:"type:char(36);primaryKey"`
+	MangaID      string  `json:"manga_id" gorm:"type:char(36);not null"`
+	VolumeNumber int     `json:"volume_number" gorm:"not null"`
+	Title        *string `json:"title"`
+	// CoverID is the cover shown for the volume, nil to fall back to a cover uploaded for its number
+	CoverID   *string   `json:"cover_id" gorm:"type:char(36)"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+
+	// Relationships
+	Cover *MangaCover `json:"cover,omitempty" gorm:"foreignKey:CoverID;references:CoverID"`
+}
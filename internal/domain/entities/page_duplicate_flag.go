@@ -0,0 +1,29 @@
+package entities
+
+import "time"
+
+const (
+	// DuplicateStatusPending marks a flag awaiting moderator review
+	DuplicateStatusPending = "pending"
+	// DuplicateStatusConfirmed marks a flag a moderator agreed is a duplicate
+	DuplicateStatusConfirmed = "confirmed"
+	// DuplicateStatusDismissed marks a flag a moderator rejected
+	DuplicateStatusDismissed = "dismissed"
+)
+
+// PageDuplicateFlag records that a page looks like an earlier page of the same manga
+type PageDuplicateFlag struct {
+	FlagID          string     `json:"flag_id" gorm:"type:char(36);primaryKey"`
+	MangaID         string     `json:"manga_id" gorm:"type:char(36);not null"`
+	PageID          string     `json:"page_id" gorm:"type:char(36);not null"`
+	DuplicatePageID string     `json:"duplicate_page_id" gorm:"type:char(36);not null"`
+	Distance        int        `json:"distance" gorm:"not null"`
+	Status          string     `json:"status" gorm:"type:varchar(20);not null;default:pending"`
+	ReviewedBy      *string    `json:"reviewed_by" gorm:"type:char(36)"`
+	ReviewedAt      *time.Time `json:"reviewed_at"`
+	CreatedAt       time.Time  `json:"created_at"`
+
+	// Relationships
+	Page          *ChapterPage `json:"page,omitempty" gorm:"foreignKey:PageID;references:PageID"`
+	DuplicatePage *ChapterPage `json:"duplicate_page,omitempty" gorm:"foreignKey:DuplicatePageID;references:PageID"`
+}
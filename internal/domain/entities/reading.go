@@ -0,0 +1,42 @@
+package entities
+
+import "time"
+
+// UserReadChapter records that a user read a chapter
+type UserReadChapter struct {
+	UserID    string    `json:"user_id" gorm:"type:char(36);primaryKey"`
+	ChapterID string    `json:"chapter_id" gorm:"type:char(36);primaryKey"`
+	ReadAt    time.Time `json:"read_at"`
+}
+
+// UserChapterPosition records the last page of a chapter a user was on
+type UserChapterPosition struct {
+	UserID     string    `json:"user_id" gorm:"type:char(36);primaryKey"`
+	ChapterID  string    `json:"chapter_id" gorm:"type:char(36);primaryKey"`
+	PageNumber int       `json:"page_number" gorm:"not null"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+// ReadingFeedQuery selects a page of the mangas a user has been reading, the most recently read first
+type ReadingFeedQuery struct {
+	UserID string
+	// IncludeCompleted keeps the mangas whose every chapter the user read
+	IncludeCompleted bool
+	Offset           int
+	Limit            int
+}
+
+// ReadingActivity represents a manga a user has been reading, with how many of its chapters they read. Versions
+// of a chapter in several languages or by several groups count once
+type ReadingActivity struct {
+	MangaID      string
+	LastReadAt   time.Time
+	ChapterCount int64
+	ReadCount    int64
+}
+
+// ReadingFeed represents a page of the mangas a user has been reading along with how many there are
+type ReadingFeed struct {
+	Items []ReadingActivity
+	Total int64
+}
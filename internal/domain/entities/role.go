@@ -1,5 +1,8 @@
 package entities
 
+// RoleAdmin is the name of the role allowed to manage any content
+const RoleAdmin = "Admin"
+
 // Role represents the role entity in the domain layer
 type Role struct {
 	RoleID   string `json:"role_id" gorm:"type:char(36);primaryKey"`
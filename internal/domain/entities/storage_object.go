@@ -0,0 +1,14 @@
+package entities
+
+import "time"
+
+// StorageObject records who uploaded a stored file and which manga it belongs to
+type StorageObject struct {
+	ObjectName  string    `json:"object_name" gorm:"type:varchar(512);primaryKey"`
+	MangaID     string    `json:"manga_id" gorm:"type:char(36);not null"`
+	UploadedBy  *string   `json:"uploaded_by" gorm:"type:char(36)"`
+	GroupID     *string   `json:"group_id" gorm:"type:char(36)"`
+	ContentType string    `json:"content_type" gorm:"type:varchar(100);not null"`
+	Size        int64     `json:"size" gorm:"not null"`
+	CreatedAt   time.Time `json:"created_at"`
+}
@@ -0,0 +1,34 @@
+package entities
+
+// Types of the results of search suggestions
+const (
+	SuggestionTypeManga    = "manga"
+	SuggestionTypeAuthor   = "author"
+	SuggestionTypeGroup    = "group"
+	SuggestionTypeCategory = "category"
+)
+
+// SuggestionManga is a manga offered while typing a search, along with the signals its popularity is based on
+type SuggestionManga struct {
+	MangaID string
+	Title   string
+	// AltTitles are the localized titles the manga is also suggested for
+	AltTitles []string `gorm:"-"`
+	Favorites int64
+	Readers   int64
+}
+
+// SuggestionRef is an author, group or category offered while typing a search, along with its mangas
+type SuggestionRef struct {
+	ID       string
+	Name     string
+	MangaIDs []string
+}
+
+// SuggestionSource holds everything search suggestions are built from
+type SuggestionSource struct {
+	Mangas     []SuggestionManga
+	Authors    []SuggestionRef
+	Groups     []SuggestionRef
+	Categories []SuggestionRef
+}
@@ -0,0 +1,32 @@
+package entities
+
+import "time"
+
+const (
+	// UploadPurposeCover marks an upload that becomes a manga cover image
+	UploadPurposeCover = "cover"
+	// UploadPurposePage marks an upload that becomes a chapter page
+	UploadPurposePage = "page"
+)
+
+// UploadIntent represents a presigned direct-to-storage upload awaiting finalization
+type UploadIntent struct {
+	UploadID    string     `json:"upload_id" gorm:"type:char(36);primaryKey"`
+	UserID      string     `json:"user_id" gorm:"type:char(36);not null"`
+	Purpose     string     `json:"purpose" gorm:"type:varchar(20);not null"`
+	MangaID     string     `json:"manga_id" gorm:"type:char(36);not null"`
+	ChapterID   *string    `json:"chapter_id" gorm:"type:char(36)"`
+	ObjectName  string     `json:"object_name" gorm:"type:varchar(1024);not null"`
+	ContentType string     `json:"content_type" gorm:"type:varchar(100);not null"`
+	Size        int64      `json:"size" gorm:"not null"`
+	ExpiresAt   time.Time  `json:"expires_at" gorm:"not null"`
+	FinalizedAt *time.Time `json:"finalized_at"`
+	CreatedAt   time.Time  `json:"created_at"`
+	// ClaimedUntil is when a finalize call in progress gives up its claim on the intent
+	ClaimedUntil *time.Time `json:"-"`
+}
+
+// IsExpired checks if the upload intent can no longer be finalized
+func (u *UploadIntent) IsExpired() bool {
+	return time.Now().After(u.ExpiresAt)
+}
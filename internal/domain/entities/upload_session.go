@@ -0,0 +1,49 @@
+package entities
+
+import "time"
+
+const (
+	// UploadSessionKindPage marks a session that uploads a single chapter page
+	UploadSessionKindPage = "page"
+	// UploadSessionKindArchive marks a session that uploads a CBZ/ZIP archive of chapter pages
+	UploadSessionKindArchive = "archive"
+)
+
+// UploadSession represents a resumable chunked upload backed by a storage multipart upload
+type UploadSession struct {
+	SessionID       string     `json:"session_id" gorm:"type:char(36);primaryKey"`
+	UserID          string     `json:"user_id" gorm:"type:char(36);not null"`
+	Kind            string     `json:"kind" gorm:"type:varchar(20);not null"`
+	MangaID         string     `json:"manga_id" gorm:"type:char(36);not null"`
+	ChapterID       string     `json:"chapter_id" gorm:"type:char(36);not null"`
+	Filename        string     `json:"filename" gorm:"not null"`
+	ContentType     string     `json:"content_type" gorm:"type:varchar(100);not null"`
+	TotalSize       int64      `json:"total_size" gorm:"not null"`
+	UploadOffset    int64      `json:"upload_offset" gorm:"not null;default:0"`
+	ObjectName      string     `json:"-" gorm:"type:varchar(1024);not null"`
+	StorageUploadID string     `json:"-" gorm:"not null"`
+	ExpiresAt       time.Time  `json:"expires_at" gorm:"not null"`
+	CompletedAt     *time.Time `json:"completed_at"`
+	CreatedAt       time.Time  `json:"created_at"`
+	UpdatedAt       time.Time  `json:"updated_at"`
+	// ClaimedUntil is when a finalize call in progress gives up its claim on the session
+	ClaimedUntil *time.Time `json:"-"`
+}
+
+// IsExpired checks if the upload session can no longer receive data
+func (u *UploadSession) IsExpired() bool {
+	return time.Now().After(u.ExpiresAt)
+}
+
+// IsComplete checks if all bytes of the upload have been received
+func (u *UploadSession) IsComplete() bool {
+	return u.UploadOffset >= u.TotalSize
+}
+
+// UploadSessionPart represents a chunk already stored as part of an upload session
+type UploadSessionPart struct {
+	SessionID  string `json:"session_id" gorm:"type:char(36);primaryKey"`
+	PartNumber int    `json:"part_number" gorm:"primaryKey"`
+	ETag       string `json:"etag" gorm:"column:etag;not null"`
+	Size       int64  `json:"size" gorm:"not null"`
+}
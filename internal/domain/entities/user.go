@@ -17,6 +17,10 @@ type User struct {
 	UpdatedAt   time.Time  `json:"updated_at"`
 	DeletedFlag bool       `json:"deleted_flag" gorm:"not null;default:false"`
 	DeletedAt   *time.Time `json:"deleted_at"`
+	// LibraryPublic lets anyone see the user's library
+	LibraryPublic bool `json:"library_public" gorm:"not null;default:false"`
+	// HistoryPaused stops recording the chapters the user opens in their reading history
+	HistoryPaused bool `json:"history_paused" gorm:"not null;default:false"`
 
 	// Relationships
 	Role *Role `json:"role,omitempty" gorm:"foreignKey:RoleID;references:RoleID"`
@@ -42,3 +46,8 @@ func (u *User) CheckPassword(password string) bool {
 func (u *User) IsDeleted() bool {
 	return u.DeletedFlag
 }
+
+// IsAdmin checks if the user has the admin role
+func (u *User) IsAdmin() bool {
+	return u.Role != nil && u.Role.RoleName == RoleAdmin
+}
@@ -0,0 +1,23 @@
+package request
+
+// ListChaptersRequest represents the query parameters of a manga's chapter list. Languages and groups are comma
+// separated, languages being BCP 47 tags matching their regional variants too
+type ListChaptersRequest struct {
+	Languages string `form:"languages" binding:"omitempty,max=200"`
+	Groups    string `form:"groups" binding:"omitempty,max=1000"`
+	// Versions is "best" to keep a single version of each chapter number, "all" by default
+	Versions string `form:"versions" binding:"omitempty,oneof=all best"`
+	// Volume keeps the chapters of a volume, given by ID, or "none" for the chapters not in any volume
+	Volume string `form:"volume" binding:"omitempty,max=36"`
+	// Sort is "volume" to order chapters by volume first, chapters not in any volume coming last, "number" by default
+	Sort  string `form:"sort" binding:"omitempty,oneof=number volume"`
+	Order string `form:"order" binding:"omitempty,oneof=asc desc"`
+	Page  int    `form:"page" binding:"omitempty,min=1"`
+	Limit int    `form:"limit" binding:"omitempty,min=1,max=500"`
+}
+
+// SetChapterAttributionRequest represents the language and group a chapter version is credited to
+type SetChapterAttributionRequest struct {
+	Language string  `json:"language" binding:"required,max=35,bcp47_language_tag"`
+	GroupID  *string `json:"group_id" binding:"omitempty,uuid"`
+}
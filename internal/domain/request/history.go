@@ -0,0 +1,24 @@
+package request
+
+// ListHistoryRequest represents the query parameters of a user's reading history
+type ListHistoryRequest struct {
+	MangaID string `form:"manga_id" binding:"omitempty,uuid"`
+	Page    int    `form:"page" binding:"omitempty,min=1"`
+	Limit   int    `form:"limit" binding:"omitempty,min=1,max=100"`
+}
+
+// ClearHistoryRequest represents the query parameters of clearing a reading history, all of it unless narrowed to
+// a manga
+type ClearHistoryRequest struct {
+	MangaID string `form:"manga_id" binding:"omitempty,uuid"`
+}
+
+// ExportHistoryRequest represents the query parameters of a reading history export
+type ExportHistoryRequest struct {
+	Format string `form:"format" binding:"omitempty,oneof=json csv"`
+}
+
+// SetHistoryPauseRequest represents whether the chapters a user opens are recorded
+type SetHistoryPauseRequest struct {
+	Paused *bool `json:"paused" binding:"required"`
+}
@@ -0,0 +1,26 @@
+package request
+
+// ListLibraryRequest represents the query parameters of a user's library
+type ListLibraryRequest struct {
+	// Shelf keeps the favorites on a shelf, given by ID, or "none" for those on no shelf
+	Shelf string `form:"shelf" binding:"omitempty,max=36"`
+	Sort  string `form:"sort" binding:"omitempty,oneof=added updated"`
+	Order string `form:"order" binding:"omitempty,oneof=asc desc"`
+	Page  int    `form:"page" binding:"omitempty,min=1"`
+	Limit int    `form:"limit" binding:"omitempty,min=1,max=100"`
+}
+
+// SaveFavoriteRequest represents the shelf a manga of the library is on, null for none
+type SaveFavoriteRequest struct {
+	ShelfID *string `json:"shelf_id" binding:"omitempty,uuid"`
+}
+
+// SaveShelfRequest represents the name of a library shelf
+type SaveShelfRequest struct {
+	Name string `json:"name" binding:"required,max=64"`
+}
+
+// SetLibraryVisibilityRequest represents whether a user's library is shared
+type SetLibraryVisibilityRequest struct {
+	Public *bool `json:"public" binding:"required"`
+}
@@ -0,0 +1,53 @@
+package request
+
+import "time"
+
+// SetVisibilityRequest represents a change of who may view a manga's images
+type SetVisibilityRequest struct {
+	Visibility string `json:"visibility" binding:"required,oneof=public restricted"`
+}
+
+// SetYearRequest represents a change of the year a manga was first published, null clearing it
+type SetYearRequest struct {
+	Year *int `json:"year" binding:"omitempty,min=1800,max=2100"`
+}
+
+// UploadCoverRequest represents the form fields sent along with a cover image
+type UploadCoverRequest struct {
+	Volume  *int   `form:"volume" binding:"omitempty,min=0"`
+	Locale  string `form:"locale" binding:"omitempty,max=35,bcp47_language_tag"`
+	Primary bool   `form:"primary"`
+}
+
+// ListMangasRequest represents the query parameters of a filtered manga listing. Categories, authors, groups and
+// statuses are comma separated IDs, an ID starting with "-" excluding the mangas that have it
+type ListMangasRequest struct {
+	Categories     string    `form:"categories" binding:"omitempty,max=1000"`
+	CategoriesMode string    `form:"categories_mode" binding:"omitempty,oneof=and or"`
+	Authors        string    `form:"authors" binding:"omitempty,max=1000"`
+	AuthorsMode    string    `form:"authors_mode" binding:"omitempty,oneof=and or"`
+	Groups         string    `form:"groups" binding:"omitempty,max=1000"`
+	GroupsMode     string    `form:"groups_mode" binding:"omitempty,oneof=and or"`
+	Status         string    `form:"status" binding:"omitempty,max=200"`
+	YearFrom       *int      `form:"year_from" binding:"omitempty,min=1,max=9999"`
+	YearTo         *int      `form:"year_to" binding:"omitempty,min=1,max=9999"`
+	ChaptersMin    *int      `form:"chapters_min" binding:"omitempty,min=0"`
+	ChaptersMax    *int      `form:"chapters_max" binding:"omitempty,min=0"`
+	UpdatedSince   time.Time `form:"updated_since" time_format:"2006-01-02"`
+	Sort           string    `form:"sort" binding:"omitempty,oneof=latest_chapter created title popularity"`
+	Order          string    `form:"order" binding:"omitempty,oneof=asc desc"`
+	Page           int       `form:"page" binding:"omitempty,min=1"`
+	Limit          int       `form:"limit" binding:"omitempty,min=1,max=100"`
+}
+
+// AddMangaTitleRequest represents a localized title added to a manga
+type AddMangaTitleRequest struct {
+	Locale  string `json:"locale" binding:"required,max=35,bcp47_language_tag"`
+	Title   string `json:"title" binding:"required,max=255"`
+	Primary bool   `json:"primary"`
+}
+
+// SetMangaDescriptionRequest represents the description of a manga in a locale
+type SetMangaDescriptionRequest struct {
+	Description string `json:"description" binding:"required,max=65535"`
+}
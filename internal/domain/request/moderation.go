@@ -0,0 +1,14 @@
+package request
+
+// ListDuplicateFlagsRequest represents the query parameters for listing duplicate page flags
+type ListDuplicateFlagsRequest struct {
+	Status  string `form:"status" binding:"omitempty,oneof=pending confirmed dismissed"`
+	MangaID string `form:"manga_id" binding:"omitempty,uuid"`
+	Page    int    `form:"page" binding:"omitempty,min=1"`
+	Limit   int    `form:"limit" binding:"omitempty,min=1,max=100"`
+}
+
+// ReviewDuplicateFlagRequest represents a moderator's decision on a duplicate page flag
+type ReviewDuplicateFlagRequest struct {
+	Status string `json:"status" binding:"required,oneof=confirmed dismissed"`
+}
@@ -0,0 +1,19 @@
+package request
+
+// MarkReadUpToRequest represents a bulk read marking of every chapter of a manga numbered up to a chapter
+type MarkReadUpToRequest struct {
+	ChapterNumber *float64 `json:"chapter_number" binding:"required,min=0"`
+}
+
+// SaveReadingPositionRequest represents the page of a chapter a user is on
+type SaveReadingPositionRequest struct {
+	PageNumber int `json:"page_number" binding:"required,min=1"`
+}
+
+// ContinueReadingRequest represents the query parameters of the continue reading feed
+type ContinueReadingRequest struct {
+	// IncludeCompleted keeps the mangas whose every chapter was read
+	IncludeCompleted bool `form:"include_completed"`
+	Page             int  `form:"page" binding:"omitempty,min=1"`
+	Limit            int  `form:"limit" binding:"omitempty,min=1,max=50"`
+}
@@ -0,0 +1,15 @@
+package request
+
+// SearchMangasRequest represents the query parameters of a full-text manga search
+type SearchMangasRequest struct {
+	Query      string `form:"q" binding:"required,max=200"`
+	Mode       string `form:"mode" binding:"omitempty,oneof=natural boolean"`
+	In         string `form:"in" binding:"omitempty,oneof=title all"`
+	StatusID   uint   `form:"status_id" binding:"omitempty,min=1"`
+	CategoryID string `form:"category_id" binding:"omitempty,uuid"`
+	AuthorID   string `form:"author_id" binding:"omitempty,uuid"`
+	GroupID    string `form:"group_id" binding:"omitempty,uuid"`
+	Sort       string `form:"sort" binding:"omitempty,oneof=relevance recent"`
+	Page       int    `form:"page" binding:"omitempty,min=1"`
+	Limit      int    `form:"limit" binding:"omitempty,min=1,max=100"`
+}
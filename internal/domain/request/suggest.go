@@ -0,0 +1,9 @@
+package request
+
+// SuggestRequest represents the query parameters of search suggestions
+type SuggestRequest struct {
+	Query string `form:"q" binding:"required,max=100"`
+	// Types is a comma separated list of the result types to include, all of them when empty
+	Types string `form:"types" binding:"omitempty,max=100"`
+	Limit int    `form:"limit" binding:"omitempty,min=1,max=20"`
+}
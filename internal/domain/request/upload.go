@@ -0,0 +1,26 @@
+package request
+
+// PresignUploadRequest represents a request for a direct-to-storage upload URL
+type PresignUploadRequest struct {
+	Purpose     string `json:"purpose" binding:"required,oneof=cover page"`
+	MangaID     string `json:"manga_id" binding:"required,uuid"`
+	ChapterID   string `json:"chapter_id" binding:"required_if=Purpose page,omitempty,uuid"`
+	Filename    string `json:"filename" binding:"required,max=255"`
+	ContentType string `json:"content_type" binding:"required,oneof=image/jpeg image/png image/gif image/webp"`
+	Size        int64  `json:"size" binding:"required,min=1,max=10485760"`
+}
+
+// CreateUploadSessionRequest represents a request to start a resumable upload
+type CreateUploadSessionRequest struct {
+	Kind        string `json:"kind" binding:"required,oneof=page archive"`
+	MangaID     string `json:"manga_id" binding:"required,uuid"`
+	ChapterID   string `json:"chapter_id" binding:"required,uuid"`
+	Filename    string `json:"filename" binding:"required,max=255"`
+	ContentType string `json:"content_type" binding:"required,max=100"`
+	Size        int64  `json:"size" binding:"required,min=1"`
+}
+
+// ReorderPagesRequest represents a request to renumber the pages of a chapter
+type ReorderPagesRequest struct {
+	PageIDs []string `json:"page_ids" binding:"required,min=1,dive,uuid"`
+}
@@ -0,0 +1,23 @@
+package request
+
+// CreateVolumeRequest represents a volume added to a manga
+type CreateVolumeRequest struct {
+	VolumeNumber *int    `json:"volume_number" binding:"required,min=0,max=9999"`
+	Title        *string `json:"title" binding:"omitempty,max=255"`
+	CoverID      *string `json:"cover_id" binding:"omitempty,uuid"`
+}
+
+// UpdateVolumeRequest represents changes to a volume, fields left out being kept. An empty title or cover_id clears it
+type UpdateVolumeRequest struct {
+	VolumeNumber *int    `json:"volume_number" binding:"omitempty,min=0,max=9999"`
+	Title        *string `json:"title" binding:"omitempty,max=255"`
+	CoverID      *string `json:"cover_id" binding:"omitempty,max=36"`
+}
+
+// SetVolumeChaptersRequest represents the chapters of a volume, listed by ID or by an inclusive range of chapter
+// numbers covering every version of them
+type SetVolumeChaptersRequest struct {
+	ChapterIDs []string `json:"chapter_ids" binding:"omitempty,max=1000,dive,uuid"`
+	From       *float64 `json:"from" binding:"omitempty,min=0"`
+	To         *float64 `json:"to" binding:"omitempty,min=0"`
+}
@@ -0,0 +1,16 @@
+package request
+
+// SetGroupWatermarkRequest represents the form fields of a group watermark, sent along with
+// an optional "image" PNG overlay for image watermarks
+type SetGroupWatermarkRequest struct {
+	Kind     string `form:"kind" binding:"required,oneof=text image"`
+	Text     string `form:"text" binding:"required_if=Kind text,max=64"`
+	Position string `form:"position" binding:"omitempty,oneof=top-left top-right bottom-left bottom-right center"`
+	Opacity  *int   `form:"opacity" binding:"omitempty,min=1,max=100"`
+	Scale    *int   `form:"scale" binding:"omitempty,min=1,max=100"`
+}
+
+// SetChapterWatermarkRequest represents turning a chapter's watermark on with a group's watermark, or off with no group
+type SetChapterWatermarkRequest struct {
+	GroupID *string `json:"group_id" binding:"omitempty,uuid"`
+}
@@ -38,3 +38,18 @@ func AuthMiddleware(tokenService serviceinf.TokenService) gin.HandlerFunc {
 		c.Next()
 	}
 }
+
+// OptionalAuthMiddleware creates a middleware that identifies the user of a valid JWT token when there is one,
+// letting the request through signed out otherwise
+func OptionalAuthMiddleware(tokenService serviceinf.TokenService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		bearerToken := strings.Split(c.GetHeader("Authorization"), " ")
+		if len(bearerToken) == 2 && bearerToken[0] == "Bearer" {
+			if claims, err := tokenService.ValidateToken(bearerToken[1]); err == nil {
+				c.Set("user_id", claims.UserID)
+				c.Set("user_email", claims.Email)
+			}
+		}
+		c.Next()
+	}
+}
@@ -0,0 +1,69 @@
+package middleware
+
+import (
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"hotaku-api/internal/domain/response"
+
+	"github.com/gin-gonic/gin"
+)
+
+// rateWindow tracks the requests made by a single client in the current window
+type rateWindow struct {
+	count   int
+	resetAt time.Time
+}
+
+// RateLimitMiddleware creates a middleware that allows at most limit requests per window
+// for each authenticated user, falling back to the client IP for anonymous requests
+func RateLimitMiddleware(limit int, window time.Duration) gin.HandlerFunc {
+	var mu sync.Mutex
+	windows := make(map[string]*rateWindow)
+	lastSweep := time.Now()
+
+	return func(c *gin.Context) {
+		key := c.GetString("user_id")
+		if key == "" {
+			key = "ip:" + c.ClientIP()
+		}
+
+		now := time.Now()
+
+		mu.Lock()
+		// Drop expired windows so the map doesn't grow without bound
+		if now.Sub(lastSweep) > window {
+			for k, w := range windows {
+				if now.After(w.resetAt) {
+					delete(windows, k)
+				}
+			}
+			lastSweep = now
+		}
+
+		w, ok := windows[key]
+		if !ok || now.After(w.resetAt) {
+			w = &rateWindow{resetAt: now.Add(window)}
+			windows[key] = w
+		}
+		w.count++
+		count, resetAt := w.count, w.resetAt
+		mu.Unlock()
+
+		c.Header("X-RateLimit-Limit", strconv.Itoa(limit))
+		c.Header("X-RateLimit-Reset", strconv.FormatInt(resetAt.Unix(), 10))
+
+		if count > limit {
+			c.Header("X-RateLimit-Remaining", "0")
+			c.Header("Retry-After", strconv.Itoa(int(time.Until(resetAt).Seconds())+1))
+			c.JSON(http.StatusTooManyRequests, response.ErrorResponse(http.StatusTooManyRequests, "Rate limit exceeded", "please try again later"))
+			c.Abort()
+			return
+		}
+
+		c.Header("X-RateLimit-Remaining", strconv.Itoa(limit-count))
+		c.Next()
+	}
+}
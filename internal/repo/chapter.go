@@ -0,0 +1,238 @@
+package repo
+
+import (
+	"errors"
+	"fmt"
+	"hotaku-api/internal/domain/entities"
+	"hotaku-api/internal/repoinf"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// ChapterRepositoryImpl implements the chapter repository interface
+type ChapterRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewChapterRepository creates a new instance of ChapterRepositoryImpl
+func NewChapterRepository(db *gorm.DB) repoinf.ChapterRepository {
+	return &ChapterRepositoryImpl{db: db}
+}
+
+// GetByID retrieves a chapter by ID along with its group and volume
+func (r *ChapterRepositoryImpl) GetByID(id string) (*entities.MangaChapter, error) {
+	var chapter entities.MangaChapter
+	if err := r.db.Where("chapter_id = ?", id).Preload("Group").Preload("Volume").First(&chapter).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("chapter not found")
+		}
+		return nil, fmt.Errorf("failed to retrieve chapter by ID: %w", err)
+	}
+	return &chapter, nil
+}
+
+// UpdateWatermarkGroup sets the group whose watermark is drawn on the chapter, nil turns it off
+func (r *ChapterRepositoryImpl) UpdateWatermarkGroup(chapterID string, groupID *string) error {
+	if err := r.db.Model(&entities.MangaChapter{}).Where("chapter_id = ?", chapterID).Update("watermark_group_id", groupID).Error; err != nil {
+		return fmt.Errorf("failed to update chapter watermark: %w", err)
+	}
+	return nil
+}
+
+// ListByWatermarkGroup retrieves the chapters watermarked by a group
+func (r *ChapterRepositoryImpl) ListByWatermarkGroup(groupID string) ([]entities.MangaChapter, error) {
+	var chapters []entities.MangaChapter
+	if err := r.db.Where("watermark_group_id = ?", groupID).Find(&chapters).Error; err != nil {
+		return nil, fmt.Errorf("failed to retrieve watermarked chapters: %w", err)
+	}
+	return chapters, nil
+}
+
+// ListWatermarked retrieves the chapters that have a watermark turned on
+func (r *ChapterRepositoryImpl) ListWatermarked() ([]entities.MangaChapter, error) {
+	var chapters []entities.MangaChapter
+	if err := r.db.Where("watermark_group_id IS NOT NULL").Order("chapter_id").Find(&chapters).Error; err != nil {
+		return nil, fmt.Errorf("failed to retrieve watermarked chapters: %w", err)
+	}
+	return chapters, nil
+}
+
+// ListWithoutPages retrieves the chapters that have no page recorded
+func (r *ChapterRepositoryImpl) ListWithoutPages() ([]entities.MangaChapter, error) {
+	var chapters []entities.MangaChapter
+	err := r.db.Where("NOT EXISTS (SELECT 1 FROM chapter_pages AS p WHERE p.chapter_id = manga_chapters.chapter_id)").
+		Find(&chapters).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve chapters without pages: %w", err)
+	}
+	return chapters, nil
+}
+
+// ListByManga retrieves every version of the chapters of a manga with their group and volume, by chapter number and
+// then upload time
+func (r *ChapterRepositoryImpl) ListByManga(mangaID string) ([]entities.MangaChapter, error) {
+	var chapters []entities.MangaChapter
+	err := r.db.Where("manga_id = ?", mangaID).
+		Preload("Group").
+		Preload("Volume").
+		Order("chapter_number ASC").
+		Order("created_at ASC").
+		Find(&chapters).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve manga chapters: %w", err)
+	}
+	return chapters, nil
+}
+
+// List retrieves a page of the chapters of a manga matching the query, with their group and volume. Chapters are
+// ordered by chapter number and then upload time, by volume number first when asked
+func (r *ChapterRepositoryImpl) List(query *entities.ChapterListQuery) (*entities.ChapterListResult, error) {
+	filtered := r.db.Table("manga_chapters AS c").Where("c.manga_id = ?", query.MangaID)
+	if len(query.Languages) > 0 {
+		condition, args := chapterLanguagesMatch(query.Languages)
+		filtered = filtered.Where(condition, args...)
+	}
+	if len(query.GroupIDs) > 0 {
+		filtered = filtered.Where("c.group_id IN ?", query.GroupIDs)
+	}
+	switch {
+	case query.NoVolume:
+		filtered = filtered.Where("c.volume_id IS NULL")
+	case query.VolumeID != "":
+		filtered = filtered.Where("c.volume_id = ?", query.VolumeID)
+	}
+
+	if query.BestVersions {
+		// Versions of a chapter number are ranked within the filtered chapters, and only the first one of each is kept
+		var order []string
+		var args []interface{}
+		if len(query.RankLanguages) > 0 {
+			rank := "CASE"
+			for i, language := range query.RankLanguages {
+				condition, conditionArgs := chapterLanguagesMatch([]string{language})
+				rank += fmt.Sprintf(" WHEN %s THEN %d", condition, i)
+				args = append(args, conditionArgs...)
+			}
+			order = append(order, fmt.Sprintf("%s ELSE %d END", rank, len(query.RankLanguages)))
+		}
+		if len(query.GroupIDs) > 0 {
+			rank := "CASE"
+			for i, groupID := range query.GroupIDs {
+				rank += fmt.Sprintf(" WHEN c.group_id = ? THEN %d", i)
+				args = append(args, groupID)
+			}
+			order = append(order, fmt.Sprintf("%s ELSE %d END", rank, len(query.GroupIDs)))
+		}
+		order = append(order, "c.created_at DESC", "c.chapter_id ASC")
+		versions := filtered.Select("c.chapter_id, c.chapter_number, c.volume_id, c.created_at, "+
+			"ROW_NUMBER() OVER (PARTITION BY c.chapter_number ORDER BY "+strings.Join(order, ", ")+") AS version_rank", args...)
+		filtered = r.db.Table("(?) AS c", versions).Where("c.version_rank = 1")
+	}
+
+	// A new session lets the count and page queries both start from the filters
+	filtered = filtered.Session(&gorm.Session{})
+
+	result := &entities.ChapterListResult{Items: []entities.MangaChapter{}}
+	if err := filtered.Count(&result.Total).Error; err != nil {
+		return nil, fmt.Errorf("failed to count manga chapters: %w", err)
+	}
+	if result.Total == 0 || query.Offset >= int(result.Total) {
+		return result, nil
+	}
+
+	direction := "ASC"
+	if query.Descending {
+		direction = "DESC"
+	}
+	order := fmt.Sprintf("c.chapter_number %[1]s, c.created_at %[1]s, c.chapter_id %[1]s", direction)
+	page := filtered
+	if query.ByVolume {
+		page = page.Joins("LEFT JOIN manga_volumes AS v ON v.volume_id = c.volume_id")
+		// Chapters not in any volume stay last whichever the direction
+		order = "v.volume_number IS NULL, v.volume_number " + direction + ", " + order
+	}
+
+	var ids []string
+	err := page.Order(order).
+		Offset(query.Offset).
+		Limit(query.Limit).
+		Pluck("c.chapter_id", &ids).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to list manga chapters: %w", err)
+	}
+	if len(ids) == 0 {
+		return result, nil
+	}
+
+	var chapters []entities.MangaChapter
+	if err := r.db.Where("chapter_id IN ?", ids).Preload("Group").Preload("Volume").Find(&chapters).Error; err != nil {
+		return nil, fmt.Errorf("failed to retrieve listed chapters: %w", err)
+	}
+	byID := make(map[string]entities.MangaChapter, len(chapters))
+	for _, chapter := range chapters {
+		byID[chapter.ChapterID] = chapter
+	}
+	for _, id := range ids {
+		// A chapter deleted in the meantime is left out of the page
+		if chapter, ok := byID[id]; ok {
+			result.Items = append(result.Items, chapter)
+		}
+	}
+	return result, nil
+}
+
+// chapterLanguagesMatch returns the condition keeping the chapters in any of the base languages, whatever their
+// region or script
+func chapterLanguagesMatch(languages []string) (string, []interface{}) {
+	var conditions []string
+	var args []interface{}
+	for _, language := range languages {
+		conditions = append(conditions, "c.language = ? OR c.language LIKE ?")
+		args = append(args, language, language+"-%")
+	}
+	return "(" + strings.Join(conditions, " OR ") + ")", args
+}
+
+// ExistsVersion checks if another chapter of the manga has the same number, language and group
+func (r *ChapterRepositoryImpl) ExistsVersion(chapter *entities.MangaChapter, language string, groupID *string) (bool, error) {
+	query := r.db.Model(&entities.MangaChapter{}).
+		Where("manga_id = ? AND chapter_number = ? AND language = ? AND chapter_id <> ?", chapter.MangaID, chapter.ChapterNumber, language, chapter.ChapterID)
+	if groupID == nil {
+		query = query.Where("group_id IS NULL")
+	} else {
+		query = query.Where("group_id = ?", *groupID)
+	}
+
+	var count int64
+	if err := query.Count(&count).Error; err != nil {
+		return false, fmt.Errorf("failed to check chapter versions: %w", err)
+	}
+	return count > 0, nil
+}
+
+// UpdateAttribution sets the language and group of a chapter
+func (r *ChapterRepositoryImpl) UpdateAttribution(chapterID, language string, groupID *string) error {
+	err := r.db.Model(&entities.MangaChapter{}).Where("chapter_id = ?", chapterID).Updates(map[string]interface{}{
+		"language": language,
+		"group_id": groupID,
+	}).Error
+	if err != nil {
+		return fmt.Errorf("failed to update chapter attribution: %w", err)
+	}
+	return nil
+}
+
+// ListReadByManga retrieves the reads of the chapters of a manga by a user
+func (r *ChapterRepositoryImpl) ListReadByManga(userID, mangaID string) ([]entities.UserReadChapter, error) {
+	var reads []entities.UserReadChapter
+	err := r.db.Table("user_read_chapters AS urc").
+		Select("urc.user_id, urc.chapter_id, urc.read_at").
+		Joins("JOIN manga_chapters AS c ON c.chapter_id = urc.chapter_id").
+		Where("urc.user_id = ? AND c.manga_id = ?", userID, mangaID).
+		Scan(&reads).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve read chapters: %w", err)
+	}
+	return reads, nil
+}
@@ -0,0 +1,283 @@
+package repo
+
+import (
+	"errors"
+	"fmt"
+	"hotaku-api/internal/domain/entities"
+	"hotaku-api/internal/repoinf"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// reorderOffset temporarily moves page numbers out of the way while renumbering,
+// so the (chapter_id, page_number) unique key is never violated mid-update
+const reorderOffset = 1000000
+
+// ChapterPageRepositoryImpl implements the chapter page repository interface
+type ChapterPageRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewChapterPageRepository creates a new instance of ChapterPageRepositoryImpl
+func NewChapterPageRepository(db *gorm.DB) repoinf.ChapterPageRepository {
+	return &ChapterPageRepositoryImpl{db: db}
+}
+
+// GetByNumber retrieves a chapter page by its page number
+func (r *ChapterPageRepositoryImpl) GetByNumber(chapterID string, pageNumber int) (*entities.ChapterPage, error) {
+	var page entities.ChapterPage
+	if err := r.db.Where("chapter_id = ? AND page_number = ?", chapterID, pageNumber).First(&page).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("page not found")
+		}
+		return nil, fmt.Errorf("failed to retrieve chapter page: %w", err)
+	}
+	return &page, nil
+}
+
+// ListByChapter retrieves all pages of a chapter ordered by page number
+func (r *ChapterPageRepositoryImpl) ListByChapter(chapterID string) ([]entities.ChapterPage, error) {
+	var pages []entities.ChapterPage
+	if err := r.db.Where("chapter_id = ?", chapterID).Order("page_number ASC").Find(&pages).Error; err != nil {
+		return nil, fmt.Errorf("failed to retrieve chapter pages: %w", err)
+	}
+	return pages, nil
+}
+
+// AppendPages numbers the pages after the current last page of the chapter and saves them
+func (r *ChapterPageRepositoryImpl) AppendPages(chapterID string, pages []entities.ChapterPage) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		maxPage, err := lockChapterPages(tx, chapterID)
+		if err != nil {
+			return err
+		}
+
+		for i := range pages {
+			pages[i].ChapterID = chapterID
+			pages[i].PageNumber = maxPage + i + 1
+		}
+		if err := tx.Create(&pages).Error; err != nil {
+			return fmt.Errorf("failed to create chapter pages: %w", err)
+		}
+		return nil
+	})
+}
+
+// InsertPagesAt saves the pages starting at position, shifting the following pages back
+func (r *ChapterPageRepositoryImpl) InsertPagesAt(chapterID string, position int, pages []entities.ChapterPage) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		maxPage, err := lockChapterPages(tx, chapterID)
+		if err != nil {
+			return err
+		}
+		if position < 1 || position > maxPage+1 {
+			return fmt.Errorf("position must be between 1 and %d", maxPage+1)
+		}
+
+		// Shift from the end so no two pages share a number at any point
+		err = tx.Exec(
+			"UPDATE chapter_pages SET page_number = page_number + ? WHERE chapter_id = ? AND page_number >= ? ORDER BY page_number DESC",
+			len(pages), chapterID, position,
+		).Error
+		if err != nil {
+			return fmt.Errorf("failed to shift chapter pages: %w", err)
+		}
+
+		for i := range pages {
+			pages[i].ChapterID = chapterID
+			pages[i].PageNumber = position + i
+		}
+		if err := tx.Create(&pages).Error; err != nil {
+			return fmt.Errorf("failed to create chapter pages: %w", err)
+		}
+		return nil
+	})
+}
+
+// Reorder renumbers all pages of a chapter to follow the given page ID order
+func (r *ChapterPageRepositoryImpl) Reorder(chapterID string, pageIDs []string) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if _, err := lockChapterPages(tx, chapterID); err != nil {
+			return err
+		}
+
+		var existingIDs []string
+		if err := tx.Model(&entities.ChapterPage{}).Where("chapter_id = ?", chapterID).Pluck("page_id", &existingIDs).Error; err != nil {
+			return fmt.Errorf("failed to retrieve chapter pages: %w", err)
+		}
+
+		// The new order must be a permutation of the current pages
+		if len(existingIDs) != len(pageIDs) {
+			return fmt.Errorf("page order must contain all %d pages of the chapter", len(existingIDs))
+		}
+		remaining := make(map[string]bool, len(existingIDs))
+		for _, id := range existingIDs {
+			remaining[id] = true
+		}
+		for _, id := range pageIDs {
+			if !remaining[id] {
+				return fmt.Errorf("page %s is missing, duplicated or not part of the chapter", id)
+			}
+			delete(remaining, id)
+		}
+
+		if err := tx.Exec("UPDATE chapter_pages SET page_number = page_number + ? WHERE chapter_id = ?", reorderOffset, chapterID).Error; err != nil {
+			return fmt.Errorf("failed to renumber chapter pages: %w", err)
+		}
+		for i, id := range pageIDs {
+			if err := tx.Model(&entities.ChapterPage{}).Where("page_id = ?", id).Update("page_number", i+1).Error; err != nil {
+				return fmt.Errorf("failed to renumber chapter pages: %w", err)
+			}
+		}
+		return nil
+	})
+}
+
+// UpdateImage points a chapter page at a new image along with its content hashes and who added it
+func (r *ChapterPageRepositoryImpl) UpdateImage(pageID, imageURL string, contentSHA256 *string, perceptualHash *uint64, uploadedBy *string) error {
+	res := r.db.Model(&entities.ChapterPage{}).Where("page_id = ?", pageID).Updates(map[string]interface{}{
+		"image_url":       imageURL,
+		"content_sha256":  contentSHA256,
+		"perceptual_hash": perceptualHash,
+		"uploaded_by":     uploadedBy,
+	})
+	if res.Error != nil {
+		return fmt.Errorf("failed to update chapter page: %w", res.Error)
+	}
+	if res.RowsAffected == 0 {
+		return fmt.Errorf("page not found")
+	}
+	return nil
+}
+
+// FilterReferencedImageURLs returns the subset of imageURLs used by at least one page
+func (r *ChapterPageRepositoryImpl) FilterReferencedImageURLs(imageURLs []string) ([]string, error) {
+	var referenced []string
+	if len(imageURLs) == 0 {
+		return referenced, nil
+	}
+	err := r.db.Model(&entities.ChapterPage{}).
+		Distinct("image_url").
+		Where("image_url IN ?", imageURLs).
+		Pluck("image_url", &referenced).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to find referenced images: %w", err)
+	}
+	return referenced, nil
+}
+
+// ListImageURLsAfter retrieves the ID and image URL of up to limit pages whose ID sorts after afterID, by ID
+func (r *ChapterPageRepositoryImpl) ListImageURLsAfter(afterID string, limit int) ([]entities.ChapterPage, error) {
+	var pages []entities.ChapterPage
+	err := r.db.Select("page_id", "image_url").
+		Where("page_id > ?", afterID).
+		Order("page_id ASC").
+		Limit(limit).
+		Find(&pages).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve page images: %w", err)
+	}
+	return pages, nil
+}
+
+// CountPagesByChapter returns the page count of each existing chapter among chapterIDs
+func (r *ChapterPageRepositoryImpl) CountPagesByChapter(chapterIDs []string) (map[string]int64, error) {
+	counts := make(map[string]int64)
+	if len(chapterIDs) == 0 {
+		return counts, nil
+	}
+
+	var rows []struct {
+		ChapterID string
+		Pages     int64
+	}
+	err := r.db.Table("manga_chapters").
+		Select("manga_chapters.chapter_id, COUNT(chapter_pages.page_id) AS pages").
+		Joins("LEFT JOIN chapter_pages ON chapter_pages.chapter_id = manga_chapters.chapter_id").
+		Where("manga_chapters.chapter_id IN ?", chapterIDs).
+		Group("manga_chapters.chapter_id").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to count chapter pages: %w", err)
+	}
+
+	for _, row := range rows {
+		counts[row.ChapterID] = row.Pages
+	}
+	return counts, nil
+}
+
+// FindImageURLByContentHash returns the image of a page of the manga with the given content, or "" if none
+func (r *ChapterPageRepositoryImpl) FindImageURLByContentHash(mangaID, contentSHA256 string) (string, error) {
+	var imageURLs []string
+	err := r.db.Model(&entities.ChapterPage{}).
+		Joins("JOIN manga_chapters ON manga_chapters.chapter_id = chapter_pages.chapter_id").
+		Where("manga_chapters.manga_id = ? AND chapter_pages.content_sha256 = ?", mangaID, contentSHA256).
+		Limit(1).
+		Pluck("chapter_pages.image_url", &imageURLs).Error
+	if err != nil {
+		return "", fmt.Errorf("failed to find page by content hash: %w", err)
+	}
+	if len(imageURLs) == 0 {
+		return "", nil
+	}
+	return imageURLs[0], nil
+}
+
+// OnlyInWatermarkedChapters reports whether the object is a page of watermarked chapters of the manga and of no
+// other chapter. Pages are matched by object key, ignoring the URL they were stored with
+func (r *ChapterPageRepositoryImpl) OnlyInWatermarkedChapters(mangaID, objectName string) (bool, error) {
+	var row struct {
+		Watermarked int64
+		Plain       int64
+	}
+	suffix := "/" + objectName
+	err := r.db.Model(&entities.ChapterPage{}).
+		Select("COUNT(manga_chapters.watermark_group_id) AS watermarked, "+
+			"SUM(manga_chapters.watermark_group_id IS NULL) AS plain").
+		Joins("JOIN manga_chapters ON manga_chapters.chapter_id = chapter_pages.chapter_id").
+		Where("manga_chapters.manga_id = ? AND RIGHT(chapter_pages.image_url, CHAR_LENGTH(?)) = ?", mangaID, suffix, suffix).
+		Scan(&row).Error
+	if err != nil {
+		return false, fmt.Errorf("failed to check watermarked pages: %w", err)
+	}
+	return row.Watermarked > 0 && row.Plain == 0, nil
+}
+
+// ListSimilar returns the pages of the manga whose perceptual hash is within maxDistance bits of hash
+func (r *ChapterPageRepositoryImpl) ListSimilar(mangaID string, hash uint64, maxDistance int) ([]entities.ChapterPage, error) {
+	var pages []entities.ChapterPage
+	err := r.db.Select("chapter_pages.*").
+		Joins("JOIN manga_chapters ON manga_chapters.chapter_id = chapter_pages.chapter_id").
+		Where("manga_chapters.manga_id = ? AND chapter_pages.perceptual_hash IS NOT NULL", mangaID).
+		Where("BIT_COUNT(chapter_pages.perceptual_hash ^ ?) <= ?", hash, maxDistance).
+		Find(&pages).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to find similar pages: %w", err)
+	}
+	return pages, nil
+}
+
+// lockChapterPages locks the chapter row for the rest of the transaction, serializing page
+// numbering per chapter, and returns the current highest page number
+func lockChapterPages(tx *gorm.DB, chapterID string) (int, error) {
+	var chapter entities.MangaChapter
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("chapter_id = ?", chapterID).First(&chapter).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return 0, fmt.Errorf("chapter not found")
+		}
+		return 0, fmt.Errorf("failed to lock chapter: %w", err)
+	}
+
+	var maxPage int
+	err = tx.Model(&entities.ChapterPage{}).
+		Where("chapter_id = ?", chapterID).
+		Select("COALESCE(MAX(page_number), 0)").
+		Scan(&maxPage).Error
+	if err != nil {
+		return 0, fmt.Errorf("failed to get max page number: %w", err)
+	}
+	return maxPage, nil
+}
@@ -0,0 +1,68 @@
+package repo
+
+import (
+	"fmt"
+	"hotaku-api/internal/domain/entities"
+	"hotaku-api/internal/repoinf"
+
+	"gorm.io/gorm"
+)
+
+// GroupRepositoryImpl implements the group repository interface
+type GroupRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewGroupRepository creates a new instance of GroupRepositoryImpl
+func NewGroupRepository(db *gorm.DB) repoinf.GroupRepository {
+	return &GroupRepositoryImpl{db: db}
+}
+
+// ListByUser retrieves the groups the user belongs to
+func (r *GroupRepositoryImpl) ListByUser(userID string) ([]entities.Group, error) {
+	var groups []entities.Group
+	err := r.db.Joins("JOIN users_groups ON users_groups.group_id = `groups`.group_id").
+		Where("users_groups.user_id = ?", userID).
+		Order("`groups`.group_name ASC").
+		Find(&groups).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve user groups: %w", err)
+	}
+	return groups, nil
+}
+
+// IsMember checks if the user belongs to the group
+func (r *GroupRepositoryImpl) IsMember(groupID, userID string) (bool, error) {
+	var count int64
+	if err := r.db.Table("users_groups").Where("group_id = ? AND user_id = ?", groupID, userID).Count(&count).Error; err != nil {
+		return false, fmt.Errorf("failed to check group membership: %w", err)
+	}
+	return count > 0, nil
+}
+
+// IsCredited checks if the group is credited on the manga
+func (r *GroupRepositoryImpl) IsCredited(groupID, mangaID string) (bool, error) {
+	var count int64
+	if err := r.db.Table("mangas_groups").Where("group_id = ? AND manga_id = ?", groupID, mangaID).Count(&count).Error; err != nil {
+		return false, fmt.Errorf("failed to check group credit: %w", err)
+	}
+	return count > 0, nil
+}
+
+// FindUserGroupForManga returns a group of the user credited on the manga, or an empty ID if there is none
+func (r *GroupRepositoryImpl) FindUserGroupForManga(userID, mangaID string) (string, error) {
+	var groupIDs []string
+	err := r.db.Table("users_groups").
+		Joins("JOIN mangas_groups ON mangas_groups.group_id = users_groups.group_id").
+		Where("users_groups.user_id = ? AND mangas_groups.manga_id = ?", userID, mangaID).
+		Order("users_groups.created_at ASC").
+		Limit(1).
+		Pluck("users_groups.group_id", &groupIDs).Error
+	if err != nil {
+		return "", fmt.Errorf("failed to find user group: %w", err)
+	}
+	if len(groupIDs) == 0 {
+		return "", nil
+	}
+	return groupIDs[0], nil
+}
@@ -0,0 +1,48 @@
+package repo
+
+import (
+	"errors"
+	"fmt"
+	"hotaku-api/internal/domain/entities"
+	"hotaku-api/internal/repoinf"
+
+	"gorm.io/gorm"
+)
+
+// GroupWatermarkRepositoryImpl implements the group watermark repository interface
+type GroupWatermarkRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewGroupWatermarkRepository creates a new instance of GroupWatermarkRepositoryImpl
+func NewGroupWatermarkRepository(db *gorm.DB) repoinf.GroupWatermarkRepository {
+	return &GroupWatermarkRepositoryImpl{db: db}
+}
+
+// GetByGroupID retrieves the watermark of a group
+func (r *GroupWatermarkRepositoryImpl) GetByGroupID(groupID string) (*entities.GroupWatermark, error) {
+	var watermark entities.GroupWatermark
+	if err := r.db.Where("group_id = ?", groupID).First(&watermark).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("group watermark not found")
+		}
+		return nil, fmt.Errorf("failed to retrieve group watermark: %w", err)
+	}
+	return &watermark, nil
+}
+
+// Save creates or replaces the watermark of a group, bumping its update time
+func (r *GroupWatermarkRepositoryImpl) Save(watermark *entities.GroupWatermark) error {
+	if err := r.db.Save(watermark).Error; err != nil {
+		return fmt.Errorf("failed to save group watermark: %w", err)
+	}
+	return nil
+}
+
+// Delete removes the watermark of a group
+func (r *GroupWatermarkRepositoryImpl) Delete(groupID string) error {
+	if err := r.db.Where("group_id = ?", groupID).Delete(&entities.GroupWatermark{}).Error; err != nil {
+		return fmt.Errorf("failed to delete group watermark: %w", err)
+	}
+	return nil
+}
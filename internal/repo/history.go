@@ -0,0 +1,116 @@
+package repo
+
+import (
+	"errors"
+	"fmt"
+	"hotaku-api/internal/domain/entities"
+	"hotaku-api/internal/repoinf"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// HistoryRepositoryImpl implements the reading history repository interface
+type HistoryRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewHistoryRepository creates a new instance of HistoryRepositoryImpl
+func NewHistoryRepository(db *gorm.DB) repoinf.HistoryRepository {
+	return &HistoryRepositoryImpl{db: db}
+}
+
+// GetLatest retrieves the entry the user opened last
+func (r *HistoryRepositoryImpl) GetLatest(userID string) (*entities.UserHistoryEntry, error) {
+	var entry entities.UserHistoryEntry
+	if err := r.db.Where("user_id = ?", userID).Order("opened_at DESC").First(&entry).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("history entry not found")
+		}
+		return nil, fmt.Errorf("failed to retrieve latest history entry: %w", err)
+	}
+	return &entry, nil
+}
+
+// Create adds an entry to the reading history
+func (r *HistoryRepositoryImpl) Create(entry *entities.UserHistoryEntry) error {
+	if err := r.db.Omit("Manga", "Chapter").Create(entry).Error; err != nil {
+		return fmt.Errorf("failed to create history entry: %w", err)
+	}
+	return nil
+}
+
+// Touch moves an entry to the given time, along with the page the user is on when set
+func (r *HistoryRepositoryImpl) Touch(entryID string, pageNumber *int, openedAt time.Time) error {
+	updates := map[string]interface{}{"opened_at": openedAt}
+	if pageNumber != nil {
+		updates["page_number"] = *pageNumber
+	}
+	err := r.db.Model(&entities.UserHistoryEntry{}).Where("entry_id = ?", entryID).Updates(updates).Error
+	if err != nil {
+		return fmt.Errorf("failed to update history entry: %w", err)
+	}
+	return nil
+}
+
+// List retrieves the reading history of a user, the latest opened first, with the manga and chapter of each entry
+func (r *HistoryRepositoryImpl) List(query *entities.HistoryQuery) (*entities.HistoryPage, error) {
+	filtered := r.db.Model(&entities.UserHistoryEntry{}).Where("user_id = ?", query.UserID)
+	if query.MangaID != nil {
+		filtered = filtered.Where("manga_id = ?", *query.MangaID)
+	}
+	filtered = filtered.Session(&gorm.Session{})
+
+	result := &entities.HistoryPage{Items: []entities.UserHistoryEntry{}}
+	if err := filtered.Count(&result.Total).Error; err != nil {
+		return nil, fmt.Errorf("failed to count history entries: %w", err)
+	}
+	if result.Total == 0 || query.Offset >= int(result.Total) {
+		return result, nil
+	}
+
+	listed := filtered.Preload("Manga.Titles").Preload("Chapter").
+		Order("opened_at DESC, entry_id ASC").
+		Offset(query.Offset)
+	if query.Limit > 0 {
+		listed = listed.Limit(query.Limit)
+	}
+	if err := listed.Find(&result.Items).Error; err != nil {
+		return nil, fmt.Errorf("failed to list history entries: %w", err)
+	}
+	return result, nil
+}
+
+// Delete removes an entry from the reading history of the user
+func (r *HistoryRepositoryImpl) Delete(userID, entryID string) error {
+	deleted := r.db.Where("entry_id = ? AND user_id = ?", entryID, userID).Delete(&entities.UserHistoryEntry{})
+	if deleted.Error != nil {
+		return fmt.Errorf("failed to delete history entry: %w", deleted.Error)
+	}
+	if deleted.RowsAffected == 0 {
+		return fmt.Errorf("history entry not found")
+	}
+	return nil
+}
+
+// Clear deletes the reading history of the user, or only its entries of a manga when set
+func (r *HistoryRepositoryImpl) Clear(userID string, mangaID *string) (int64, error) {
+	cleared := r.db.Where("user_id = ?", userID)
+	if mangaID != nil {
+		cleared = cleared.Where("manga_id = ?", *mangaID)
+	}
+	deleted := cleared.Delete(&entities.UserHistoryEntry{})
+	if deleted.Error != nil {
+		return 0, fmt.Errorf("failed to clear history: %w", deleted.Error)
+	}
+	return deleted.RowsAffected, nil
+}
+
+// SetPaused changes whether the chapters the user opens are recorded
+func (r *HistoryRepositoryImpl) SetPaused(userID string, paused bool) error {
+	err := r.db.Model(&entities.User{}).Where("user_id = ? AND deleted_flag = ?", userID, false).Update("history_paused", paused).Error
+	if err != nil {
+		return fmt.Errorf("failed to update history pause: %w", err)
+	}
+	return nil
+}
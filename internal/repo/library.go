@@ -0,0 +1,274 @@
+package repo
+
+import (
+	"errors"
+	"fmt"
+	"hotaku-api/internal/domain/entities"
+	"hotaku-api/internal/repoinf"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	// mysqlErrDuplicateEntry is the MySQL error number of an insert violating a unique key
+	mysqlErrDuplicateEntry = 1062
+	// favoriteLatestChapterExpr is when the latest chapter of a favorite manga was added
+	favoriteLatestChapterExpr = "(SELECT MAX(c.created_at) FROM manga_chapters AS c WHERE c.manga_id = f.manga_id)"
+	// shelfOrderExpr orders the built-in shelves as a reader goes through a manga, custom shelves last
+	shelfOrderExpr = "FIELD(kind, 'reading', 'plan_to_read', 'completed', 'dropped', 'custom')"
+)
+
+// LibraryRepositoryImpl implements the library repository interface
+type LibraryRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewLibraryRepository creates a new instance of LibraryRepositoryImpl
+func NewLibraryRepository(db *gorm.DB) repoinf.LibraryRepository {
+	return &LibraryRepositoryImpl{db: db}
+}
+
+// ListShelves retrieves the shelves of a user, built-in ones first and custom ones by name
+func (r *LibraryRepositoryImpl) ListShelves(userID string) ([]entities.LibraryShelf, error) {
+	var shelves []entities.LibraryShelf
+	err := r.db.Where("user_id = ?", userID).
+		Order(shelfOrderExpr).
+		Order("name ASC").
+		Find(&shelves).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve library shelves: %w", err)
+	}
+	return shelves, nil
+}
+
+// CreateShelves saves shelves, skipping the built-in ones the user already has
+func (r *LibraryRepositoryImpl) CreateShelves(shelves []entities.LibraryShelf) error {
+	if len(shelves) == 0 {
+		return nil
+	}
+	if err := r.db.Clauses(clause.OnConflict{DoNothing: true}).Create(&shelves).Error; err != nil {
+		return fmt.Errorf("failed to create library shelves: %w", err)
+	}
+	return nil
+}
+
+// CreateShelf saves a shelf, reporting false when the user already has a shelf with the same name
+func (r *LibraryRepositoryImpl) CreateShelf(shelf *entities.LibraryShelf) (bool, error) {
+	if err := r.db.Create(shelf).Error; err != nil {
+		var mysqlErr *mysql.MySQLError
+		if errors.As(err, &mysqlErr) && mysqlErr.Number == mysqlErrDuplicateEntry {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to create library shelf: %w", err)
+	}
+	return true, nil
+}
+
+// GetShelf retrieves a shelf by ID
+func (r *LibraryRepositoryImpl) GetShelf(shelfID string) (*entities.LibraryShelf, error) {
+	var shelf entities.LibraryShelf
+	if err := r.db.Where("shelf_id = ?", shelfID).First(&shelf).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("shelf not found")
+		}
+		return nil, fmt.Errorf("failed to retrieve library shelf: %w", err)
+	}
+	return &shelf, nil
+}
+
+// UpdateShelf saves the name of a shelf
+func (r *LibraryRepositoryImpl) UpdateShelf(shelf *entities.LibraryShelf) error {
+	if err := r.db.Model(shelf).Select("name", "updated_at").Updates(shelf).Error; err != nil {
+		return fmt.Errorf("failed to update library shelf: %w", err)
+	}
+	return nil
+}
+
+// DeleteShelf removes a shelf, its favorites staying in the library without a shelf
+func (r *LibraryRepositoryImpl) DeleteShelf(shelfID string) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(&entities.UserFavoriteManga{}).Where("shelf_id = ?", shelfID).Update("shelf_id", nil).Error
+		if err != nil {
+			return fmt.Errorf("failed to take favorites off shelf: %w", err)
+		}
+		res := tx.Where("shelf_id = ?", shelfID).Delete(&entities.LibraryShelf{})
+		if res.Error != nil {
+			return fmt.Errorf("failed to delete library shelf: %w", res.Error)
+		}
+		if res.RowsAffected == 0 {
+			return fmt.Errorf("shelf not found")
+		}
+		return nil
+	})
+}
+
+// CountByShelf returns how many favorites of the user each shelf holds
+func (r *LibraryRepositoryImpl) CountByShelf(userID string) (map[string]int64, error) {
+	var rows []struct {
+		ShelfID string
+		Count   int64
+	}
+	err := r.db.Model(&entities.UserFavoriteManga{}).
+		Select("shelf_id, COUNT(*) AS count").
+		Where("user_id = ? AND shelf_id IS NOT NULL", userID).
+		Group("shelf_id").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to count favorites by shelf: %w", err)
+	}
+
+	counts := make(map[string]int64, len(rows))
+	for _, row := range rows {
+		counts[row.ShelfID] = row.Count
+	}
+	return counts, nil
+}
+
+// GetFavorite retrieves the favorite of a user for a manga
+func (r *LibraryRepositoryImpl) GetFavorite(userID, mangaID string) (*entities.UserFavoriteManga, error) {
+	var favorite entities.UserFavoriteManga
+	if err := r.db.Where("user_id = ? AND manga_id = ?", userID, mangaID).First(&favorite).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("favorite not found")
+		}
+		return nil, fmt.Errorf("failed to retrieve favorite: %w", err)
+	}
+	return &favorite, nil
+}
+
+// AddFavorite saves a new favorite
+func (r *LibraryRepositoryImpl) AddFavorite(favorite *entities.UserFavoriteManga) error {
+	if err := r.db.Create(favorite).Error; err != nil {
+		return fmt.Errorf("failed to add favorite: %w", err)
+	}
+	return nil
+}
+
+// UpdateFavoriteShelf moves a favorite to a shelf, nil taking it off its shelf
+func (r *LibraryRepositoryImpl) UpdateFavoriteShelf(favoriteID string, shelfID *string) error {
+	err := r.db.Model(&entities.UserFavoriteManga{}).Where("favorite_id = ?", favoriteID).Update("shelf_id", shelfID).Error
+	if err != nil {
+		return fmt.Errorf("failed to update favorite shelf: %w", err)
+	}
+	return nil
+}
+
+// DeleteFavorite removes a manga from a user's library
+func (r *LibraryRepositoryImpl) DeleteFavorite(userID, mangaID string) error {
+	res := r.db.Where("user_id = ? AND manga_id = ?", userID, mangaID).Delete(&entities.UserFavoriteManga{})
+	if res.Error != nil {
+		return fmt.Errorf("failed to delete favorite: %w", res.Error)
+	}
+	if res.RowsAffected == 0 {
+		return fmt.Errorf("favorite not found")
+	}
+	return nil
+}
+
+// List retrieves a page of a user's library with the chapter counts of its mangas, how many of them the user
+// hasn't read and when their latest chapter was added
+func (r *LibraryRepositoryImpl) List(query *entities.LibraryQuery) (*entities.LibraryResult, error) {
+	filtered := r.db.Table("user_favorite_mangas AS f").Where("f.user_id = ?", query.UserID)
+	switch {
+	case query.NoShelf:
+		filtered = filtered.Where("f.shelf_id IS NULL")
+	case query.ShelfID != nil:
+		filtered = filtered.Where("f.shelf_id = ?", *query.ShelfID)
+	}
+	filtered = filtered.Session(&gorm.Session{})
+
+	result := &entities.LibraryResult{Items: []entities.LibraryItem{}}
+	if err := filtered.Count(&result.Total).Error; err != nil {
+		return nil, fmt.Errorf("failed to count favorites: %w", err)
+	}
+	if result.Total == 0 || query.Offset >= int(result.Total) {
+		return result, nil
+	}
+
+	direction := "ASC"
+	if query.Descending {
+		direction = "DESC"
+	}
+	order := "f.added_at " + direction
+	if query.Sort == entities.LibrarySortUpdated {
+		// Mangas without chapters stay last whichever the direction
+		order = favoriteLatestChapterExpr + " IS NULL, " + favoriteLatestChapterExpr + " " + direction
+	}
+
+	var favorites []entities.UserFavoriteManga
+	err := filtered.Select("f.*").
+		Order(order + ", f.favorite_id ASC").
+		Offset(query.Offset).
+		Limit(query.Limit).
+		Scan(&favorites).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to list favorites: %w", err)
+	}
+	if len(favorites) == 0 {
+		return result, nil
+	}
+
+	ids := make([]string, 0, len(favorites))
+	for _, favorite := range favorites {
+		ids = append(ids, favorite.MangaID)
+	}
+	var mangas []entities.Manga
+	err = r.db.Where("manga_id IN ?", ids).
+		Preload("Authors").
+		Preload("PrimaryCover", "is_primary = ?", true).
+		Preload("Titles").
+		Preload("Descriptions").
+		Find(&mangas).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve favorite mangas: %w", err)
+	}
+	var stats []struct {
+		MangaID         string
+		ChapterCount    int64
+		ReadCount       int64
+		LatestChapterAt *time.Time
+	}
+	err = r.db.Table("manga_chapters AS c").
+		Select("c.manga_id, COUNT(DISTINCT c.chapter_number) AS chapter_count, MAX(c.created_at) AS latest_chapter_at, "+
+			"COUNT(DISTINCT IF(urc.chapter_id IS NULL, NULL, c.chapter_number)) AS read_count").
+		Joins("LEFT JOIN user_read_chapters AS urc ON urc.chapter_id = c.chapter_id AND urc.user_id = ?", query.UserID).
+		Where("c.manga_id IN ?", ids).
+		Group("c.manga_id").
+		Scan(&stats).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to count chapters of favorite mangas: %w", err)
+	}
+
+	byID := make(map[string]entities.Manga, len(mangas))
+	for _, manga := range mangas {
+		byID[manga.MangaID] = manga
+	}
+	for _, favorite := range favorites {
+		manga, ok := byID[favorite.MangaID]
+		if !ok {
+			continue
+		}
+		item := entities.LibraryItem{Favorite: favorite, Manga: manga}
+		for _, stat := range stats {
+			if stat.MangaID == favorite.MangaID {
+				item.ChapterCount = stat.ChapterCount
+				item.UnreadCount = stat.ChapterCount - stat.ReadCount
+				item.LatestChapterAt = stat.LatestChapterAt
+			}
+		}
+		result.Items = append(result.Items, item)
+	}
+	return result, nil
+}
+
+// SetPublic changes whether anyone may see the user's library
+func (r *LibraryRepositoryImpl) SetPublic(userID string, public bool) error {
+	err := r.db.Model(&entities.User{}).Where("user_id = ? AND deleted_flag = ?", userID, false).Update("library_public", public).Error
+	if err != nil {
+		return fmt.Errorf("failed to update library visibility: %w", err)
+	}
+	return nil
+}
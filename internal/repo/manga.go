@@ -0,0 +1,67 @@
+package repo
+
+import (
+	"errors"
+	"fmt"
+	"hotaku-api/internal/domain/entities"
+	"hotaku-api/internal/repoinf"
+
+	"gorm.io/gorm"
+)
+
+// MangaRepositoryImpl implements the manga repository interface
+type MangaRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewMangaRepository creates a new instance of MangaRepositoryImpl
+func NewMangaRepository(db *gorm.DB) repoinf.MangaRepository {
+	return &MangaRepositoryImpl{db: db}
+}
+
+// GetByID retrieves a manga by ID along with its authors, primary cover and localized titles and descriptions
+func (r *MangaRepositoryImpl) GetByID(id string) (*entities.Manga, error) {
+	var manga entities.Manga
+	if err := r.db.Where("manga_id = ?", id).Preload("Authors").Preload("PrimaryCover", "is_primary = ?", true).Preload("Titles").Preload("Descriptions").First(&manga).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("manga not found")
+		}
+		return nil, fmt.Errorf("failed to retrieve manga by ID: %w", err)
+	}
+	return &manga, nil
+}
+
+// ListWithoutCovers retrieves the mangas that have no cover recorded
+func (r *MangaRepositoryImpl) ListWithoutCovers() ([]entities.Manga, error) {
+	var mangas []entities.Manga
+	err := r.db.Where("NOT EXISTS (SELECT 1 FROM manga_covers AS c WHERE c.manga_id = mangas.manga_id)").
+		Find(&mangas).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve mangas without covers: %w", err)
+	}
+	return mangas, nil
+}
+
+// UpdateYear sets the year a manga was first published, nil clearing it
+func (r *MangaRepositoryImpl) UpdateYear(id string, year *int) error {
+	res := r.db.Model(&entities.Manga{}).Where("manga_id = ?", id).Update("year", year)
+	if res.Error != nil {
+		return fmt.Errorf("failed to update manga year: %w", res.Error)
+	}
+	if res.RowsAffected == 0 {
+		return fmt.Errorf("manga not found")
+	}
+	return nil
+}
+
+// UpdateVisibility changes who may view the images of a manga
+func (r *MangaRepositoryImpl) UpdateVisibility(id, visibility string) error {
+	res := r.db.Model(&entities.Manga{}).Where("manga_id = ?", id).Update("visibility", visibility)
+	if res.Error != nil {
+		return fmt.Errorf("failed to update manga visibility: %w", res.Error)
+	}
+	if res.RowsAffected == 0 {
+		return fmt.Errorf("manga not found")
+	}
+	return nil
+}
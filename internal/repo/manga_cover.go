@@ -0,0 +1,121 @@
+package repo
+
+import (
+	"errors"
+	"fmt"
+	"hotaku-api/internal/domain/entities"
+	"hotaku-api/internal/repoinf"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// MangaCoverRepositoryImpl implements the manga cover repository interface
+type MangaCoverRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewMangaCoverRepository creates a new instance of MangaCoverRepositoryImpl
+func NewMangaCoverRepository(db *gorm.DB) repoinf.MangaCoverRepository {
+	return &MangaCoverRepositoryImpl{db: db}
+}
+
+// Create saves a cover, making it primary if requested or if the manga has no cover yet
+func (r *MangaCoverRepositoryImpl) Create(cover *entities.MangaCover) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := lockManga(tx, cover.MangaID); err != nil {
+			return err
+		}
+
+		var count int64
+		if err := tx.Model(&entities.MangaCover{}).Where("manga_id = ?", cover.MangaID).Count(&count).Error; err != nil {
+			return fmt.Errorf("failed to count manga covers: %w", err)
+		}
+		if count == 0 {
+			cover.IsPrimary = true
+		}
+
+		if cover.IsPrimary {
+			if err := clearPrimaryCover(tx, cover.MangaID); err != nil {
+				return err
+			}
+		}
+		if err := tx.Create(cover).Error; err != nil {
+			return fmt.Errorf("failed to create manga cover: %w", err)
+		}
+		return nil
+	})
+}
+
+// GetByID retrieves a manga cover by ID
+func (r *MangaCoverRepositoryImpl) GetByID(coverID string) (*entities.MangaCover, error) {
+	var cover entities.MangaCover
+	if err := r.db.Where("cover_id = ?", coverID).First(&cover).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("cover not found")
+		}
+		return nil, fmt.Errorf("failed to retrieve manga cover: %w", err)
+	}
+	return &cover, nil
+}
+
+// ListByManga retrieves all covers of a manga, primary first and then by volume
+func (r *MangaCoverRepositoryImpl) ListByManga(mangaID string) ([]entities.MangaCover, error) {
+	var covers []entities.MangaCover
+	err := r.db.Where("manga_id = ?", mangaID).
+		Order("is_primary DESC").
+		Order("volume IS NULL, volume ASC").
+		Order("created_at ASC").
+		Find(&covers).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve manga covers: %w", err)
+	}
+	return covers, nil
+}
+
+// SetPrimary makes the cover the primary cover of its manga
+func (r *MangaCoverRepositoryImpl) SetPrimary(mangaID, coverID string) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := lockManga(tx, mangaID); err != nil {
+			return err
+		}
+		if err := clearPrimaryCover(tx, mangaID); err != nil {
+			return err
+		}
+
+		res := tx.Model(&entities.MangaCover{}).
+			Where("cover_id = ? AND manga_id = ?", coverID, mangaID).
+			Update("is_primary", true)
+		if res.Error != nil {
+			return fmt.Errorf("failed to set primary cover: %w", res.Error)
+		}
+		if res.RowsAffected == 0 {
+			return fmt.Errorf("cover not found")
+		}
+		return nil
+	})
+}
+
+// lockManga locks the manga row for the rest of the transaction, serializing cover changes
+func lockManga(tx *gorm.DB, mangaID string) error {
+	var manga entities.Manga
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("manga_id = ?", mangaID).First(&manga).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return fmt.Errorf("manga not found")
+		}
+		return fmt.Errorf("failed to lock manga: %w", err)
+	}
+	return nil
+}
+
+// clearPrimaryCover unsets the current primary cover of a manga
+func clearPrimaryCover(tx *gorm.DB, mangaID string) error {
+	err := tx.Model(&entities.MangaCover{}).
+		Where("manga_id = ? AND is_primary = ?", mangaID, true).
+		Update("is_primary", false).Error
+	if err != nil {
+		return fmt.Errorf("failed to clear primary cover: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,176 @@
+package repo
+
+import (
+	"fmt"
+	"hotaku-api/internal/domain/entities"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const (
+	// latestChapterExpr is when the latest chapter of a listed manga was added, resolved from idx_manga_chapters_manga_id_created_at
+	latestChapterExpr = "(SELECT MAX(c.created_at) FROM manga_chapters AS c WHERE c.manga_id = m.manga_id)"
+	// chapterCountExpr is how many chapters a listed manga has, versions of a chapter in several languages or
+	// by several groups counting once
+	chapterCountExpr = "(SELECT COUNT(DISTINCT c.chapter_number) FROM manga_chapters AS c WHERE c.manga_id = m.manga_id)"
+)
+
+// refreshPopularitySQL recomputes how popular every manga is: its distinct readers plus its favorites, weighted as
+// in suggestions. Mangas added since the last refresh count as not popular until the next one
+var refreshPopularitySQL = fmt.Sprintf("INSERT INTO manga_popularity (manga_id, readers, favorites, score) "+
+	"SELECT m.manga_id, COALESCE(r.readers, 0), COALESCE(f.favorites, 0), COALESCE(f.favorites, 0) * %d + COALESCE(r.readers, 0) "+
+	"FROM mangas AS m "+
+	"LEFT JOIN (SELECT c.manga_id, COUNT(DISTINCT urc.user_id) AS readers FROM user_read_chapters AS urc "+
+	"JOIN manga_chapters AS c ON c.chapter_id = urc.chapter_id GROUP BY c.manga_id) AS r ON r.manga_id = m.manga_id "+
+	"LEFT JOIN (SELECT manga_id, COUNT(*) AS favorites FROM user_favorite_mangas GROUP BY manga_id) AS f ON f.manga_id = m.manga_id "+
+	"ON DUPLICATE KEY UPDATE readers = VALUES(readers), favorites = VALUES(favorites), score = VALUES(score)",
+	entities.MangaFavoritePopularity)
+
+// mangaChapterStats is the chapter count and latest chapter of a listed manga
+type mangaChapterStats struct {
+	MangaID         string
+	ChapterCount    int64
+	LatestChapterAt *time.Time
+}
+
+// List returns a page of mangas matching the query, along with the total count of matches
+func (r *MangaRepositoryImpl) List(query *entities.MangaListQuery) (*entities.MangaListResult, error) {
+	filtered := r.db.Table("mangas AS m")
+	filtered = filterMangaRefs(filtered, query.Categories, "mangas_categories", "category_id")
+	filtered = filterMangaRefs(filtered, query.Authors, "mangas_authors", "author_id")
+	filtered = filterMangaRefs(filtered, query.Groups, "mangas_groups", "group_id")
+	if len(query.StatusIDs) > 0 {
+		filtered = filtered.Where("m.status_id IN ?", query.StatusIDs)
+	}
+	if len(query.ExcludedStatusIDs) > 0 {
+		filtered = filtered.Where("m.status_id NOT IN ?", query.ExcludedStatusIDs)
+	}
+	if query.YearFrom != nil {
+		filtered = filtered.Where("m.year >= ?", *query.YearFrom)
+	}
+	if query.YearTo != nil {
+		filtered = filtered.Where("m.year <= ?", *query.YearTo)
+	}
+	if query.ChaptersMin != nil {
+		filtered = filtered.Where(chapterCountExpr+" >= ?", *query.ChaptersMin)
+	}
+	if query.ChaptersMax != nil {
+		filtered = filtered.Where(chapterCountExpr+" <= ?", *query.ChaptersMax)
+	}
+	if query.UpdatedSince != nil {
+		filtered = filtered.Where("EXISTS (SELECT 1 FROM manga_chapters AS c WHERE c.manga_id = m.manga_id AND c.created_at >= ?)", *query.UpdatedSince)
+	}
+
+	// A new session lets the count and page queries both start from the filters
+	filtered = filtered.Session(&gorm.Session{})
+
+	result := &entities.MangaListResult{Items: []entities.MangaListItem{}}
+	if err := filtered.Count(&result.Total).Error; err != nil {
+		return nil, fmt.Errorf("failed to count mangas: %w", err)
+	}
+	if result.Total == 0 || query.Offset >= int(result.Total) {
+		return result, nil
+	}
+
+	direction := "ASC"
+	if query.Descending {
+		direction = "DESC"
+	}
+	var order string
+	switch query.Sort {
+	case entities.MangaListSortCreated:
+		order = "m.created_at " + direction
+	case entities.MangaListSortTitle:
+		order = "m.title " + direction
+	case entities.MangaListSortPopularity:
+		order = "COALESCE(p.score, 0) " + direction
+	default:
+		// Mangas without chapters stay last whichever the direction
+		order = latestChapterExpr + " IS NULL, " + latestChapterExpr + " " + direction
+	}
+
+	page := filtered
+	if query.Sort == entities.MangaListSortPopularity {
+		page = page.Joins("LEFT JOIN manga_popularity AS p ON p.manga_id = m.manga_id")
+	}
+
+	var ids []string
+	err := page.Order(order+", m.manga_id ASC").
+		Offset(query.Offset).
+		Limit(query.Limit).
+		Pluck("m.manga_id", &ids).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to list mangas: %w", err)
+	}
+	if len(ids) == 0 {
+		return result, nil
+	}
+
+	var mangas []entities.Manga
+	err = r.db.Where("manga_id IN ?", ids).
+		Preload("Authors").
+		Preload("PrimaryCover", "is_primary = ?", true).
+		Preload("Titles").
+		Preload("Descriptions").
+		Find(&mangas).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve listed mangas: %w", err)
+	}
+	var stats []mangaChapterStats
+	err = r.db.Table("manga_chapters").
+		Select("manga_id, COUNT(DISTINCT chapter_number) AS chapter_count, MAX(created_at) AS latest_chapter_at").
+		Where("manga_id IN ?", ids).
+		Group("manga_id").
+		Scan(&stats).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to count chapters of listed mangas: %w", err)
+	}
+
+	byID := make(map[string]entities.Manga, len(mangas))
+	for _, manga := range mangas {
+		byID[manga.MangaID] = manga
+	}
+	statsByID := make(map[string]mangaChapterStats, len(stats))
+	for _, stat := range stats {
+		statsByID[stat.MangaID] = stat
+	}
+	for _, id := range ids {
+		// A manga deleted in the meantime is left out of the page
+		manga, ok := byID[id]
+		if !ok {
+			continue
+		}
+		stat := statsByID[id]
+		result.Items = append(result.Items, entities.MangaListItem{
+			Manga:           manga,
+			ChapterCount:    stat.ChapterCount,
+			LatestChapterAt: stat.LatestChapterAt,
+		})
+	}
+	return result, nil
+}
+
+// RefreshPopularity recomputes the popularity listings are sorted by
+func (r *MangaRepositoryImpl) RefreshPopularity() error {
+	if err := r.db.Exec(refreshPopularitySQL).Error; err != nil {
+		return fmt.Errorf("failed to refresh manga popularity: %w", err)
+	}
+	return nil
+}
+
+// filterMangaRefs narrows a manga listing to the mangas linked to all or any of the included IDs through a join
+// table, and none of the excluded ones. Both go through the join table's ID index, then its primary key
+func filterMangaRefs(filtered *gorm.DB, filter entities.MangaRefFilter, table, idColumn string) *gorm.DB {
+	switch {
+	case len(filter.Include) > 0 && filter.MatchAll:
+		filtered = filtered.Where(fmt.Sprintf("m.manga_id IN (SELECT manga_id FROM %s WHERE %s IN ? GROUP BY manga_id HAVING COUNT(*) = ?)", table, idColumn),
+			filter.Include, len(filter.Include))
+	case len(filter.Include) > 0:
+		filtered = filtered.Where(fmt.Sprintf("m.manga_id IN (SELECT manga_id FROM %s WHERE %s IN ?)", table, idColumn), filter.Include)
+	}
+	if len(filter.Exclude) > 0 {
+		filtered = filtered.Where(fmt.Sprintf("NOT EXISTS (SELECT 1 FROM %[1]s AS x WHERE x.manga_id = m.manga_id AND x.%[2]s IN ?)", table, idColumn), filter.Exclude)
+	}
+	return filtered
+}
@@ -0,0 +1,177 @@
+package repo
+
+import (
+	"fmt"
+	"hotaku-api/internal/domain/entities"
+	"hotaku-api/internal/repoinf"
+
+	"gorm.io/gorm"
+)
+
+// MangaSearchRepositoryImpl implements the manga search repository interface with MySQL full-text indexes
+type MangaSearchRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewMangaSearchRepository creates a new instance of MangaSearchRepositoryImpl
+func NewMangaSearchRepository(db *gorm.DB) repoinf.MangaSearchRepository {
+	return &MangaSearchRepositoryImpl{db: db}
+}
+
+// maxFacetValues is how many values of each facet are counted, the most frequent first
+const maxFacetValues = 20
+
+// mangaSearchScore is a matching manga ID along with its relevance
+type mangaSearchScore struct {
+	MangaID string
+	Score   float64
+}
+
+// Search returns a page of mangas matching the query, along with the total count and facets of the matches
+func (r *MangaSearchRepositoryImpl) Search(query *entities.MangaSearchQuery) (*entities.MangaSearchResult, error) {
+	// MATCH only uses a FULLTEXT index whose columns are exactly the ones listed
+	columns := "title"
+	if query.WithDescription {
+		columns = "title, description"
+	}
+	modifier := "IN NATURAL LANGUAGE MODE"
+	if query.Mode == entities.MangaSearchModeBoolean {
+		modifier = "IN BOOLEAN MODE"
+	}
+	// Localized titles and descriptions live in their own tables, each with its own FULLTEXT index. Each table is
+	// matched on its own so every MATCH goes through its index, then a manga scores as its best match
+	match := fmt.Sprintf("MATCH (%s) AGAINST (? %s)", columns, modifier)
+	titleMatch := fmt.Sprintf("MATCH (title) AGAINST (? %s)", modifier)
+	candidates := "SELECT manga_id, " + match + " AS score FROM mangas WHERE " + match +
+		" UNION ALL SELECT manga_id, " + titleMatch + " AS score FROM manga_titles WHERE " + titleMatch
+	args := []interface{}{query.Text, query.Text, query.Text, query.Text}
+	if query.WithDescription {
+		descriptionMatch := fmt.Sprintf("MATCH (description) AGAINST (? %s)", modifier)
+		candidates += " UNION ALL SELECT manga_id, " + descriptionMatch + " AS score FROM manga_descriptions WHERE " + descriptionMatch
+		args = append(args, query.Text, query.Text)
+	}
+
+	filtered := r.db.Model(&entities.Manga{}).
+		Joins("JOIN (SELECT manga_id, MAX(score) AS score FROM ("+candidates+") AS matches GROUP BY manga_id) AS s "+
+			"ON s.manga_id = mangas.manga_id", args...)
+	if query.StatusID != 0 {
+		filtered = filtered.Where("mangas.status_id = ?", query.StatusID)
+	}
+	if query.CategoryID != "" {
+		filtered = filtered.Where("mangas.manga_id IN (SELECT manga_id FROM mangas_categories WHERE category_id = ?)", query.CategoryID)
+	}
+	if query.AuthorID != "" {
+		filtered = filtered.Where("mangas.manga_id IN (SELECT manga_id FROM mangas_authors WHERE author_id = ?)", query.AuthorID)
+	}
+	if query.GroupID != "" {
+		filtered = filtered.Where("mangas.manga_id IN (SELECT manga_id FROM mangas_groups WHERE group_id = ?)", query.GroupID)
+	}
+
+	// A new session lets the count, facet and page queries all start from the filters
+	filtered = filtered.Session(&gorm.Session{})
+
+	result := &entities.MangaSearchResult{Hits: []entities.MangaSearchHit{}}
+	if err := filtered.Count(&result.Total).Error; err != nil {
+		return nil, fmt.Errorf("failed to count manga search results: %w", err)
+	}
+	if result.Total == 0 {
+		return result, nil
+	}
+
+	facets, err := r.facets(filtered)
+	if err != nil {
+		return nil, err
+	}
+	result.Facets = *facets
+
+	order := "s.score DESC, mangas.created_at DESC"
+	if query.Sort == entities.MangaSearchSortRecent {
+		order = "mangas.created_at DESC, s.score DESC"
+	}
+	var scores []mangaSearchScore
+	err = filtered.Select("mangas.manga_id, s.score").
+		Order(order).
+		Offset(query.Offset).
+		Limit(query.Limit).
+		Scan(&scores).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to search mangas: %w", err)
+	}
+
+	if result.Hits, err = loadMangaSearchHits(r.db, scores); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// facets counts the mangas matched by filtered by status, category, author and group
+func (r *MangaSearchRepositoryImpl) facets(filtered *gorm.DB) (*entities.MangaSearchFacets, error) {
+	matched := filtered.Select("mangas.manga_id")
+	count := func(table, idColumn, namesTable, nameColumn string) ([]entities.MangaSearchFacet, error) {
+		var facets []entities.MangaSearchFacet
+		err := r.db.Table(table+" AS t").
+			Select(fmt.Sprintf("CAST(t.%[1]s AS CHAR) AS value, n.%[2]s AS name, COUNT(*) AS count", idColumn, nameColumn)).
+			Joins(fmt.Sprintf("JOIN %[1]s AS n ON n.%[2]s = t.%[2]s", namesTable, idColumn)).
+			Where("t.manga_id IN (?)", matched).
+			Group("t." + idColumn + ", n." + nameColumn).
+			Order("count DESC, name ASC").
+			Limit(maxFacetValues).
+			Scan(&facets).Error
+		if err != nil {
+			return nil, fmt.Errorf("failed to count manga search facets: %w", err)
+		}
+		return facets, nil
+	}
+
+	var facets entities.MangaSearchFacets
+	var err error
+	if facets.Statuses, err = count("mangas", "status_id", "manga_statuses", "status_name"); err != nil {
+		return nil, err
+	}
+	if facets.Categories, err = count("mangas_categories", "category_id", "categories", "category_name"); err != nil {
+		return nil, err
+	}
+	if facets.Authors, err = count("mangas_authors", "author_id", "authors", "author_name"); err != nil {
+		return nil, err
+	}
+	if facets.Groups, err = count("mangas_groups", "group_id", "`groups`", "group_name"); err != nil {
+		return nil, err
+	}
+	return &facets, nil
+}
+
+// loadMangaSearchHits loads the mangas of a page of search results, with their authors, primary cover and localized titles,
+// keeping the order of the scores
+func loadMangaSearchHits(db *gorm.DB, scores []mangaSearchScore) ([]entities.MangaSearchHit, error) {
+	if len(scores) == 0 {
+		return []entities.MangaSearchHit{}, nil
+	}
+
+	ids := make([]string, len(scores))
+	for i, score := range scores {
+		ids[i] = score.MangaID
+	}
+	var mangas []entities.Manga
+	err := db.Where("manga_id IN ?", ids).
+		Preload("Authors").
+		Preload("PrimaryCover", "is_primary = ?", true).
+		Preload("Titles").
+		Preload("Descriptions").
+		Find(&mangas).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve manga search results: %w", err)
+	}
+
+	byID := make(map[string]entities.Manga, len(mangas))
+	for _, manga := range mangas {
+		byID[manga.MangaID] = manga
+	}
+	hits := make([]entities.MangaSearchHit, 0, len(scores))
+	for _, score := range scores {
+		// A manga deleted in the meantime is left out of the page
+		if manga, ok := byID[score.MangaID]; ok {
+			hits = append(hits, entities.MangaSearchHit{Manga: manga, Score: score.Score})
+		}
+	}
+	return hits, nil
+}
@@ -0,0 +1,933 @@
+package repo
+
+import (
+	"encoding/gob"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"hotaku-api/internal/domain/entities"
+	"hotaku-api/internal/repoinf"
+	"hotaku-api/utils"
+
+	"gorm.io/gorm"
+)
+
+// Fields of a manga an indexed word was found in
+const (
+	fieldTitle uint8 = 1 << iota
+	fieldAuthor
+	fieldDescription
+)
+
+const (
+	// searchIndexSnapshotVersion is bumped whenever the snapshot layout changes, older snapshots are then ignored
+	searchIndexSnapshotVersion = 2
+	// searchIndexSyncSkew re-reads rows updated shortly before the last sync, in case the clocks of the
+	// database and the API drift apart
+	searchIndexSyncSkew = time.Minute
+	// searchIndexBatchSize is how many mangas are loaded per query while syncing
+	searchIndexBatchSize = 500
+
+	// prefixMatchWeight scores a word completing a prefix term, relative to an exact match
+	prefixMatchWeight = 0.8
+	// typoPenalty lowers the score of a word for each typo it takes to match a term
+	typoPenalty = 0.25
+	// maxTypoWords caps how many indexed words a term matches with typos, the closest ones being kept
+	maxTypoWords = 50
+)
+
+// indexedRef is an author, category or group of an indexed manga
+type indexedRef struct {
+	ID   string
+	Name string
+}
+
+// indexedManga is what the search index knows of a manga
+type indexedManga struct {
+	MangaID     string
+	Title       string
+	Description string
+	// AltTitles and AltDescriptions are the localized titles and descriptions
+	AltTitles       []string
+	AltDescriptions []string
+	StatusID        uint
+	StatusName      string
+	Authors         []indexedRef
+	Categories      []indexedRef
+	Groups          []indexedRef
+	CreatedAt       time.Time
+}
+
+// searchIndexSnapshot is the content of a file written by Save
+type searchIndexSnapshot struct {
+	Version  int
+	SyncedAt time.Time
+	Mangas   []indexedManga
+}
+
+// indexTerm is a word of a search query
+type indexTerm struct {
+	word     string
+	required bool
+	excluded bool
+	prefix   bool
+}
+
+// MangaSearchIndexImpl implements the manga search index interface with an inverted index held in memory.
+// Words are folded so diacritics don't matter, may contain typos, and the last word of a natural query
+// matches the words it starts so results follow what is being typed
+type MangaSearchIndexImpl struct {
+	db *gorm.DB
+
+	// syncMu keeps syncs and rebuilds from running at the same time, mu guards the index itself
+	syncMu sync.Mutex
+	mu     sync.RWMutex
+
+	mangas map[string]*indexedManga
+	// postings maps each word to the mangas containing it and the fields it was found in
+	postings map[string]map[string]uint8
+	// vocabulary holds every indexed word in sorted order for prefix and typo lookups
+	vocabulary []string
+	// bigrams and lengths map the distinct character pairs and the length of the vocabulary words to their
+	// positions, narrowing down the words compared with a term for typos
+	bigrams  map[string][]int32
+	lengths  map[int][]int32
+	syncedAt time.Time
+}
+
+// NewMangaSearchIndex creates a new, empty instance of MangaSearchIndexImpl
+func NewMangaSearchIndex(db *gorm.DB) repoinf.MangaSearchIndex {
+	return &MangaSearchIndexImpl{
+		db:       db,
+		mangas:   make(map[string]*indexedManga),
+		postings: make(map[string]map[string]uint8),
+	}
+}
+
+// Search returns a page of mangas matching the query, along with the total count and facets of the matches
+func (idx *MangaSearchIndexImpl) Search(query *entities.MangaSearchQuery) (*entities.MangaSearchResult, error) {
+	terms := parseIndexQuery(query.Text, query.Mode == entities.MangaSearchModeBoolean)
+
+	idx.mu.RLock()
+	scores := idx.match(terms, query.WithDescription)
+	matched := make([]*indexedManga, 0, len(scores))
+	for id := range scores {
+		manga := idx.mangas[id]
+		if matchesSearchFilters(manga, query) {
+			matched = append(matched, manga)
+		}
+	}
+	idx.mu.RUnlock()
+
+	result := &entities.MangaSearchResult{
+		Total:  int64(len(matched)),
+		Facets: searchFacets(matched),
+	}
+
+	sort.Slice(matched, func(i, j int) bool {
+		a, b := matched[i], matched[j]
+		if query.Sort == entities.MangaSearchSortRecent && !a.CreatedAt.Equal(b.CreatedAt) {
+			return a.CreatedAt.After(b.CreatedAt)
+		}
+		if scores[a.MangaID] != scores[b.MangaID] {
+			return scores[a.MangaID] > scores[b.MangaID]
+		}
+		if !a.CreatedAt.Equal(b.CreatedAt) {
+			return a.CreatedAt.After(b.CreatedAt)
+		}
+		return a.MangaID < b.MangaID
+	})
+
+	from := min(query.Offset, len(matched))
+	to := min(from+query.Limit, len(matched))
+	page := make([]mangaSearchScore, 0, to-from)
+	for _, manga := range matched[from:to] {
+		page = append(page, mangaSearchScore{MangaID: manga.MangaID, Score: scores[manga.MangaID]})
+	}
+
+	var err error
+	if result.Hits, err = loadMangaSearchHits(idx.db, page); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// Rebuild indexes every manga from scratch. Searches keep using the previous content until it is done
+func (idx *MangaSearchIndexImpl) Rebuild() (*entities.MangaSearchIndexStats, error) {
+	idx.syncMu.Lock()
+	defer idx.syncMu.Unlock()
+	return idx.sync(true)
+}
+
+// Sync reindexes the mangas changed since the last rebuild or sync and drops the deleted ones. Mangas whose
+// authors, categories or groups were linked, unlinked or renamed are reindexed as well
+func (idx *MangaSearchIndexImpl) Sync() (*entities.MangaSearchIndexStats, error) {
+	idx.syncMu.Lock()
+	defer idx.syncMu.Unlock()
+	return idx.sync(false)
+}
+
+// Save writes a snapshot of the index to a file, replacing it only once the snapshot is complete
+func (idx *MangaSearchIndexImpl) Save(path string) error {
+	idx.mu.RLock()
+	snapshot := searchIndexSnapshot{
+		Version:  searchIndexSnapshotVersion,
+		SyncedAt: idx.syncedAt,
+		Mangas:   make([]indexedManga, 0, len(idx.mangas)),
+	}
+	for _, manga := range idx.mangas {
+		snapshot.Mangas = append(snapshot.Mangas, *manga)
+	}
+	idx.mu.RUnlock()
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("failed to create search index directory: %w", err)
+	}
+	tmpPath := path + ".tmp"
+	file, err := os.Create(tmpPath)
+	if err != nil {
+		return fmt.Errorf("failed to create search index snapshot: %w", err)
+	}
+	if err := gob.NewEncoder(file).Encode(&snapshot); err != nil {
+		file.Close()
+		os.Remove(tmpPath)
+		return fmt.Errorf("failed to write search index snapshot: %w", err)
+	}
+	if err := file.Close(); err != nil {
+		os.Remove(tmpPath)
+		return fmt.Errorf("failed to write search index snapshot: %w", err)
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		return fmt.Errorf("failed to replace search index snapshot: %w", err)
+	}
+	return nil
+}
+
+// Load replaces the index with a snapshot written by Save. The next sync catches up with the changes
+// made since the snapshot was taken
+func (idx *MangaSearchIndexImpl) Load(path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("failed to open search index snapshot: %w", err)
+	}
+	defer file.Close()
+
+	var snapshot searchIndexSnapshot
+	if err := gob.NewDecoder(file).Decode(&snapshot); err != nil {
+		return fmt.Errorf("failed to read search index snapshot: %w", err)
+	}
+	if snapshot.Version != searchIndexSnapshotVersion {
+		return fmt.Errorf("search index snapshot version %d is not supported", snapshot.Version)
+	}
+
+	idx.syncMu.Lock()
+	defer idx.syncMu.Unlock()
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	idx.mangas = make(map[string]*indexedManga, len(snapshot.Mangas))
+	idx.postings = make(map[string]map[string]uint8)
+	for i := range snapshot.Mangas {
+		idx.put(&snapshot.Mangas[i])
+	}
+	idx.refreshVocabulary()
+	idx.syncedAt = snapshot.SyncedAt
+	return nil
+}
+
+// sync loads the mangas that changed since the last sync, or all of them on a rebuild, and swaps them into the index.
+// Changes are found from timestamps: linking or unlinking an author, category or group bumps the manga's updated_at
+// through triggers, so neither the mangas nor their links are scanned again
+func (idx *MangaSearchIndexImpl) sync(rebuild bool) (*entities.MangaSearchIndexStats, error) {
+	startedAt := time.Now()
+
+	idx.mu.RLock()
+	since := idx.syncedAt
+	idx.mu.RUnlock()
+	if rebuild {
+		since = time.Time{}
+	}
+
+	query := idx.db.Model(&entities.Manga{})
+	if !since.IsZero() {
+		query = query.Where("updated_at > ?", since.Add(-searchIndexSyncSkew))
+	}
+	var changed []string
+	if err := query.Pluck("manga_id", &changed).Error; err != nil {
+		return nil, fmt.Errorf("failed to list changed mangas to index: %w", err)
+	}
+	renamed, err := idx.renamedRefs(since)
+	if err != nil {
+		return nil, err
+	}
+
+	isChanged := make(map[string]bool, len(changed))
+	for _, id := range changed {
+		isChanged[id] = true
+	}
+	added := 0
+	idx.mu.RLock()
+	indexed := len(idx.mangas)
+	for _, id := range changed {
+		if _, ok := idx.mangas[id]; !ok {
+			added++
+		}
+	}
+	for id, manga := range idx.mangas {
+		if !isChanged[id] && refersToAny(manga, renamed) {
+			changed = append(changed, id)
+		}
+	}
+	idx.mu.RUnlock()
+
+	var deleted []string
+	if !rebuild {
+		if deleted, err = idx.deletedMangas(int64(indexed + added)); err != nil {
+			return nil, err
+		}
+	}
+
+	mangas, err := idx.loadMangas(changed)
+	if err != nil {
+		return nil, err
+	}
+	// A changed manga that is gone by the time it is loaded was deleted in between
+	loaded := make(map[string]bool, len(mangas))
+	for _, manga := range mangas {
+		loaded[manga.MangaID] = true
+	}
+	for _, id := range changed {
+		if !loaded[id] {
+			deleted = append(deleted, id)
+		}
+	}
+
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+	if rebuild {
+		idx.mangas = make(map[string]*indexedManga, len(mangas))
+		idx.postings = make(map[string]map[string]uint8)
+	}
+	for _, id := range deleted {
+		idx.remove(id)
+	}
+	for _, manga := range mangas {
+		idx.put(manga)
+	}
+	if rebuild || len(deleted) > 0 || len(mangas) > 0 {
+		idx.refreshVocabulary()
+	}
+	idx.syncedAt = startedAt
+
+	return &entities.MangaSearchIndexStats{
+		Documents: len(idx.mangas),
+		Terms:     len(idx.vocabulary),
+		SyncedAt:  idx.syncedAt,
+	}, nil
+}
+
+// deletedMangas returns the indexed mangas that no longer exist. Deletions leave no timestamp behind, so the
+// manga IDs are only listed when there are fewer mangas than expected once the sync is applied
+func (idx *MangaSearchIndexImpl) deletedMangas(expected int64) ([]string, error) {
+	var count int64
+	if err := idx.db.Model(&entities.Manga{}).Count(&count).Error; err != nil {
+		return nil, fmt.Errorf("failed to count mangas to index: %w", err)
+	}
+	if count == expected {
+		return nil, nil
+	}
+
+	var ids []string
+	if err := idx.db.Model(&entities.Manga{}).Pluck("manga_id", &ids).Error; err != nil {
+		return nil, fmt.Errorf("failed to list mangas to index: %w", err)
+	}
+	existing := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		existing[id] = true
+	}
+
+	var deleted []string
+	idx.mu.RLock()
+	for id := range idx.mangas {
+		if !existing[id] {
+			deleted = append(deleted, id)
+		}
+	}
+	idx.mu.RUnlock()
+	return deleted, nil
+}
+
+// mangaLinks holds the IDs of the authors, categories and groups of a manga, sorted
+type mangaLinks struct {
+	authors    []string
+	categories []string
+	groups     []string
+}
+
+// loadLinks returns the authors, categories and groups of mangas by manga ID
+func (idx *MangaSearchIndexImpl) loadLinks(mangaIDs []string) (map[string]*mangaLinks, error) {
+	links := make(map[string]*mangaLinks)
+	load := func(table, idColumn string, ids func(*mangaLinks) *[]string) error {
+		var rows []struct {
+			MangaID string
+			ID      string
+		}
+		err := idx.db.Table(table).Select("manga_id, "+idColumn+" AS id").Where("manga_id IN ?", mangaIDs).Order(idColumn).Scan(&rows).Error
+		if err != nil {
+			return fmt.Errorf("failed to list %s to index: %w", table, err)
+		}
+		for _, row := range rows {
+			if links[row.MangaID] == nil {
+				links[row.MangaID] = &mangaLinks{}
+			}
+			list := ids(links[row.MangaID])
+			*list = append(*list, row.ID)
+		}
+		return nil
+	}
+
+	if err := load("mangas_authors", "author_id", func(l *mangaLinks) *[]string { return &l.authors }); err != nil {
+		return nil, err
+	}
+	if err := load("mangas_categories", "category_id", func(l *mangaLinks) *[]string { return &l.categories }); err != nil {
+		return nil, err
+	}
+	if err := load("mangas_groups", "group_id", func(l *mangaLinks) *[]string { return &l.groups }); err != nil {
+		return nil, err
+	}
+	return links, nil
+}
+
+// renamedRefs returns the IDs of the authors, categories and groups updated since a sync
+func (idx *MangaSearchIndexImpl) renamedRefs(since time.Time) (map[string]bool, error) {
+	renamed := make(map[string]bool)
+	if since.IsZero() {
+		return renamed, nil
+	}
+
+	for _, table := range []struct{ name, idColumn string }{
+		{"authors", "author_id"},
+		{"categories", "category_id"},
+		{"`groups`", "group_id"},
+	} {
+		var ids []string
+		err := idx.db.Table(table.name).Where("updated_at > ?", since.Add(-searchIndexSyncSkew)).Pluck(table.idColumn, &ids).Error
+		if err != nil {
+			return nil, fmt.Errorf("failed to list updated %s: %w", strings.Trim(table.name, "`"), err)
+		}
+		for _, id := range ids {
+			renamed[id] = true
+		}
+	}
+	return renamed, nil
+}
+
+// loadMangas loads the indexed content of mangas in batches, leaving out the ones that don't exist
+func (idx *MangaSearchIndexImpl) loadMangas(ids []string) ([]*indexedManga, error) {
+	mangas := make([]*indexedManga, 0, len(ids))
+	for batch := range slices.Chunk(ids, searchIndexBatchSize) {
+		var rows []struct {
+			MangaID     string
+			Title       string
+			Description *string
+			StatusID    uint
+			StatusName  *string
+			CreatedAt   time.Time
+		}
+		err := idx.db.Table("mangas AS m").
+			Select("m.manga_id, m.title, m.description, m.status_id, s.status_name, m.created_at").
+			Joins("LEFT JOIN manga_statuses AS s ON s.status_id = m.status_id").
+			Where("m.manga_id IN ?", batch).
+			Scan(&rows).Error
+		if err != nil {
+			return nil, fmt.Errorf("failed to load mangas to index: %w", err)
+		}
+
+		links, err := idx.loadLinks(batch)
+		if err != nil {
+			return nil, err
+		}
+		var authorIDs, categoryIDs, groupIDs []string
+		for _, id := range batch {
+			if l := links[id]; l != nil {
+				authorIDs = append(authorIDs, l.authors...)
+				categoryIDs = append(categoryIDs, l.categories...)
+				groupIDs = append(groupIDs, l.groups...)
+			}
+		}
+		authors, err := idx.loadNames("authors", "author_id", "author_name", authorIDs)
+		if err != nil {
+			return nil, err
+		}
+		categories, err := idx.loadNames("categories", "category_id", "category_name", categoryIDs)
+		if err != nil {
+			return nil, err
+		}
+		groups, err := idx.loadNames("`groups`", "group_id", "group_name", groupIDs)
+		if err != nil {
+			return nil, err
+		}
+		altTitles, err := idx.loadLocalized("manga_titles", "title", batch)
+		if err != nil {
+			return nil, err
+		}
+		altDescriptions, err := idx.loadLocalized("manga_descriptions", "description", batch)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, row := range rows {
+			manga := &indexedManga{
+				MangaID:         row.MangaID,
+				Title:           row.Title,
+				AltTitles:       altTitles[row.MangaID],
+				AltDescriptions: altDescriptions[row.MangaID],
+				StatusID:        row.StatusID,
+				CreatedAt:       row.CreatedAt,
+			}
+			if row.Description != nil {
+				manga.Description = *row.Description
+			}
+			if row.StatusName != nil {
+				manga.StatusName = *row.StatusName
+			}
+			if l := links[row.MangaID]; l != nil {
+				manga.Authors = toIndexedRefs(l.authors, authors)
+				manga.Categories = toIndexedRefs(l.categories, categories)
+				manga.Groups = toIndexedRefs(l.groups, groups)
+			}
+			mangas = append(mangas, manga)
+		}
+	}
+	return mangas, nil
+}
+
+// loadNames returns the names of the rows of a table by ID
+func (idx *MangaSearchIndexImpl) loadNames(table, idColumn, nameColumn string, ids []string) (map[string]string, error) {
+	names := make(map[string]string)
+	if len(ids) == 0 {
+		return names, nil
+	}
+
+	var rows []indexedRef
+	err := idx.db.Table(table).Select(idColumn+" AS id, "+nameColumn+" AS name").Where(idColumn+" IN ?", ids).Scan(&rows).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to load %s to index: %w", strings.Trim(table, "`"), err)
+	}
+	for _, row := range rows {
+		names[row.ID] = row.Name
+	}
+	return names, nil
+}
+
+// loadLocalized returns the localized titles or descriptions of mangas by manga ID
+func (idx *MangaSearchIndexImpl) loadLocalized(table, column string, mangaIDs []string) (map[string][]string, error) {
+	var rows []struct {
+		MangaID string
+		Text    string
+	}
+	err := idx.db.Table(table).Select("manga_id, "+column+" AS text").Where("manga_id IN ?", mangaIDs).Order("locale").Scan(&rows).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to load %s to index: %w", table, err)
+	}
+	texts := make(map[string][]string)
+	for _, row := range rows {
+		texts[row.MangaID] = append(texts[row.MangaID], row.Text)
+	}
+	return texts, nil
+}
+
+// put indexes a manga, replacing what was indexed for it before. The caller holds the write lock
+func (idx *MangaSearchIndexImpl) put(manga *indexedManga) {
+	idx.remove(manga.MangaID)
+	idx.mangas[manga.MangaID] = manga
+
+	for word, fields := range indexedWords(manga) {
+		if idx.postings[word] == nil {
+			idx.postings[word] = make(map[string]uint8)
+		}
+		idx.postings[word][manga.MangaID] = fields
+	}
+}
+
+// remove drops a manga from the index. The caller holds the write lock
+func (idx *MangaSearchIndexImpl) remove(mangaID string) {
+	manga, ok := idx.mangas[mangaID]
+	if !ok {
+		return
+	}
+	for word := range indexedWords(manga) {
+		delete(idx.postings[word], mangaID)
+		if len(idx.postings[word]) == 0 {
+			delete(idx.postings, word)
+		}
+	}
+	delete(idx.mangas, mangaID)
+}
+
+// refreshVocabulary sorts the indexed words again after the postings changed. The caller holds the write lock
+func (idx *MangaSearchIndexImpl) refreshVocabulary() {
+	vocabulary := make([]string, 0, len(idx.postings))
+	for word := range idx.postings {
+		vocabulary = append(vocabulary, word)
+	}
+	sort.Strings(vocabulary)
+
+	bigrams := make(map[string][]int32)
+	lengths := make(map[int][]int32)
+	for i, word := range vocabulary {
+		runes := []rune(word)
+		for _, bigram := range distinctBigrams(runes) {
+			bigrams[bigram] = append(bigrams[bigram], int32(i))
+		}
+		lengths[len(runes)] = append(lengths[len(runes)], int32(i))
+	}
+	idx.vocabulary = vocabulary
+	idx.bigrams = bigrams
+	idx.lengths = lengths
+}
+
+// match scores the mangas matching the terms. Every required term must match, excluded terms must not,
+// and when no term is required any optional term is enough. The caller holds the read lock
+func (idx *MangaSearchIndexImpl) match(terms []indexTerm, withDescription bool) map[string]float64 {
+	var required map[string]float64
+	optional := make(map[string]float64)
+	hasRequired := false
+
+	for _, term := range terms {
+		if term.excluded {
+			continue
+		}
+		scores := idx.matchTerm(term, withDescription, true)
+		if !term.required {
+			for id, score := range scores {
+				optional[id] += score
+			}
+			continue
+		}
+		if !hasRequired {
+			hasRequired = true
+			required = scores
+			continue
+		}
+		for id := range required {
+			if score, ok := scores[id]; ok {
+				required[id] += score
+			} else {
+				delete(required, id)
+			}
+		}
+	}
+
+	result := optional
+	if hasRequired {
+		result = required
+		for id := range result {
+			result[id] += optional[id]
+		}
+	}
+	for _, term := range terms {
+		if term.excluded {
+			// Typos aren't allowed in excluded words, they would hide too much
+			for id := range idx.matchTerm(term, withDescription, false) {
+				delete(result, id)
+			}
+		}
+	}
+	return result
+}
+
+// matchTerm scores the mangas containing a term: exact words score best, then words completing a prefix
+// term, then words with typos. The caller holds the read lock
+func (idx *MangaSearchIndexImpl) matchTerm(term indexTerm, withDescription, typos bool) map[string]float64 {
+	weights := make(map[string]float64)
+	if _, ok := idx.postings[term.word]; ok {
+		weights[term.word] = 1
+	}
+	if term.prefix {
+		for i := sort.SearchStrings(idx.vocabulary, term.word); i < len(idx.vocabulary) && strings.HasPrefix(idx.vocabulary[i], term.word); i++ {
+			if idx.vocabulary[i] != term.word {
+				weights[idx.vocabulary[i]] = prefixMatchWeight
+			}
+		}
+	}
+	if tolerance := utils.TypoTolerance(term.word); typos && tolerance > 0 {
+		for _, typo := range idx.typoMatches(term.word, tolerance) {
+			weights[typo.word] = max(weights[typo.word], 1-typoPenalty*float64(typo.distance))
+		}
+	}
+
+	scores := make(map[string]float64)
+	for word, weight := range weights {
+		for id, fields := range idx.postings[word] {
+			if !withDescription {
+				fields &^= fieldDescription
+			}
+			if fields == 0 {
+				continue
+			}
+			scores[id] = max(scores[id], weight*fieldWeight(fields))
+		}
+	}
+	return scores
+}
+
+// typoMatch is an indexed word matching a term with typos
+type typoMatch struct {
+	word     string
+	distance int
+}
+
+// typoMatches returns the indexed words within tolerance typos of a word, closest first and at most maxTypoWords.
+// A typo changes at most 3 character pairs of a word, a swap of adjacent characters being the worst case, so when
+// the word has more than 3 distinct pairs per typo only the words sharing enough of them are compared. Shorter words
+// are compared with the words whose length is within the tolerance. The caller holds the read lock
+func (idx *MangaSearchIndexImpl) typoMatches(word string, tolerance int) []typoMatch {
+	runes := []rune(word)
+	bigrams := distinctBigrams(runes)
+
+	var candidates []int32
+	if needed := len(bigrams) - 3*tolerance; needed > 0 {
+		shared := make(map[int32]int)
+		for _, bigram := range bigrams {
+			for _, i := range idx.bigrams[bigram] {
+				shared[i]++
+			}
+		}
+		for i, count := range shared {
+			if count >= needed {
+				candidates = append(candidates, i)
+			}
+		}
+	} else {
+		for length := len(runes) - tolerance; length <= len(runes)+tolerance; length++ {
+			candidates = append(candidates, idx.lengths[length]...)
+		}
+	}
+
+	var matches []typoMatch
+	for _, i := range candidates {
+		candidate := idx.vocabulary[i]
+		if distance := utils.EditDistance(word, candidate, tolerance); distance > 0 && distance <= tolerance {
+			matches = append(matches, typoMatch{word: candidate, distance: distance})
+		}
+	}
+	sort.Slice(matches, func(i, j int) bool {
+		if matches[i].distance != matches[j].distance {
+			return matches[i].distance < matches[j].distance
+		}
+		return matches[i].word < matches[j].word
+	})
+	if len(matches) > maxTypoWords {
+		matches = matches[:maxTypoWords]
+	}
+	return matches
+}
+
+// distinctBigrams returns the distinct pairs of consecutive characters of a word
+func distinctBigrams(runes []rune) []string {
+	seen := make(map[string]bool, len(runes))
+	var bigrams []string
+	for i := 1; i < len(runes); i++ {
+		bigram := string(runes[i-1 : i+1])
+		if !seen[bigram] {
+			seen[bigram] = true
+			bigrams = append(bigrams, bigram)
+		}
+	}
+	return bigrams
+}
+
+// fieldWeight returns how much a match counts depending on the best field it was found in
+func fieldWeight(fields uint8) float64 {
+	switch {
+	case fields&fieldTitle != 0:
+		return 3
+	case fields&fieldAuthor != 0:
+		return 2
+	default:
+		return 1
+	}
+}
+
+// indexedWords returns the words of a manga along with the fields they were found in
+func indexedWords(manga *indexedManga) map[string]uint8 {
+	words := make(map[string]uint8)
+	add := func(text string, field uint8) {
+		for _, word := range utils.Tokenize(text) {
+			words[word] |= field
+		}
+	}
+
+	add(manga.Title, fieldTitle)
+	for _, title := range manga.AltTitles {
+		add(title, fieldTitle)
+	}
+	for _, author := range manga.Authors {
+		add(author.Name, fieldAuthor)
+	}
+	add(manga.Description, fieldDescription)
+	for _, description := range manga.AltDescriptions {
+		add(description, fieldDescription)
+	}
+	return words
+}
+
+// parseIndexQuery splits a query into terms. Every word of a natural query is required and the last one is
+// a prefix unless the query ends with a space. A boolean query follows the +, - and * operators, the words
+// of a quoted phrase sharing the operator in front of it
+func parseIndexQuery(text string, boolean bool) []indexTerm {
+	var terms []indexTerm
+	if !boolean {
+		for _, word := range utils.Tokenize(text) {
+			terms = append(terms, indexTerm{word: word, required: true})
+		}
+		if len(terms) > 0 && !strings.HasSuffix(text, " ") {
+			terms[len(terms)-1].prefix = true
+		}
+		return terms
+	}
+
+	for _, chunk := range booleanChunks(text) {
+		operator := chunk[0]
+		body := strings.TrimLeft(chunk, "+-~<>")
+		body = strings.Trim(body, `()"`)
+		prefix := strings.HasSuffix(body, "*")
+		words := utils.Tokenize(body)
+		for i, word := range words {
+			terms = append(terms, indexTerm{
+				word:     word,
+				required: operator == '+',
+				excluded: operator == '-',
+				prefix:   prefix && i == len(words)-1,
+			})
+		}
+	}
+	return terms
+}
+
+// booleanChunks splits a boolean query on spaces, keeping quoted phrases together
+func booleanChunks(text string) []string {
+	var chunks []string
+	var current strings.Builder
+	quoted := false
+	for _, r := range text {
+		switch {
+		case r == '"':
+			quoted = !quoted
+			current.WriteRune(r)
+		case r == ' ' || r == '\t' || r == '\n':
+			if quoted {
+				current.WriteRune(r)
+			} else if current.Len() > 0 {
+				chunks = append(chunks, current.String())
+				current.Reset()
+			}
+		default:
+			current.WriteRune(r)
+		}
+	}
+	if current.Len() > 0 {
+		chunks = append(chunks, current.String())
+	}
+	return chunks
+}
+
+// matchesSearchFilters reports whether a manga passes the filters of a search
+func matchesSearchFilters(manga *indexedManga, query *entities.MangaSearchQuery) bool {
+	if query.StatusID != 0 && manga.StatusID != query.StatusID {
+		return false
+	}
+	return hasRef(manga.Categories, query.CategoryID) && hasRef(manga.Authors, query.AuthorID) && hasRef(manga.Groups, query.GroupID)
+}
+
+// hasRef reports whether refs contains id, any refs passing an empty id
+func hasRef(refs []indexedRef, id string) bool {
+	if id == "" {
+		return true
+	}
+	for _, ref := range refs {
+		if ref.ID == id {
+			return true
+		}
+	}
+	return false
+}
+
+// searchFacets counts the mangas by status, category, author and group
+func searchFacets(mangas []*indexedManga) entities.MangaSearchFacets {
+	statuses := make(map[indexedRef]int64)
+	categories := make(map[indexedRef]int64)
+	authors := make(map[indexedRef]int64)
+	groups := make(map[indexedRef]int64)
+	for _, manga := range mangas {
+		statuses[indexedRef{ID: strconv.FormatUint(uint64(manga.StatusID), 10), Name: manga.StatusName}]++
+		for _, ref := range manga.Categories {
+			categories[ref]++
+		}
+		for _, ref := range manga.Authors {
+			authors[ref]++
+		}
+		for _, ref := range manga.Groups {
+			groups[ref]++
+		}
+	}
+
+	return entities.MangaSearchFacets{
+		Statuses:   topFacets(statuses),
+		Categories: topFacets(categories),
+		Authors:    topFacets(authors),
+		Groups:     topFacets(groups),
+	}
+}
+
+// topFacets returns the most frequent facet values, by name when tied
+func topFacets(counts map[indexedRef]int64) []entities.MangaSearchFacet {
+	facets := make([]entities.MangaSearchFacet, 0, len(counts))
+	for ref, count := range counts {
+		facets = append(facets, entities.MangaSearchFacet{Value: ref.ID, Name: ref.Name, Count: count})
+	}
+	sort.Slice(facets, func(i, j int) bool {
+		if facets[i].Count != facets[j].Count {
+			return facets[i].Count > facets[j].Count
+		}
+		return facets[i].Name < facets[j].Name
+	})
+	if len(facets) > maxFacetValues {
+		facets = facets[:maxFacetValues]
+	}
+	return facets
+}
+
+// toIndexedRefs pairs IDs with their names, leaving out the ones deleted in the meantime
+func toIndexedRefs(ids []string, names map[string]string) []indexedRef {
+	refs := make([]indexedRef, 0, len(ids))
+	for _, id := range ids {
+		if name, ok := names[id]; ok {
+			refs = append(refs, indexedRef{ID: id, Name: name})
+		}
+	}
+	return refs
+}
+
+// refersToAny reports whether any author, category or group of a manga is in ids
+func refersToAny(manga *indexedManga, ids map[string]bool) bool {
+	if len(ids) == 0 {
+		return false
+	}
+	for _, refs := range [][]indexedRef{manga.Authors, manga.Categories, manga.Groups} {
+		for _, ref := range refs {
+			if ids[ref.ID] {
+				return true
+			}
+		}
+	}
+	return false
+}
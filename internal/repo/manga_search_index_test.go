@@ -0,0 +1,177 @@
+package repo
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"testing"
+
+	"hotaku-api/utils"
+)
+
+// newTestSearchIndex builds a search index holding the given mangas, without a database
+func newTestSearchIndex(mangas ...*indexedManga) *MangaSearchIndexImpl {
+	idx := &MangaSearchIndexImpl{
+		mangas:   make(map[string]*indexedManga),
+		postings: make(map[string]map[string]uint8),
+	}
+	for _, manga := range mangas {
+		idx.put(manga)
+	}
+	idx.refreshVocabulary()
+	return idx
+}
+
+// rankedIDs returns the IDs of the matches of a query, best score first
+func rankedIDs(idx *MangaSearchIndexImpl, text string, boolean, withDescription bool) []string {
+	scores := idx.match(parseIndexQuery(text, boolean), withDescription)
+	ids := make([]string, 0, len(scores))
+	for id := range scores {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool {
+		if scores[ids[i]] != scores[ids[j]] {
+			return scores[ids[i]] > scores[ids[j]]
+		}
+		return ids[i] < ids[j]
+	})
+	return ids
+}
+
+func TestSearchIndexRanksTitleOverAuthorOverDescription(t *testing.T) {
+	idx := newTestSearchIndex(
+		&indexedManga{MangaID: "description", Title: "Blue Period", Description: "A story about a dragon"},
+		&indexedManga{MangaID: "author", Title: "Spy Family", Authors: []indexedRef{{ID: "a1", Name: "Dragon Tatsuya"}}},
+		&indexedManga{MangaID: "title", Title: "Dragon Ball"},
+	)
+
+	got := rankedIDs(idx, "dragon ", false, true)
+	want := []string{"title", "author", "description"}
+	if !slices.Equal(got, want) {
+		t.Fatalf("ranking = %v, want %v", got, want)
+	}
+
+	if got := rankedIDs(idx, "dragon ", false, false); !slices.Equal(got, []string{"title", "author"}) {
+		t.Fatalf("ranking without descriptions = %v, want [title author]", got)
+	}
+}
+
+func TestSearchIndexRanksExactOverPrefixOverTypo(t *testing.T) {
+	idx := newTestSearchIndex(
+		&indexedManga{MangaID: "typo", Title: "Mina"},
+		&indexedManga{MangaID: "prefix", Title: "Mirage"},
+		&indexedManga{MangaID: "exact", Title: "Mira"},
+	)
+
+	got := rankedIDs(idx, "mira", false, false)
+	want := []string{"exact", "prefix", "typo"}
+	if !slices.Equal(got, want) {
+		t.Fatalf("ranking = %v, want %v", got, want)
+	}
+}
+
+func TestSearchIndexMatchesTypos(t *testing.T) {
+	idx := newTestSearchIndex(
+		&indexedManga{MangaID: "naruto", Title: "Naruto Shippuden"},
+		&indexedManga{MangaID: "dao", Title: "Đảo Hải Tặc"},
+		&indexedManga{MangaID: "berserk", Title: "Berserk"},
+	)
+
+	tests := []struct {
+		query string
+		want  []string
+	}{
+		{"naruto shipuden ", []string{"naruto"}}, // a missing letter
+		{"nartuo ", []string{"naruto"}},          // swapped letters
+		{"dao hai tac ", []string{"dao"}},        // folded diacritics
+		{"bersek ", []string{"berserk"}},         // a missing letter
+		{"naruto shipudden", []string{"naruto"}}, // a typo in the prefix term
+		{"brsk ", nil},                           // too many typos for a short word
+		{"nar ", nil},                            // short words need an exact match
+	}
+	for _, tt := range tests {
+		if got := rankedIDs(idx, tt.query, false, false); !slices.Equal(got, tt.want) {
+			t.Errorf("match(%q) = %v, want %v", tt.query, got, tt.want)
+		}
+	}
+}
+
+func TestSearchIndexExcludedWordsDontAllowTypos(t *testing.T) {
+	idx := newTestSearchIndex(
+		&indexedManga{MangaID: "one", Title: "Monster Hunter"},
+		&indexedManga{MangaID: "two", Title: "Monsters Inc"},
+	)
+
+	if got := rankedIDs(idx, "+monster -hunter", true, false); !slices.Equal(got, []string{"two"}) {
+		t.Fatalf("ranking = %v, want [two]", got)
+	}
+	if got := rankedIDs(idx, "+monster -huntre", true, false); len(got) != 2 {
+		t.Fatalf("a typo in an excluded word hid matches: %v", got)
+	}
+}
+
+func TestTypoMatchesFindsEveryWordWithinTolerance(t *testing.T) {
+	// Every word within the tolerance must survive the candidate filter, compared with a full scan
+	words := []string{
+		"shippuden", "shipuden", "shippudne", "hsippuden", "shippudenn", "shppuden", "shippuuden",
+		"naruto", "nartuo", "naurto", "narutos", "aruto", "naruo", "narito", "maruto",
+		"berserk", "bersek", "berzerk", "beserk", "berserker",
+		"ab", "abc", "abcd", "abdc", "bacd", "abcde", "tac", "tact",
+	}
+	var mangas []*indexedManga
+	for i, word := range words {
+		mangas = append(mangas, &indexedManga{MangaID: fmt.Sprintf("m%d", i), Title: word})
+	}
+	idx := newTestSearchIndex(mangas...)
+
+	for _, word := range words {
+		tolerance := utils.TypoTolerance(word)
+		if tolerance == 0 {
+			continue
+		}
+
+		var want []string
+		for _, candidate := range idx.vocabulary {
+			if distance := utils.EditDistance(word, candidate, tolerance); distance > 0 && distance <= tolerance {
+				want = append(want, candidate)
+			}
+		}
+		var got []string
+		for _, match := range idx.typoMatches(word, tolerance) {
+			got = append(got, match.word)
+		}
+		slices.Sort(want)
+		slices.Sort(got)
+		if !slices.Equal(got, want) {
+			t.Errorf("typoMatches(%q) = %v, want %v", word, got, want)
+		}
+	}
+}
+
+func TestTypoMatchesKeepsTheClosestWords(t *testing.T) {
+	// Substituting one or two letters of a long word gives many words within two typos of it
+	var mangas []*indexedManga
+	base := []rune("abcdefghij")
+	for i := range base {
+		for r := 'k'; r <= 'z'; r++ {
+			word := slices.Clone(base)
+			word[i] = r
+			mangas = append(mangas, &indexedManga{MangaID: string(word), Title: string(word)})
+			if i+1 < len(base) {
+				word[i+1] = r
+				mangas = append(mangas, &indexedManga{MangaID: string(word), Title: string(word)})
+			}
+		}
+	}
+	idx := newTestSearchIndex(mangas...)
+
+	matches := idx.typoMatches(string(base), 2)
+	if len(matches) != maxTypoWords {
+		t.Fatalf("got %d matches, want them capped at %d", len(matches), maxTypoWords)
+	}
+	for i, match := range matches {
+		if match.distance != 1 {
+			t.Fatalf("match %d %q is %d typos away, want only the 1 typo words", i, match.word, match.distance)
+		}
+	}
+}
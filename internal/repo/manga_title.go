@@ -0,0 +1,148 @@
+package repo
+
+import (
+	"errors"
+	"fmt"
+	"hotaku-api/internal/domain/entities"
+	"hotaku-api/internal/repoinf"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// MangaTitleRepositoryImpl implements the manga title repository interface
+type MangaTitleRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewMangaTitleRepository creates a new instance of MangaTitleRepositoryImpl
+func NewMangaTitleRepository(db *gorm.DB) repoinf.MangaTitleRepository {
+	return &MangaTitleRepositoryImpl{db: db}
+}
+
+// Create saves a title, making it primary if requested or if its locale has no title yet
+func (r *MangaTitleRepositoryImpl) Create(title *entities.MangaTitle) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := lockManga(tx, title.MangaID); err != nil {
+			return err
+		}
+
+		var count int64
+		err := tx.Model(&entities.MangaTitle{}).Where("manga_id = ? AND locale = ?", title.MangaID, title.Locale).Count(&count).Error
+		if err != nil {
+			return fmt.Errorf("failed to count manga titles: %w", err)
+		}
+		if count == 0 {
+			title.IsPrimary = true
+		}
+
+		if title.IsPrimary {
+			err := tx.Model(&entities.MangaTitle{}).
+				Where("manga_id = ? AND locale = ? AND is_primary = ?", title.MangaID, title.Locale, true).
+				Update("is_primary", false).Error
+			if err != nil {
+				return fmt.Errorf("failed to clear primary title: %w", err)
+			}
+		}
+		if err := tx.Create(title).Error; err != nil {
+			return fmt.Errorf("failed to create manga title: %w", err)
+		}
+		return touchManga(tx, title.MangaID)
+	})
+}
+
+// ListByManga retrieves all titles of a manga by locale, primary first
+func (r *MangaTitleRepositoryImpl) ListByManga(mangaID string) ([]entities.MangaTitle, error) {
+	var titles []entities.MangaTitle
+	err := r.db.Where("manga_id = ?", mangaID).
+		Order("locale ASC").
+		Order("is_primary DESC").
+		Order("created_at ASC").
+		Find(&titles).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve manga titles: %w", err)
+	}
+	return titles, nil
+}
+
+// Delete removes a title, promoting the oldest other title of its locale when it was the primary one
+func (r *MangaTitleRepositoryImpl) Delete(mangaID, titleID string) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := lockManga(tx, mangaID); err != nil {
+			return err
+		}
+
+		var title entities.MangaTitle
+		if err := tx.Where("title_id = ? AND manga_id = ?", titleID, mangaID).First(&title).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return fmt.Errorf("title not found")
+			}
+			return fmt.Errorf("failed to retrieve manga title: %w", err)
+		}
+		if err := tx.Delete(&title).Error; err != nil {
+			return fmt.Errorf("failed to delete manga title: %w", err)
+		}
+
+		if title.IsPrimary {
+			var next entities.MangaTitle
+			err := tx.Where("manga_id = ? AND locale = ?", mangaID, title.Locale).Order("created_at ASC").First(&next).Error
+			switch {
+			case errors.Is(err, gorm.ErrRecordNotFound):
+			case err != nil:
+				return fmt.Errorf("failed to retrieve manga title: %w", err)
+			default:
+				if err := tx.Model(&next).Update("is_primary", true).Error; err != nil {
+					return fmt.Errorf("failed to set primary title: %w", err)
+				}
+			}
+		}
+		return touchManga(tx, mangaID)
+	})
+}
+
+// ListDescriptions retrieves all localized descriptions of a manga
+func (r *MangaTitleRepositoryImpl) ListDescriptions(mangaID string) ([]entities.MangaDescription, error) {
+	var descriptions []entities.MangaDescription
+	if err := r.db.Where("manga_id = ?", mangaID).Order("locale ASC").Find(&descriptions).Error; err != nil {
+		return nil, fmt.Errorf("failed to retrieve manga descriptions: %w", err)
+	}
+	return descriptions, nil
+}
+
+// SaveDescription creates or replaces the description of a manga in a locale
+func (r *MangaTitleRepositoryImpl) SaveDescription(description *entities.MangaDescription) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := lockManga(tx, description.MangaID); err != nil {
+			return err
+		}
+		err := tx.Clauses(clause.OnConflict{DoUpdates: clause.AssignmentColumns([]string{"description", "updated_at"})}).
+			Create(description).Error
+		if err != nil {
+			return fmt.Errorf("failed to save manga description: %w", err)
+		}
+		return touchManga(tx, description.MangaID)
+	})
+}
+
+// DeleteDescription removes the description of a manga in a locale
+func (r *MangaTitleRepositoryImpl) DeleteDescription(mangaID, locale string) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		res := tx.Where("manga_id = ? AND locale = ?", mangaID, locale).Delete(&entities.MangaDescription{})
+		if res.Error != nil {
+			return fmt.Errorf("failed to delete manga description: %w", res.Error)
+		}
+		if res.RowsAffected == 0 {
+			return fmt.Errorf("description not found")
+		}
+		return touchManga(tx, mangaID)
+	})
+}
+
+// touchManga bumps the update time of a manga, so search index syncs pick up changes to its titles and descriptions
+func touchManga(tx *gorm.DB, mangaID string) error {
+	if err := tx.Model(&entities.Manga{}).Where("manga_id = ?", mangaID).Update("updated_at", time.Now()).Error; err != nil {
+		return fmt.Errorf("failed to update manga: %w", err)
+	}
+	return nil
+}
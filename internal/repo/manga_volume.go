@@ -0,0 +1,102 @@
+package repo
+
+import (
+	"errors"
+	"fmt"
+	"hotaku-api/internal/domain/entities"
+	"hotaku-api/internal/repoinf"
+
+	"gorm.io/gorm"
+)
+
+// MangaVolumeRepositoryImpl implements the manga volume repository interface
+type MangaVolumeRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewMangaVolumeRepository creates a new instance of MangaVolumeRepositoryImpl
+func NewMangaVolumeRepository(db *gorm.DB) repoinf.MangaVolumeRepository {
+	return &MangaVolumeRepositoryImpl{db: db}
+}
+
+// Create saves a new volume
+func (r *MangaVolumeRepositoryImpl) Create(volume *entities.MangaVolume) error {
+	if err := r.db.Create(volume).Error; err != nil {
+		return fmt.Errorf("failed to create manga volume: %w", err)
+	}
+	return nil
+}
+
+// GetByID retrieves a volume by ID along with its cover
+func (r *MangaVolumeRepositoryImpl) GetByID(volumeID string) (*entities.MangaVolume, error) {
+	var volume entities.MangaVolume
+	if err := r.db.Where("volume_id = ?", volumeID).Preload("Cover").First(&volume).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("volume not found")
+		}
+		return nil, fmt.Errorf("failed to retrieve manga volume: %w", err)
+	}
+	return &volume, nil
+}
+
+// ListByManga retrieves all volumes of a manga with their cover, by volume number
+func (r *MangaVolumeRepositoryImpl) ListByManga(mangaID string) ([]entities.MangaVolume, error) {
+	var volumes []entities.MangaVolume
+	err := r.db.Where("manga_id = ?", mangaID).
+		Preload("Cover").
+		Order("volume_number ASC").
+		Find(&volumes).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve manga volumes: %w", err)
+	}
+	return volumes, nil
+}
+
+// Update saves the number, title and cover of a volume
+func (r *MangaVolumeRepositoryImpl) Update(volume *entities.MangaVolume) error {
+	err := r.db.Model(volume).Select("volume_number", "title", "cover_id", "updated_at").Updates(volume).Error
+	if err != nil {
+		return fmt.Errorf("failed to update manga volume: %w", err)
+	}
+	return nil
+}
+
+// Delete removes a volume, its chapters staying in the manga without a volume
+func (r *MangaVolumeRepositoryImpl) Delete(volumeID string) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(&entities.MangaChapter{}).Where("volume_id = ?", volumeID).Update("volume_id", nil).Error
+		if err != nil {
+			return fmt.Errorf("failed to remove chapters from volume: %w", err)
+		}
+		res := tx.Where("volume_id = ?", volumeID).Delete(&entities.MangaVolume{})
+		if res.Error != nil {
+			return fmt.Errorf("failed to delete manga volume: %w", res.Error)
+		}
+		if res.RowsAffected == 0 {
+			return fmt.Errorf("volume not found")
+		}
+		return nil
+	})
+}
+
+// SetChapters makes the chapters the only ones of the volume, moving them out of any other volume
+func (r *MangaVolumeRepositoryImpl) SetChapters(volumeID string, chapterIDs []string) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		query := tx.Model(&entities.MangaChapter{}).Where("volume_id = ?", volumeID)
+		if len(chapterIDs) > 0 {
+			query = query.Where("chapter_id NOT IN ?", chapterIDs)
+		}
+		if err := query.Update("volume_id", nil).Error; err != nil {
+			return fmt.Errorf("failed to remove chapters from volume: %w", err)
+		}
+		if len(chapterIDs) == 0 {
+			return nil
+		}
+
+		err := tx.Model(&entities.MangaChapter{}).Where("chapter_id IN ?", chapterIDs).Update("volume_id", volumeID).Error
+		if err != nil {
+			return fmt.Errorf("failed to add chapters to volume: %w", err)
+		}
+		return nil
+	})
+}
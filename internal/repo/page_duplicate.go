@@ -0,0 +1,89 @@
+package repo
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"hotaku-api/internal/domain/entities"
+	"hotaku-api/internal/repoinf"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// PageDuplicateRepositoryImpl implements the duplicate page flag repository interface
+type PageDuplicateRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewPageDuplicateRepository creates a new instance of PageDuplicateRepositoryImpl
+func NewPageDuplicateRepository(db *gorm.DB) repoinf.PageDuplicateRepository {
+	return &PageDuplicateRepositoryImpl{db: db}
+}
+
+// CreateFlags saves the flags, skipping page pairs that were already flagged
+func (r *PageDuplicateRepositoryImpl) CreateFlags(flags []entities.PageDuplicateFlag) error {
+	if len(flags) == 0 {
+		return nil
+	}
+	if err := r.db.Clauses(clause.OnConflict{DoNothing: true}).Create(&flags).Error; err != nil {
+		return fmt.Errorf("failed to create duplicate flags: %w", err)
+	}
+	return nil
+}
+
+// GetByID retrieves a duplicate flag by ID along with both pages
+func (r *PageDuplicateRepositoryImpl) GetByID(flagID string) (*entities.PageDuplicateFlag, error) {
+	var flag entities.PageDuplicateFlag
+	if err := r.db.Preload("Page").Preload("DuplicatePage").Where("flag_id = ?", flagID).First(&flag).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("duplicate flag not found")
+		}
+		return nil, fmt.Errorf("failed to retrieve duplicate flag: %w", err)
+	}
+	return &flag, nil
+}
+
+// List returns a page of flags, oldest first, optionally filtered by status and manga, along with the total count
+func (r *PageDuplicateRepositoryImpl) List(status, mangaID string, offset, limit int) ([]entities.PageDuplicateFlag, int64, error) {
+	query := r.db.Model(&entities.PageDuplicateFlag{})
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	if mangaID != "" {
+		query = query.Where("manga_id = ?", mangaID)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to count duplicate flags: %w", err)
+	}
+
+	var flags []entities.PageDuplicateFlag
+	err := query.Preload("Page").Preload("DuplicatePage").
+		Order("created_at ASC").
+		Offset(offset).
+		Limit(limit).
+		Find(&flags).Error
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to retrieve duplicate flags: %w", err)
+	}
+	return flags, total, nil
+}
+
+// UpdateStatus records a moderator's decision on a flag
+func (r *PageDuplicateRepositoryImpl) UpdateStatus(flagID, status, reviewerID string) error {
+	res := r.db.Model(&entities.PageDuplicateFlag{}).Where("flag_id = ?", flagID).Updates(map[string]interface{}{
+		"status":      status,
+		"reviewed_by": reviewerID,
+		"reviewed_at": time.Now(),
+	})
+	if res.Error != nil {
+		return fmt.Errorf("failed to update duplicate flag: %w", res.Error)
+	}
+	if res.RowsAffected == 0 {
+		return fmt.Errorf("duplicate flag not found")
+	}
+	return nil
+}
@@ -0,0 +1,119 @@
+package repo
+
+import (
+	"fmt"
+	"hotaku-api/internal/domain/entities"
+	"hotaku-api/internal/repoinf"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	// readingActivitySQL lists when the user read or last opened each chapter, along with its manga
+	readingActivitySQL = "SELECT c.manga_id, urc.read_at AS at FROM user_read_chapters AS urc " +
+		"JOIN manga_chapters AS c ON c.chapter_id = urc.chapter_id WHERE urc.user_id = @user " +
+		"UNION ALL SELECT c.manga_id, p.updated_at AS at FROM user_chapter_positions AS p " +
+		"JOIN manga_chapters AS c ON c.chapter_id = p.chapter_id WHERE p.user_id = @user"
+	// readingFeedSQL is the latest activity of the user on each manga with how many chapters the manga has and
+	// the user read, versions of a chapter counting once
+	readingFeedSQL = "SELECT a.manga_id, MAX(a.at) AS last_read_at, " +
+		"(SELECT COUNT(DISTINCT c.chapter_number) FROM manga_chapters AS c WHERE c.manga_id = a.manga_id) AS chapter_count, " +
+		"(SELECT COUNT(DISTINCT c.chapter_number) FROM user_read_chapters AS urc " +
+		"JOIN manga_chapters AS c ON c.chapter_id = urc.chapter_id " +
+		"WHERE urc.user_id = @user AND c.manga_id = a.manga_id) AS read_count " +
+		"FROM (" + readingActivitySQL + ") AS a GROUP BY a.manga_id"
+)
+
+// ReadingRepositoryImpl implements the reading repository interface
+type ReadingRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewReadingRepository creates a new instance of ReadingRepositoryImpl
+func NewReadingRepository(db *gorm.DB) repoinf.ReadingRepository {
+	return &ReadingRepositoryImpl{db: db}
+}
+
+// MarkRead records that the user read the chapters, moving the read time of those already read
+func (r *ReadingRepositoryImpl) MarkRead(userID string, chapterIDs []string, readAt time.Time) error {
+	if len(chapterIDs) == 0 {
+		return nil
+	}
+
+	reads := make([]entities.UserReadChapter, 0, len(chapterIDs))
+	for _, chapterID := range chapterIDs {
+		reads = append(reads, entities.UserReadChapter{UserID: userID, ChapterID: chapterID, ReadAt: readAt})
+	}
+	err := r.db.Clauses(clause.OnConflict{DoUpdates: clause.AssignmentColumns([]string{"read_at"})}).
+		Create(&reads).Error
+	if err != nil {
+		return fmt.Errorf("failed to mark chapters read: %w", err)
+	}
+	return nil
+}
+
+// MarkUnread removes the reads of the chapters by the user
+func (r *ReadingRepositoryImpl) MarkUnread(userID string, chapterIDs []string) error {
+	if len(chapterIDs) == 0 {
+		return nil
+	}
+
+	err := r.db.Where("user_id = ? AND chapter_id IN ?", userID, chapterIDs).Delete(&entities.UserReadChapter{}).Error
+	if err != nil {
+		return fmt.Errorf("failed to mark chapters unread: %w", err)
+	}
+	return nil
+}
+
+// SavePosition creates or replaces the last page of a chapter the user was on
+func (r *ReadingRepositoryImpl) SavePosition(position *entities.UserChapterPosition) error {
+	err := r.db.Clauses(clause.OnConflict{DoUpdates: clause.AssignmentColumns([]string{"page_number", "updated_at"})}).
+		Create(position).Error
+	if err != nil {
+		return fmt.Errorf("failed to save reading position: %w", err)
+	}
+	return nil
+}
+
+// ListPositionsByManga retrieves the positions of the user in the chapters of a manga
+func (r *ReadingRepositoryImpl) ListPositionsByManga(userID, mangaID string) ([]entities.UserChapterPosition, error) {
+	var positions []entities.UserChapterPosition
+	err := r.db.Table("user_chapter_positions AS p").
+		Select("p.user_id, p.chapter_id, p.page_number, p.updated_at").
+		Joins("JOIN manga_chapters AS c ON c.chapter_id = p.chapter_id").
+		Where("p.user_id = ? AND c.manga_id = ?", userID, mangaID).
+		Scan(&positions).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve reading positions: %w", err)
+	}
+	return positions, nil
+}
+
+// ListFeed retrieves a page of the mangas the user read or opened a chapter of, the most recent first, leaving out
+// the mangas whose every chapter they read unless asked otherwise
+func (r *ReadingRepositoryImpl) ListFeed(query *entities.ReadingFeedQuery) (*entities.ReadingFeed, error) {
+	feedSQL := readingFeedSQL
+	if !query.IncludeCompleted {
+		feedSQL += " HAVING read_count < chapter_count"
+	}
+	args := map[string]interface{}{"user": query.UserID}
+
+	result := &entities.ReadingFeed{Items: []entities.ReadingActivity{}}
+	if err := r.db.Raw("SELECT COUNT(*) FROM ("+feedSQL+") AS f", args).Scan(&result.Total).Error; err != nil {
+		return nil, fmt.Errorf("failed to count reading feed: %w", err)
+	}
+	if result.Total == 0 || query.Offset >= int(result.Total) {
+		return result, nil
+	}
+
+	args["limit"] = query.Limit
+	args["offset"] = query.Offset
+	err := r.db.Raw(feedSQL+" ORDER BY last_read_at DESC, a.manga_id ASC LIMIT @limit OFFSET @offset", args).
+		Scan(&result.Items).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to list reading feed: %w", err)
+	}
+	return result, nil
+}
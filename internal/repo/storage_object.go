@@ -0,0 +1,218 @@
+package repo
+
+import (
+	"errors"
+	"fmt"
+	"hotaku-api/internal/domain/entities"
+	"hotaku-api/internal/repoinf"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// StorageObjectRepositoryImpl implements the storage object repository interface
+type StorageObjectRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewStorageObjectRepository creates a new instance of StorageObjectRepositoryImpl
+func NewStorageObjectRepository(db *gorm.DB) repoinf.StorageObjectRepository {
+	return &StorageObjectRepositoryImpl{db: db}
+}
+
+// Create saves a new storage object record to the database
+func (r *StorageObjectRepositoryImpl) Create(object *entities.StorageObject) error {
+	if err := r.db.Create(object).Error; err != nil {
+		return fmt.Errorf("failed to create storage object: %w", err)
+	}
+	return nil
+}
+
+// CreateWithinQuota saves a new storage object record unless it takes its uploader or group over their quota,
+// zero meaning unlimited, reporting whether it was saved. The uploader and group rows are locked so concurrent
+// uploads are counted one after the other
+func (r *StorageObjectRepositoryImpl) CreateWithinQuota(object *entities.StorageObject, userQuota, groupQuota int64) (bool, error) {
+	saved := false
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if object.UploadedBy != nil && userQuota > 0 {
+			err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("user_id = ?", *object.UploadedBy).First(&entities.User{}).Error
+			if err != nil {
+				return fmt.Errorf("failed to lock user: %w", err)
+			}
+			used, _, err := usage(tx, "uploaded_by = ?", *object.UploadedBy)
+			if err != nil {
+				return err
+			}
+			if used+object.Size > userQuota {
+				return nil
+			}
+		}
+		if object.GroupID != nil && groupQuota > 0 {
+			err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("group_id = ?", *object.GroupID).First(&entities.Group{}).Error
+			if err != nil {
+				return fmt.Errorf("failed to lock group: %w", err)
+			}
+			used, _, err := usage(tx, "group_id = ?", *object.GroupID)
+			if err != nil {
+				return err
+			}
+			if used+object.Size > groupQuota {
+				return nil
+			}
+		}
+
+		if err := tx.Create(object).Error; err != nil {
+			return fmt.Errorf("failed to create storage object: %w", err)
+		}
+		saved = true
+		return nil
+	})
+	if err != nil {
+		return false, err
+	}
+	return saved, nil
+}
+
+// GetByName retrieves a storage object record by object name
+func (r *StorageObjectRepositoryImpl) GetByName(objectName string) (*entities.StorageObject, error) {
+	var object entities.StorageObject
+	if err := r.db.Where("object_name = ?", objectName).First(&object).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("file not found")
+		}
+		return nil, fmt.Errorf("failed to retrieve storage object: %w", err)
+	}
+	return &object, nil
+}
+
+// Delete removes a storage object record
+func (r *StorageObjectRepositoryImpl) Delete(objectName string) error {
+	if err := r.db.Where("object_name = ?", objectName).Delete(&entities.StorageObject{}).Error; err != nil {
+		return fmt.Errorf("failed to delete storage object: %w", err)
+	}
+	return nil
+}
+
+// UsageByUser returns the total size and number of objects uploaded by the user
+func (r *StorageObjectRepositoryImpl) UsageByUser(userID string) (int64, int64, error) {
+	return usage(r.db, "uploaded_by = ?", userID)
+}
+
+// UsageByGroup returns the total size and number of objects credited to the group
+func (r *StorageObjectRepositoryImpl) UsageByGroup(groupID string) (int64, int64, error) {
+	return usage(r.db, "group_id = ?", groupID)
+}
+
+// usage sums the size of the objects matching the condition
+func usage(db *gorm.DB, query string, args ...interface{}) (int64, int64, error) {
+	var result struct {
+		Bytes   int64
+		Objects int64
+	}
+	err := db.Model(&entities.StorageObject{}).
+		Select("COALESCE(SUM(size), 0) AS bytes, COUNT(*) AS objects").
+		Where(query, args...).
+		Scan(&result).Error
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to compute storage usage: %w", err)
+	}
+	return result.Bytes, result.Objects, nil
+}
+
+// DeleteWithReferences removes the chapter pages and covers showing the object that were added by one of owners,
+// or all of them when owners is nil, whatever base URL their image URL was built with. The object record is
+// removed along with the last reference, which is reported so the caller deletes the object itself
+func (r *StorageObjectRepositoryImpl) DeleteWithReferences(objectName string, owners []string) (bool, error) {
+	// Image URLs end with the bucket followed by the object name
+	suffix := "/" + objectName
+	showsObject := "RIGHT(image_url, CHAR_LENGTH(?)) = ?"
+	// References added before uploaders were recorded belong to the object's uploader
+	ownedBy := func(query *gorm.DB) *gorm.DB {
+		if owners == nil {
+			return query
+		}
+		return query.Where("uploaded_by IN ? OR uploaded_by IS NULL", owners)
+	}
+
+	unreferenced := false
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var pages []entities.ChapterPage
+		if err := ownedBy(tx.Where(showsObject, suffix, suffix)).Find(&pages).Error; err != nil {
+			return fmt.Errorf("failed to find chapter pages: %w", err)
+		}
+
+		for _, page := range pages {
+			if _, err := lockChapterPages(tx, page.ChapterID); err != nil {
+				return err
+			}
+			// Re-read under the lock in case the page moved since the lookup
+			if err := tx.Where("page_id = ?", page.PageID).First(&page).Error; err != nil {
+				return fmt.Errorf("failed to retrieve chapter page: %w", err)
+			}
+			if err := tx.Delete(&entities.ChapterPage{}, "page_id = ?", page.PageID).Error; err != nil {
+				return fmt.Errorf("failed to delete chapter page: %w", err)
+			}
+			// Close the gap so the chapter stays numbered 1..n
+			err := tx.Exec(
+				"UPDATE chapter_pages SET page_number = page_number - 1 WHERE chapter_id = ? AND page_number > ? ORDER BY page_number ASC",
+				page.ChapterID, page.PageNumber,
+			).Error
+			if err != nil {
+				return fmt.Errorf("failed to renumber chapter pages: %w", err)
+			}
+		}
+
+		var covers []entities.MangaCover
+		if err := ownedBy(tx.Where(showsObject, suffix, suffix)).Find(&covers).Error; err != nil {
+			return fmt.Errorf("failed to find manga covers: %w", err)
+		}
+
+		for _, cover := range covers {
+			if err := lockManga(tx, cover.MangaID); err != nil {
+				return err
+			}
+			if err := tx.Where("cover_id = ?", cover.CoverID).First(&cover).Error; err != nil {
+				return fmt.Errorf("failed to retrieve manga cover: %w", err)
+			}
+			if err := tx.Delete(&entities.MangaCover{}, "cover_id = ?", cover.CoverID).Error; err != nil {
+				return fmt.Errorf("failed to delete manga cover: %w", err)
+			}
+			if !cover.IsPrimary {
+				continue
+			}
+			// Fall back to the most recent remaining cover
+			err := tx.Exec(
+				"UPDATE manga_covers SET is_primary = TRUE WHERE manga_id = ? ORDER BY created_at DESC LIMIT 1",
+				cover.MangaID,
+			).Error
+			if err != nil {
+				return fmt.Errorf("failed to promote manga cover: %w", err)
+			}
+		}
+
+		// Other users' pages may still show a deduplicated object
+		var remaining int64
+		if err := tx.Model(&entities.ChapterPage{}).Where(showsObject, suffix, suffix).Count(&remaining).Error; err != nil {
+			return fmt.Errorf("failed to count chapter pages: %w", err)
+		}
+		if remaining > 0 {
+			return nil
+		}
+		if err := tx.Model(&entities.MangaCover{}).Where(showsObject, suffix, suffix).Count(&remaining).Error; err != nil {
+			return fmt.Errorf("failed to count manga covers: %w", err)
+		}
+		if remaining > 0 {
+			return nil
+		}
+
+		if err := tx.Where("object_name = ?", objectName).Delete(&entities.StorageObject{}).Error; err != nil {
+			return fmt.Errorf("failed to delete storage object: %w", err)
+		}
+		unreferenced = true
+		return nil
+	})
+	if err != nil {
+		return false, err
+	}
+	return unreferenced, nil
+}
@@ -0,0 +1,111 @@
+package repo
+
+import (
+	"fmt"
+	"hotaku-api/internal/domain/entities"
+	"hotaku-api/internal/repoinf"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// SuggestionRepositoryImpl implements the suggestion repository interface
+type SuggestionRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewSuggestionRepository creates a new instance of SuggestionRepositoryImpl
+func NewSuggestionRepository(db *gorm.DB) repoinf.SuggestionRepository {
+	return &SuggestionRepositoryImpl{db: db}
+}
+
+// Load returns every manga, author, group and category along with their links and popularity signals.
+// Readers are the distinct users who read any chapter of a manga
+func (r *SuggestionRepositoryImpl) Load() (*entities.SuggestionSource, error) {
+	var source entities.SuggestionSource
+	err := r.db.Table("mangas AS m").
+		Select("m.manga_id, m.title, COALESCE(f.favorites, 0) AS favorites, COALESCE(rd.readers, 0) AS readers").
+		Joins("LEFT JOIN (SELECT manga_id, COUNT(*) AS favorites FROM user_favorite_mangas GROUP BY manga_id) AS f ON f.manga_id = m.manga_id").
+		Joins("LEFT JOIN (SELECT c.manga_id, COUNT(DISTINCT urc.user_id) AS readers FROM user_read_chapters AS urc " +
+			"JOIN manga_chapters AS c ON c.chapter_id = urc.chapter_id GROUP BY c.manga_id) AS rd ON rd.manga_id = m.manga_id").
+		Scan(&source.Mangas).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to load manga suggestions: %w", err)
+	}
+
+	var titles []struct {
+		MangaID string
+		Title   string
+	}
+	if err := r.db.Table("manga_titles").Select("manga_id, title").Scan(&titles).Error; err != nil {
+		return nil, fmt.Errorf("failed to load manga title suggestions: %w", err)
+	}
+	altTitles := make(map[string][]string)
+	for _, title := range titles {
+		altTitles[title.MangaID] = append(altTitles[title.MangaID], title.Title)
+	}
+	for i := range source.Mangas {
+		source.Mangas[i].AltTitles = altTitles[source.Mangas[i].MangaID]
+	}
+
+	if source.Authors, err = r.loadRefs("authors", "author_id", "author_name", "mangas_authors"); err != nil {
+		return nil, err
+	}
+	if source.Groups, err = r.loadRefs("`groups`", "group_id", "group_name", "mangas_groups"); err != nil {
+		return nil, err
+	}
+	if source.Categories, err = r.loadRefs("categories", "category_id", "category_name", "mangas_categories"); err != nil {
+		return nil, err
+	}
+	return &source, nil
+}
+
+// loadRefs returns the rows of a table along with the mangas linked to each of them
+func (r *SuggestionRepositoryImpl) loadRefs(table, idColumn, nameColumn, linkTable string) ([]entities.SuggestionRef, error) {
+	var refs []entities.SuggestionRef
+	if err := r.db.Table(table).Select(idColumn + " AS id, " + nameColumn + " AS name").Scan(&refs).Error; err != nil {
+		return nil, fmt.Errorf("failed to load %s suggestions: %w", strings.Trim(table, "`"), err)
+	}
+
+	var links []struct {
+		ID      string
+		MangaID string
+	}
+	if err := r.db.Table(linkTable).Select(idColumn + " AS id, manga_id").Scan(&links).Error; err != nil {
+		return nil, fmt.Errorf("failed to load %s links of suggestions: %w", strings.Trim(table, "`"), err)
+	}
+	mangaIDs := make(map[string][]string)
+	for _, link := range links {
+		mangaIDs[link.ID] = append(mangaIDs[link.ID], link.MangaID)
+	}
+	for i := range refs {
+		refs[i].MangaIDs = mangaIDs[refs[i].ID]
+	}
+	return refs, nil
+}
+
+// Fingerprint returns a value that changes whenever mangas, their titles, authors, groups, categories, their links
+// or favorites change. Links are checksummed rather than counted, so swapping a link for another is noticed too
+func (r *SuggestionRepositoryImpl) Fingerprint() (string, error) {
+	var fingerprint string
+	err := r.db.Raw("SELECT CONCAT_WS('|', " +
+		"(SELECT COUNT(*) FROM mangas), (SELECT MAX(updated_at) FROM mangas), " +
+		"(SELECT COUNT(*) FROM manga_titles), (SELECT MAX(updated_at) FROM manga_titles), " +
+		"(SELECT COUNT(*) FROM authors), (SELECT MAX(updated_at) FROM authors), " +
+		"(SELECT COUNT(*) FROM `groups`), (SELECT MAX(updated_at) FROM `groups`), " +
+		"(SELECT COUNT(*) FROM categories), (SELECT MAX(updated_at) FROM categories), " +
+		linkChecksum("mangas_authors", "author_id") + ", " +
+		linkChecksum("mangas_groups", "group_id") + ", " +
+		linkChecksum("mangas_categories", "category_id") + ", " +
+		"(SELECT COUNT(*) FROM user_favorite_mangas))").
+		Scan(&fingerprint).Error
+	if err != nil {
+		return "", fmt.Errorf("failed to fingerprint suggestions: %w", err)
+	}
+	return fingerprint, nil
+}
+
+// linkChecksum returns a subquery summing up the rows of a link table of mangas, order aside
+func linkChecksum(table, idColumn string) string {
+	return fmt.Sprintf("(SELECT CONCAT(COUNT(*), ':', COALESCE(BIT_XOR(CRC32(CONCAT(manga_id, '/', %s))), 0)) FROM %s)", idColumn, table)
+}
@@ -0,0 +1,108 @@
+package repo
+
+import (
+	"errors"
+	"fmt"
+	"hotaku-api/internal/domain/entities"
+	"hotaku-api/internal/repoinf"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// UploadIntentRepositoryImpl implements the upload intent repository interface
+type UploadIntentRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewUploadIntentRepository creates a new instance of UploadIntentRepositoryImpl
+func NewUploadIntentRepository(db *gorm.DB) repoinf.UploadIntentRepository {
+	return &UploadIntentRepositoryImpl{db: db}
+}
+
+// Create saves a new upload intent to the database
+func (r *UploadIntentRepositoryImpl) Create(intent *entities.UploadIntent) error {
+	if err := r.db.Create(intent).Error; err != nil {
+		return fmt.Errorf("failed to create upload intent: %w", err)
+	}
+	return nil
+}
+
+// GetByID retrieves an upload intent by ID
+func (r *UploadIntentRepositoryImpl) GetByID(id string) (*entities.UploadIntent, error) {
+	var intent entities.UploadIntent
+	if err := r.db.Where("upload_id = ?", id).First(&intent).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("upload not found")
+		}
+		return nil, fmt.Errorf("failed to retrieve upload intent by ID: %w", err)
+	}
+	return &intent, nil
+}
+
+// Claim reserves an upload intent for a finalize call until the lease runs out, failing if it was finalized or
+// another call holds it
+func (r *UploadIntentRepositoryImpl) Claim(id string, lease time.Duration) error {
+	now := time.Now()
+	res := r.db.Model(&entities.UploadIntent{}).
+		Where("upload_id = ? AND finalized_at IS NULL AND (claimed_until IS NULL OR claimed_until < ?)", id, now).
+		Update("claimed_until", now.Add(lease))
+	if res.Error != nil {
+		return fmt.Errorf("failed to claim upload intent: %w", res.Error)
+	}
+	if res.RowsAffected == 0 {
+		return fmt.Errorf("upload already finalized or being finalized")
+	}
+	return nil
+}
+
+// Release gives up the claim on an upload intent so finalizing can be retried
+func (r *UploadIntentRepositoryImpl) Release(id string) error {
+	err := r.db.Model(&entities.UploadIntent{}).
+		Where("upload_id = ? AND finalized_at IS NULL", id).
+		Update("claimed_until", nil).Error
+	if err != nil {
+		return fmt.Errorf("failed to release upload intent: %w", err)
+	}
+	return nil
+}
+
+// MarkFinalized marks an upload intent as finalized, failing if it already was
+func (r *UploadIntentRepositoryImpl) MarkFinalized(id string) error {
+	res := r.db.Model(&entities.UploadIntent{}).
+		Where("upload_id = ? AND finalized_at IS NULL", id).
+		Updates(map[string]interface{}{"finalized_at": time.Now(), "claimed_until": nil})
+	if res.Error != nil {
+		return fmt.Errorf("failed to finalize upload intent: %w", res.Error)
+	}
+	if res.RowsAffected == 0 {
+		return fmt.Errorf("upload already finalized")
+	}
+	return nil
+}
+
+// ListExpired retrieves up to limit intents that expired before the given time without being finalized and nobody
+// is finalizing
+func (r *UploadIntentRepositoryImpl) ListExpired(before time.Time, limit int) ([]entities.UploadIntent, error) {
+	var intents []entities.UploadIntent
+	err := r.db.Where("finalized_at IS NULL AND expires_at < ? AND (claimed_until IS NULL OR claimed_until < ?)", before, before).
+		Order("expires_at ASC").
+		Limit(limit).
+		Find(&intents).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to list expired upload intents: %w", err)
+	}
+	return intents, nil
+}
+
+// Delete removes an upload intent
+func (r *UploadIntentRepositoryImpl) Delete(id string) error {
+	res := r.db.Where("upload_id = ?", id).Delete(&entities.UploadIntent{})
+	if res.Error != nil {
+		return fmt.Errorf("failed to delete upload intent: %w", res.Error)
+	}
+	if res.RowsAffected == 0 {
+		return fmt.Errorf("upload not found")
+	}
+	return nil
+}
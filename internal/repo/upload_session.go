@@ -0,0 +1,138 @@
+package repo
+
+import (
+	"errors"
+	"fmt"
+	"hotaku-api/internal/domain/entities"
+	"hotaku-api/internal/repoinf"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// UploadSessionRepositoryImpl implements the upload session repository interface
+type UploadSessionRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewUploadSessionRepository creates a new instance of UploadSessionRepositoryImpl
+func NewUploadSessionRepository(db *gorm.DB) repoinf.UploadSessionRepository {
+	return &UploadSessionRepositoryImpl{db: db}
+}
+
+// Create saves a new upload session to the database
+func (r *UploadSessionRepositoryImpl) Create(session *entities.UploadSession) error {
+	if err := r.db.Create(session).Error; err != nil {
+		return fmt.Errorf("failed to create upload session: %w", err)
+	}
+	return nil
+}
+
+// GetByID retrieves an upload session by ID
+func (r *UploadSessionRepositoryImpl) GetByID(id string) (*entities.UploadSession, error) {
+	var session entities.UploadSession
+	if err := r.db.Where("session_id = ?", id).First(&session).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("upload session not found")
+		}
+		return nil, fmt.Errorf("failed to retrieve upload session by ID: %w", err)
+	}
+	return &session, nil
+}
+
+// AppendPart records a stored chunk and advances the session offset, failing if the
+// offset moved since the chunk was accepted
+func (r *UploadSessionRepositoryImpl) AppendPart(sessionID string, expectedOffset int64, part *entities.UploadSessionPart) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		res := tx.Model(&entities.UploadSession{}).
+			Where("session_id = ? AND upload_offset = ? AND completed_at IS NULL", sessionID, expectedOffset).
+			Update("upload_offset", expectedOffset+part.Size)
+		if res.Error != nil {
+			return fmt.Errorf("failed to update upload offset: %w", res.Error)
+		}
+		if res.RowsAffected == 0 {
+			return fmt.Errorf("upload offset mismatch")
+		}
+
+		// A retried chunk reuses its part number, so replace any previous record
+		if err := tx.Save(part).Error; err != nil {
+			return fmt.Errorf("failed to record upload part: %w", err)
+		}
+		return nil
+	})
+}
+
+// ListParts retrieves the stored chunks of a session ordered by part number
+func (r *UploadSessionRepositoryImpl) ListParts(sessionID string) ([]entities.UploadSessionPart, error) {
+	var parts []entities.UploadSessionPart
+	if err := r.db.Where("session_id = ?", sessionID).Order("part_number ASC").Find(&parts).Error; err != nil {
+		return nil, fmt.Errorf("failed to retrieve upload parts: %w", err)
+	}
+	return parts, nil
+}
+
+// Claim reserves an upload session for a finalize call until the lease runs out, failing if it was completed or
+// another call holds it
+func (r *UploadSessionRepositoryImpl) Claim(id string, lease time.Duration) error {
+	now := time.Now()
+	res := r.db.Model(&entities.UploadSession{}).
+		Where("session_id = ? AND completed_at IS NULL AND (claimed_until IS NULL OR claimed_until < ?)", id, now).
+		Update("claimed_until", now.Add(lease))
+	if res.Error != nil {
+		return fmt.Errorf("failed to claim upload session: %w", res.Error)
+	}
+	if res.RowsAffected == 0 {
+		return fmt.Errorf("upload session already completed or being finalized")
+	}
+	return nil
+}
+
+// Release gives up the claim on an upload session so finalizing can be retried
+func (r *UploadSessionRepositoryImpl) Release(id string) error {
+	err := r.db.Model(&entities.UploadSession{}).
+		Where("session_id = ? AND completed_at IS NULL", id).
+		Update("claimed_until", nil).Error
+	if err != nil {
+		return fmt.Errorf("failed to release upload session: %w", err)
+	}
+	return nil
+}
+
+// MarkCompleted marks an upload session as completed, failing if it already was
+func (r *UploadSessionRepositoryImpl) MarkCompleted(id string) error {
+	res := r.db.Model(&entities.UploadSession{}).
+		Where("session_id = ? AND completed_at IS NULL", id).
+		Updates(map[string]interface{}{"completed_at": time.Now(), "claimed_until": nil})
+	if res.Error != nil {
+		return fmt.Errorf("failed to complete upload session: %w", res.Error)
+	}
+	if res.RowsAffected == 0 {
+		return fmt.Errorf("upload session already completed")
+	}
+	return nil
+}
+
+// Delete removes an upload session and its parts
+func (r *UploadSessionRepositoryImpl) Delete(id string) error {
+	res := r.db.Where("session_id = ?", id).Delete(&entities.UploadSession{})
+	if res.Error != nil {
+		return fmt.Errorf("failed to delete upload session: %w", res.Error)
+	}
+	if res.RowsAffected == 0 {
+		return fmt.Errorf("upload session not found")
+	}
+	return nil
+}
+
+// ListExpired retrieves up to limit incomplete sessions that expired before the given time and nobody is finalizing
+func (r *UploadSessionRepositoryImpl) ListExpired(before time.Time, limit int) ([]entities.UploadSession, error) {
+	var sessions []entities.UploadSession
+	err := r.db.Where("completed_at IS NULL AND expires_at < ? AND (claimed_until IS NULL OR claimed_until < ?)", before, before).
+		Order("expires_at ASC").
+		Limit(limit).
+		Find(&sessions).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to list expired upload sessions: %w", err)
+	}
+	return sessions, nil
+}
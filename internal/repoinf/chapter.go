@@ -0,0 +1,26 @@
+package repoinf
+
+import (
+	"hotaku-api/internal/domain/entities"
+)
+
+// ChapterRepository defines the interface for manga chapter data access
+type ChapterRepository interface {
+	GetByID(id string) (*entities.MangaChapter, error)
+	// UpdateWatermarkGroup sets the group whose watermark is drawn on the chapter, nil turns it off
+	UpdateWatermarkGroup(chapterID string, groupID *string) error
+	ListByWatermarkGroup(groupID string) ([]entities.MangaChapter, error)
+	// ListWatermarked retrieves the chapters that have a watermark turned on
+	ListWatermarked() ([]entities.MangaChapter, error)
+	// ListWithoutPages retrieves the chapters that have no page recorded
+	ListWithoutPages() ([]entities.MangaChapter, error)
+	// ListByManga retrieves every version of the chapters of a manga with their group and volume, by chapter number
+	ListByManga(mangaID string) ([]entities.MangaChapter, error)
+	// List retrieves a page of the chapters of a manga matching the query, with their group and volume
+	List(query *entities.ChapterListQuery) (*entities.ChapterListResult, error)
+	// ExistsVersion checks if another chapter of the manga has the same number, language and group
+	ExistsVersion(chapter *entities.MangaChapter, language string, groupID *string) (bool, error)
+	// UpdateAttribution sets the language and group of a chapter
+	UpdateAttribution(chapterID, language string, groupID *string) error
+	ListReadByManga(userID, mangaID string) ([]entities.UserReadChapter, error)
+}
@@ -0,0 +1,28 @@
+package repoinf
+
+import (
+	"hotaku-api/internal/domain/entities"
+)
+
+// ChapterPageRepository defines the interface for chapter page data access
+type ChapterPageRepository interface {
+	GetByNumber(chapterID string, pageNumber int) (*entities.ChapterPage, error)
+	ListByChapter(chapterID string) ([]entities.ChapterPage, error)
+	AppendPages(chapterID string, pages []entities.ChapterPage) error
+	InsertPagesAt(chapterID string, position int, pages []entities.ChapterPage) error
+	Reorder(chapterID string, pageIDs []string) error
+	// UpdateImage points a chapter page at a new image along with its content hashes and who added it
+	UpdateImage(pageID, imageURL string, contentSHA256 *string, perceptualHash *uint64, uploadedBy *string) error
+	// FilterReferencedImageURLs returns the subset of imageURLs used by at least one page
+	FilterReferencedImageURLs(imageURLs []string) ([]string, error)
+	// ListImageURLsAfter retrieves the ID and image URL of up to limit pages whose ID sorts after afterID, by ID
+	ListImageURLsAfter(afterID string, limit int) ([]entities.ChapterPage, error)
+	// CountPagesByChapter returns the page count of each existing chapter among chapterIDs
+	CountPagesByChapter(chapterIDs []string) (map[string]int64, error)
+	// FindImageURLByContentHash returns the image of a page of the manga with the given content, or "" if none
+	FindImageURLByContentHash(mangaID, contentSHA256 string) (string, error)
+	// OnlyInWatermarkedChapters reports whether the object is a page of watermarked chapters of the manga and of no other chapter
+	OnlyInWatermarkedChapters(mangaID, objectName string) (bool, error)
+	// ListSimilar returns the pages of the manga whose perceptual hash is within maxDistance bits of hash
+	ListSimilar(mangaID string, hash uint64, maxDistance int) ([]entities.ChapterPage, error)
+}
@@ -0,0 +1,15 @@
+package repoinf
+
+import (
+	"hotaku-api/internal/domain/entities"
+)
+
+// GroupRepository defines the interface for group data access
+type GroupRepository interface {
+	ListByUser(userID string) ([]entities.Group, error)
+	IsMember(groupID, userID string) (bool, error)
+	// IsCredited checks if the group is credited on the manga
+	IsCredited(groupID, mangaID string) (bool, error)
+	// FindUserGroupForManga returns a group of the user credited on the manga, or an empty ID if there is none
+	FindUserGroupForManga(userID, mangaID string) (string, error)
+}
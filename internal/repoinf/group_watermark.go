@@ -0,0 +1,13 @@
+package repoinf
+
+import (
+	"hotaku-api/internal/domain/entities"
+)
+
+// GroupWatermarkRepository defines the interface for group watermark data access
+type GroupWatermarkRepository interface {
+	GetByGroupID(groupID string) (*entities.GroupWatermark, error)
+	// Save creates or replaces the watermark of a group
+	Save(watermark *entities.GroupWatermark) error
+	Delete(groupID string) error
+}
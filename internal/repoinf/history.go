@@ -0,0 +1,24 @@
+package repoinf
+
+import (
+	"time"
+
+	"hotaku-api/internal/domain/entities"
+)
+
+// HistoryRepository defines the interface for reading history data access
+type HistoryRepository interface {
+	// GetLatest retrieves the entry the user opened last
+	GetLatest(userID string) (*entities.UserHistoryEntry, error)
+	Create(entry *entities.UserHistoryEntry) error
+	// Touch moves an entry to the given time, along with the page the user is on when set
+	Touch(entryID string, pageNumber *int, openedAt time.Time) error
+	// List retrieves the reading history of a user, the latest opened first, with the manga and chapter of each entry
+	List(query *entities.HistoryQuery) (*entities.HistoryPage, error)
+	Delete(userID, entryID string) error
+	// Clear deletes the reading history of the user, or only its entries of a manga when set, returning how many
+	// entries were deleted
+	Clear(userID string, mangaID *string) (int64, error)
+	// SetPaused changes whether the chapters the user opens are recorded
+	SetPaused(userID string, paused bool) error
+}
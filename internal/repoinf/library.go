@@ -0,0 +1,30 @@
+package repoinf
+
+import (
+	"hotaku-api/internal/domain/entities"
+)
+
+// LibraryRepository defines the interface for user library data access
+type LibraryRepository interface {
+	// ListShelves retrieves the shelves of a user, built-in ones first
+	ListShelves(userID string) ([]entities.LibraryShelf, error)
+	// CreateShelves saves shelves, skipping the built-in ones the user already has
+	CreateShelves(shelves []entities.LibraryShelf) error
+	// CreateShelf saves a shelf, reporting false when the user already has a shelf with the same name
+	CreateShelf(shelf *entities.LibraryShelf) (bool, error)
+	GetShelf(shelfID string) (*entities.LibraryShelf, error)
+	UpdateShelf(shelf *entities.LibraryShelf) error
+	// DeleteShelf removes a shelf, its favorites staying in the library without a shelf
+	DeleteShelf(shelfID string) error
+	// CountByShelf returns how many favorites of the user each shelf holds
+	CountByShelf(userID string) (map[string]int64, error)
+	GetFavorite(userID, mangaID string) (*entities.UserFavoriteManga, error)
+	AddFavorite(favorite *entities.UserFavoriteManga) error
+	// UpdateFavoriteShelf moves a favorite to a shelf, nil taking it off its shelf
+	UpdateFavoriteShelf(favoriteID string, shelfID *string) error
+	DeleteFavorite(userID, mangaID string) error
+	// List retrieves a page of a user's library with the chapter counts of its mangas
+	List(query *entities.LibraryQuery) (*entities.LibraryResult, error)
+	// SetPublic changes whether anyone may see the user's library
+	SetPublic(userID string, public bool) error
+}
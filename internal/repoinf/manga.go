@@ -0,0 +1,19 @@
+package repoinf
+
+import (
+	"hotaku-api/internal/domain/entities"
+)
+
+// MangaRepository defines the interface for manga data access
+type MangaRepository interface {
+	GetByID(id string) (*entities.Manga, error)
+	UpdateVisibility(id, visibility string) error
+	// UpdateYear sets the year a manga was first published, nil clearing it
+	UpdateYear(id string, year *int) error
+	// RefreshPopularity recomputes the popularity listings are sorted by
+	RefreshPopularity() error
+	// ListWithoutCovers retrieves the mangas that have no cover recorded
+	ListWithoutCovers() ([]entities.Manga, error)
+	// List returns a page of mangas matching the query, along with the total count of matches
+	List(query *entities.MangaListQuery) (*entities.MangaListResult, error)
+}
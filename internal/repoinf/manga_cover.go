@@ -0,0 +1,14 @@
+package repoinf
+
+import (
+	"hotaku-api/internal/domain/entities"
+)
+
+// MangaCoverRepository defines the interface for manga cover data access
+type MangaCoverRepository interface {
+	// Create saves a cover, making it primary if requested or if the manga has no cover yet
+	Create(cover *entities.MangaCover) error
+	GetByID(coverID string) (*entities.MangaCover, error)
+	ListByManga(mangaID string) ([]entities.MangaCover, error)
+	SetPrimary(mangaID, coverID string) error
+}
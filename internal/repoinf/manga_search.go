@@ -0,0 +1,25 @@
+package repoinf
+
+import (
+	"hotaku-api/internal/domain/entities"
+)
+
+// MangaSearchRepository defines the interface for full-text manga search, so the search backend can be
+// replaced without touching the use cases
+type MangaSearchRepository interface {
+	// Search returns a page of mangas matching the query, along with the total count and facets of the matches
+	Search(query *entities.MangaSearchQuery) (*entities.MangaSearchResult, error)
+}
+
+// MangaSearchIndex defines the interface of a manga search backend held in memory and kept in sync with the database
+type MangaSearchIndex interface {
+	MangaSearchRepository
+	// Rebuild indexes every manga from scratch
+	Rebuild() (*entities.MangaSearchIndexStats, error)
+	// Sync reindexes the mangas changed since the last rebuild or sync and drops the deleted ones
+	Sync() (*entities.MangaSearchIndexStats, error)
+	// Save writes a snapshot of the index to a file
+	Save(path string) error
+	// Load replaces the index with a snapshot written by Save
+	Load(path string) error
+}
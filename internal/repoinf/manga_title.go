@@ -0,0 +1,18 @@
+package repoinf
+
+import (
+	"hotaku-api/internal/domain/entities"
+)
+
+// MangaTitleRepository defines the interface for localized manga title and description data access
+type MangaTitleRepository interface {
+	// Create saves a title, making it primary if requested or if its locale has no title yet
+	Create(title *entities.MangaTitle) error
+	ListByManga(mangaID string) ([]entities.MangaTitle, error)
+	// Delete removes a title, promoting another title of its locale when it was the primary one
+	Delete(mangaID, titleID string) error
+	ListDescriptions(mangaID string) ([]entities.MangaDescription, error)
+	// SaveDescription creates or replaces the description of a manga in a locale
+	SaveDescription(description *entities.MangaDescription) error
+	DeleteDescription(mangaID, locale string) error
+}
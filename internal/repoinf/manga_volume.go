@@ -0,0 +1,19 @@
+package repoinf
+
+import (
+	"hotaku-api/internal/domain/entities"
+)
+
+// MangaVolumeRepository defines the interface for manga volume data access
+type MangaVolumeRepository interface {
+	Create(volume *entities.MangaVolume) error
+	// GetByID retrieves a volume along with its cover
+	GetByID(volumeID string) (*entities.MangaVolume, error)
+	// ListByManga retrieves all volumes of a manga with their cover, by volume number
+	ListByManga(mangaID string) ([]entities.MangaVolume, error)
+	Update(volume *entities.MangaVolume) error
+	// Delete removes a volume, its chapters staying in the manga without a volume
+	Delete(volumeID string) error
+	// SetChapters makes the chapters the only ones of the volume, moving them out of any other volume
+	SetChapters(volumeID string, chapterIDs []string) error
+}
@@ -0,0 +1,15 @@
+package repoinf
+
+import (
+	"hotaku-api/internal/domain/entities"
+)
+
+// PageDuplicateRepository defines the interface for duplicate page flag data access
+type PageDuplicateRepository interface {
+	// CreateFlags saves the flags, skipping page pairs that were already flagged
+	CreateFlags(flags []entities.PageDuplicateFlag) error
+	GetByID(flagID string) (*entities.PageDuplicateFlag, error)
+	// List returns a page of flags, optionally filtered by status and manga, along with the total count
+	List(status, mangaID string, offset, limit int) ([]entities.PageDuplicateFlag, int64, error)
+	UpdateStatus(flagID, status, reviewerID string) error
+}
@@ -0,0 +1,19 @@
+package repoinf
+
+import (
+	"time"
+
+	"hotaku-api/internal/domain/entities"
+)
+
+// ReadingRepository defines the interface for reading progress data access
+type ReadingRepository interface {
+	// MarkRead records that the user read the chapters, moving the read time of those already read
+	MarkRead(userID string, chapterIDs []string, readAt time.Time) error
+	MarkUnread(userID string, chapterIDs []string) error
+	// SavePosition creates or replaces the last page of a chapter the user was on
+	SavePosition(position *entities.UserChapterPosition) error
+	ListPositionsByManga(userID, mangaID string) ([]entities.UserChapterPosition, error)
+	// ListFeed retrieves a page of the mangas the user read or opened a chapter of, the most recent first
+	ListFeed(query *entities.ReadingFeedQuery) (*entities.ReadingFeed, error)
+}
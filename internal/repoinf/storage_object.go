@@ -0,0 +1,23 @@
+package repoinf
+
+import (
+	"hotaku-api/internal/domain/entities"
+)
+
+// StorageObjectRepository defines the interface for stored file ownership data access
+type StorageObjectRepository interface {
+	Create(object *entities.StorageObject) error
+	GetByName(objectName string) (*entities.StorageObject, error)
+	Delete(objectName string) error
+	// UsageByUser returns the total size and number of objects uploaded by the user
+	UsageByUser(userID string) (int64, int64, error)
+	// UsageByGroup returns the total size and number of objects credited to the group
+	UsageByGroup(groupID string) (int64, int64, error)
+	// CreateWithinQuota saves a new storage object record unless it takes its uploader or group over their quota,
+	// zero meaning unlimited, reporting whether it was saved
+	CreateWithinQuota(object *entities.StorageObject, userQuota, groupQuota int64) (bool, error)
+	// DeleteWithReferences removes the chapter pages and covers showing the object that were added by one of owners,
+	// or all of them when owners is nil, whatever base URL their image URL was built with. The object record is
+	// removed along with the last reference, which is reported so the caller deletes the object itself
+	DeleteWithReferences(objectName string, owners []string) (bool, error)
+}
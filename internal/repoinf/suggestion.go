@@ -0,0 +1,13 @@
+package repoinf
+
+import (
+	"hotaku-api/internal/domain/entities"
+)
+
+// SuggestionRepository defines the interface for loading what search suggestions are built from
+type SuggestionRepository interface {
+	// Load returns every manga, author, group and category along with their links and popularity signals
+	Load() (*entities.SuggestionSource, error)
+	// Fingerprint returns a value that changes whenever mangas, authors, groups, categories, their links or favorites change
+	Fingerprint() (string, error)
+}
@@ -0,0 +1,23 @@
+package repoinf
+
+import (
+	"time"
+
+	"hotaku-api/internal/domain/entities"
+)
+
+// UploadIntentRepository defines the interface for upload intent data access
+type UploadIntentRepository interface {
+	Create(intent *entities.UploadIntent) error
+	GetByID(id string) (*entities.UploadIntent, error)
+	// Claim reserves an upload intent for a finalize call until the lease runs out, failing if it was finalized or
+	// another call holds it
+	Claim(id string, lease time.Duration) error
+	// Release gives up the claim on an upload intent so finalizing can be retried
+	Release(id string) error
+	MarkFinalized(id string) error
+	// ListExpired retrieves up to limit intents that expired before the given time without being finalized and
+	// nobody is finalizing
+	ListExpired(before time.Time, limit int) ([]entities.UploadIntent, error)
+	Delete(id string) error
+}
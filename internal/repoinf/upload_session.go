@@ -0,0 +1,25 @@
+package repoinf
+
+import (
+	"time"
+
+	"hotaku-api/internal/domain/entities"
+)
+
+// UploadSessionRepository defines the interface for upload session data access
+type UploadSessionRepository interface {
+	Create(session *entities.UploadSession) error
+	GetByID(id string) (*entities.UploadSession, error)
+	AppendPart(sessionID string, expectedOffset int64, part *entities.UploadSessionPart) error
+	ListParts(sessionID string) ([]entities.UploadSessionPart, error)
+	// Claim reserves an upload session for a finalize call until the lease runs out, failing if it was completed
+	// or another call holds it
+	Claim(id string, lease time.Duration) error
+	// Release gives up the claim on an upload session so finalizing can be retried
+	Release(id string) error
+	MarkCompleted(id string) error
+	// ListExpired retrieves up to limit incomplete sessions that expired before the given time and nobody is
+	// finalizing
+	ListExpired(before time.Time, limit int) ([]entities.UploadSession, error)
+	Delete(id string) error
+}
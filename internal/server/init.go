@@ -4,8 +4,10 @@ import (
 	"hotaku-api/config"
 	"hotaku-api/internal/controllers"
 	"hotaku-api/internal/repo"
+	"hotaku-api/internal/repoinf"
 	"hotaku-api/internal/service"
 	"hotaku-api/internal/usecase"
+	"log"
 	"os"
 )
 
@@ -16,6 +18,22 @@ func InitializeServer() *Server {
 
 	// Initialize repositories
 	userRepo := repo.NewUserRepository(config.DB)
+	chapterRepo := repo.NewChapterRepository(config.DB)
+	chapterPageRepo := repo.NewChapterPageRepository(config.DB)
+	mangaRepo := repo.NewMangaRepository(config.DB)
+	uploadIntentRepo := repo.NewUploadIntentRepository(config.DB)
+	uploadSessionRepo := repo.NewUploadSessionRepository(config.DB)
+	groupRepo := repo.NewGroupRepository(config.DB)
+	storageObjectRepo := repo.NewStorageObjectRepository(config.DB)
+	mangaCoverRepo := repo.NewMangaCoverRepository(config.DB)
+	mangaTitleRepo := repo.NewMangaTitleRepository(config.DB)
+	mangaVolumeRepo := repo.NewMangaVolumeRepository(config.DB)
+	readingRepo := repo.NewReadingRepository(config.DB)
+	libraryRepo := repo.NewLibraryRepository(config.DB)
+	historyRepo := repo.NewHistoryRepository(config.DB)
+	pageDuplicateRepo := repo.NewPageDuplicateRepository(config.DB)
+	groupWatermarkRepo := repo.NewGroupWatermarkRepository(config.DB)
+	suggestionRepo := repo.NewSuggestionRepository(config.DB)
 
 	// Get JWT secret from environment
 	jwtSecret := os.Getenv("JWT_SECRET")
@@ -27,17 +45,64 @@ func InitializeServer() *Server {
 	// Initialize MinIO service
 	appConfig := config.LoadConfig()
 	minioService := InitializeMinioService(appConfig)
+	imageURLService := service.NewImageURLService(minioService, appConfig.MinIO.PrivateBucket, appConfig.ImageURL.BaseURL, appConfig.ImageURL.Secret, appConfig.ImageURL.TTL)
+	watermarkService := service.NewWatermarkService(appConfig.Image.JPEGQuality)
+	mangaSearchRepo, searchIndex := InitializeSearch(appConfig)
 
 	// Initialize use cases
 	authUseCase := usecase.NewAuthUseCase(userRepo, tokenService)
+	fileUseCase := usecase.NewFileUseCase(userRepo, groupRepo, storageObjectRepo, minioService, appConfig.Quota.UserBytes, appConfig.Quota.GroupBytes)
+	chapterPageUseCase := usecase.NewChapterPageUseCase(userRepo, groupRepo, chapterRepo, chapterPageRepo, pageDuplicateRepo, fileUseCase, minioService, appConfig.Upload.MaxPages, appConfig.Upload.Concurrency)
+	mangaUseCase := usecase.NewMangaUseCase(userRepo, mangaRepo, groupRepo, imageURLService, appConfig.MinIO.PrivateBucket)
+	quotaUseCase := usecase.NewQuotaUseCase(groupRepo, storageObjectRepo, appConfig.Quota.UserBytes, appConfig.Quota.GroupBytes)
+	mangaCoverUseCase := usecase.NewMangaCoverUseCase(userRepo, groupRepo, mangaRepo, mangaCoverRepo, fileUseCase, minioService, imageURLService)
+	watermarkUseCase := usecase.NewWatermarkUseCase(userRepo, groupRepo, chapterRepo, groupWatermarkRepo, minioService, watermarkService, appConfig.MinIO.PrivateBucket)
+	chapterUseCase := usecase.NewChapterUseCase(userRepo, mangaRepo, chapterRepo, chapterPageRepo, groupRepo, mangaVolumeRepo, minioService, imageURLService, watermarkUseCase)
+	directUploadUseCase := usecase.NewDirectUploadUseCase(uploadIntentRepo, mangaRepo, chapterRepo, chapterPageUseCase, mangaCoverUseCase, minioService)
+	uploadSessionUseCase := usecase.NewUploadSessionUseCase(uploadSessionRepo, chapterRepo, chapterPageUseCase, minioService)
+	storageGCUseCase := usecase.NewStorageGCUseCase(chapterRepo, chapterPageRepo, mangaCoverRepo, storageObjectRepo, groupWatermarkRepo, minioService)
+	moderationUseCase := usecase.NewModerationUseCase(userRepo, pageDuplicateRepo)
+	imageUseCase := usecase.NewImageUseCase(mangaRepo, chapterPageRepo, imageURLService)
+	searchUseCase := usecase.NewSearchUseCase(userRepo, groupRepo, mangaSearchRepo, imageURLService, searchIndex, appConfig.Search.IndexPath)
+	suggestUseCase := usecase.NewSuggestUseCase(suggestionRepo)
+	mangaTitleUseCase := usecase.NewMangaTitleUseCase(userRepo, mangaRepo, mangaTitleRepo, suggestUseCase)
+	volumeUseCase := usecase.NewVolumeUseCase(userRepo, mangaRepo, mangaVolumeRepo, chapterRepo, mangaCoverRepo, groupRepo, imageURLService)
+	readingUseCase := usecase.NewReadingUseCase(userRepo, mangaRepo, chapterRepo, chapterPageRepo, readingRepo, historyRepo, groupRepo, imageURLService)
+	libraryUseCase := usecase.NewLibraryUseCase(userRepo, mangaRepo, libraryRepo, groupRepo, imageURLService, suggestUseCase)
+	historyUseCase := usecase.NewHistoryUseCase(userRepo, historyRepo)
+
+	backfillLegacyPages(chapterPageUseCase)
+	backfillLegacyCovers(mangaCoverUseCase)
+	if appConfig.StorageGC.Enabled {
+		startStorageGC(storageGCUseCase, appConfig.StorageGC)
+	}
+	startUploadSessionSweep(uploadSessionUseCase, appConfig.Upload.SessionSweepInterval)
+	startUploadIntentSweep(directUploadUseCase, appConfig.Upload.SessionSweepInterval)
+	if searchIndex != nil {
+		startSearchIndexSync(searchIndex, appConfig.Search.SyncInterval)
+	}
+	startSuggestionRefresh(suggestUseCase, appConfig.Search.SuggestRefreshInterval)
+	startPopularityRefresh(mangaUseCase, appConfig.Search.PopularityRefreshInterval)
+	startWatermarkRendering(chapterUseCase, appConfig.Watermark)
 
 	// Initialize controllers
 	authController := controllers.NewAuthController(authUseCase)
 	healthController := controllers.NewHealthController()
-	uploadController := controllers.NewUploadController(minioService)
+	uploadController := controllers.NewUploadController(minioService, chapterPageUseCase, directUploadUseCase, fileUseCase, mangaCoverUseCase, quotaUseCase, imageUseCase, appConfig.Upload.MaxRequestBytes)
+	chapterController := controllers.NewChapterController(chapterUseCase)
+	sessionController := controllers.NewUploadSessionController(uploadSessionUseCase, quotaUseCase)
+	mangaController := controllers.NewMangaController(mangaUseCase, mangaCoverUseCase, mangaTitleUseCase, quotaUseCase)
+	storageController := controllers.NewStorageController(quotaUseCase)
+	moderationController := controllers.NewModerationController(moderationUseCase)
+	groupController := controllers.NewGroupController(watermarkUseCase)
+	searchController := controllers.NewSearchController(searchUseCase, suggestUseCase)
+	volumeController := controllers.NewVolumeController(volumeUseCase, chapterUseCase)
+	readingController := controllers.NewReadingController(readingUseCase)
+	libraryController := controllers.NewLibraryController(libraryUseCase)
+	historyController := controllers.NewHistoryController(historyUseCase)
 
 	// Initialize and return server
-	return NewServer(authController, healthController, uploadController, tokenService)
+	return NewServer(authController, healthController, uploadController, chapterController, sessionController, mangaController, storageController, moderationController, groupController, searchController, volumeController, readingController, libraryController, historyController, tokenService, appConfig)
 }
 
 // InitializeServerWithConfig creates and configures all dependencies with custom config
@@ -47,6 +112,22 @@ func InitializeServerWithConfig(appConfig *config.Config) *Server {
 
 	// Initialize repositories
 	userRepo := repo.NewUserRepository(config.DB)
+	chapterRepo := repo.NewChapterRepository(config.DB)
+	chapterPageRepo := repo.NewChapterPageRepository(config.DB)
+	mangaRepo := repo.NewMangaRepository(config.DB)
+	uploadIntentRepo := repo.NewUploadIntentRepository(config.DB)
+	uploadSessionRepo := repo.NewUploadSessionRepository(config.DB)
+	groupRepo := repo.NewGroupRepository(config.DB)
+	storageObjectRepo := repo.NewStorageObjectRepository(config.DB)
+	mangaCoverRepo := repo.NewMangaCoverRepository(config.DB)
+	mangaTitleRepo := repo.NewMangaTitleRepository(config.DB)
+	mangaVolumeRepo := repo.NewMangaVolumeRepository(config.DB)
+	readingRepo := repo.NewReadingRepository(config.DB)
+	libraryRepo := repo.NewLibraryRepository(config.DB)
+	historyRepo := repo.NewHistoryRepository(config.DB)
+	pageDuplicateRepo := repo.NewPageDuplicateRepository(config.DB)
+	groupWatermarkRepo := repo.NewGroupWatermarkRepository(config.DB)
+	suggestionRepo := repo.NewSuggestionRepository(config.DB)
 
 	// Get JWT secret from environment
 	jwtSecret := os.Getenv("JWT_SECRET")
@@ -57,17 +138,64 @@ func InitializeServerWithConfig(appConfig *config.Config) *Server {
 
 	// Initialize MinIO service
 	minioService := InitializeMinioService(appConfig)
+	imageURLService := service.NewImageURLService(minioService, appConfig.MinIO.PrivateBucket, appConfig.ImageURL.BaseURL, appConfig.ImageURL.Secret, appConfig.ImageURL.TTL)
+	watermarkService := service.NewWatermarkService(appConfig.Image.JPEGQuality)
+	mangaSearchRepo, searchIndex := InitializeSearch(appConfig)
 
 	// Initialize use cases
 	authUseCase := usecase.NewAuthUseCase(userRepo, tokenService)
+	fileUseCase := usecase.NewFileUseCase(userRepo, groupRepo, storageObjectRepo, minioService, appConfig.Quota.UserBytes, appConfig.Quota.GroupBytes)
+	chapterPageUseCase := usecase.NewChapterPageUseCase(userRepo, groupRepo, chapterRepo, chapterPageRepo, pageDuplicateRepo, fileUseCase, minioService, appConfig.Upload.MaxPages, appConfig.Upload.Concurrency)
+	mangaUseCase := usecase.NewMangaUseCase(userRepo, mangaRepo, groupRepo, imageURLService, appConfig.MinIO.PrivateBucket)
+	quotaUseCase := usecase.NewQuotaUseCase(groupRepo, storageObjectRepo, appConfig.Quota.UserBytes, appConfig.Quota.GroupBytes)
+	mangaCoverUseCase := usecase.NewMangaCoverUseCase(userRepo, groupRepo, mangaRepo, mangaCoverRepo, fileUseCase, minioService, imageURLService)
+	watermarkUseCase := usecase.NewWatermarkUseCase(userRepo, groupRepo, chapterRepo, groupWatermarkRepo, minioService, watermarkService, appConfig.MinIO.PrivateBucket)
+	chapterUseCase := usecase.NewChapterUseCase(userRepo, mangaRepo, chapterRepo, chapterPageRepo, groupRepo, mangaVolumeRepo, minioService, imageURLService, watermarkUseCase)
+	directUploadUseCase := usecase.NewDirectUploadUseCase(uploadIntentRepo, mangaRepo, chapterRepo, chapterPageUseCase, mangaCoverUseCase, minioService)
+	uploadSessionUseCase := usecase.NewUploadSessionUseCase(uploadSessionRepo, chapterRepo, chapterPageUseCase, minioService)
+	storageGCUseCase := usecase.NewStorageGCUseCase(chapterRepo, chapterPageRepo, mangaCoverRepo, storageObjectRepo, groupWatermarkRepo, minioService)
+	moderationUseCase := usecase.NewModerationUseCase(userRepo, pageDuplicateRepo)
+	imageUseCase := usecase.NewImageUseCase(mangaRepo, chapterPageRepo, imageURLService)
+	searchUseCase := usecase.NewSearchUseCase(userRepo, groupRepo, mangaSearchRepo, imageURLService, searchIndex, appConfig.Search.IndexPath)
+	suggestUseCase := usecase.NewSuggestUseCase(suggestionRepo)
+	mangaTitleUseCase := usecase.NewMangaTitleUseCase(userRepo, mangaRepo, mangaTitleRepo, suggestUseCase)
+	volumeUseCase := usecase.NewVolumeUseCase(userRepo, mangaRepo, mangaVolumeRepo, chapterRepo, mangaCoverRepo, groupRepo, imageURLService)
+	readingUseCase := usecase.NewReadingUseCase(userRepo, mangaRepo, chapterRepo, chapterPageRepo, readingRepo, historyRepo, groupRepo, imageURLService)
+	libraryUseCase := usecase.NewLibraryUseCase(userRepo, mangaRepo, libraryRepo, groupRepo, imageURLService, suggestUseCase)
+	historyUseCase := usecase.NewHistoryUseCase(userRepo, historyRepo)
+
+	backfillLegacyPages(chapterPageUseCase)
+	backfillLegacyCovers(mangaCoverUseCase)
+	if appConfig.StorageGC.Enabled {
+		startStorageGC(storageGCUseCase, appConfig.StorageGC)
+	}
+	startUploadSessionSweep(uploadSessionUseCase, appConfig.Upload.SessionSweepInterval)
+	startUploadIntentSweep(directUploadUseCase, appConfig.Upload.SessionSweepInterval)
+	if searchIndex != nil {
+		startSearchIndexSync(searchIndex, appConfig.Search.SyncInterval)
+	}
+	startSuggestionRefresh(suggestUseCase, appConfig.Search.SuggestRefreshInterval)
+	startPopularityRefresh(mangaUseCase, appConfig.Search.PopularityRefreshInterval)
+	startWatermarkRendering(chapterUseCase, appConfig.Watermark)
 
 	// Initialize controllers
 	authController := controllers.NewAuthController(authUseCase)
 	healthController := controllers.NewHealthController()
-	uploadController := controllers.NewUploadController(minioService)
+	uploadController := controllers.NewUploadController(minioService, chapterPageUseCase, directUploadUseCase, fileUseCase, mangaCoverUseCase, quotaUseCase, imageUseCase, appConfig.Upload.MaxRequestBytes)
+	chapterController := controllers.NewChapterController(chapterUseCase)
+	sessionController := controllers.NewUploadSessionController(uploadSessionUseCase, quotaUseCase)
+	mangaController := controllers.NewMangaController(mangaUseCase, mangaCoverUseCase, mangaTitleUseCase, quotaUseCase)
+	storageController := controllers.NewStorageController(quotaUseCase)
+	moderationController := controllers.NewModerationController(moderationUseCase)
+	groupController := controllers.NewGroupController(watermarkUseCase)
+	searchController := controllers.NewSearchController(searchUseCase, suggestUseCase)
+	volumeController := controllers.NewVolumeController(volumeUseCase, chapterUseCase)
+	readingController := controllers.NewReadingController(readingUseCase)
+	libraryController := controllers.NewLibraryController(libraryUseCase)
+	historyController := controllers.NewHistoryController(historyUseCase)
 
 	// Initialize and return server
-	return NewServer(authController, healthController, uploadController, tokenService)
+	return NewServer(authController, healthController, uploadController, chapterController, sessionController, mangaController, storageController, moderationController, groupController, searchController, volumeController, readingController, libraryController, historyController, tokenService, appConfig)
 }
 
 // InitializeMinioService initializes the MinIO service
@@ -78,3 +206,25 @@ func InitializeMinioService(appConfig *config.Config) *service.MinIOService {
 	}
 	return minioService
 }
+
+// InitializeSearch returns the configured manga search backend, along with the embedded search index when it is
+// the one. The index starts from its last snapshot and catches up with the database, or indexes every manga
+func InitializeSearch(appConfig *config.Config) (repoinf.MangaSearchRepository, repoinf.MangaSearchIndex) {
+	if appConfig.Search.Backend != config.SearchBackendIndex {
+		return repo.NewMangaSearchRepository(config.DB), nil
+	}
+
+	index := repo.NewMangaSearchIndex(config.DB)
+	if err := index.Load(appConfig.Search.IndexPath); err != nil {
+		log.Printf("Search index snapshot not loaded, indexing every manga: %v", err)
+	}
+	stats, err := index.Sync()
+	if err != nil {
+		panic("Failed to build search index: " + err.Error())
+	}
+	if err := index.Save(appConfig.Search.IndexPath); err != nil {
+		log.Printf("Failed to save search index snapshot: %v", err)
+	}
+	log.Printf("Search index ready: %d mangas, %d terms", stats.Documents, stats.Terms)
+	return index, index
+}
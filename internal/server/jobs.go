@@ -0,0 +1,152 @@
+package server
+
+import (
+	"hotaku-api/config"
+	"hotaku-api/internal/repoinf"
+	"hotaku-api/internal/usecaseinf"
+	"log"
+	"time"
+)
+
+// startStorageGC periodically removes orphaned objects from storage in the background
+func startStorageGC(gcUseCase usecaseinf.StorageGCUseCase, gcConfig config.StorageGCConfig) {
+	go func() {
+		ticker := time.NewTicker(gcConfig.Interval)
+		defer ticker.Stop()
+
+		for range ticker.C {
+			report, err := gcUseCase.CollectGarbage(gcConfig.DryRun, gcConfig.GracePeriod)
+			if err != nil {
+				log.Printf("Storage garbage collection failed: %v", err)
+				continue
+			}
+			log.Printf("Storage garbage collection: scanned %d objects, %d orphans, deleted %d, freed %d bytes, %d failures (dry run: %t)",
+				report.Scanned, len(report.Orphans), report.Deleted, report.FreedBytes, len(report.Failed), report.DryRun)
+		}
+	}()
+}
+
+// startUploadSessionSweep periodically discards the resumable uploads that expired before being finalized
+func startUploadSessionSweep(sessionUseCase usecaseinf.UploadSessionUseCase, interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for range ticker.C {
+			swept, err := sessionUseCase.SweepExpired()
+			if err != nil {
+				log.Printf("Upload session sweep failed: %v", err)
+			}
+			if swept > 0 {
+				log.Printf("Upload session sweep: discarded %d expired sessions", swept)
+			}
+		}
+	}()
+}
+
+// startUploadIntentSweep periodically discards the presigned uploads that expired before being finalized
+func startUploadIntentSweep(directUploadUseCase usecaseinf.DirectUploadUseCase, interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for range ticker.C {
+			swept, err := directUploadUseCase.SweepExpired()
+			if err != nil {
+				log.Printf("Upload intent sweep failed: %v", err)
+			}
+			if swept > 0 {
+				log.Printf("Upload intent sweep: discarded %d expired uploads", swept)
+			}
+		}
+	}()
+}
+
+// startSearchIndexSync periodically applies manga changes to the embedded search index in the background
+func startSearchIndexSync(index repoinf.MangaSearchIndex, interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for range ticker.C {
+			if _, err := index.Sync(); err != nil {
+				log.Printf("Search index sync failed: %v", err)
+			}
+		}
+	}()
+}
+
+// startSuggestionRefresh builds the search suggestions in the background, then rebuilds them whenever
+// what they are built from changed
+func startSuggestionRefresh(suggestUseCase usecaseinf.SuggestUseCase, interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			if err := suggestUseCase.Refresh(); err != nil {
+				log.Printf("Search suggestion refresh failed: %v", err)
+			}
+			<-ticker.C
+		}
+	}()
+}
+
+// startPopularityRefresh recomputes the popularity mangas are listed by in the background, then again periodically
+func startPopularityRefresh(mangaUseCase usecaseinf.MangaUseCase, interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			if err := mangaUseCase.RefreshPopularity(); err != nil {
+				log.Printf("Manga popularity refresh failed: %v", err)
+			}
+			<-ticker.C
+		}
+	}()
+}
+
+// startWatermarkRendering periodically renders the missing watermarked variants of the watermarked chapters in the
+// background, a bounded batch at a time
+func startWatermarkRendering(chapterUseCase usecaseinf.ChapterUseCase, watermarkConfig config.WatermarkConfig) {
+	go func() {
+		ticker := time.NewTicker(watermarkConfig.RenderInterval)
+		defer ticker.Stop()
+
+		for range ticker.C {
+			rendered, err := chapterUseCase.RenderWatermarks(watermarkConfig.RenderBatch)
+			if err != nil {
+				log.Printf("Watermark rendering failed: %v", err)
+			}
+			if rendered > 0 {
+				log.Printf("Watermark rendering: rendered %d pages", rendered)
+			}
+		}
+	}()
+}
+
+// backfillLegacyCovers records the covers of the mangas whose images were uploaded before covers were recorded
+func backfillLegacyCovers(coverUseCase usecaseinf.MangaCoverUseCase) {
+	mangas, covers, err := coverUseCase.BackfillLegacyCovers()
+	if err != nil {
+		log.Printf("Legacy cover backfill failed: %v", err)
+		return
+	}
+	if mangas > 0 {
+		log.Printf("Legacy cover backfill: recorded %d covers of %d mangas", covers, mangas)
+	}
+}
+
+// backfillLegacyPages records the pages of the chapters uploaded before pages were recorded, before any request
+// reads them
+func backfillLegacyPages(pageUseCase usecaseinf.ChapterPageUseCase) {
+	chapters, pages, err := pageUseCase.BackfillLegacyPages()
+	if err != nil {
+		log.Printf("Legacy page backfill failed: %v", err)
+		return
+	}
+	if chapters > 0 {
+		log.Printf("Legacy page backfill: recorded %d pages of %d chapters", pages, chapters)
+	}
+}
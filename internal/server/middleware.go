@@ -1,6 +1,7 @@
 package server
 
 import (
+	"hotaku-api/config"
 	"hotaku-api/internal/middleware"
 	"hotaku-api/internal/serviceinf"
 	"time"
@@ -18,14 +19,21 @@ func (s *Server) setupMiddleware() {
 	// CORS middleware (if needed)
 	s.router.Use(cors.New(cors.Config{
 		AllowOrigins:     []string{"*"}, // TODO: Change to specific origins
-		AllowMethods:     []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
-		AllowHeaders:     []string{"Origin", "Content-Type", "Accept", "Authorization"},
+		AllowMethods:     []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
+		AllowHeaders:     []string{"Origin", "Content-Type", "Accept", "Authorization", "Upload-Offset"},
+		ExposeHeaders:    []string{"Upload-Offset", "Upload-Length"},
 		AllowCredentials: true,
 		MaxAge:           12 * time.Hour,
 	}))
 }
 
-// setupAuthMiddleware creates the authentication middleware
+// setupAuthMiddleware creates the authentication middlewares
 func (s *Server) setupAuthMiddleware(tokenService serviceinf.TokenService) {
 	s.authMiddleware = middleware.AuthMiddleware(tokenService)
+	s.optionalAuthMiddleware = middleware.OptionalAuthMiddleware(tokenService)
+}
+
+// setupRateLimitMiddleware creates the per-user rate limiters
+func (s *Server) setupRateLimitMiddleware(downloadConfig config.DownloadConfig) {
+	s.downloadLimiter = middleware.RateLimitMiddleware(downloadConfig.RateLimit, downloadConfig.RateWindow)
 }
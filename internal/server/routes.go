@@ -26,11 +26,161 @@ func (s *Server) setupRoutes() {
 	{
 		upload.POST("/manga/:manga_id/image", s.uploadController.UploadMangaImage)
 		upload.POST("/manga/:manga_id/chapters/:chapter_id/pages", s.uploadController.UploadChapterPages)
+		upload.POST("/manga/:manga_id/chapters/:chapter_id/archive", s.uploadController.UploadChapterArchive)
 		upload.PUT("/manga/:manga_id/chapters/:chapter_id/pages/:page", s.uploadController.ReplacePage)
+		upload.PUT("/manga/:manga_id/chapters/:chapter_id/pages/order", s.uploadController.ReorderPages)
+		upload.POST("/presigned", s.uploadController.PresignUpload)
+		upload.POST("/presigned/:upload_id/finalize", s.uploadController.FinalizeUpload)
+		upload.POST("/sessions", s.sessionController.CreateSession)
+		upload.GET("/sessions/:session_id", s.sessionController.GetSession)
+		upload.PATCH("/sessions/:session_id", s.sessionController.UploadChunk)
+		upload.POST("/sessions/:session_id/finalize", s.sessionController.FinalizeSession)
+		upload.DELETE("/sessions/:session_id", s.sessionController.CancelSession)
 		upload.DELETE("/files/*object_name", s.uploadController.DeleteFile)
 		upload.GET("/files/*object_name", s.uploadController.GetFileInfo)
 	}
 
+	// Setup manga routes
+	mangas := s.router.Group("/api/v1/mangas")
+	mangas.Use(s.optionalAuthMiddleware) // Restricted mangas show their images to signed in users allowed to see them
+	{
+		mangas.GET("", s.mangaController.ListMangas)
+		mangas.GET("/:id", s.mangaController.GetManga)
+		mangas.GET("/:id/covers", s.mangaController.ListCovers)
+		mangas.GET("/:id/titles", s.mangaController.ListTitles)
+		mangas.GET("/:id/descriptions", s.mangaController.ListDescriptions)
+		mangas.GET("/:id/chapters", s.chapterController.ListChapters)
+		mangas.GET("/:id/volumes", s.volumeController.ListVolumes)
+
+		protected := mangas.Group("")
+		protected.Use(s.authMiddleware)
+		{
+			protected.PUT("/:id/visibility", s.mangaController.SetVisibility)
+			protected.PUT("/:id/year", s.mangaController.SetYear)
+			protected.POST("/:id/covers", s.mangaController.UploadCover)
+			protected.PUT("/:id/covers/:cover_id/primary", s.mangaController.SetPrimaryCover)
+			protected.DELETE("/:id/covers/:cover_id", s.mangaController.DeleteCover)
+			protected.POST("/:id/titles", s.mangaController.AddTitle)
+			protected.DELETE("/:id/titles/:title_id", s.mangaController.DeleteTitle)
+			protected.PUT("/:id/descriptions/:locale", s.mangaController.SetDescription)
+			protected.DELETE("/:id/descriptions/:locale", s.mangaController.DeleteDescription)
+			protected.GET("/:id/progress", s.readingController.GetProgress)
+			protected.POST("/:id/read", s.readingController.MarkReadUpTo)
+			protected.GET("/:id/volumes/progress", s.volumeController.GetProgress)
+			protected.POST("/:id/volumes", s.volumeController.CreateVolume)
+			protected.PUT("/:id/volumes/:volume_id", s.volumeController.UpdateVolume)
+			protected.DELETE("/:id/volumes/:volume_id", s.volumeController.DeleteVolume)
+			protected.PUT("/:id/volumes/:volume_id/chapters", s.volumeController.SetChapters)
+		}
+	}
+
+	// Setup storage routes
+	storage := s.router.Group("/api/v1/storage")
+	storage.Use(s.authMiddleware)
+	{
+		storage.GET("/usage", s.storageController.GetUsage)
+	}
+
+	// Setup moderation routes
+	moderation := s.router.Group("/api/v1/moderation")
+	moderation.Use(s.authMiddleware)
+	{
+		moderation.GET("/duplicates", s.moderationController.ListDuplicates)
+		moderation.PUT("/duplicates/:flag_id", s.moderationController.ReviewDuplicate)
+	}
+
+	// Setup chapter routes
+	chapters := s.router.Group("/api/v1/chapters")
+	chapters.Use(s.optionalAuthMiddleware)
+	{
+		chapters.GET("/:id/pages", s.chapterController.GetManifest)
+
+		protected := chapters.Group("")
+		protected.Use(s.authMiddleware)
+		{
+			protected.GET("/:id/download.cbz", s.downloadLimiter, s.chapterController.DownloadChapter)
+			protected.PUT("/:id/watermark", s.chapterController.SetWatermark)
+			protected.PUT("/:id/attribution", s.chapterController.SetAttribution)
+			protected.PUT("/:id/read", s.readingController.MarkRead)
+			protected.DELETE("/:id/read", s.readingController.MarkUnread)
+			protected.PUT("/:id/position", s.readingController.SavePosition)
+		}
+	}
+
+	// Setup volume routes
+	volumes := s.router.Group("/api/v1/volumes")
+	volumes.Use(s.authMiddleware)
+	{
+		volumes.GET("/:id/download.cbz", s.downloadLimiter, s.volumeController.DownloadVolume)
+	}
+
+	// Setup reading routes
+	reading := s.router.Group("/api/v1/reading")
+	reading.Use(s.authMiddleware)
+	{
+		reading.GET("/continue", s.readingController.ContinueReading)
+	}
+
+	// Setup reading history routes
+	history := s.router.Group("/api/v1/history")
+	history.Use(s.authMiddleware)
+	{
+		history.GET("", s.historyController.ListHistory)
+		history.DELETE("", s.historyController.ClearHistory)
+		history.GET("/export", s.historyController.ExportHistory)
+		history.PUT("/pause", s.historyController.SetPaused)
+		history.DELETE("/:entry_id", s.historyController.DeleteEntry)
+	}
+
+	// Setup library routes
+	library := s.router.Group("/api/v1/library")
+	library.Use(s.authMiddleware)
+	{
+		library.GET("", s.libraryController.GetLibrary)
+		library.PUT("/mangas/:manga_id", s.libraryController.SaveFavorite)
+		library.DELETE("/mangas/:manga_id", s.libraryController.RemoveFavorite)
+		library.GET("/shelves", s.libraryController.ListShelves)
+		library.POST("/shelves", s.libraryController.CreateShelf)
+		library.PUT("/shelves/:shelf_id", s.libraryController.RenameShelf)
+		library.DELETE("/shelves/:shelf_id", s.libraryController.DeleteShelf)
+		library.PUT("/visibility", s.libraryController.SetVisibility)
+	}
+
+	// Setup user routes
+	users := s.router.Group("/api/v1/users")
+	users.Use(s.optionalAuthMiddleware)
+	{
+		users.GET("/:id/library", s.libraryController.GetPublicLibrary)
+	}
+
+	// Setup group routes
+	groups := s.router.Group("/api/v1/groups")
+	groups.Use(s.authMiddleware)
+	{
+		groups.GET("/:id/watermark", s.groupController.GetWatermark)
+		groups.PUT("/:id/watermark", s.groupController.SetWatermark)
+		groups.DELETE("/:id/watermark", s.groupController.DeleteWatermark)
+	}
+
+	// Setup search routes
+	search := s.router.Group("/api/v1/search")
+	search.Use(s.optionalAuthMiddleware)
+	{
+		search.GET("", s.searchController.SearchMangas)
+
+		protected := search.Group("")
+		protected.Use(s.authMiddleware)
+		{
+			protected.POST("/index/rebuild", s.searchController.RebuildIndex)
+		}
+	}
+
+	// Setup search suggestion routes
+	suggest := s.router.Group("/api/v1/suggest")
+	{
+		suggest.GET("", s.searchController.Suggest)
+	}
+
 	// Setup public image routes (no authentication required)
 	images := s.router.Group("/api/v1/images")
 	{
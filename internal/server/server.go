@@ -2,6 +2,7 @@ package server
 
 import (
 	"fmt"
+	"hotaku-api/config"
 	"hotaku-api/internal/controllers"
 	"hotaku-api/internal/serviceinf"
 	"log"
@@ -11,11 +12,25 @@ import (
 
 // Server represents the HTTP server
 type Server struct {
-	router           *gin.Engine
-	authController   *controllers.AuthController
-	healthController *controllers.HealthController
-	uploadController *controllers.UploadController
-	authMiddleware   gin.HandlerFunc
+	router               *gin.Engine
+	authController       *controllers.AuthController
+	healthController     *controllers.HealthController
+	uploadController     *controllers.UploadController
+	chapterController    *controllers.ChapterController
+	sessionController    *controllers.UploadSessionController
+	mangaController      *controllers.MangaController
+	storageController    *controllers.StorageController
+	moderationController *controllers.ModerationController
+	groupController      *controllers.GroupController
+	searchController     *controllers.SearchController
+	volumeController     *controllers.VolumeController
+	readingController    *controllers.ReadingController
+	libraryController    *controllers.LibraryController
+	historyController    *controllers.HistoryController
+	authMiddleware       gin.HandlerFunc
+	// optionalAuthMiddleware identifies signed in users on public routes
+	optionalAuthMiddleware gin.HandlerFunc
+	downloadLimiter        gin.HandlerFunc
 }
 
 // NewServer creates a new server instance
@@ -23,20 +38,44 @@ func NewServer(
 	authController *controllers.AuthController,
 	healthController *controllers.HealthController,
 	uploadController *controllers.UploadController,
+	chapterController *controllers.ChapterController,
+	sessionController *controllers.UploadSessionController,
+	mangaController *controllers.MangaController,
+	storageController *controllers.StorageController,
+	moderationController *controllers.ModerationController,
+	groupController *controllers.GroupController,
+	searchController *controllers.SearchController,
+	volumeController *controllers.VolumeController,
+	readingController *controllers.ReadingController,
+	libraryController *controllers.LibraryController,
+	historyController *controllers.HistoryController,
 	tokenService serviceinf.TokenService,
+	appConfig *config.Config,
 ) *Server {
 	router := gin.Default()
 
 	server := &Server{
-		router:           router,
-		authController:   authController,
-		healthController: healthController,
-		uploadController: uploadController,
+		router:               router,
+		authController:       authController,
+		healthController:     healthController,
+		uploadController:     uploadController,
+		chapterController:    chapterController,
+		sessionController:    sessionController,
+		mangaController:      mangaController,
+		storageController:    storageController,
+		moderationController: moderationController,
+		groupController:      groupController,
+		searchController:     searchController,
+		volumeController:     volumeController,
+		readingController:    readingController,
+		libraryController:    libraryController,
+		historyController:    historyController,
 	}
 
 	// Setup middleware
 	server.setupMiddleware()
 	server.setupAuthMiddleware(tokenService)
+	server.setupRateLimitMiddleware(appConfig.Download)
 
 	// Setup routes
 	server.setupRoutes()
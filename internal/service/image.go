@@ -0,0 +1,360 @@
+package service
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"image"
+	"image/draw"
+	"image/jpeg"
+	"image/png"
+	"net/http"
+
+	"hotaku-api/utils"
+)
+
+// imageProcessor strips metadata from uploaded images before they are published, applies
+// their EXIF orientation and optionally caps their dimensions
+type imageProcessor struct {
+	maxWidth    int
+	maxHeight   int
+	jpegQuality int
+}
+
+// process returns the normalized image along with its sniffed content type.
+// JPEG and PNG images are only re-encoded when they must be rotated or shrunk, otherwise
+// their metadata segments are dropped and the image data is kept byte for byte.
+// GIFs carry no EXIF and would lose their animation, so they are stored as is
+func (p *imageProcessor) process(data []byte) ([]byte, string, error) {
+	contentType := http.DetectContentType(data)
+	switch contentType {
+	case "image/jpeg":
+		return p.processJPEG(data)
+	case "image/png":
+		return p.processPNG(data)
+	case "image/gif":
+		return data, contentType, nil
+	case "image/webp":
+		stripped, err := stripWebPMetadata(data)
+		return stripped, contentType, err
+	default:
+		return nil, "", fmt.Errorf("unsupported image content: %s", contentType)
+	}
+}
+
+// processJPEG strips a JPEG's metadata, re-encoding it if it must be rotated or shrunk
+func (p *imageProcessor) processJPEG(data []byte) ([]byte, string, error) {
+	stripped, orientation, err := stripJPEGMetadata(data)
+	if err != nil {
+		return nil, "", err
+	}
+
+	config, err := jpeg.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, "", fmt.Errorf("invalid JPEG image: %w", err)
+	}
+	if orientation <= 1 && p.fits(config.Width, config.Height) {
+		return stripped, "image/jpeg", nil
+	}
+	if config.Width*config.Height > utils.MaxImagePixels {
+		return nil, "", fmt.Errorf("image dimensions %dx%d exceed the maximum of %d pixels", config.Width, config.Height, utils.MaxImagePixels)
+	}
+
+	img, err := jpeg.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, "", fmt.Errorf("invalid JPEG image: %w", err)
+	}
+	rgba := p.shrink(orient(toRGBA(img), orientation))
+
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, rgba, &jpeg.Options{Quality: p.jpegQuality}); err != nil {
+		return nil, "", fmt.Errorf("failed to encode JPEG image: %w", err)
+	}
+	return buf.Bytes(), "image/jpeg", nil
+}
+
+// processPNG strips a PNG's metadata chunks, re-encoding it if it must be shrunk
+func (p *imageProcessor) processPNG(data []byte) ([]byte, string, error) {
+	config, err := png.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, "", fmt.Errorf("invalid PNG image: %w", err)
+	}
+	if p.fits(config.Width, config.Height) {
+		stripped, err := stripPNGMetadata(data)
+		return stripped, "image/png", err
+	}
+	if config.Width*config.Height > utils.MaxImagePixels {
+		return nil, "", fmt.Errorf("image dimensions %dx%d exceed the maximum of %d pixels", config.Width, config.Height, utils.MaxImagePixels)
+	}
+
+	img, err := png.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, "", fmt.Errorf("invalid PNG image: %w", err)
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, p.shrink(toRGBA(img))); err != nil {
+		return nil, "", fmt.Errorf("failed to encode PNG image: %w", err)
+	}
+	return buf.Bytes(), "image/png", nil
+}
+
+// fits reports whether an image of the given size is within the configured caps
+func (p *imageProcessor) fits(width, height int) bool {
+	return (p.maxWidth == 0 || width <= p.maxWidth) && (p.maxHeight == 0 || height <= p.maxHeight)
+}
+
+// shrink scales an image down to fit the configured caps, keeping its aspect ratio
+func (p *imageProcessor) shrink(img *image.RGBA) *image.RGBA {
+	width, height := img.Rect.Dx(), img.Rect.Dy()
+	if p.fits(width, height) {
+		return img
+	}
+
+	scale := 1.0
+	if p.maxWidth > 0 && width > p.maxWidth {
+		scale = float64(p.maxWidth) / float64(width)
+	}
+	if p.maxHeight > 0 && height > p.maxHeight {
+		scale = min(scale, float64(p.maxHeight)/float64(height))
+	}
+	return resizeArea(img, max(int(float64(width)*scale), 1), max(int(float64(height)*scale), 1))
+}
+
+// toRGBA converts an image to RGBA with its origin at (0, 0)
+func toRGBA(img image.Image) *image.RGBA {
+	bounds := img.Bounds()
+	rgba := image.NewRGBA(image.Rect(0, 0, bounds.Dx(), bounds.Dy()))
+	draw.Draw(rgba, rgba.Rect, img, bounds.Min, draw.Src)
+	return rgba
+}
+
+// orient applies an EXIF orientation (1-8) so the image displays upright without its metadata
+func orient(src *image.RGBA, orientation int) *image.RGBA {
+	if orientation < 2 || orientation > 8 {
+		return src
+	}
+
+	w, h := src.Rect.Dx(), src.Rect.Dy()
+	dstW, dstH := w, h
+	if orientation >= 5 {
+		dstW, dstH = h, w
+	}
+	dst := image.NewRGBA(image.Rect(0, 0, dstW, dstH))
+
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			var dx, dy int
+			switch orientation {
+			case 2: // mirrored horizontally
+				dx, dy = w-1-x, y
+			case 3: // rotated 180°
+				dx, dy = w-1-x, h-1-y
+			case 4: // mirrored vertically
+				dx, dy = x, h-1-y
+			case 5: // mirrored along the top-left diagonal
+				dx, dy = y, x
+			case 6: // rotated 90° clockwise
+				dx, dy = h-1-y, x
+			case 7: // mirrored along the top-right diagonal
+				dx, dy = h-1-y, w-1-x
+			case 8: // rotated 90° counter-clockwise
+				dx, dy = y, w-1-x
+			}
+			copy(dst.Pix[dst.PixOffset(dx, dy):dst.PixOffset(dx, dy)+4], src.Pix[src.PixOffset(x, y):src.PixOffset(x, y)+4])
+		}
+	}
+	return dst
+}
+
+// resizeArea downscales an image by averaging the source pixels covered by each destination pixel
+func resizeArea(src *image.RGBA, width, height int) *image.RGBA {
+	srcW, srcH := src.Rect.Dx(), src.Rect.Dy()
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+
+	for y := 0; y < height; y++ {
+		y0 := y * srcH / height
+		y1 := max((y+1)*srcH/height, y0+1)
+		for x := 0; x < width; x++ {
+			x0 := x * srcW / width
+			x1 := max((x+1)*srcW/width, x0+1)
+
+			var sum [4]int
+			for sy := y0; sy < y1; sy++ {
+				offset := src.PixOffset(x0, sy)
+				for sx := x0; sx < x1; sx++ {
+					for c := 0; c < 4; c++ {
+						sum[c] += int(src.Pix[offset+c])
+					}
+					offset += 4
+				}
+			}
+
+			count := (x1 - x0) * (y1 - y0)
+			offset := dst.PixOffset(x, y)
+			for c := 0; c < 4; c++ {
+				dst.Pix[offset+c] = uint8(sum[c] / count)
+			}
+		}
+	}
+	return dst
+}
+
+// stripJPEGMetadata drops the EXIF, XMP, comment and vendor segments of a JPEG and returns
+// its EXIF orientation (0 if none). JFIF, ICC profile and Adobe segments are kept since they
+// affect how the image is decoded
+func stripJPEGMetadata(data []byte) ([]byte, int, error) {
+	if len(data) < 4 || data[0] != 0xFF || data[1] != 0xD8 {
+		return nil, 0, fmt.Errorf("invalid JPEG image")
+	}
+
+	out := make([]byte, 0, len(data))
+	out = append(out, 0xFF, 0xD8)
+	orientation := 0
+
+	i := 2
+	for {
+		if i+4 > len(data) || data[i] != 0xFF {
+			return nil, 0, fmt.Errorf("invalid JPEG image")
+		}
+		marker := data[i+1]
+		if marker == 0xFF {
+			// Fill byte before the marker
+			i++
+			continue
+		}
+		if marker == 0xDA {
+			// Start of scan: the entropy-coded image data runs to the end
+			return append(out, data[i:]...), orientation, nil
+		}
+
+		length := int(binary.BigEndian.Uint16(data[i+2:]))
+		end := i + 2 + length
+		if length < 2 || end > len(data) {
+			return nil, 0, fmt.Errorf("invalid JPEG image")
+		}
+		segment := data[i:end]
+
+		switch {
+		case marker == 0xE1:
+			if o := exifOrientation(segment[4:]); o != 0 {
+				orientation = o
+			}
+		case marker == 0xE0, marker == 0xE2, marker == 0xEE:
+			out = append(out, segment...)
+		case marker >= 0xE3 && marker <= 0xEF, marker == 0xFE:
+			// Vendor application data and comments
+		default:
+			out = append(out, segment...)
+		}
+		i = end
+	}
+}
+
+// exifOrientation reads the orientation tag from the payload of a JPEG APP1 segment, returning 0 if absent
+func exifOrientation(payload []byte) int {
+	if len(payload) < 14 || string(payload[:6]) != "Exif\x00\x00" {
+		return 0
+	}
+	tiff := payload[6:]
+
+	var order binary.ByteOrder
+	switch string(tiff[:2]) {
+	case "II":
+		order = binary.LittleEndian
+	case "MM":
+		order = binary.BigEndian
+	default:
+		return 0
+	}
+
+	ifd := int(order.Uint32(tiff[4:]))
+	if ifd+2 > len(tiff) {
+		return 0
+	}
+	entries := int(order.Uint16(tiff[ifd:]))
+	for n := 0; n < entries; n++ {
+		entry := ifd + 2 + n*12
+		if entry+12 > len(tiff) {
+			return 0
+		}
+		// Tag 0x0112 (Orientation) holds a single SHORT value
+		if order.Uint16(tiff[entry:]) == 0x0112 && order.Uint16(tiff[entry+2:]) == 3 {
+			return int(order.Uint16(tiff[entry+8:]))
+		}
+	}
+	return 0
+}
+
+// pngSignature starts every PNG file
+var pngSignature = []byte("\x89PNG\r\n\x1a\n")
+
+// stripPNGMetadata drops the text, EXIF and timestamp chunks of a PNG
+func stripPNGMetadata(data []byte) ([]byte, error) {
+	if !bytes.HasPrefix(data, pngSignature) {
+		return nil, fmt.Errorf("invalid PNG image")
+	}
+
+	out := make([]byte, 0, len(data))
+	out = append(out, pngSignature...)
+
+	i := len(pngSignature)
+	for i < len(data) {
+		if i+8 > len(data) {
+			return nil, fmt.Errorf("invalid PNG image")
+		}
+		// Length, type, data and CRC
+		end := i + 12 + int(binary.BigEndian.Uint32(data[i:]))
+		if end > len(data) || end < i {
+			return nil, fmt.Errorf("invalid PNG image")
+		}
+
+		switch string(data[i+4 : i+8]) {
+		case "tEXt", "zTXt", "iTXt", "eXIf", "tIME":
+		default:
+			out = append(out, data[i:end]...)
+		}
+		i = end
+	}
+	return out, nil
+}
+
+// stripWebPMetadata drops the EXIF and XMP chunks of a WebP image and clears their flags
+func stripWebPMetadata(data []byte) ([]byte, error) {
+	if len(data) < 12 || string(data[:4]) != "RIFF" || string(data[8:12]) != "WEBP" {
+		return nil, fmt.Errorf("invalid WebP image")
+	}
+
+	out := make([]byte, 12, len(data))
+	copy(out, data[:12])
+
+	i := 12
+	for i < len(data) {
+		if i+8 > len(data) {
+			return nil, fmt.Errorf("invalid WebP image")
+		}
+		// Chunks are padded to an even size
+		size := int(binary.LittleEndian.Uint32(data[i+4:]))
+		end := i + 8 + size + size%2
+		if end > len(data) || end < i {
+			return nil, fmt.Errorf("invalid WebP image")
+		}
+
+		switch string(data[i : i+4]) {
+		case "EXIF", "XMP ":
+		case "VP8X":
+			chunk := append([]byte(nil), data[i:end]...)
+			if len(chunk) > 8 {
+				// Clear the EXIF (0x08) and XMP (0x04) presence flags
+				chunk[8] &^= 0x08 | 0x04
+			}
+			out = append(out, chunk...)
+		default:
+			out = append(out, data[i:end]...)
+		}
+		i = end
+	}
+
+	binary.LittleEndian.PutUint32(out[4:], uint32(len(out)-8))
+	return out, nil
+}
@@ -1,8 +1,10 @@
 package service
 
 import (
+	"bytes"
 	"context"
 	"fmt"
+	"io"
 	"mime/multipart"
 	"os"
 	"path/filepath"
@@ -10,6 +12,7 @@ import (
 	"time"
 
 	"hotaku-api/config"
+	"hotaku-api/internal/serviceinf"
 
 	"github.com/google/uuid"
 	"github.com/minio/minio-go/v7"
@@ -24,6 +27,7 @@ const (
 type MinIOService struct {
 	client     *minio.Client
 	bucketName string
+	images     *imageProcessor
 }
 
 // NewMinIOService creates a new MinIO service instance
@@ -40,10 +44,15 @@ func NewMinIOService(cfg *config.Config) (*MinIOService, error) {
 	service := &MinIOService{
 		client:     minioClient,
 		bucketName: cfg.MinIO.BucketName,
+		images: &imageProcessor{
+			maxWidth:    cfg.Image.MaxWidth,
+			maxHeight:   cfg.Image.MaxHeight,
+			jpegQuality: cfg.Image.JPEGQuality,
+		},
 	}
 
 	// Ensure bucket exists
-	if err := service.ensureBucketExists(true); err != nil {
+	if err := service.ensureBucketExists(!cfg.MinIO.PrivateBucket); err != nil {
 		return nil, fmt.Errorf("failed to ensure bucket exists: %w", err)
 	}
 
@@ -84,6 +93,13 @@ func (s *MinIOService) ensureBucketExists(isPublic bool) error {
 		}
 	}
 
+	// A bucket created before private mode was enabled still has its public read policy
+	if exists && !isPublic {
+		if err := s.client.SetBucketPolicy(context.Background(), s.bucketName, ""); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
@@ -101,88 +117,174 @@ func (s *MinIOService) constructFileURL(filename string) string {
 	return fmt.Sprintf("%s://%s/%s/%s", scheme, publicURL, s.bucketName, filename)
 }
 
+// FileURL returns the public URL of an object
+func (s *MinIOService) FileURL(objectName string) string {
+	return s.constructFileURL(objectName)
+}
+
+// MangaImageObjectName generates a new unique object name for a manga image
+func (s *MinIOService) MangaImageObjectName(mangaID, ext string) string {
+	return fmt.Sprintf("manga/%s/%s%s", mangaID, uuid.New().String(), ext)
+}
+
+// ChapterPageObjectName generates a new unique object name for a chapter page. Page order is
+// tracked in chapter_pages, so the name doesn't encode the page number
+func (s *MinIOService) ChapterPageObjectName(mangaID, chapterID, ext string) string {
+	return fmt.Sprintf("manga/%s/chapters/%s/%s%s", mangaID, chapterID, uuid.New().String(), ext)
+}
+
+// StagingObjectName generates a new unique object name for a direct upload awaiting finalization
+func (s *MinIOService) StagingObjectName(userID, ext string) string {
+	return fmt.Sprintf("uploads/%s/%s%s", userID, uuid.New().String(), ext)
+}
+
+// WatermarkOverlayObjectName generates a new unique object name for a group's PNG watermark overlay.
+// Overlays live outside the manga namespace so the image route never serves them
+func (s *MinIOService) WatermarkOverlayObjectName(groupID string) string {
+	return fmt.Sprintf("groups/%s/watermarks/%s.png", groupID, uuid.New().String())
+}
+
+// ObjectNameFromURL extracts the object name from a URL built by constructFileURL
+func (s *MinIOService) ObjectNameFromURL(fileURL string) (string, error) {
+	marker := "/" + s.bucketName + "/"
+	idx := strings.Index(fileURL, marker)
+	if idx == -1 {
+		return "", fmt.Errorf("URL does not belong to bucket %s", s.bucketName)
+	}
+	return fileURL[idx+len(marker):], nil
+}
+
+// NormalizeObjectName cleans a user supplied object name and rejects anything outside the manga namespace
+func (s *MinIOService) NormalizeObjectName(objectName string) (string, error) {
+	objectName = strings.TrimPrefix(objectName, "/")
+	if !strings.HasPrefix(objectName, "manga/") || strings.ContainsAny(objectName, "\\\x00") {
+		return "", fmt.Errorf("invalid object name")
+	}
+	for _, segment := range strings.Split(objectName, "/") {
+		if segment == "" || segment == "." || segment == ".." {
+			return "", fmt.Errorf("invalid object name")
+		}
+	}
+	return objectName, nil
+}
+
 // UploadMangaImage uploads a manga image file to MinIO
-func (s *MinIOService) UploadMangaImage(file *multipart.FileHeader, mangaID string) (string, error) {
+func (s *MinIOService) UploadMangaImage(file *multipart.FileHeader, mangaID string) (*serviceinf.StoredImage, error) {
 	// Validate file (e.g., 10MB limit for manga images)
 	if err := s.validateImageFile(file); err != nil {
-		return "", err
+		return nil, err
 	}
 
 	// Open the uploaded file
 	src, err := file.Open()
 	if err != nil {
-		return "", fmt.Errorf("failed to open uploaded file: %w", err)
+		return nil, fmt.Errorf("failed to open uploaded file: %w", err)
 	}
 	defer src.Close()
 
 	// Generate unique filename
-	ext := filepath.Ext(file.Filename)
-	filename := fmt.Sprintf("manga/%s/%s%s", mangaID, uuid.New().String(), ext)
+	filename := s.MangaImageObjectName(mangaID, filepath.Ext(file.Filename))
 
-	// Get content type
-	contentType := file.Header.Get("Content-Type")
-	if contentType == "" {
-		contentType = "application/octet-stream"
-	}
-
-	// Upload to MinIO
-	_, err = s.client.PutObject(
-		context.Background(),
-		s.bucketName,
-		filename,
-		src,
-		file.Size,
-		minio.PutObjectOptions{
-			ContentType: contentType,
-		},
-	)
-	if err != nil {
-		return "", fmt.Errorf("failed to upload file to MinIO: %w", err)
-	}
-
-	return s.constructFileURL(filename), nil
+	return s.putImage(context.Background(), filename, src, file.Size)
 }
 
 // UploadChapterPage uploads a chapter page image to MinIO
-func (s *MinIOService) UploadChapterPage(file *multipart.FileHeader, mangaID, chapterID string, pageNumber int) (string, error) {
+func (s *MinIOService) UploadChapterPage(file *multipart.FileHeader, mangaID, chapterID string) (*serviceinf.StoredImage, error) {
 	// Validate file (e.g., 10MB limit for manga images)
 	if err := s.validateImageFile(file); err != nil {
-		return "", err
+		return nil, err
 	}
 
 	// Open the uploaded file
 	src, err := file.Open()
 	if err != nil {
-		return "", fmt.Errorf("failed to open uploaded file: %w", err)
+		return nil, fmt.Errorf("failed to open uploaded file: %w", err)
 	}
 	defer src.Close()
 
-	// Generate unique filename
-	ext := filepath.Ext(file.Filename)
-	filename := fmt.Sprintf("manga/%s/chapters/%s/page_%03d%s", mangaID, chapterID, pageNumber, ext)
+	return s.UploadChapterPageFromReader(context.Background(), src, file.Size, file.Header.Get("Content-Type"), filepath.Ext(file.Filename), mangaID, chapterID)
+}
 
-	// Get content type
-	contentType := file.Header.Get("Content-Type")
-	if contentType == "" {
-		contentType = "application/octet-stream"
+// UploadChapterPageFromReader uploads a chapter page image read from an arbitrary reader to MinIO
+func (s *MinIOService) UploadChapterPageFromReader(ctx context.Context, reader io.Reader, size int64, contentType, ext, mangaID, chapterID string) (*serviceinf.StoredImage, error) {
+	if err := s.ValidateImage("page"+ext, contentType, size); err != nil {
+		return nil, err
+	}
+
+	return s.putImage(ctx, s.ChapterPageObjectName(mangaID, chapterID, ext), reader, size)
+}
+
+// StoreImageFromObject normalizes an already stored image, such as a direct upload, into a new object
+func (s *MinIOService) StoreImageFromObject(srcObject, dstObject string) (*serviceinf.StoredImage, error) {
+	obj, err := s.GetObject(srcObject)
+	if err != nil {
+		return nil, err
+	}
+	defer obj.Close()
+
+	info, err := obj.Stat()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get file info: %w", err)
+	}
+	if info.Size > MaxFileSize {
+		return nil, fmt.Errorf("file size %d exceeds maximum allowed size %d", info.Size, MaxFileSize)
+	}
+
+	return s.putImage(context.Background(), dstObject, obj, info.Size)
+}
+
+// putImage strips the metadata of an image, normalizes it and uploads the result to MinIO.
+// Images are at most MaxFileSize, so they are processed in memory
+func (s *MinIOService) putImage(ctx context.Context, objectName string, reader io.Reader, size int64) (*serviceinf.StoredImage, error) {
+	data, err := io.ReadAll(io.LimitReader(reader, size))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read uploaded file: %w", err)
+	}
+
+	processed, contentType, err := s.images.process(data)
+	if err != nil {
+		return nil, err
 	}
 
 	// Upload to MinIO
 	_, err = s.client.PutObject(
-		context.Background(),
+		ctx,
 		s.bucketName,
-		filename,
-		src,
-		file.Size,
+		objectName,
+		bytes.NewReader(processed),
+		int64(len(processed)),
 		minio.PutObjectOptions{
 			ContentType: contentType,
 		},
 	)
 	if err != nil {
-		return "", fmt.Errorf("failed to upload file to MinIO: %w", err)
+		return nil, fmt.Errorf("failed to upload file to MinIO: %w", err)
 	}
 
-	return s.constructFileURL(filename), nil
+	return &serviceinf.StoredImage{
+		URL:          s.constructFileURL(objectName),
+		ContentType:  contentType,
+		OriginalSize: int64(len(data)),
+		Size:         int64(len(processed)),
+	}, nil
+}
+
+// PutObject stores data as-is, for derived content such as watermarked variants that must not be normalized again
+func (s *MinIOService) PutObject(objectName string, data []byte, contentType string) error {
+	_, err := s.client.PutObject(
+		context.Background(),
+		s.bucketName,
+		objectName,
+		bytes.NewReader(data),
+		int64(len(data)),
+		minio.PutObjectOptions{
+			ContentType: contentType,
+		},
+	)
+	if err != nil {
+		return fmt.Errorf("failed to upload file to MinIO: %w", err)
+	}
+	return nil
 }
 
 // DeleteFile deletes a file from MinIO
@@ -203,6 +305,84 @@ func (s *MinIOService) GetFileURL(objectName string, expiry time.Duration) (stri
 	return url.String(), nil
 }
 
+// PresignedPostPolicy generates a presigned POST upload of a single object. The policy only accepts the given
+// content type and exact size, so the client can't store anything else under the object name. It returns the URL
+// to post to along with the form fields to send before the file
+func (s *MinIOService) PresignedPostPolicy(objectName, contentType string, size int64, expiry time.Duration) (string, map[string]string, error) {
+	policy := minio.NewPostPolicy()
+	if err := policy.SetBucket(s.bucketName); err != nil {
+		return "", nil, fmt.Errorf("failed to create upload policy: %w", err)
+	}
+	if err := policy.SetKey(objectName); err != nil {
+		return "", nil, fmt.Errorf("failed to create upload policy: %w", err)
+	}
+	if err := policy.SetExpires(time.Now().UTC().Add(expiry)); err != nil {
+		return "", nil, fmt.Errorf("failed to create upload policy: %w", err)
+	}
+	if err := policy.SetContentType(contentType); err != nil {
+		return "", nil, fmt.Errorf("failed to create upload policy: %w", err)
+	}
+	if err := policy.SetContentLengthRange(size, size); err != nil {
+		return "", nil, fmt.Errorf("failed to create upload policy: %w", err)
+	}
+
+	url, fields, err := s.client.PresignedPostPolicy(context.Background(), policy)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to generate presigned upload policy: %w", err)
+	}
+	return url.String(), fields, nil
+}
+
+// StatObject gets the metadata of an object
+func (s *MinIOService) StatObject(objectName string) (minio.ObjectInfo, error) {
+	objInfo, err := s.client.StatObject(context.Background(), s.bucketName, objectName, minio.StatObjectOptions{})
+	if err != nil {
+		return minio.ObjectInfo{}, fmt.Errorf("failed to get file info: %w", err)
+	}
+	return objInfo, nil
+}
+
+// StartMultipartUpload begins a multipart upload and returns its storage upload ID
+func (s *MinIOService) StartMultipartUpload(objectName, contentType string) (string, error) {
+	core := minio.Core{Client: s.client}
+	uploadID, err := core.NewMultipartUpload(context.Background(), s.bucketName, objectName, minio.PutObjectOptions{
+		ContentType: contentType,
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to start multipart upload: %w", err)
+	}
+	return uploadID, nil
+}
+
+// UploadPart uploads a single part of a multipart upload and returns its ETag
+func (s *MinIOService) UploadPart(objectName, uploadID string, partNumber int, reader io.Reader, size int64) (string, error) {
+	core := minio.Core{Client: s.client}
+	part, err := core.PutObjectPart(context.Background(), s.bucketName, objectName, uploadID, partNumber, reader, size, minio.PutObjectPartOptions{})
+	if err != nil {
+		return "", fmt.Errorf("failed to upload part %d: %w", partNumber, err)
+	}
+	return part.ETag, nil
+}
+
+// CompleteMultipartUpload assembles the uploaded parts into the final object
+func (s *MinIOService) CompleteMultipartUpload(objectName, uploadID string, parts []minio.CompletePart) error {
+	core := minio.Core{Client: s.client}
+	_, err := core.CompleteMultipartUpload(context.Background(), s.bucketName, objectName, uploadID, parts, minio.PutObjectOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to complete multipart upload: %w", err)
+	}
+	return nil
+}
+
+// AbortMultipartUpload discards a multipart upload and its stored parts
+func (s *MinIOService) AbortMultipartUpload(objectName, uploadID string) error {
+	core := minio.Core{Client: s.client}
+	if err := core.AbortMultipartUpload(context.Background(), s.bucketName, objectName, uploadID); err != nil {
+		return fmt.Errorf("failed to abort multipart upload: %w", err)
+	}
+	return nil
+}
+
 // ListFiles lists files in a directory
 func (s *MinIOService) ListFiles(prefix string) ([]string, error) {
 	var files []string
@@ -222,6 +402,28 @@ func (s *MinIOService) ListFiles(prefix string) ([]string, error) {
 	return files, nil
 }
 
+// WalkObjects calls fn for every object under prefix, stopping at the first error
+func (s *MinIOService) WalkObjects(prefix string, fn func(minio.ObjectInfo) error) error {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	opts := minio.ListObjectsOptions{
+		Prefix:    prefix,
+		Recursive: true,
+	}
+
+	for obj := range s.client.ListObjects(ctx, s.bucketName, opts) {
+		if obj.Err != nil {
+			return fmt.Errorf("error listing objects: %w", obj.Err)
+		}
+		if err := fn(obj); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
 // GetFileSize gets the size of a file
 func (s *MinIOService) GetFileSize(objectName string) (int64, error) {
 	objInfo, err := s.client.StatObject(context.Background(), s.bucketName, objectName, minio.StatObjectOptions{})
@@ -261,13 +463,17 @@ func (s *MinIOService) GetObject(objectName string) (*minio.Object, error) {
 
 // validateImageFile validates the uploaded file
 func (s *MinIOService) validateImageFile(file *multipart.FileHeader) error {
+	return s.ValidateImage(file.Filename, file.Header.Get("Content-Type"), file.Size)
+}
+
+// ValidateImage validates an image by its filename, content type and size
+func (s *MinIOService) ValidateImage(filename, contentType string, size int64) error {
 	// Check file size
-	if file.Size > MaxFileSize {
-		return fmt.Errorf("file size %d exceeds maximum allowed size %d", file.Size, MaxFileSize)
+	if size > MaxFileSize {
+		return fmt.Errorf("file size %d exceeds maximum allowed size %d", size, MaxFileSize)
 	}
 
 	// Check content type
-	contentType := file.Header.Get("Content-Type")
 	allowedTypes := map[string]bool{
 		"image/jpeg": true,
 		"image/jpg":  true,
@@ -281,7 +487,7 @@ func (s *MinIOService) validateImageFile(file *multipart.FileHeader) error {
 	}
 
 	// Check file extension
-	ext := strings.ToLower(filepath.Ext(file.Filename))
+	ext := strings.ToLower(filepath.Ext(filename))
 	allowedExts := map[string]bool{
 		".jpg":  true,
 		".jpeg": true,
@@ -0,0 +1,149 @@
+package service
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	_ "image/gif" // register GIF decoding, watermarked GIFs are stored as PNG
+	"image/jpeg"
+	"image/png"
+	"net/http"
+	"strings"
+
+	"hotaku-api/internal/domain/entities"
+	"hotaku-api/internal/serviceinf"
+	"hotaku-api/utils"
+)
+
+// WatermarkServiceImpl draws text or PNG overlays on images. The result is always re-encoded,
+// as JPEG for JPEG sources and as PNG otherwise; WebP has no decoder in the standard library
+type WatermarkServiceImpl struct {
+	jpegQuality int
+}
+
+// NewWatermarkService creates a new watermark service instance
+func NewWatermarkService(jpegQuality int) serviceinf.WatermarkService {
+	return &WatermarkServiceImpl{jpegQuality: jpegQuality}
+}
+
+// Apply draws the watermark over an image and returns the encoded result with its content type
+func (s *WatermarkServiceImpl) Apply(data []byte, watermark serviceinf.Watermark) ([]byte, string, error) {
+	contentType := http.DetectContentType(data)
+	if contentType != "image/jpeg" && contentType != "image/png" && contentType != "image/gif" {
+		return nil, "", fmt.Errorf("watermarking is not supported for %s images", contentType)
+	}
+
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, "", fmt.Errorf("invalid image: %w", err)
+	}
+	if config.Width*config.Height > utils.MaxImagePixels {
+		return nil, "", fmt.Errorf("image dimensions %dx%d exceed the maximum of %d pixels", config.Width, config.Height, utils.MaxImagePixels)
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, "", fmt.Errorf("invalid image: %w", err)
+	}
+	page := toRGBA(img)
+
+	stamp, err := s.stamp(watermark, page.Rect.Dx())
+	if err != nil {
+		return nil, "", err
+	}
+	at := stampPosition(watermark.Position, page.Rect.Size(), stamp.Rect.Size())
+	alpha := image.NewUniform(color.Alpha{A: uint8(watermark.Opacity * 255 / 100)})
+	draw.DrawMask(page, stamp.Rect.Add(at), stamp, image.Point{}, alpha, image.Point{}, draw.Over)
+
+	var buf bytes.Buffer
+	if contentType == "image/jpeg" {
+		if err := jpeg.Encode(&buf, page, &jpeg.Options{Quality: s.jpegQuality}); err != nil {
+			return nil, "", fmt.Errorf("failed to encode JPEG image: %w", err)
+		}
+		return buf.Bytes(), contentType, nil
+	}
+	if err := png.Encode(&buf, page); err != nil {
+		return nil, "", fmt.Errorf("failed to encode PNG image: %w", err)
+	}
+	return buf.Bytes(), "image/png", nil
+}
+
+// stamp renders the watermark at its scaled size for a page of the given width
+func (s *WatermarkServiceImpl) stamp(watermark serviceinf.Watermark, pageWidth int) (*image.RGBA, error) {
+	width := max(pageWidth*watermark.Scale/100, 1)
+	if len(watermark.Overlay) == 0 {
+		return textStamp(watermark.Text, width), nil
+	}
+
+	overlay, err := png.Decode(bytes.NewReader(watermark.Overlay))
+	if err != nil {
+		return nil, fmt.Errorf("invalid watermark overlay: %w", err)
+	}
+	rgba := toRGBA(overlay)
+	if rgba.Rect.Dx() <= width {
+		return rgba, nil
+	}
+	height := max(rgba.Rect.Dy()*width/rgba.Rect.Dx(), 1)
+	return resizeArea(rgba, width, height), nil
+}
+
+// textStamp renders white text with a dark drop shadow using the built-in 5x7 font, with each
+// font pixel scaled up so the text is at most width pixels wide
+func textStamp(text string, width int) *image.RGBA {
+	glyphs := []rune(strings.ToUpper(text))
+	if len(glyphs) == 0 {
+		return image.NewRGBA(image.Rect(0, 0, 1, 1))
+	}
+
+	// Each glyph takes one column of spacing, the shadow one extra column and row
+	cell := max(width/(len(glyphs)*(glyphWidth+1)), 1)
+	stamp := image.NewRGBA(image.Rect(0, 0, len(glyphs)*(glyphWidth+1)*cell, (glyphHeight+1)*cell))
+
+	shadow := image.NewUniform(color.RGBA{A: 0xFF})
+	fill := image.NewUniform(color.RGBA{R: 0xFF, G: 0xFF, B: 0xFF, A: 0xFF})
+	for _, layer := range []struct {
+		src    image.Image
+		offset int
+	}{{shadow, cell}, {fill, 0}} {
+		for i, r := range glyphs {
+			glyph, ok := font5x7[r]
+			if !ok {
+				glyph = font5x7['?']
+			}
+			for y, row := range glyph {
+				for x := 0; x < glyphWidth; x++ {
+					if row&(1<<(glyphWidth-1-x)) == 0 {
+						continue
+					}
+					x0 := (i*(glyphWidth+1)+x)*cell + layer.offset
+					y0 := y*cell + layer.offset
+					draw.Draw(stamp, image.Rect(x0, y0, x0+cell, y0+cell), layer.src, image.Point{}, draw.Src)
+				}
+			}
+		}
+	}
+	return stamp
+}
+
+// stampPosition returns where the top-left corner of the stamp goes, keeping a margin of 2% of
+// the page width from the edges
+func stampPosition(position string, page, stamp image.Point) image.Point {
+	margin := page.X / 50
+	left, top := margin, margin
+	right, bottom := page.X-stamp.X-margin, page.Y-stamp.Y-margin
+
+	switch position {
+	case entities.WatermarkPositionTopLeft:
+		return image.Pt(left, top)
+	case entities.WatermarkPositionTopRight:
+		return image.Pt(right, top)
+	case entities.WatermarkPositionBottomLeft:
+		return image.Pt(left, bottom)
+	case entities.WatermarkPositionCenter:
+		return image.Pt((page.X-stamp.X)/2, (page.Y-stamp.Y)/2)
+	default:
+		return image.Pt(right, bottom)
+	}
+}
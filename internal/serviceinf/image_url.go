@@ -0,0 +1,9 @@
+package serviceinf
+
+// ImageURLService defines the interface for building and verifying the URLs images are served from
+type ImageURLService interface {
+	// Resolve returns the URL clients should load a stored image from, signed and expiring when restricted
+	Resolve(fileURL string, restricted bool) string
+	// Verify checks the expiry and signature of a signed image URL
+	Verify(objectName, expires, signature string) error
+}
@@ -0,0 +1,50 @@
+package serviceinf
+
+import (
+	"context"
+	"io"
+	"mime/multipart"
+	"time"
+
+	"github.com/minio/minio-go/v7"
+)
+
+// StorageService defines the interface for object storage operations
+type StorageService interface {
+	UploadMangaImage(file *multipart.FileHeader, mangaID string) (*StoredImage, error)
+	UploadChapterPage(file *multipart.FileHeader, mangaID, chapterID string) (*StoredImage, error)
+	// UploadChapterPageFromReader stores a page read from reader, giving up when ctx is cancelled
+	UploadChapterPageFromReader(ctx context.Context, reader io.Reader, size int64, contentType, ext, mangaID, chapterID string) (*StoredImage, error)
+	// StoreImageFromObject normalizes an already stored image, such as a direct upload, into a new object
+	StoreImageFromObject(srcObject, dstObject string) (*StoredImage, error)
+	// PutObject stores data as-is, for derived content such as watermarked variants that must not be normalized again
+	PutObject(objectName string, data []byte, contentType string) error
+	DeleteFile(objectName string) error
+	ListFiles(prefix string) ([]string, error)
+	WalkObjects(prefix string, fn func(minio.ObjectInfo) error) error
+	GetObject(objectName string) (*minio.Object, error)
+	ObjectNameFromURL(fileURL string) (string, error)
+	NormalizeObjectName(objectName string) (string, error)
+	FileURL(objectName string) string
+	MangaImageObjectName(mangaID, ext string) string
+	ChapterPageObjectName(mangaID, chapterID, ext string) string
+	StagingObjectName(userID, ext string) string
+	WatermarkOverlayObjectName(groupID string) string
+	// PresignedPostPolicy returns a URL and form fields for a POST upload restricted to the content type and size
+	PresignedPostPolicy(objectName, contentType string, size int64, expiry time.Duration) (string, map[string]string, error)
+	StatObject(objectName string) (minio.ObjectInfo, error)
+	CopyFile(srcObject, dstObject string) error
+	ValidateImage(filename, contentType string, size int64) error
+	StartMultipartUpload(objectName, contentType string) (string, error)
+	UploadPart(objectName, uploadID string, partNumber int, reader io.Reader, size int64) (string, error)
+	CompleteMultipartUpload(objectName, uploadID string, parts []minio.CompletePart) error
+	AbortMultipartUpload(objectName, uploadID string) error
+}
+
+// StoredImage describes an image after its metadata was stripped and it was normalized for storage
+type StoredImage struct {
+	URL          string
+	ContentType  string
+	OriginalSize int64
+	Size         int64
+}
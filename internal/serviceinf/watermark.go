@@ -0,0 +1,20 @@
+package serviceinf
+
+// WatermarkService defines the interface for drawing watermarks on images
+type WatermarkService interface {
+	// Apply draws the watermark over an image and returns the encoded result with its content type
+	Apply(data []byte, watermark Watermark) ([]byte, string, error)
+}
+
+// Watermark describes what is drawn on an image and where
+type Watermark struct {
+	// Text is drawn with the built-in font when no overlay is set
+	Text string
+	// Overlay is a PNG image drawn instead of the text
+	Overlay  []byte
+	Position string
+	// Opacity is a percentage from 1 to 100
+	Opacity int
+	// Scale is the width of the watermark as a percentage of the image width
+	Scale int
+}
@@ -0,0 +1,330 @@
+package usecase
+
+import (
+	"archive/zip"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strconv"
+	"strings"
+
+	"hotaku-api/internal/domain/dto"
+	"hotaku-api/internal/domain/entities"
+	"hotaku-api/internal/repoinf"
+	"hotaku-api/internal/serviceinf"
+	"hotaku-api/internal/usecaseinf"
+)
+
+// ChapterUseCaseImpl implements the chapter use cases
+type ChapterUseCaseImpl struct {
+	userRepo         repoinf.UserRepository
+	mangaRepo        repoinf.MangaRepository
+	chapterRepo      repoinf.ChapterRepository
+	pageRepo         repoinf.ChapterPageRepository
+	groupRepo        repoinf.GroupRepository
+	volumeRepo       repoinf.MangaVolumeRepository
+	storageService   serviceinf.StorageService
+	access           *mangaAccess
+	watermarkUseCase usecaseinf.WatermarkUseCase
+}
+
+// NewChapterUseCase creates a new instance of ChapterUseCaseImpl
+func NewChapterUseCase(
+	userRepo repoinf.UserRepository,
+	mangaRepo repoinf.MangaRepository,
+	chapterRepo repoinf.ChapterRepository,
+	pageRepo repoinf.ChapterPageRepository,
+	groupRepo repoinf.GroupRepository,
+	volumeRepo repoinf.MangaVolumeRepository,
+	storageService serviceinf.StorageService,
+	imageURLService serviceinf.ImageURLService,
+	watermarkUseCase usecaseinf.WatermarkUseCase,
+) usecaseinf.ChapterUseCase {
+	return &ChapterUseCaseImpl{
+		userRepo:         userRepo,
+		mangaRepo:        mangaRepo,
+		chapterRepo:      chapterRepo,
+		pageRepo:         pageRepo,
+		groupRepo:        groupRepo,
+		volumeRepo:       volumeRepo,
+		storageService:   storageService,
+		access:           newMangaAccess(userRepo, groupRepo, imageURLService),
+		watermarkUseCase: watermarkUseCase,
+	}
+}
+
+// GetManifest returns the pages of a chapter in reading order with the URLs to load them from, to a user who may
+// see them
+func (uc *ChapterUseCaseImpl) GetManifest(userID, chapterID string) (*dto.ChapterManifestResponse, error) {
+	chapter, err := uc.chapterRepo.GetByID(chapterID)
+	if err != nil {
+		return nil, err
+	}
+
+	manga, err := uc.mangaRepo.GetByID(chapter.MangaID)
+	if err != nil {
+		return nil, err
+	}
+	if err := uc.access.check(userID, manga); err != nil {
+		return nil, err
+	}
+
+	objects, err := uc.deliveredObjects(chapter)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &dto.ChapterManifestResponse{
+		ChapterID: chapter.ChapterID,
+		MangaID:   manga.MangaID,
+		Pages:     make([]dto.ManifestPage, 0, len(objects)),
+	}
+	for i, objectName := range objects {
+		result.Pages = append(result.Pages, dto.ManifestPage{
+			PageNumber: i + 1,
+			URL:        uc.access.imageURLService.Resolve(uc.storageService.FileURL(objectName), manga.IsRestricted()),
+		})
+	}
+	return result, nil
+}
+
+// PrepareDownload resolves the pages and metadata of a chapter archive for a user who may see them
+func (uc *ChapterUseCaseImpl) PrepareDownload(userID, chapterID string) (*dto.ChapterDownload, error) {
+	chapter, err := uc.chapterRepo.GetByID(chapterID)
+	if err != nil {
+		return nil, usecaseinf.ErrChapterNotFound
+	}
+
+	manga, err := uc.mangaRepo.GetByID(chapter.MangaID)
+	if err != nil {
+		return nil, err
+	}
+	if err := uc.access.check(userID, manga); err != nil {
+		return nil, err
+	}
+
+	objects, err := uc.deliveredObjects(chapter)
+	if err != nil {
+		return nil, err
+	}
+	if len(objects) == 0 {
+		return nil, usecaseinf.ErrChapterEmpty
+	}
+
+	number := strconv.FormatFloat(chapter.ChapterNumber, 'f', -1, 64)
+
+	var writers []string
+	for _, author := range manga.Authors {
+		writers = append(writers, author.AuthorName)
+	}
+
+	info := dto.ComicInfo{
+		Series:    manga.Title,
+		Number:    number,
+		Writer:    strings.Join(writers, ", "),
+		PageCount: len(objects),
+		Manga:     "YesAndRightToLeft",
+	}
+	if chapter.Title != nil {
+		info.Title = *chapter.Title
+	}
+	if chapter.Volume != nil {
+		info.Volume = &chapter.Volume.VolumeNumber
+	}
+	if chapter.Group != nil {
+		info.Translator = chapter.Group.GroupName
+	}
+	if chapter.Language != entities.ChapterLanguageUnknown {
+		info.LanguageISO = chapter.Language
+	}
+	if manga.Description != nil {
+		info.Summary = *manga.Description
+	}
+
+	filename := fmt.Sprintf("%s - Ch. %s", manga.Title, number)
+	if info.LanguageISO != "" {
+		// Versions of a chapter in other languages download side by side
+		filename += " [" + info.LanguageISO + "]"
+	}
+
+	return &dto.ChapterDownload{
+		Filename:  filename + ".cbz",
+		Objects:   objects,
+		ComicInfo: info,
+	}, nil
+}
+
+// SetWatermark turns the watermark of a chapter on with the given group's watermark, or off when groupID is nil.
+// Every page is rendered right away so pages that can't be watermarked are reported before readers see them
+func (uc *ChapterUseCaseImpl) SetWatermark(userID, chapterID string, groupID *string) (*dto.ChapterWatermarkResponse, error) {
+	chapter, err := uc.chapterRepo.GetByID(chapterID)
+	if err != nil {
+		return nil, usecaseinf.ErrChapterNotFound
+	}
+
+	result := &dto.ChapterWatermarkResponse{
+		ChapterID:        chapter.ChapterID,
+		WatermarkGroupID: groupID,
+	}
+
+	if groupID == nil {
+		if chapter.WatermarkGroupID == nil {
+			return result, nil
+		}
+		if err := uc.watermarkUseCase.CheckGroupAccess(userID, *chapter.WatermarkGroupID); err != nil {
+			return nil, err
+		}
+	} else {
+		if err := uc.watermarkUseCase.CheckChapterAccess(userID, chapter.MangaID, *groupID); err != nil {
+			return nil, err
+		}
+		// Switching groups needs access to the group whose watermark is taken off too
+		if chapter.WatermarkGroupID != nil && *chapter.WatermarkGroupID != *groupID {
+			if err := uc.watermarkUseCase.CheckGroupAccess(userID, *chapter.WatermarkGroupID); err != nil {
+				return nil, err
+			}
+		}
+
+		objects, err := uc.pageObjects(chapter.ChapterID)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := uc.watermarkUseCase.RenderChapter(chapter.MangaID, chapter.ChapterID, *groupID, objects, 0); err != nil {
+			return nil, err
+		}
+		result.Pages = len(objects)
+	}
+
+	if err := uc.chapterRepo.UpdateWatermarkGroup(chapter.ChapterID, groupID); err != nil {
+		return nil, err
+	}
+	if chapter.WatermarkGroupID != nil && (groupID == nil || *groupID != *chapter.WatermarkGroupID) {
+		_ = uc.watermarkUseCase.PurgeChapter(chapter.MangaID, chapter.ChapterID)
+	}
+	return result, nil
+}
+
+// RenderWatermarks renders the missing watermarked variants of the watermarked chapters, such as pages added after
+// the watermark was turned on or variants discarded by a watermark change, at most limit of them. A chapter whose
+// pages can't be rendered doesn't hold the others back
+func (uc *ChapterUseCaseImpl) RenderWatermarks(limit int) (int, error) {
+	chapters, err := uc.chapterRepo.ListWatermarked()
+	if err != nil {
+		return 0, err
+	}
+
+	rendered := 0
+	var errs []error
+	for _, chapter := range chapters {
+		if rendered >= limit {
+			break
+		}
+		objects, err := uc.pageObjects(chapter.ChapterID)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		n, err := uc.watermarkUseCase.RenderChapter(chapter.MangaID, chapter.ChapterID, *chapter.WatermarkGroupID, objects, limit-rendered)
+		rendered += n
+		if err != nil {
+			errs = append(errs, fmt.Errorf("chapter %s: %w", chapter.ChapterID, err))
+		}
+	}
+	return rendered, errors.Join(errs...)
+}
+
+// deliveredObjects returns the storage objects readers get for a chapter's pages, which are
+// the watermarked variants when the chapter is watermarked. Variants are rendered when the watermark is
+// turned on and by the background job, never while serving a reader
+func (uc *ChapterUseCaseImpl) deliveredObjects(chapter *entities.MangaChapter) ([]string, error) {
+	objects, err := uc.pageObjects(chapter.ChapterID)
+	if err != nil {
+		return nil, err
+	}
+	if chapter.WatermarkGroupID == nil || len(objects) == 0 {
+		return objects, nil
+	}
+	return uc.watermarkUseCase.ChapterVariants(chapter.MangaID, chapter.ChapterID, *chapter.WatermarkGroupID, objects)
+}
+
+// pageObjects returns the storage objects of a chapter's pages in reading order. Chapters uploaded before pages
+// were recorded are backfilled at startup, see BackfillLegacyPages
+func (uc *ChapterUseCaseImpl) pageObjects(chapterID string) ([]string, error) {
+	pages, err := uc.pageRepo.ListByChapter(chapterID)
+	if err != nil {
+		return nil, err
+	}
+
+	var objects []string
+	for _, page := range pages {
+		objectName, err := uc.storageService.ObjectNameFromURL(page.ImageURL)
+		if err != nil {
+			return nil, err
+		}
+		objects = append(objects, objectName)
+	}
+	return objects, nil
+}
+
+// WriteArchive streams a prepared chapter archive as a CBZ to the writer
+func (uc *ChapterUseCaseImpl) WriteArchive(download *dto.ChapterDownload, w io.Writer) error {
+	zw := zip.NewWriter(w)
+
+	infoWriter, err := zw.Create("ComicInfo.xml")
+	if err != nil {
+		return fmt.Errorf("failed to create ComicInfo.xml: %w", err)
+	}
+	if _, err := io.WriteString(infoWriter, xml.Header); err != nil {
+		return fmt.Errorf("failed to write ComicInfo.xml: %w", err)
+	}
+	encoder := xml.NewEncoder(infoWriter)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(download.ComicInfo); err != nil {
+		return fmt.Errorf("failed to write ComicInfo.xml: %w", err)
+	}
+
+	// Pages are named by position so readers order them correctly regardless of the storage keys
+	width := len(strconv.Itoa(len(download.Objects)))
+	if width < 3 {
+		width = 3
+	}
+	for i, objectName := range download.Objects {
+		name := fmt.Sprintf("%0*d%s", width, i+1, strings.ToLower(path.Ext(objectName)))
+		if len(download.Names) > 0 {
+			name = download.Names[i]
+		}
+		if err := uc.writeArchiveEntry(zw, name, objectName); err != nil {
+			return err
+		}
+	}
+
+	if err := zw.Close(); err != nil {
+		return fmt.Errorf("failed to finalize archive: %w", err)
+	}
+	return nil
+}
+
+// writeArchiveEntry copies a single storage object into the archive without compression
+func (uc *ChapterUseCaseImpl) writeArchiveEntry(zw *zip.Writer, name, objectName string) error {
+	obj, err := uc.storageService.GetObject(objectName)
+	if err != nil {
+		return err
+	}
+	defer obj.Close()
+
+	// Images are already compressed, so store them as-is
+	entry, err := zw.CreateHeader(&zip.FileHeader{
+		Name:   name,
+		Method: zip.Store,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create archive entry %s: %w", name, err)
+	}
+
+	if _, err := io.Copy(entry, obj); err != nil {
+		return fmt.Errorf("failed to write archive entry %s: %w", name, err)
+	}
+	return nil
+}
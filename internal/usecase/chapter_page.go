@@ -0,0 +1,601 @@
+package usecase
+
+import (
+	"archive/zip"
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"path"
+	"sort"
+	"strings"
+
+	"hotaku-api/internal/domain/dto"
+	"hotaku-api/internal/domain/entities"
+	"hotaku-api/internal/repoinf"
+	"hotaku-api/internal/serviceinf"
+	"hotaku-api/internal/usecaseinf"
+	"hotaku-api/utils"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// MaxArchiveEntries limits how many entries a single archive may contain
+	MaxArchiveEntries = 1000
+	// MaxArchiveEntrySize is the largest image an archive entry may inflate to, the largest image storage accepts
+	MaxArchiveEntrySize = 10 * 1024 * 1024 // 10MB
+	// MaxArchiveUncompressedSize limits how much the images of a single archive may inflate to altogether
+	MaxArchiveUncompressedSize = 1024 * 1024 * 1024 // 1GB
+	// MaxDuplicateDistance is the largest perceptual hash distance at which two pages are flagged as duplicates
+	MaxDuplicateDistance = 5
+)
+
+// uploadedPage is a page image already stored but not yet numbered in chapter_pages
+type uploadedPage struct {
+	url            string
+	filename       string
+	contentType    string
+	size           int64
+	originalSize   int64
+	contentSHA256  string
+	perceptualHash *uint64
+	uploadedBy     string
+	// reused is set when the image points at an object stored for another page, which this upload doesn't own
+	reused bool
+}
+
+// ChapterPageUseCaseImpl implements the chapter page use cases
+type ChapterPageUseCaseImpl struct {
+	userRepo       repoinf.UserRepository
+	groupRepo      repoinf.GroupRepository
+	chapterRepo    repoinf.ChapterRepository
+	pageRepo       repoinf.ChapterPageRepository
+	duplicateRepo  repoinf.PageDuplicateRepository
+	fileUseCase    usecaseinf.FileUseCase
+	storageService serviceinf.StorageService
+	// maxPages and concurrency bound the pages of a single upload and how many are stored at once
+	maxPages    int
+	concurrency int
+}
+
+// NewChapterPageUseCase creates a new instance of ChapterPageUseCaseImpl
+func NewChapterPageUseCase(
+	userRepo repoinf.UserRepository,
+	groupRepo repoinf.GroupRepository,
+	chapterRepo repoinf.ChapterRepository,
+	pageRepo repoinf.ChapterPageRepository,
+	duplicateRepo repoinf.PageDuplicateRepository,
+	fileUseCase usecaseinf.FileUseCase,
+	storageService serviceinf.StorageService,
+	maxPages int,
+	concurrency int,
+) usecaseinf.ChapterPageUseCase {
+	return &ChapterPageUseCaseImpl{
+		userRepo:       userRepo,
+		groupRepo:      groupRepo,
+		chapterRepo:    chapterRepo,
+		pageRepo:       pageRepo,
+		duplicateRepo:  duplicateRepo,
+		fileUseCase:    fileUseCase,
+		storageService: storageService,
+		maxPages:       maxPages,
+		concurrency:    concurrency,
+	}
+}
+
+// BeginPageUpload starts a batch of chapter pages that are stored as they arrive until ctx is cancelled.
+// A partial batch keeps the pages that were stored when others fail, otherwise nothing is kept
+func (uc *ChapterPageUseCaseImpl) BeginPageUpload(ctx context.Context, userID, mangaID, chapterID string, partial bool) (usecaseinf.PageUploadBatch, error) {
+	if err := uc.ensureChapterInManga(mangaID, chapterID); err != nil {
+		return nil, err
+	}
+	return newPageUploadBatch(ctx, uc, userID, mangaID, chapterID, partial), nil
+}
+
+// ReplacePage replaces the image of an existing chapter page
+func (uc *ChapterPageUseCaseImpl) ReplacePage(userID, mangaID, chapterID string, pageNumber int, file *multipart.FileHeader) (*dto.PageUploadResponse, error) {
+	if err := uc.ensureChapterInManga(mangaID, chapterID); err != nil {
+		return nil, err
+	}
+
+	page, err := uc.pageRepo.GetByNumber(chapterID, pageNumber)
+	if err != nil {
+		return nil, err
+	}
+
+	upload, err := hashUploadedFile(file)
+	if err != nil {
+		return nil, err
+	}
+	err = uc.storePage(userID, mangaID, upload, make(map[string]string), func() (*serviceinf.StoredImage, error) {
+		return uc.storageService.UploadChapterPage(file, mangaID, chapterID)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := uc.pageRepo.UpdateImage(page.PageID, upload.url, &upload.contentSHA256, upload.perceptualHash, &userID); err != nil {
+		uc.discardUploads([]uploadedPage{*upload})
+		return nil, err
+	}
+
+	// Identical pages share one object, so the previous image may still be shown elsewhere
+	if referenced, err := uc.pageRepo.FilterReferencedImageURLs([]string{page.ImageURL}); err == nil && len(referenced) == 0 {
+		uc.discardUploads([]uploadedPage{{url: page.ImageURL}})
+	}
+
+	page.ImageURL = upload.url
+	page.ContentSHA256 = &upload.contentSHA256
+	page.PerceptualHash = upload.perceptualHash
+	page.UploadedBy = &userID
+	uc.flagDuplicates(mangaID, []entities.ChapterPage{*page})
+
+	return &dto.PageUploadResponse{
+		PageID:       page.PageID,
+		PageNumber:   page.PageNumber,
+		URL:          upload.url,
+		Filename:     file.Filename,
+		Size:         upload.size,
+		OriginalSize: upload.originalSize,
+		Deduplicated: upload.reused,
+	}, nil
+}
+
+// ReorderPages atomically renumbers all pages of a chapter to follow the given page ID order, for an admin or a
+// member of a group credited on the manga
+func (uc *ChapterPageUseCaseImpl) ReorderPages(userID, mangaID, chapterID string, pageIDs []string) ([]dto.ChapterPageResponse, error) {
+	if err := uc.ensureChapterInManga(mangaID, chapterID); err != nil {
+		return nil, err
+	}
+	if err := uc.checkManageAccess(userID, mangaID); err != nil {
+		return nil, err
+	}
+
+	if err := uc.pageRepo.Reorder(chapterID, pageIDs); err != nil {
+		return nil, err
+	}
+
+	pages, err := uc.pageRepo.ListByChapter(chapterID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]dto.ChapterPageResponse, 0, len(pages))
+	for _, page := range pages {
+		result = append(result, dto.ChapterPageResponse{
+			PageID:     page.PageID,
+			PageNumber: page.PageNumber,
+			URL:        page.ImageURL,
+		})
+	}
+	return result, nil
+}
+
+// ImportArchive extracts the images of a CBZ/ZIP archive and appends them as pages of a chapter
+func (uc *ChapterPageUseCaseImpl) ImportArchive(userID, mangaID, chapterID string, archive io.ReaderAt, size int64) (*dto.ArchiveImportResponse, error) {
+	if err := uc.ensureChapterInManga(mangaID, chapterID); err != nil {
+		return nil, err
+	}
+
+	zr, err := zip.NewReader(archive, size)
+	if err != nil {
+		return nil, fmt.Errorf("invalid archive: %w", err)
+	}
+
+	if len(zr.File) > MaxArchiveEntries {
+		return nil, fmt.Errorf("archive contains %d entries, maximum allowed is %d", len(zr.File), MaxArchiveEntries)
+	}
+
+	result := &dto.ArchiveImportResponse{
+		Imported: []dto.PageUploadResponse{},
+		Failed:   []dto.FileErrorResponse{},
+		Skipped:  []string{},
+	}
+
+	// Keep only image entries, in natural order. Sizes are checked from the headers before anything is inflated, so
+	// a small archive of highly compressible entries is refused up front
+	var entries []*zip.File
+	var uncompressedSize uint64
+	for _, entry := range zr.File {
+		if entry.FileInfo().IsDir() {
+			continue
+		}
+		if isIgnoredArchiveEntry(entry.Name) || !isImageExtension(entry.Name) {
+			result.Skipped = append(result.Skipped, entry.Name)
+			continue
+		}
+		if entry.UncompressedSize64 > MaxArchiveEntrySize {
+			result.Failed = append(result.Failed, dto.FileErrorResponse{
+				Filename: entry.Name,
+				Error:    fmt.Sprintf("file size %d exceeds maximum allowed size %d", entry.UncompressedSize64, MaxArchiveEntrySize),
+			})
+			continue
+		}
+		uncompressedSize += entry.UncompressedSize64
+		if uncompressedSize > MaxArchiveUncompressedSize {
+			return nil, fmt.Errorf("archive images exceed the maximum uncompressed size %d", MaxArchiveUncompressedSize)
+		}
+		entries = append(entries, entry)
+	}
+	sort.SliceStable(entries, func(i, j int) bool {
+		return utils.NaturalLess(entries[i].Name, entries[j].Name)
+	})
+
+	// Upload each entry, keeping only the ones that succeed so page numbers have no gaps
+	var uploaded []uploadedPage
+	stored := make(map[string]string)
+	for _, entry := range entries {
+		upload, err := inspectArchiveEntry(entry)
+		if err == nil {
+			err = uc.storePage(userID, mangaID, upload, stored, func() (*serviceinf.StoredImage, error) {
+				return uc.uploadArchiveEntry(entry, upload.contentType, mangaID, chapterID)
+			})
+		}
+		if err != nil {
+			result.Failed = append(result.Failed, dto.FileErrorResponse{
+				Filename: entry.Name,
+				Error:    err.Error(),
+			})
+			continue
+		}
+		uploaded = append(uploaded, *upload)
+	}
+
+	if len(uploaded) > 0 {
+		result.Imported, err = uc.recordPages(mangaID, chapterID, uploaded, 0)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return result, nil
+}
+
+// BackfillLegacyPages records the pages of the chapters uploaded before pages were recorded, which only exist in
+// storage as page_NNN objects, numbering them in the natural order of their names. It must run before pages are
+// read or uploaded, since a chapter with recorded pages is never looked up in storage again
+func (uc *ChapterPageUseCaseImpl) BackfillLegacyPages() (int, int, error) {
+	chapters, err := uc.chapterRepo.ListWithoutPages()
+	if err != nil {
+		return 0, 0, err
+	}
+
+	backfilledChapters, backfilledPages := 0, 0
+	for _, chapter := range chapters {
+		prefix := fmt.Sprintf("manga/%s/chapters/%s/", chapter.MangaID, chapter.ChapterID)
+		files, err := uc.storageService.ListFiles(prefix)
+		if err != nil {
+			return backfilledChapters, backfilledPages, err
+		}
+
+		var objects []string
+		for _, file := range files {
+			// Watermarked variants live one directory down
+			if path.Dir(file)+"/" == prefix && isImageExtension(file) {
+				objects = append(objects, file)
+			}
+		}
+		if len(objects) == 0 {
+			continue
+		}
+		sort.SliceStable(objects, func(i, j int) bool {
+			return utils.NaturalLess(objects[i], objects[j])
+		})
+
+		pages := make([]entities.ChapterPage, 0, len(objects))
+		for _, objectName := range objects {
+			pages = append(pages, entities.ChapterPage{
+				PageID:     uuid.New().String(),
+				ExternalID: uuid.New().String(),
+				ImageURL:   uc.storageService.FileURL(objectName),
+			})
+		}
+		if err := uc.pageRepo.AppendPages(chapter.ChapterID, pages); err != nil {
+			return backfilledChapters, backfilledPages, err
+		}
+		backfilledChapters++
+		backfilledPages += len(pages)
+	}
+	return backfilledChapters, backfilledPages, nil
+}
+
+// AppendPageFromObject stores a normalized copy of an already stored object as the next page of a chapter
+func (uc *ChapterPageUseCaseImpl) AppendPageFromObject(userID, mangaID, chapterID, sourceObject, filename, contentType string, size int64) (*dto.PageUploadResponse, error) {
+	if err := uc.ensureChapterInManga(mangaID, chapterID); err != nil {
+		return nil, err
+	}
+
+	obj, err := uc.storageService.GetObject(sourceObject)
+	if err != nil {
+		return nil, err
+	}
+	contentSHA256, perceptualHash, err := utils.HashImage(obj)
+	obj.Close()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read uploaded file: %w", err)
+	}
+
+	upload := &uploadedPage{filename: filename, contentType: contentType, size: size, originalSize: size, contentSHA256: contentSHA256, perceptualHash: perceptualHash}
+	err = uc.storePage(userID, mangaID, upload, make(map[string]string), func() (*serviceinf.StoredImage, error) {
+		objectName := uc.storageService.ChapterPageObjectName(mangaID, chapterID, strings.ToLower(path.Ext(sourceObject)))
+		return uc.storageService.StoreImageFromObject(sourceObject, objectName)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	pages, err := uc.recordPages(mangaID, chapterID, []uploadedPage{*upload}, 0)
+	if err != nil {
+		return nil, err
+	}
+	return &pages[0], nil
+}
+
+// inspectArchiveEntry reads an archive entry once to sniff its content type and hash it
+func inspectArchiveEntry(entry *zip.File) (*uploadedPage, error) {
+	src, size, err := openArchiveEntry(entry)
+	if err != nil {
+		return nil, err
+	}
+	defer src.Close()
+
+	// Sniff the real content type instead of trusting the extension
+	reader := bufio.NewReader(src)
+	head, err := reader.Peek(512)
+	if err != nil && err != io.EOF {
+		return nil, fmt.Errorf("failed to read archive entry: %w", err)
+	}
+
+	contentSHA256, perceptualHash, err := utils.HashImage(reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read archive entry: %w", err)
+	}
+	return &uploadedPage{
+		filename:       entry.Name,
+		contentType:    http.DetectContentType(head),
+		size:           size,
+		originalSize:   size,
+		contentSHA256:  contentSHA256,
+		perceptualHash: perceptualHash,
+	}, nil
+}
+
+// uploadArchiveEntry uploads a single archive entry as a chapter page image
+func (uc *ChapterPageUseCaseImpl) uploadArchiveEntry(entry *zip.File, contentType, mangaID, chapterID string) (*serviceinf.StoredImage, error) {
+	src, size, err := openArchiveEntry(entry)
+	if err != nil {
+		return nil, err
+	}
+	defer src.Close()
+
+	ext := strings.ToLower(path.Ext(entry.Name))
+	return uc.storageService.UploadChapterPageFromReader(context.Background(), src, size, contentType, ext, mangaID, chapterID)
+}
+
+// openArchiveEntry opens an archive entry along with its size, reading at most its declared size and never more
+// than MaxArchiveEntrySize, so an entry lying about its size can't inflate past the limit
+func openArchiveEntry(entry *zip.File) (io.ReadCloser, int64, error) {
+	size := min(int64(entry.UncompressedSize64), MaxArchiveEntrySize)
+	src, err := entry.Open()
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to open archive entry: %w", err)
+	}
+	return struct {
+		io.Reader
+		io.Closer
+	}{io.LimitReader(src, size), src}, size, nil
+}
+
+// storePage points upload at an object already holding the same bytes for the manga, or stores
+// it with store and records the user as its owner. stored maps the content hashes stored so far
+// in the current batch to their URLs, so repeats within a batch share one object as well
+func (uc *ChapterPageUseCaseImpl) storePage(userID, mangaID string, upload *uploadedPage, stored map[string]string, store func() (*serviceinf.StoredImage, error)) error {
+	upload.uploadedBy = userID
+	fileURL, ok := stored[upload.contentSHA256]
+	if !ok {
+		var err error
+		if fileURL, err = uc.pageRepo.FindImageURLByContentHash(mangaID, upload.contentSHA256); err != nil {
+			return err
+		}
+	}
+	if fileURL != "" {
+		upload.url = fileURL
+		upload.reused = true
+		return nil
+	}
+
+	image, err := store()
+	if err != nil {
+		return err
+	}
+	// Stripping metadata may change the size and the sniffed type may differ from the declared one
+	upload.url = image.URL
+	upload.contentType = image.ContentType
+	upload.size = image.Size
+	if err := uc.recordUpload(userID, mangaID, *upload); err != nil {
+		uc.discardUploads([]uploadedPage{*upload})
+		return err
+	}
+	stored[upload.contentSHA256] = image.URL
+	return nil
+}
+
+// recordUpload records the user as the owner of an uploaded page image
+func (uc *ChapterPageUseCaseImpl) recordUpload(userID, mangaID string, upload uploadedPage) error {
+	objectName, err := uc.storageService.ObjectNameFromURL(upload.url)
+	if err != nil {
+		return err
+	}
+	return uc.fileUseCase.RecordUpload(userID, mangaID, objectName, upload.contentType, upload.size)
+}
+
+// recordPages numbers and saves uploaded page images in one transaction, appending them or
+// inserting them at position when it is positive. On failure the uploaded objects are removed
+func (uc *ChapterPageUseCaseImpl) recordPages(mangaID, chapterID string, uploaded []uploadedPage, position int) ([]dto.PageUploadResponse, error) {
+	pages := make([]entities.ChapterPage, 0, len(uploaded))
+	for i := range uploaded {
+		pages = append(pages, entities.ChapterPage{
+			PageID:         uuid.New().String(),
+			ExternalID:     uuid.New().String(),
+			ImageURL:       uploaded[i].url,
+			ContentSHA256:  &uploaded[i].contentSHA256,
+			PerceptualHash: uploaded[i].perceptualHash,
+			UploadedBy:     &uploaded[i].uploadedBy,
+		})
+	}
+
+	var err error
+	if position > 0 {
+		err = uc.pageRepo.InsertPagesAt(chapterID, position, pages)
+	} else {
+		err = uc.pageRepo.AppendPages(chapterID, pages)
+	}
+	if err != nil {
+		// Don't leave unreferenced objects behind
+		uc.discardUploads(uploaded)
+		return nil, err
+	}
+
+	uc.flagDuplicates(mangaID, pages)
+
+	result := make([]dto.PageUploadResponse, 0, len(pages))
+	for i, page := range pages {
+		result = append(result, dto.PageUploadResponse{
+			PageID:       page.PageID,
+			PageNumber:   page.PageNumber,
+			URL:          page.ImageURL,
+			Filename:     uploaded[i].filename,
+			Size:         uploaded[i].size,
+			OriginalSize: uploaded[i].originalSize,
+			Deduplicated: uploaded[i].reused,
+		})
+	}
+	return result, nil
+}
+
+// flagDuplicates queues the pages of the manga that look like the given pages for moderator
+// review. Each pair is flagged once, by the newer page. Flagging is best effort and never
+// fails the upload that triggered it
+func (uc *ChapterPageUseCaseImpl) flagDuplicates(mangaID string, pages []entities.ChapterPage) {
+	// Pages later in the batch flag the earlier ones, not the other way around
+	pending := make(map[string]bool, len(pages))
+	for _, page := range pages {
+		pending[page.PageID] = true
+	}
+
+	var flags []entities.PageDuplicateFlag
+	for _, page := range pages {
+		delete(pending, page.PageID)
+		if page.PerceptualHash == nil {
+			continue
+		}
+
+		similar, err := uc.pageRepo.ListSimilar(mangaID, *page.PerceptualHash, MaxDuplicateDistance)
+		if err != nil {
+			return
+		}
+		for _, match := range similar {
+			if match.PageID == page.PageID || pending[match.PageID] {
+				continue
+			}
+			flags = append(flags, entities.PageDuplicateFlag{
+				FlagID:          uuid.New().String(),
+				MangaID:         mangaID,
+				PageID:          page.PageID,
+				DuplicatePageID: match.PageID,
+				Distance:        utils.HammingDistance(*page.PerceptualHash, *match.PerceptualHash),
+				Status:          entities.DuplicateStatusPending,
+			})
+		}
+	}
+
+	_ = uc.duplicateRepo.CreateFlags(flags)
+}
+
+// discardUploads removes stored page images and their ownership records, ignoring failures.
+// Reused images belong to other pages and are left alone
+func (uc *ChapterPageUseCaseImpl) discardUploads(uploaded []uploadedPage) {
+	for _, upload := range uploaded {
+		if upload.reused {
+			continue
+		}
+		if objectName, err := uc.storageService.ObjectNameFromURL(upload.url); err == nil {
+			_ = uc.fileUseCase.Discard(objectName)
+		}
+	}
+}
+
+// checkManageAccess verifies that the user may rearrange the pages of the manga's chapters: admins always may,
+// others must belong to a group credited on the manga
+func (uc *ChapterPageUseCaseImpl) checkManageAccess(userID, mangaID string) error {
+	user, err := uc.userRepo.GetByID(userID)
+	if err != nil {
+		return err
+	}
+	if user.IsAdmin() {
+		return nil
+	}
+	groupID, err := uc.groupRepo.FindUserGroupForManga(userID, mangaID)
+	if err != nil {
+		return err
+	}
+	if groupID == "" {
+		return usecaseinf.ErrPageForbidden
+	}
+	return nil
+}
+
+// ensureChapterInManga verifies that the chapter exists and belongs to the manga
+func (uc *ChapterPageUseCaseImpl) ensureChapterInManga(mangaID, chapterID string) error {
+	chapter, err := uc.chapterRepo.GetByID(chapterID)
+	if err != nil {
+		return err
+	}
+	if chapter.MangaID != mangaID {
+		return fmt.Errorf("chapter does not belong to manga")
+	}
+	return nil
+}
+
+// hashUploadedFile reads a multipart file to hash its content
+func hashUploadedFile(file *multipart.FileHeader) (*uploadedPage, error) {
+	src, err := file.Open()
+	if err != nil {
+		return nil, fmt.Errorf("failed to open file %s: %w", file.Filename, err)
+	}
+	defer src.Close()
+
+	contentSHA256, perceptualHash, err := utils.HashImage(src)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file %s: %w", file.Filename, err)
+	}
+	return &uploadedPage{
+		filename:       file.Filename,
+		contentType:    file.Header.Get("Content-Type"),
+		size:           file.Size,
+		originalSize:   file.Size,
+		contentSHA256:  contentSHA256,
+		perceptualHash: perceptualHash,
+	}, nil
+}
+
+// isIgnoredArchiveEntry reports whether an archive entry is OS metadata rather than content
+func isIgnoredArchiveEntry(name string) bool {
+	if strings.HasPrefix(name, "__MACOSX/") {
+		return true
+	}
+	return strings.HasPrefix(path.Base(name), ".")
+}
+
+// isImageExtension reports whether the filename has a supported image extension
+func isImageExtension(name string) bool {
+	switch strings.ToLower(path.Ext(name)) {
+	case ".jpg", ".jpeg", ".png", ".gif", ".webp":
+		return true
+	default:
+		return false
+	}
+}
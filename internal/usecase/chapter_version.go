@@ -0,0 +1,244 @@
+package usecase
+
+import (
+	"slices"
+	"sort"
+
+	"hotaku-api/internal/domain/dto"
+	"hotaku-api/internal/domain/entities"
+	"hotaku-api/internal/domain/request"
+	"hotaku-api/internal/usecaseinf"
+	"hotaku-api/utils"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// DefaultChapterPageSize is how many chapters are listed per page unless the request asks otherwise
+	DefaultChapterPageSize = 100
+	// ChapterVolumeNone is the volume filter keeping the chapters not in any volume
+	ChapterVolumeNone = "none"
+)
+
+// ListChapters returns a page of the chapters of a manga, latest first unless the request asks otherwise, filtered
+// by language, group and volume. With versions=best only one version of each chapter number is kept: the one in the
+// earliest language of the filter, or else of the preferred languages, then by the earliest group of the filter,
+// then the latest uploaded
+func (uc *ChapterUseCaseImpl) ListChapters(mangaID string, req *request.ListChaptersRequest, languages []string) (*dto.ChapterListResponse, error) {
+	query := &entities.ChapterListQuery{
+		MangaID:      mangaID,
+		BestVersions: req.Versions == "best",
+		ByVolume:     req.Sort == "volume",
+		Descending:   req.Order != "asc",
+		Limit:        req.Limit,
+	}
+	for _, value := range splitFilterList(req.Languages) {
+		language, ok := utils.NormalizeLocale(value)
+		if !ok {
+			return nil, usecaseinf.ErrInvalidChapterFilter
+		}
+		query.Languages = append(query.Languages, utils.LocaleLanguage(language))
+	}
+	for _, value := range splitFilterList(req.Groups) {
+		id, err := uuid.Parse(value)
+		if err != nil {
+			return nil, usecaseinf.ErrInvalidChapterFilter
+		}
+		query.GroupIDs = append(query.GroupIDs, id.String())
+	}
+	switch req.Volume {
+	case "":
+	case ChapterVolumeNone:
+		query.NoVolume = true
+	default:
+		id, err := uuid.Parse(req.Volume)
+		if err != nil {
+			return nil, usecaseinf.ErrInvalidChapterFilter
+		}
+		query.VolumeID = id.String()
+	}
+	if query.BestVersions {
+		query.RankLanguages = query.Languages
+		if len(query.RankLanguages) == 0 {
+			for _, language := range languages {
+				query.RankLanguages = append(query.RankLanguages, utils.LocaleLanguage(language))
+			}
+		}
+	}
+
+	page := max(req.Page, 1)
+	if query.Limit == 0 {
+		query.Limit = DefaultChapterPageSize
+	}
+	query.Offset = (page - 1) * query.Limit
+
+	if _, err := uc.mangaRepo.GetByID(mangaID); err != nil {
+		return nil, usecaseinf.ErrMangaNotFound
+	}
+	chapters, err := uc.chapterRepo.List(query)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &dto.ChapterListResponse{
+		Items: make([]dto.ChapterResponse, 0, len(chapters.Items)),
+		Total: chapters.Total,
+		Page:  page,
+		Limit: query.Limit,
+	}
+	for i := range chapters.Items {
+		result.Items = append(result.Items, *toChapterResponse(&chapters.Items[i]))
+	}
+	return result, nil
+}
+
+// SetAttribution credits a chapter to a language and a scanlation group credited on its manga. Admins may credit
+// any chapter, group members only chapters they move between their own groups
+func (uc *ChapterUseCaseImpl) SetAttribution(userID, chapterID string, req *request.SetChapterAttributionRequest) (*dto.ChapterResponse, error) {
+	chapter, err := uc.chapterRepo.GetByID(chapterID)
+	if err != nil {
+		return nil, usecaseinf.ErrChapterNotFound
+	}
+	language, ok := utils.NormalizeLocale(req.Language)
+	if !ok {
+		return nil, usecaseinf.ErrInvalidLocale
+	}
+	groupID := req.GroupID
+
+	if err := uc.checkAttributionAccess(userID, chapter.GroupID, groupID); err != nil {
+		return nil, err
+	}
+	if groupID != nil {
+		credited, err := uc.groupRepo.IsCredited(*groupID, chapter.MangaID)
+		if err != nil {
+			return nil, err
+		}
+		if !credited {
+			return nil, usecaseinf.ErrGroupNotCredited
+		}
+	}
+
+	exists, err := uc.chapterRepo.ExistsVersion(chapter, language, groupID)
+	if err != nil {
+		return nil, err
+	}
+	if exists {
+		return nil, usecaseinf.ErrChapterVersionExists
+	}
+
+	if err := uc.chapterRepo.UpdateAttribution(chapter.ChapterID, language, groupID); err != nil {
+		return nil, err
+	}
+	if chapter, err = uc.chapterRepo.GetByID(chapter.ChapterID); err != nil {
+		return nil, err
+	}
+	return toChapterResponse(chapter), nil
+}
+
+// checkAttributionAccess verifies that the user may credit a chapter away from its current group and to the new
+// one: admins always may, others must belong to both groups, and a chapter without any group is left to admins
+func (uc *ChapterUseCaseImpl) checkAttributionAccess(userID string, currentGroupID, groupID *string) error {
+	user, err := uc.userRepo.GetByID(userID)
+	if err != nil {
+		return err
+	}
+	if user.IsAdmin() {
+		return nil
+	}
+	if currentGroupID == nil && groupID == nil {
+		return usecaseinf.ErrChapterForbidden
+	}
+
+	for _, id := range []*string{currentGroupID, groupID} {
+		if id == nil {
+			continue
+		}
+		member, err := uc.groupRepo.IsMember(*id, userID)
+		if err != nil {
+			return err
+		}
+		if !member {
+			return usecaseinf.ErrChapterForbidden
+		}
+	}
+	return nil
+}
+
+// bestChapterVersions keeps a single version of each chapter number, in the order of the chapters. Versions are
+// ranked by the language they are in, then by their group, then the latest uploaded
+func bestChapterVersions(chapters []entities.MangaChapter, languages, groups []string) []entities.MangaChapter {
+	best := make(map[float64]int)
+	var numbers []float64
+	for i := range chapters {
+		number := chapters[i].ChapterNumber
+		current, ok := best[number]
+		if !ok {
+			best[number] = i
+			numbers = append(numbers, number)
+			continue
+		}
+		if betterChapterVersion(&chapters[i], &chapters[current], languages, groups) {
+			best[number] = i
+		}
+	}
+
+	sort.Float64s(numbers)
+	result := make([]entities.MangaChapter, 0, len(numbers))
+	for _, number := range numbers {
+		result = append(result, chapters[best[number]])
+	}
+	return result
+}
+
+// betterChapterVersion reports whether a version of a chapter ranks above another one
+func betterChapterVersion(a, b *entities.MangaChapter, languages, groups []string) bool {
+	if rankA, rankB := languageRank(languages, a.Language), languageRank(languages, b.Language); rankA != rankB {
+		return rankA < rankB
+	}
+	if rankA, rankB := groupRank(groups, a.GroupID), groupRank(groups, b.GroupID); rankA != rankB {
+		return rankA < rankB
+	}
+	return a.CreatedAt.After(b.CreatedAt)
+}
+
+// languageRank returns the position of the first of the languages a chapter language serves, len(languages) if none
+func languageRank(languages []string, language string) int {
+	for i, preferred := range languages {
+		if utils.LocaleMatch(preferred, language) > 0 {
+			return i
+		}
+	}
+	return len(languages)
+}
+
+// groupRank returns the position of a chapter's group among the groups, len(groups) if it isn't one of them
+func groupRank(groups []string, groupID *string) int {
+	if groupID != nil {
+		if i := slices.Index(groups, *groupID); i >= 0 {
+			return i
+		}
+	}
+	return len(groups)
+}
+
+// toChapterResponse converts a chapter to its response representation
+func toChapterResponse(chapter *entities.MangaChapter) *dto.ChapterResponse {
+	result := &dto.ChapterResponse{
+		ChapterID:     chapter.ChapterID,
+		MangaID:       chapter.MangaID,
+		ChapterNumber: chapter.ChapterNumber,
+		Title:         chapter.Title,
+		Language:      chapter.Language,
+		GroupID:       chapter.GroupID,
+		CreatedAt:     chapter.CreatedAt,
+		UpdatedAt:     chapter.UpdatedAt,
+	}
+	if chapter.Group != nil {
+		result.GroupName = &chapter.Group.GroupName
+	}
+	if chapter.Volume != nil {
+		result.VolumeID = &chapter.Volume.VolumeID
+		result.VolumeNumber = &chapter.Volume.VolumeNumber
+	}
+	return result
+}
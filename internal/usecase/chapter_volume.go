@@ -0,0 +1,101 @@
+package usecase
+
+import (
+	"fmt"
+	"path"
+	"slices"
+	"strconv"
+	"strings"
+
+	"hotaku-api/internal/domain/dto"
+	"hotaku-api/internal/domain/entities"
+	"hotaku-api/internal/usecaseinf"
+)
+
+// PrepareVolumeDownload resolves the pages and metadata of a volume archive. It holds a single version of each
+// chapter of the volume, the one in the most preferred of the languages or else the latest uploaded, its pages
+// named by chapter and then by position so readers keep the chapters in order. Only a user who may see the pages
+// may download them
+func (uc *ChapterUseCaseImpl) PrepareVolumeDownload(userID, volumeID string, languages []string) (*dto.ChapterDownload, error) {
+	volume, err := uc.volumeRepo.GetByID(volumeID)
+	if err != nil {
+		return nil, usecaseinf.ErrVolumeNotFound
+	}
+
+	manga, err := uc.mangaRepo.GetByID(volume.MangaID)
+	if err != nil {
+		return nil, err
+	}
+	if err := uc.access.check(userID, manga); err != nil {
+		return nil, err
+	}
+
+	chapters, err := uc.chapterRepo.ListByManga(volume.MangaID)
+	if err != nil {
+		return nil, err
+	}
+	var volumeChapters []entities.MangaChapter
+	for _, chapter := range chapters {
+		if chapter.VolumeID != nil && *chapter.VolumeID == volume.VolumeID {
+			volumeChapters = append(volumeChapters, chapter)
+		}
+	}
+	volumeChapters = bestChapterVersions(volumeChapters, languages, nil)
+
+	download := &dto.ChapterDownload{}
+	chapterWidth := max(len(strconv.Itoa(len(volumeChapters))), 3)
+	var translators, languageISOs []string
+	for i := range volumeChapters {
+		chapter := &volumeChapters[i]
+		objects, err := uc.deliveredObjects(chapter)
+		if err != nil {
+			return nil, err
+		}
+
+		pageWidth := max(len(strconv.Itoa(len(objects))), 3)
+		for j, objectName := range objects {
+			download.Objects = append(download.Objects, objectName)
+			download.Names = append(download.Names, fmt.Sprintf("%0*d-%0*d%s", chapterWidth, i+1, pageWidth, j+1, strings.ToLower(path.Ext(objectName))))
+		}
+		if chapter.Group != nil && !slices.Contains(translators, chapter.Group.GroupName) {
+			translators = append(translators, chapter.Group.GroupName)
+		}
+		if chapter.Language != entities.ChapterLanguageUnknown && !slices.Contains(languageISOs, chapter.Language) {
+			languageISOs = append(languageISOs, chapter.Language)
+		}
+	}
+	if len(download.Objects) == 0 {
+		return nil, usecaseinf.ErrVolumeEmpty
+	}
+
+	var writers []string
+	for _, author := range manga.Authors {
+		writers = append(writers, author.AuthorName)
+	}
+
+	download.ComicInfo = dto.ComicInfo{
+		Series:     manga.Title,
+		Volume:     &volume.VolumeNumber,
+		Writer:     strings.Join(writers, ", "),
+		Translator: strings.Join(translators, ", "),
+		PageCount:  len(download.Objects),
+		Manga:      "YesAndRightToLeft",
+	}
+	if volume.Title != nil {
+		download.ComicInfo.Title = *volume.Title
+	}
+	// ComicInfo holds a single language, so volumes mixing languages leave it out
+	if len(languageISOs) == 1 {
+		download.ComicInfo.LanguageISO = languageISOs[0]
+	}
+	if manga.Description != nil {
+		download.ComicInfo.Summary = *manga.Description
+	}
+
+	filename := fmt.Sprintf("%s - Vol. %d", manga.Title, volume.VolumeNumber)
+	if download.ComicInfo.LanguageISO != "" {
+		filename += " [" + download.ComicInfo.LanguageISO + "]"
+	}
+	download.Filename = filename + ".cbz"
+	return download, nil
+}
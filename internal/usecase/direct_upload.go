@@ -0,0 +1,229 @@
+package usecase
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"path"
+	"strings"
+	"time"
+
+	"hotaku-api/internal/domain/dto"
+	"hotaku-api/internal/domain/entities"
+	"hotaku-api/internal/domain/request"
+	"hotaku-api/internal/repoinf"
+	"hotaku-api/internal/serviceinf"
+	"hotaku-api/internal/usecaseinf"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// PresignedUploadExpiry is how long a presigned upload URL stays valid
+	PresignedUploadExpiry = 15 * time.Minute
+	// FinalizeClaimLease is how long a finalize call holds an upload before another call may take over, in case
+	// it never got to release it
+	FinalizeClaimLease = 5 * time.Minute
+	// expiredIntentSweepBatch is how many expired upload intents are discarded per query
+	expiredIntentSweepBatch = 100
+)
+
+// DirectUploadUseCaseImpl implements the direct-to-storage upload use cases
+type DirectUploadUseCaseImpl struct {
+	intentRepo         repoinf.UploadIntentRepository
+	mangaRepo          repoinf.MangaRepository
+	chapterRepo        repoinf.ChapterRepository
+	chapterPageUseCase usecaseinf.ChapterPageUseCase
+	coverUseCase       usecaseinf.MangaCoverUseCase
+	storageService     serviceinf.StorageService
+}
+
+// NewDirectUploadUseCase creates a new instance of DirectUploadUseCaseImpl
+func NewDirectUploadUseCase(
+	intentRepo repoinf.UploadIntentRepository,
+	mangaRepo repoinf.MangaRepository,
+	chapterRepo repoinf.ChapterRepository,
+	chapterPageUseCase usecaseinf.ChapterPageUseCase,
+	coverUseCase usecaseinf.MangaCoverUseCase,
+	storageService serviceinf.StorageService,
+) usecaseinf.DirectUploadUseCase {
+	return &DirectUploadUseCaseImpl{
+		intentRepo:         intentRepo,
+		mangaRepo:          mangaRepo,
+		chapterRepo:        chapterRepo,
+		chapterPageUseCase: chapterPageUseCase,
+		coverUseCase:       coverUseCase,
+		storageService:     storageService,
+	}
+}
+
+// Presign registers an upload intent and returns a presigned POST policy the client uploads with, only accepting
+// the declared content type and size
+func (uc *DirectUploadUseCaseImpl) Presign(userID string, req *request.PresignUploadRequest) (*dto.PresignedUploadResponse, error) {
+	ext := strings.ToLower(path.Ext(req.Filename))
+	if err := uc.storageService.ValidateImage(req.Filename, req.ContentType, req.Size); err != nil {
+		return nil, err
+	}
+
+	// Make sure the target exists before handing out an upload URL
+	if _, err := uc.mangaRepo.GetByID(req.MangaID); err != nil {
+		return nil, err
+	}
+	var chapterID *string
+	if req.Purpose == entities.UploadPurposePage {
+		chapter, err := uc.chapterRepo.GetByID(req.ChapterID)
+		if err != nil {
+			return nil, err
+		}
+		if chapter.MangaID != req.MangaID {
+			return nil, fmt.Errorf("chapter does not belong to manga")
+		}
+		chapterID = &chapter.ChapterID
+	}
+
+	intent := &entities.UploadIntent{
+		UploadID:    uuid.New().String(),
+		UserID:      userID,
+		Purpose:     req.Purpose,
+		MangaID:     req.MangaID,
+		ChapterID:   chapterID,
+		ObjectName:  uc.storageService.StagingObjectName(userID, ext),
+		ContentType: req.ContentType,
+		Size:        req.Size,
+		ExpiresAt:   time.Now().Add(PresignedUploadExpiry),
+	}
+
+	uploadURL, fields, err := uc.storageService.PresignedPostPolicy(intent.ObjectName, intent.ContentType, intent.Size, PresignedUploadExpiry)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := uc.intentRepo.Create(intent); err != nil {
+		return nil, err
+	}
+
+	return &dto.PresignedUploadResponse{
+		UploadID:  intent.UploadID,
+		UploadURL: uploadURL,
+		Method:    http.MethodPost,
+		Fields:    fields,
+		ExpiresAt: intent.ExpiresAt,
+	}, nil
+}
+
+// Finalize verifies an uploaded object and registers it as a cover or chapter page
+func (uc *DirectUploadUseCaseImpl) Finalize(userID, uploadID string) (*dto.FinalizeUploadResponse, error) {
+	intent, err := uc.intentRepo.GetByID(uploadID)
+	if err != nil {
+		return nil, err
+	}
+	if intent.UserID != userID {
+		return nil, fmt.Errorf("upload not found")
+	}
+	if intent.FinalizedAt != nil {
+		return nil, fmt.Errorf("upload already finalized")
+	}
+	if intent.IsExpired() {
+		_ = uc.storageService.DeleteFile(intent.ObjectName)
+		return nil, fmt.Errorf("upload has expired")
+	}
+
+	// Claim the intent so concurrent finalize calls can't both register it. It is only marked finalized once
+	// registered, a failure releasing it so the client can retry
+	if err := uc.intentRepo.Claim(intent.UploadID, FinalizeClaimLease); err != nil {
+		return nil, err
+	}
+	result, err := uc.register(userID, intent)
+	if err != nil {
+		_ = uc.intentRepo.Release(intent.UploadID)
+		return nil, err
+	}
+	if err := uc.intentRepo.MarkFinalized(intent.UploadID); err != nil {
+		return nil, err
+	}
+
+	_ = uc.storageService.DeleteFile(intent.ObjectName)
+	return result, nil
+}
+
+// SweepExpired discards the uploads that expired before being finalized, along with their staged objects, which
+// count against no quota
+func (uc *DirectUploadUseCaseImpl) SweepExpired() (int, error) {
+	swept := 0
+	for {
+		intents, err := uc.intentRepo.ListExpired(time.Now(), expiredIntentSweepBatch)
+		if err != nil {
+			return swept, err
+		}
+		for i := range intents {
+			_ = uc.storageService.DeleteFile(intents[i].ObjectName)
+			if err := uc.intentRepo.Delete(intents[i].UploadID); err != nil {
+				return swept, err
+			}
+			swept++
+		}
+		if len(intents) < expiredIntentSweepBatch {
+			return swept, nil
+		}
+	}
+}
+
+// register verifies the staged object of an upload intent and registers it as a cover or chapter page
+func (uc *DirectUploadUseCaseImpl) register(userID string, intent *entities.UploadIntent) (*dto.FinalizeUploadResponse, error) {
+	if err := verifyStagedImage(uc.storageService, intent.ObjectName, intent.ContentType, intent.Size); err != nil {
+		// The staged object is unusable, drop it so the client can retry cleanly
+		_ = uc.storageService.DeleteFile(intent.ObjectName)
+		return nil, err
+	}
+
+	result := &dto.FinalizeUploadResponse{Purpose: intent.Purpose}
+	switch intent.Purpose {
+	case entities.UploadPurposePage:
+		page, err := uc.chapterPageUseCase.AppendPageFromObject(userID, intent.MangaID, *intent.ChapterID, intent.ObjectName, path.Base(intent.ObjectName), intent.ContentType, intent.Size)
+		if err != nil {
+			return nil, err
+		}
+		result.Page = page
+	case entities.UploadPurposeCover:
+		cover, err := uc.coverUseCase.AddCoverFromObject(userID, intent.MangaID, intent.ObjectName)
+		if err != nil {
+			return nil, err
+		}
+		result.Cover = cover
+	default:
+		return nil, fmt.Errorf("unsupported upload purpose: %s", intent.Purpose)
+	}
+	return result, nil
+}
+
+// verifyStagedImage checks that a staged object is an image matching the declared type and size
+func verifyStagedImage(storageService serviceinf.StorageService, objectName, contentType string, size int64) error {
+	info, err := storageService.StatObject(objectName)
+	if err != nil {
+		return fmt.Errorf("uploaded file not found")
+	}
+	if info.Size != size {
+		return fmt.Errorf("uploaded file size %d does not match declared size %d", info.Size, size)
+	}
+	if err := storageService.ValidateImage(objectName, contentType, info.Size); err != nil {
+		return err
+	}
+
+	// Don't trust the declared content type, sniff the actual bytes
+	obj, err := storageService.GetObject(objectName)
+	if err != nil {
+		return err
+	}
+	defer obj.Close()
+
+	head := make([]byte, 512)
+	n, err := io.ReadFull(obj, head)
+	if err != nil && err != io.ErrUnexpectedEOF && err != io.EOF {
+		return fmt.Errorf("failed to read uploaded file: %w", err)
+	}
+	if sniffed := http.DetectContentType(head[:n]); sniffed != contentType {
+		return fmt.Errorf("uploaded file content %s does not match declared type %s", sniffed, contentType)
+	}
+
+	return nil
+}
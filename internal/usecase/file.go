@@ -0,0 +1,179 @@
+package usecase
+
+import (
+	"fmt"
+
+	"hotaku-api/internal/domain/dto"
+	"hotaku-api/internal/domain/entities"
+	"hotaku-api/internal/repoinf"
+	"hotaku-api/internal/serviceinf"
+	"hotaku-api/internal/usecaseinf"
+)
+
+// FileUseCaseImpl implements the stored file ownership use cases
+type FileUseCaseImpl struct {
+	userRepo       repoinf.UserRepository
+	groupRepo      repoinf.GroupRepository
+	objectRepo     repoinf.StorageObjectRepository
+	storageService serviceinf.StorageService
+	userQuota      int64
+	groupQuota     int64
+}
+
+// NewFileUseCase creates a new instance of FileUseCaseImpl. A quota of zero means unlimited
+func NewFileUseCase(
+	userRepo repoinf.UserRepository,
+	groupRepo repoinf.GroupRepository,
+	objectRepo repoinf.StorageObjectRepository,
+	storageService serviceinf.StorageService,
+	userQuota, groupQuota int64,
+) usecaseinf.FileUseCase {
+	return &FileUseCaseImpl{
+		userRepo:       userRepo,
+		groupRepo:      groupRepo,
+		objectRepo:     objectRepo,
+		storageService: storageService,
+		userQuota:      userQuota,
+		groupQuota:     groupQuota,
+	}
+}
+
+// RecordUpload records the uploader and group of a newly stored object, failing when it would take the user or
+// their group over quota. The check and the record happen together so that concurrent uploads can't both pass a
+// check made before they were stored
+func (uc *FileUseCaseImpl) RecordUpload(userID, mangaID, objectName, contentType string, size int64) error {
+	object := &entities.StorageObject{
+		ObjectName:  objectName,
+		MangaID:     mangaID,
+		UploadedBy:  &userID,
+		ContentType: contentType,
+		Size:        size,
+	}
+
+	// Credit the upload to the user's group on this manga so fellow members can manage it
+	groupID, err := uc.groupRepo.FindUserGroupForManga(userID, mangaID)
+	if err != nil {
+		return err
+	}
+	if groupID != "" {
+		object.GroupID = &groupID
+	}
+
+	withinQuota, err := uc.objectRepo.CreateWithinQuota(object, uc.userQuota, uc.groupQuota)
+	if err != nil {
+		return err
+	}
+	if !withinQuota {
+		return fmt.Errorf("%w: storing %d more bytes goes over quota", usecaseinf.ErrQuotaExceeded, size)
+	}
+	return nil
+}
+
+// Discard removes a stored object and its ownership record, used to roll back failed uploads
+func (uc *FileUseCaseImpl) Discard(objectName string) error {
+	if err := uc.storageService.DeleteFile(objectName); err != nil {
+		return err
+	}
+	return uc.objectRepo.Delete(objectName)
+}
+
+// CheckAccess verifies that the user may manage a stored file
+func (uc *FileUseCaseImpl) CheckAccess(userID, objectName string) error {
+	_, _, err := uc.authorize(userID, objectName)
+	return err
+}
+
+// GetInfo returns information about a stored file the user may manage
+func (uc *FileUseCaseImpl) GetInfo(userID, objectName string) (*dto.FileInfoResponse, error) {
+	objectName, object, err := uc.authorize(userID, objectName)
+	if err != nil {
+		return nil, err
+	}
+
+	info, err := uc.storageService.StatObject(objectName)
+	if err != nil {
+		return nil, usecaseinf.ErrFileNotFound
+	}
+
+	result := &dto.FileInfoResponse{
+		ObjectName:  objectName,
+		Size:        info.Size,
+		ContentType: info.ContentType,
+	}
+	if object != nil {
+		result.MangaID = object.MangaID
+		result.UploadedBy = object.UploadedBy
+		result.GroupID = object.GroupID
+	}
+	return result, nil
+}
+
+// Delete removes the references the user or the uploader added to a stored file the user may manage, and
+// the file itself once nothing else references it
+func (uc *FileUseCaseImpl) Delete(userID, objectName string) error {
+	objectName, object, err := uc.authorize(userID, objectName)
+	if err != nil {
+		return err
+	}
+
+	// Identical pages share one object, so only the pages added by the user or the uploader are removed
+	// unless an admin deletes the file
+	user, err := uc.userRepo.GetByID(userID)
+	if err != nil {
+		return err
+	}
+	var owners []string
+	if !user.IsAdmin() {
+		owners = []string{userID}
+		if object.UploadedBy != nil {
+			owners = append(owners, *object.UploadedBy)
+		}
+	}
+
+	// Drop the database references first; a leftover object is harmless, a dangling page is not
+	unreferenced, err := uc.objectRepo.DeleteWithReferences(objectName, owners)
+	if err != nil || !unreferenced {
+		return err
+	}
+	return uc.storageService.DeleteFile(objectName)
+}
+
+// authorize validates the object name and checks that the user uploaded the file, belongs to
+// its group or is an admin. Files stored before ownership was tracked are left to admins
+func (uc *FileUseCaseImpl) authorize(userID, objectName string) (string, *entities.StorageObject, error) {
+	objectName, err := uc.storageService.NormalizeObjectName(objectName)
+	if err != nil {
+		return "", nil, usecaseinf.ErrInvalidObjectName
+	}
+
+	if _, err := uc.storageService.StatObject(objectName); err != nil {
+		return "", nil, usecaseinf.ErrFileNotFound
+	}
+
+	user, err := uc.userRepo.GetByID(userID)
+	if err != nil {
+		return "", nil, err
+	}
+
+	object, err := uc.objectRepo.GetByName(objectName)
+	if err != nil {
+		if user.IsAdmin() {
+			return objectName, nil, nil
+		}
+		return "", nil, usecaseinf.ErrFileForbidden
+	}
+
+	if user.IsAdmin() || (object.UploadedBy != nil && *object.UploadedBy == userID) {
+		return objectName, object, nil
+	}
+	if object.GroupID != nil {
+		member, err := uc.groupRepo.IsMember(*object.GroupID, userID)
+		if err != nil {
+			return "", nil, err
+		}
+		if member {
+			return objectName, object, nil
+		}
+	}
+	return "", nil, usecaseinf.ErrFileForbidden
+}
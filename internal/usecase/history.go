@@ -0,0 +1,125 @@
+package usecase
+
+import (
+	"hotaku-api/internal/domain/dto"
+	"hotaku-api/internal/domain/entities"
+	"hotaku-api/internal/domain/request"
+	"hotaku-api/internal/repoinf"
+	"hotaku-api/internal/usecaseinf"
+)
+
+// DefaultHistoryPageSize is how many history entries are listed per page unless the request asks otherwise
+const DefaultHistoryPageSize = 50
+
+// HistoryUseCaseImpl implements the reading history use cases
+type HistoryUseCaseImpl struct {
+	userRepo    repoinf.UserRepository
+	historyRepo repoinf.HistoryRepository
+}
+
+// NewHistoryUseCase creates a new instance of HistoryUseCaseImpl
+func NewHistoryUseCase(userRepo repoinf.UserRepository, historyRepo repoinf.HistoryRepository) usecaseinf.HistoryUseCase {
+	return &HistoryUseCaseImpl{
+		userRepo:    userRepo,
+		historyRepo: historyRepo,
+	}
+}
+
+// ListHistory returns a page of the user's reading history, the latest opened first, optionally narrowed to a manga
+func (uc *HistoryUseCaseImpl) ListHistory(userID string, req *request.ListHistoryRequest, languages []string) (*dto.HistoryResponse, error) {
+	user, err := uc.userRepo.GetByID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	page := max(req.Page, 1)
+	limit := req.Limit
+	if limit == 0 {
+		limit = DefaultHistoryPageSize
+	}
+	query := &entities.HistoryQuery{
+		UserID: userID,
+		Offset: (page - 1) * limit,
+		Limit:  limit,
+	}
+	if req.MangaID != "" {
+		query.MangaID = &req.MangaID
+	}
+
+	listed, err := uc.historyRepo.List(query)
+	if err != nil {
+		return nil, err
+	}
+	return &dto.HistoryResponse{
+		Paused: user.HistoryPaused,
+		Items:  toHistoryEntryResponses(listed.Items, languages),
+		Total:  listed.Total,
+		Page:   page,
+		Limit:  limit,
+	}, nil
+}
+
+// ExportHistory returns the whole reading history of the user, the latest opened first
+func (uc *HistoryUseCaseImpl) ExportHistory(userID string, languages []string) ([]dto.HistoryEntryResponse, error) {
+	listed, err := uc.historyRepo.List(&entities.HistoryQuery{UserID: userID})
+	if err != nil {
+		return nil, err
+	}
+	return toHistoryEntryResponses(listed.Items, languages), nil
+}
+
+// DeleteEntry removes an entry from the user's reading history
+func (uc *HistoryUseCaseImpl) DeleteEntry(userID, entryID string) error {
+	if err := uc.historyRepo.Delete(userID, entryID); err != nil {
+		return usecaseinf.ErrHistoryEntryNotFound
+	}
+	return nil
+}
+
+// ClearHistory removes the user's reading history, or only its entries of a manga
+func (uc *HistoryUseCaseImpl) ClearHistory(userID string, req *request.ClearHistoryRequest) (*dto.HistoryClearResponse, error) {
+	var mangaID *string
+	if req.MangaID != "" {
+		mangaID = &req.MangaID
+	}
+
+	deleted, err := uc.historyRepo.Clear(userID, mangaID)
+	if err != nil {
+		return nil, err
+	}
+	return &dto.HistoryClearResponse{Deleted: deleted}, nil
+}
+
+// SetPaused changes whether the chapters the user opens are recorded. Pausing keeps the entries already recorded
+func (uc *HistoryUseCaseImpl) SetPaused(userID string, req *request.SetHistoryPauseRequest) (*dto.HistoryPauseResponse, error) {
+	if err := uc.historyRepo.SetPaused(userID, *req.Paused); err != nil {
+		return nil, err
+	}
+	return &dto.HistoryPauseResponse{Paused: *req.Paused}, nil
+}
+
+// toHistoryEntryResponses converts history entries to their response representation, titling mangas in the best
+// matching languages
+func toHistoryEntryResponses(entries []entities.UserHistoryEntry, languages []string) []dto.HistoryEntryResponse {
+	result := make([]dto.HistoryEntryResponse, 0, len(entries))
+	for i := range entries {
+		entry := &entries[i]
+		item := dto.HistoryEntryResponse{
+			EntryID:    entry.EntryID,
+			MangaID:    entry.MangaID,
+			ChapterID:  entry.ChapterID,
+			PageNumber: entry.PageNumber,
+			OpenedAt:   entry.OpenedAt,
+		}
+		if entry.Manga != nil {
+			item.MangaTitle, _ = localizedTitle(entry.Manga, languages)
+		}
+		if entry.Chapter != nil {
+			item.ChapterNumber = entry.Chapter.ChapterNumber
+			item.ChapterTitle = entry.Chapter.Title
+			item.Language = entry.Chapter.Language
+		}
+		result = append(result, item)
+	}
+	return result
+}
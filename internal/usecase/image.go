@@ -0,0 +1,59 @@
+package usecase
+
+import (
+	"fmt"
+	"strings"
+
+	"hotaku-api/internal/repoinf"
+	"hotaku-api/internal/serviceinf"
+	"hotaku-api/internal/usecaseinf"
+)
+
+// ImageUseCaseImpl implements the image serving use cases
+type ImageUseCaseImpl struct {
+	mangaRepo       repoinf.MangaRepository
+	pageRepo        repoinf.ChapterPageRepository
+	imageURLService serviceinf.ImageURLService
+}
+
+// NewImageUseCase creates a new instance of ImageUseCaseImpl
+func NewImageUseCase(mangaRepo repoinf.MangaRepository, pageRepo repoinf.ChapterPageRepository, imageURLService serviceinf.ImageURLService) usecaseinf.ImageUseCase {
+	return &ImageUseCaseImpl{
+		mangaRepo:       mangaRepo,
+		pageRepo:        pageRepo,
+		imageURLService: imageURLService,
+	}
+}
+
+// CheckAccess verifies that an image may be served, requiring a valid signature for restricted mangas and
+// refusing the original pages of watermarked chapters, which readers only get as watermarked variants.
+// objectName must already be normalized, so it starts with manga/<manga_id>/
+func (uc *ImageUseCaseImpl) CheckAccess(objectName, expires, signature string) error {
+	parts := strings.Split(objectName, "/")
+	if len(parts) < 3 {
+		return usecaseinf.ErrInvalidObjectName
+	}
+
+	manga, err := uc.mangaRepo.GetByID(parts[1])
+	if err != nil {
+		return usecaseinf.ErrFileNotFound
+	}
+	if manga.IsRestricted() {
+		if err := uc.imageURLService.Verify(objectName, expires, signature); err != nil {
+			return fmt.Errorf("%w: %v", usecaseinf.ErrImageAccessDenied, err)
+		}
+	}
+
+	// Pages live under manga/<manga_id>/chapters/, their variants under a watermarked/ directory of the chapter
+	if len(parts) < 4 || parts[2] != "chapters" || strings.Contains(objectName, "/watermarked/") {
+		return nil
+	}
+	hidden, err := uc.pageRepo.OnlyInWatermarkedChapters(manga.MangaID, objectName)
+	if err != nil {
+		return err
+	}
+	if hidden {
+		return fmt.Errorf("%w: the page is only served watermarked", usecaseinf.ErrImageAccessDenied)
+	}
+	return nil
+}
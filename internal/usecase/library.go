@@ -0,0 +1,355 @@
+package usecase
+
+import (
+	"strings"
+
+	"hotaku-api/internal/domain/dto"
+	"hotaku-api/internal/domain/entities"
+	"hotaku-api/internal/domain/request"
+	"hotaku-api/internal/repoinf"
+	"hotaku-api/internal/serviceinf"
+	"hotaku-api/internal/usecaseinf"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// DefaultLibraryPageSize is how many favorites are listed per page unless the request asks otherwise
+	DefaultLibraryPageSize = 20
+	// LibraryShelfNone is the shelf filter keeping the favorites on no shelf
+	LibraryShelfNone = "none"
+)
+
+// builtinShelves are the shelves every library starts with, by kind along with their name
+var builtinShelves = []struct {
+	kind string
+	name string
+}{
+	{entities.ShelfKindReading, "Reading"},
+	{entities.ShelfKindPlanToRead, "Plan to Read"},
+	{entities.ShelfKindCompleted, "Completed"},
+	{entities.ShelfKindDropped, "Dropped"},
+}
+
+// LibraryUseCaseImpl implements the user library use cases
+type LibraryUseCaseImpl struct {
+	userRepo    repoinf.UserRepository
+	mangaRepo   repoinf.MangaRepository
+	libraryRepo repoinf.LibraryRepository
+	access      *mangaAccess
+	// suggestUseCase is invalidated when favorites change, as they rank the suggestions
+	suggestUseCase usecaseinf.SuggestUseCase
+}
+
+// NewLibraryUseCase creates a new instance of LibraryUseCaseImpl
+func NewLibraryUseCase(
+	userRepo repoinf.UserRepository,
+	mangaRepo repoinf.MangaRepository,
+	libraryRepo repoinf.LibraryRepository,
+	groupRepo repoinf.GroupRepository,
+	imageURLService serviceinf.ImageURLService,
+	suggestUseCase usecaseinf.SuggestUseCase,
+) usecaseinf.LibraryUseCase {
+	return &LibraryUseCaseImpl{
+		userRepo:       userRepo,
+		mangaRepo:      mangaRepo,
+		libraryRepo:    libraryRepo,
+		access:         newMangaAccess(userRepo, groupRepo, imageURLService),
+		suggestUseCase: suggestUseCase,
+	}
+}
+
+// GetLibrary returns a page of the user's library, the latest added first unless the request asks otherwise
+func (uc *LibraryUseCaseImpl) GetLibrary(userID string, req *request.ListLibraryRequest, languages []string) (*dto.LibraryResponse, error) {
+	user, err := uc.userRepo.GetByID(userID)
+	if err != nil {
+		return nil, err
+	}
+	return uc.listLibrary(user, userID, req, languages)
+}
+
+// GetPublicLibrary returns a page of another user's library when they share it to the viewer, empty when signed
+// out. Private libraries are reported missing so their existence isn't revealed
+func (uc *LibraryUseCaseImpl) GetPublicLibrary(viewerID, ownerID string, req *request.ListLibraryRequest, languages []string) (*dto.LibraryResponse, error) {
+	owner, err := uc.userRepo.GetByID(ownerID)
+	if err != nil || !owner.LibraryPublic {
+		return nil, usecaseinf.ErrLibraryNotFound
+	}
+	return uc.listLibrary(owner, viewerID, req, languages)
+}
+
+// SaveFavorite adds a manga to the user's library, or moves it to another shelf if it already is in it
+func (uc *LibraryUseCaseImpl) SaveFavorite(userID, mangaID string, req *request.SaveFavoriteRequest) (*dto.FavoriteResponse, error) {
+	if _, err := uc.mangaRepo.GetByID(mangaID); err != nil {
+		return nil, usecaseinf.ErrMangaNotFound
+	}
+	if err := uc.ensureShelves(userID); err != nil {
+		return nil, err
+	}
+	if req.ShelfID != nil {
+		if _, err := uc.getShelf(userID, *req.ShelfID); err != nil {
+			return nil, err
+		}
+	}
+
+	favorite, err := uc.libraryRepo.GetFavorite(userID, mangaID)
+	if err != nil {
+		favorite = &entities.UserFavoriteManga{
+			FavoriteID: uuid.New().String(),
+			ExternalID: uuid.New().String(),
+			UserID:     userID,
+			MangaID:    mangaID,
+			ShelfID:    req.ShelfID,
+		}
+		if err := uc.libraryRepo.AddFavorite(favorite); err != nil {
+			return nil, err
+		}
+		uc.suggestUseCase.Invalidate()
+		return toFavoriteResponse(favorite), nil
+	}
+
+	if err := uc.libraryRepo.UpdateFavoriteShelf(favorite.FavoriteID, req.ShelfID); err != nil {
+		return nil, err
+	}
+	favorite.ShelfID = req.ShelfID
+	return toFavoriteResponse(favorite), nil
+}
+
+// RemoveFavorite removes a manga from the user's library
+func (uc *LibraryUseCaseImpl) RemoveFavorite(userID, mangaID string) error {
+	if err := uc.libraryRepo.DeleteFavorite(userID, mangaID); err != nil {
+		return usecaseinf.ErrFavoriteNotFound
+	}
+	uc.suggestUseCase.Invalidate()
+	return nil
+}
+
+// ListShelves returns the shelves of the user's library, built-in ones first, with how many mangas each holds
+func (uc *LibraryUseCaseImpl) ListShelves(userID string) ([]dto.LibraryShelfResponse, error) {
+	if err := uc.ensureShelves(userID); err != nil {
+		return nil, err
+	}
+
+	shelves, err := uc.libraryRepo.ListShelves(userID)
+	if err != nil {
+		return nil, err
+	}
+	counts, err := uc.libraryRepo.CountByShelf(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]dto.LibraryShelfResponse, 0, len(shelves))
+	for i := range shelves {
+		shelf := toLibraryShelfResponse(&shelves[i])
+		shelf.MangaCount = counts[shelf.ShelfID]
+		result = append(result, *shelf)
+	}
+	return result, nil
+}
+
+// CreateShelf adds a custom shelf to the user's library
+func (uc *LibraryUseCaseImpl) CreateShelf(userID string, req *request.SaveShelfRequest) (*dto.LibraryShelfResponse, error) {
+	if err := uc.ensureShelves(userID); err != nil {
+		return nil, err
+	}
+	name := strings.TrimSpace(req.Name)
+	if err := uc.checkShelfName(userID, "", name); err != nil {
+		return nil, err
+	}
+
+	shelf := entities.LibraryShelf{
+		ShelfID: uuid.New().String(),
+		UserID:  userID,
+		Name:    name,
+		Kind:    entities.ShelfKindCustom,
+	}
+	// The unique key settles two shelves created with the same name at once
+	created, err := uc.libraryRepo.CreateShelf(&shelf)
+	if err != nil {
+		return nil, err
+	}
+	if !created {
+		return nil, usecaseinf.ErrShelfExists
+	}
+	saved, err := uc.libraryRepo.GetShelf(shelf.ShelfID)
+	if err != nil {
+		return nil, err
+	}
+	return toLibraryShelfResponse(saved), nil
+}
+
+// RenameShelf changes the name of a shelf of the user's library, built-in ones included
+func (uc *LibraryUseCaseImpl) RenameShelf(userID, shelfID string, req *request.SaveShelfRequest) (*dto.LibraryShelfResponse, error) {
+	shelf, err := uc.getShelf(userID, shelfID)
+	if err != nil {
+		return nil, err
+	}
+	name := strings.TrimSpace(req.Name)
+	if err := uc.checkShelfName(userID, shelfID, name); err != nil {
+		return nil, err
+	}
+
+	shelf.Name = name
+	if err := uc.libraryRepo.UpdateShelf(shelf); err != nil {
+		return nil, err
+	}
+	counts, err := uc.libraryRepo.CountByShelf(userID)
+	if err != nil {
+		return nil, err
+	}
+	result := toLibraryShelfResponse(shelf)
+	result.MangaCount = counts[shelf.ShelfID]
+	return result, nil
+}
+
+// DeleteShelf removes a custom shelf, its mangas staying in the library without a shelf
+func (uc *LibraryUseCaseImpl) DeleteShelf(userID, shelfID string) error {
+	shelf, err := uc.getShelf(userID, shelfID)
+	if err != nil {
+		return err
+	}
+	if shelf.IsBuiltin() {
+		return usecaseinf.ErrShelfBuiltin
+	}
+	return uc.libraryRepo.DeleteShelf(shelfID)
+}
+
+// SetVisibility changes whether anyone may see the user's library
+func (uc *LibraryUseCaseImpl) SetVisibility(userID string, req *request.SetLibraryVisibilityRequest) (*dto.LibraryVisibilityResponse, error) {
+	if err := uc.libraryRepo.SetPublic(userID, *req.Public); err != nil {
+		return nil, err
+	}
+	return &dto.LibraryVisibilityResponse{Public: *req.Public}, nil
+}
+
+// listLibrary returns a page of a user's library as the viewer sees it
+func (uc *LibraryUseCaseImpl) listLibrary(user *entities.User, viewerID string, req *request.ListLibraryRequest, languages []string) (*dto.LibraryResponse, error) {
+	query := &entities.LibraryQuery{
+		UserID:     user.UserID,
+		Sort:       req.Sort,
+		Descending: req.Order != "asc",
+	}
+	switch req.Shelf {
+	case "":
+	case LibraryShelfNone:
+		query.NoShelf = true
+	default:
+		id, err := uuid.Parse(req.Shelf)
+		if err != nil {
+			return nil, usecaseinf.ErrInvalidLibraryFilter
+		}
+		shelfID := id.String()
+		query.ShelfID = &shelfID
+	}
+	if query.Sort == "" {
+		query.Sort = entities.LibrarySortAdded
+	}
+	page := max(req.Page, 1)
+	limit := req.Limit
+	if limit == 0 {
+		limit = DefaultLibraryPageSize
+	}
+	query.Offset = (page - 1) * limit
+	query.Limit = limit
+
+	listed, err := uc.libraryRepo.List(query)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &dto.LibraryResponse{
+		UserID: user.UserID,
+		Public: user.LibraryPublic,
+		Items:  make([]dto.LibraryItemResponse, 0, len(listed.Items)),
+		Total:  listed.Total,
+		Page:   page,
+		Limit:  limit,
+	}
+	for i := range listed.Items {
+		item := &listed.Items[i]
+		manga, err := toMangaResponse(&item.Manga, languages, uc.access, viewerID)
+		if err != nil {
+			return nil, err
+		}
+		result.Items = append(result.Items, dto.LibraryItemResponse{
+			FavoriteResponse: *toFavoriteResponse(&item.Favorite),
+			Manga:            *manga,
+			ChapterCount:     item.ChapterCount,
+			UnreadCount:      item.UnreadCount,
+			LatestChapterAt:  item.LatestChapterAt,
+		})
+	}
+	return result, nil
+}
+
+// ensureShelves creates the built-in shelves the user doesn't have yet
+func (uc *LibraryUseCaseImpl) ensureShelves(userID string) error {
+	shelves, err := uc.libraryRepo.ListShelves(userID)
+	if err != nil {
+		return err
+	}
+
+	var missing []entities.LibraryShelf
+	for _, builtin := range builtinShelves {
+		found := false
+		for _, shelf := range shelves {
+			if shelf.Kind == builtin.kind {
+				found = true
+				break
+			}
+		}
+		if !found {
+			missing = append(missing, entities.LibraryShelf{
+				ShelfID: uuid.New().String(),
+				UserID:  userID,
+				Name:    builtin.name,
+				Kind:    builtin.kind,
+			})
+		}
+	}
+	return uc.libraryRepo.CreateShelves(missing)
+}
+
+// getShelf retrieves a shelf of the user
+func (uc *LibraryUseCaseImpl) getShelf(userID, shelfID string) (*entities.LibraryShelf, error) {
+	shelf, err := uc.libraryRepo.GetShelf(shelfID)
+	if err != nil || shelf.UserID != userID {
+		return nil, usecaseinf.ErrShelfNotFound
+	}
+	return shelf, nil
+}
+
+// checkShelfName fails if another shelf of the user than the given one already has the name, ignoring case
+func (uc *LibraryUseCaseImpl) checkShelfName(userID, shelfID, name string) error {
+	shelves, err := uc.libraryRepo.ListShelves(userID)
+	if err != nil {
+		return err
+	}
+	for _, shelf := range shelves {
+		if shelf.ShelfID != shelfID && strings.EqualFold(shelf.Name, name) {
+			return usecaseinf.ErrShelfExists
+		}
+	}
+	return nil
+}
+
+// toLibraryShelfResponse converts a library shelf to its response representation
+func toLibraryShelfResponse(shelf *entities.LibraryShelf) *dto.LibraryShelfResponse {
+	return &dto.LibraryShelfResponse{
+		ShelfID:   shelf.ShelfID,
+		Name:      shelf.Name,
+		Kind:      shelf.Kind,
+		CreatedAt: shelf.CreatedAt,
+	}
+}
+
+// toFavoriteResponse converts a favorite to its response representation
+func toFavoriteResponse(favorite *entities.UserFavoriteManga) *dto.FavoriteResponse {
+	return &dto.FavoriteResponse{
+		FavoriteID: favorite.FavoriteID,
+		MangaID:    favorite.MangaID,
+		ShelfID:    favorite.ShelfID,
+		AddedAt:    favorite.AddedAt,
+	}
+}
@@ -0,0 +1,271 @@
+package usecase
+
+import (
+	"strconv"
+	"strings"
+
+	"hotaku-api/internal/domain/dto"
+	"hotaku-api/internal/domain/entities"
+	"hotaku-api/internal/domain/request"
+	"hotaku-api/internal/repoinf"
+	"hotaku-api/internal/serviceinf"
+	"hotaku-api/internal/usecaseinf"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// DefaultMangaListPageSize is how many mangas are listed per page unless the request asks otherwise
+	DefaultMangaListPageSize = 20
+	// maxMangaFilterValues is how many IDs a single listing filter may hold
+	maxMangaFilterValues = 20
+)
+
+// MangaUseCaseImpl implements the manga use cases
+type MangaUseCaseImpl struct {
+	userRepo      repoinf.UserRepository
+	mangaRepo     repoinf.MangaRepository
+	access        *mangaAccess
+	privateBucket bool
+}
+
+// NewMangaUseCase creates a new instance of MangaUseCaseImpl. Mangas may only be restricted with a private bucket,
+// as a public one serves every image to anyone
+func NewMangaUseCase(
+	userRepo repoinf.UserRepository,
+	mangaRepo repoinf.MangaRepository,
+	groupRepo repoinf.GroupRepository,
+	imageURLService serviceinf.ImageURLService,
+	privateBucket bool,
+) usecaseinf.MangaUseCase {
+	return &MangaUseCaseImpl{
+		userRepo:      userRepo,
+		mangaRepo:     mangaRepo,
+		access:        newMangaAccess(userRepo, groupRepo, imageURLService),
+		privateBucket: privateBucket,
+	}
+}
+
+// GetManga returns a manga with its primary cover and every localized title, its title and description being
+// the ones best matching the languages, most preferred first. The cover is left out for a user who may not see it
+func (uc *MangaUseCaseImpl) GetManga(userID, mangaID string, languages []string) (*dto.MangaResponse, error) {
+	manga, err := uc.mangaRepo.GetByID(mangaID)
+	if err != nil {
+		return nil, err
+	}
+	result, err := toMangaResponse(manga, languages, uc.access, userID)
+	if err != nil {
+		return nil, err
+	}
+	result.AltTitles = toMangaTitleResponses(manga.Titles)
+	return result, nil
+}
+
+// ListMangas returns a page of mangas matching the filters of the request, the latest updated first unless sorted
+// otherwise, titled in the languages best matching the preferred ones
+func (uc *MangaUseCaseImpl) ListMangas(userID string, req *request.ListMangasRequest, languages []string) (*dto.MangaListResponse, error) {
+	query := &entities.MangaListQuery{
+		YearFrom:    req.YearFrom,
+		YearTo:      req.YearTo,
+		ChaptersMin: req.ChaptersMin,
+		ChaptersMax: req.ChaptersMax,
+		Sort:        req.Sort,
+	}
+	var err error
+	if query.Categories, err = parseMangaRefFilter(req.Categories, req.CategoriesMode); err != nil {
+		return nil, err
+	}
+	if query.Authors, err = parseMangaRefFilter(req.Authors, req.AuthorsMode); err != nil {
+		return nil, err
+	}
+	if query.Groups, err = parseMangaRefFilter(req.Groups, req.GroupsMode); err != nil {
+		return nil, err
+	}
+	if query.StatusIDs, query.ExcludedStatusIDs, err = parseStatusFilter(req.Status); err != nil {
+		return nil, err
+	}
+	if (query.YearFrom != nil && query.YearTo != nil && *query.YearFrom > *query.YearTo) ||
+		(query.ChaptersMin != nil && query.ChaptersMax != nil && *query.ChaptersMin > *query.ChaptersMax) {
+		return nil, usecaseinf.ErrInvalidMangaFilter
+	}
+	if !req.UpdatedSince.IsZero() {
+		query.UpdatedSince = &req.UpdatedSince
+	}
+
+	if query.Sort == "" {
+		query.Sort = entities.MangaListSortLatestChapter
+	}
+	// Titles read best from A to Z, everything else from the latest or most popular down
+	query.Descending = query.Sort != entities.MangaListSortTitle
+	if req.Order != "" {
+		query.Descending = req.Order == "desc"
+	}
+	page := max(req.Page, 1)
+	limit := req.Limit
+	if limit == 0 {
+		limit = DefaultMangaListPageSize
+	}
+	query.Offset = (page - 1) * limit
+	query.Limit = limit
+
+	listed, err := uc.mangaRepo.List(query)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &dto.MangaListResponse{
+		Items: make([]dto.MangaListItemResponse, 0, len(listed.Items)),
+		Total: listed.Total,
+		Page:  page,
+		Limit: limit,
+	}
+	for i := range listed.Items {
+		item := &listed.Items[i]
+		manga, err := toMangaResponse(&item.Manga, languages, uc.access, userID)
+		if err != nil {
+			return nil, err
+		}
+		result.Items = append(result.Items, dto.MangaListItemResponse{
+			MangaResponse:   *manga,
+			ChapterCount:    item.ChapterCount,
+			LatestChapterAt: item.LatestChapterAt,
+		})
+	}
+	return result, nil
+}
+
+// SetVisibility changes whether the images of a manga are served through signed URLs only
+func (uc *MangaUseCaseImpl) SetVisibility(userID, mangaID, visibility string) (*dto.MangaResponse, error) {
+	user, err := uc.userRepo.GetByID(userID)
+	if err != nil {
+		return nil, err
+	}
+	if !user.IsAdmin() {
+		return nil, usecaseinf.ErrModeratorOnly
+	}
+	if visibility == entities.MangaVisibilityRestricted && !uc.privateBucket {
+		return nil, usecaseinf.ErrRestrictedNeedsPrivateBucket
+	}
+
+	if err := uc.mangaRepo.UpdateVisibility(mangaID, visibility); err != nil {
+		return nil, err
+	}
+	return uc.GetManga(userID, mangaID, nil)
+}
+
+// SetYear sets the year a manga was first published, nil clearing it. Only admins may change it
+func (uc *MangaUseCaseImpl) SetYear(userID, mangaID string, year *int) (*dto.MangaResponse, error) {
+	user, err := uc.userRepo.GetByID(userID)
+	if err != nil {
+		return nil, err
+	}
+	if !user.IsAdmin() {
+		return nil, usecaseinf.ErrModeratorOnly
+	}
+
+	if err := uc.mangaRepo.UpdateYear(mangaID, year); err != nil {
+		return nil, err
+	}
+	return uc.GetManga(userID, mangaID, nil)
+}
+
+// RefreshPopularity recomputes the popularity listings are sorted by
+func (uc *MangaUseCaseImpl) RefreshPopularity() error {
+	return uc.mangaRepo.RefreshPopularity()
+}
+
+// toMangaResponse converts a manga to its response representation, picking the title and description best matching
+// the languages and resolving its cover to a servable URL when the user may see it
+func toMangaResponse(manga *entities.Manga, languages []string, access *mangaAccess, userID string) (*dto.MangaResponse, error) {
+	authors := make([]string, 0, len(manga.Authors))
+	for _, author := range manga.Authors {
+		authors = append(authors, author.AuthorName)
+	}
+
+	result := &dto.MangaResponse{
+		MangaID:    manga.MangaID,
+		Year:       manga.Year,
+		StatusID:   manga.StatusID,
+		Visibility: manga.Visibility,
+		Authors:    authors,
+		CreatedAt:  manga.CreatedAt,
+		UpdatedAt:  manga.UpdatedAt,
+	}
+	result.Title, result.TitleLocale = localizedTitle(manga, languages)
+	result.Description, result.DescriptionLocale = localizedDescription(manga, languages)
+	if manga.PrimaryCover != nil {
+		coverURL, err := access.resolve(userID, manga, manga.PrimaryCover.ImageURL)
+		if err != nil {
+			return nil, err
+		}
+		result.CoverURL = coverURL
+	}
+	return result, nil
+}
+
+// parseMangaRefFilter parses a comma separated list of author, group or category IDs, an ID starting with "-"
+// being excluded. mode is "or" to match any included ID, every one of them otherwise
+func parseMangaRefFilter(list, mode string) (entities.MangaRefFilter, error) {
+	filter := entities.MangaRefFilter{MatchAll: mode != "or"}
+	included := make(map[string]bool)
+	excluded := make(map[string]bool)
+	for _, value := range splitFilterList(list) {
+		exclude := strings.HasPrefix(value, "-")
+		id, err := uuid.Parse(strings.TrimPrefix(value, "-"))
+		if err != nil {
+			return filter, usecaseinf.ErrInvalidMangaFilter
+		}
+		key := id.String()
+		switch {
+		case exclude && !excluded[key]:
+			excluded[key] = true
+			filter.Exclude = append(filter.Exclude, key)
+		case !exclude && !included[key]:
+			included[key] = true
+			filter.Include = append(filter.Include, key)
+		}
+		// An ID both required and excluded can't match anything, which is more likely a mistake than intended
+		if included[key] && excluded[key] {
+			return filter, usecaseinf.ErrInvalidMangaFilter
+		}
+	}
+	if len(filter.Include)+len(filter.Exclude) > maxMangaFilterValues {
+		return filter, usecaseinf.ErrInvalidMangaFilter
+	}
+	return filter, nil
+}
+
+// parseStatusFilter parses a comma separated list of status IDs into the included and excluded ones
+func parseStatusFilter(list string) (included, excluded []uint, err error) {
+	seen := make(map[string]bool)
+	for _, value := range splitFilterList(list) {
+		id, err := strconv.ParseUint(strings.TrimPrefix(value, "-"), 10, 32)
+		if err != nil || id == 0 {
+			return nil, nil, usecaseinf.ErrInvalidMangaFilter
+		}
+		if seen[value] {
+			continue
+		}
+		seen[value] = true
+		if strings.HasPrefix(value, "-") {
+			excluded = append(excluded, uint(id))
+		} else {
+			included = append(included, uint(id))
+		}
+	}
+	if len(included)+len(excluded) > maxMangaFilterValues {
+		return nil, nil, usecaseinf.ErrInvalidMangaFilter
+	}
+	return included, excluded, nil
+}
+
+// splitFilterList splits a comma separated list, skipping blank values
+func splitFilterList(list string) []string {
+	var values []string
+	for _, value := range strings.Split(list, ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
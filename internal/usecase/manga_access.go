@@ -0,0 +1,70 @@
+package usecase
+
+import (
+	"hotaku-api/internal/domain/entities"
+	"hotaku-api/internal/repoinf"
+	"hotaku-api/internal/serviceinf"
+	"hotaku-api/internal/usecaseinf"
+)
+
+// mangaAccess decides who may load the images of a manga. Anyone may load those of a public manga, while those of a
+// restricted manga are limited to admins and members of a group credited on the manga
+type mangaAccess struct {
+	userRepo        repoinf.UserRepository
+	groupRepo       repoinf.GroupRepository
+	imageURLService serviceinf.ImageURLService
+}
+
+// newMangaAccess creates a new instance of mangaAccess
+func newMangaAccess(userRepo repoinf.UserRepository, groupRepo repoinf.GroupRepository, imageURLService serviceinf.ImageURLService) *mangaAccess {
+	return &mangaAccess{
+		userRepo:        userRepo,
+		groupRepo:       groupRepo,
+		imageURLService: imageURLService,
+	}
+}
+
+// canView reports whether the user, empty when signed out, may load the images of the manga
+func (a *mangaAccess) canView(userID string, manga *entities.Manga) (bool, error) {
+	if !manga.IsRestricted() {
+		return true, nil
+	}
+	if userID == "" {
+		return false, nil
+	}
+
+	user, err := a.userRepo.GetByID(userID)
+	if err != nil {
+		return false, err
+	}
+	if user.IsAdmin() {
+		return true, nil
+	}
+	groupID, err := a.groupRepo.FindUserGroupForManga(userID, manga.MangaID)
+	if err != nil {
+		return false, err
+	}
+	return groupID != "", nil
+}
+
+// check fails with ErrMangaRestricted when the user may not load the images of the manga
+func (a *mangaAccess) check(userID string, manga *entities.Manga) error {
+	allowed, err := a.canView(userID, manga)
+	if err != nil {
+		return err
+	}
+	if !allowed {
+		return usecaseinf.ErrMangaRestricted
+	}
+	return nil
+}
+
+// resolve returns the URL the user loads an image of the manga from, or nil when they may not load it
+func (a *mangaAccess) resolve(userID string, manga *entities.Manga, fileURL string) (*string, error) {
+	allowed, err := a.canView(userID, manga)
+	if err != nil || !allowed {
+		return nil, err
+	}
+	url := a.imageURLService.Resolve(fileURL, manga.IsRestricted())
+	return &url, nil
+}
@@ -0,0 +1,235 @@
+package usecase
+
+import (
+	"fmt"
+	"mime/multipart"
+	"path"
+	"sort"
+	"strings"
+
+	"hotaku-api/internal/domain/dto"
+	"hotaku-api/internal/domain/entities"
+	"hotaku-api/internal/domain/request"
+	"hotaku-api/internal/repoinf"
+	"hotaku-api/internal/serviceinf"
+	"hotaku-api/internal/usecaseinf"
+
+	"github.com/google/uuid"
+	"github.com/minio/minio-go/v7"
+)
+
+// MangaCoverUseCaseImpl implements the manga cover use cases
+type MangaCoverUseCaseImpl struct {
+	mangaRepo      repoinf.MangaRepository
+	coverRepo      repoinf.MangaCoverRepository
+	fileUseCase    usecaseinf.FileUseCase
+	storageService serviceinf.StorageService
+	access         *mangaAccess
+}
+
+// NewMangaCoverUseCase creates a new instance of MangaCoverUseCaseImpl
+func NewMangaCoverUseCase(
+	userRepo repoinf.UserRepository,
+	groupRepo repoinf.GroupRepository,
+	mangaRepo repoinf.MangaRepository,
+	coverRepo repoinf.MangaCoverRepository,
+	fileUseCase usecaseinf.FileUseCase,
+	storageService serviceinf.StorageService,
+	imageURLService serviceinf.ImageURLService,
+) usecaseinf.MangaCoverUseCase {
+	return &MangaCoverUseCaseImpl{
+		mangaRepo:      mangaRepo,
+		coverRepo:      coverRepo,
+		fileUseCase:    fileUseCase,
+		storageService: storageService,
+		access:         newMangaAccess(userRepo, groupRepo, imageURLService),
+	}
+}
+
+// ListCovers returns all covers of a manga, primary first, to a user who may see them
+func (uc *MangaCoverUseCaseImpl) ListCovers(userID, mangaID string) ([]dto.MangaCoverResponse, error) {
+	manga, err := uc.mangaRepo.GetByID(mangaID)
+	if err != nil {
+		return nil, err
+	}
+	if err := uc.access.check(userID, manga); err != nil {
+		return nil, err
+	}
+
+	covers, err := uc.coverRepo.ListByManga(mangaID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]dto.MangaCoverResponse, 0, len(covers))
+	for i := range covers {
+		cover := toMangaCoverResponse(&covers[i])
+		cover.URL = uc.access.imageURLService.Resolve(cover.URL, manga.IsRestricted())
+		result = append(result, *cover)
+	}
+	return result, nil
+}
+
+// BackfillLegacyCovers records the covers of the mangas whose images were uploaded before covers were recorded,
+// which only exist in storage. The most recently uploaded image becomes the primary cover
+func (uc *MangaCoverUseCaseImpl) BackfillLegacyCovers() (int, int, error) {
+	mangas, err := uc.mangaRepo.ListWithoutCovers()
+	if err != nil {
+		return 0, 0, err
+	}
+
+	backfilledMangas, backfilledCovers := 0, 0
+	for _, manga := range mangas {
+		prefix := fmt.Sprintf("manga/%s/", manga.MangaID)
+		var objects []minio.ObjectInfo
+		err := uc.storageService.WalkObjects(prefix, func(obj minio.ObjectInfo) error {
+			// Chapter pages live further down
+			if path.Dir(obj.Key)+"/" == prefix && isImageExtension(obj.Key) {
+				objects = append(objects, obj)
+			}
+			return nil
+		})
+		if err != nil {
+			return backfilledMangas, backfilledCovers, err
+		}
+		if len(objects) == 0 {
+			continue
+		}
+		sort.Slice(objects, func(i, j int) bool {
+			return objects[i].LastModified.Before(objects[j].LastModified)
+		})
+
+		for i, obj := range objects {
+			cover := &entities.MangaCover{
+				CoverID:   uuid.New().String(),
+				MangaID:   manga.MangaID,
+				ImageURL:  uc.storageService.FileURL(obj.Key),
+				IsPrimary: i == len(objects)-1,
+				CreatedAt: obj.LastModified,
+			}
+			if err := uc.coverRepo.Create(cover); err != nil {
+				return backfilledMangas, backfilledCovers, err
+			}
+		}
+		backfilledMangas++
+		backfilledCovers += len(objects)
+	}
+	return backfilledMangas, backfilledCovers, nil
+}
+
+// UploadCover stores an image as a new cover of a manga
+func (uc *MangaCoverUseCaseImpl) UploadCover(userID, mangaID string, file *multipart.FileHeader, req *request.UploadCoverRequest) (*dto.MangaCoverResponse, error) {
+	if _, err := uc.mangaRepo.GetByID(mangaID); err != nil {
+		return nil, err
+	}
+
+	image, err := uc.storageService.UploadMangaImage(file, mangaID)
+	if err != nil {
+		return nil, err
+	}
+
+	return uc.recordCover(userID, mangaID, image, req)
+}
+
+// AddCoverFromObject stores a normalized copy of an already stored object as a new cover of a manga
+func (uc *MangaCoverUseCaseImpl) AddCoverFromObject(userID, mangaID, sourceObject string) (*dto.MangaCoverResponse, error) {
+	if _, err := uc.mangaRepo.GetByID(mangaID); err != nil {
+		return nil, err
+	}
+
+	objectName := uc.storageService.MangaImageObjectName(mangaID, strings.ToLower(path.Ext(sourceObject)))
+	image, err := uc.storageService.StoreImageFromObject(sourceObject, objectName)
+	if err != nil {
+		return nil, err
+	}
+
+	return uc.recordCover(userID, mangaID, image, &request.UploadCoverRequest{})
+}
+
+// SetPrimaryCover makes a cover the one shown for the manga
+func (uc *MangaCoverUseCaseImpl) SetPrimaryCover(userID, mangaID, coverID string) (*dto.MangaCoverResponse, error) {
+	cover, objectName, err := uc.getCover(mangaID, coverID)
+	if err != nil {
+		return nil, err
+	}
+	if err := uc.fileUseCase.CheckAccess(userID, objectName); err != nil {
+		return nil, err
+	}
+
+	if err := uc.coverRepo.SetPrimary(mangaID, coverID); err != nil {
+		return nil, err
+	}
+
+	cover.IsPrimary = true
+	return toMangaCoverResponse(cover), nil
+}
+
+// DeleteCover removes a cover and its image
+func (uc *MangaCoverUseCaseImpl) DeleteCover(userID, mangaID, coverID string) error {
+	_, objectName, err := uc.getCover(mangaID, coverID)
+	if err != nil {
+		return err
+	}
+
+	// Deleting the file removes the cover record and promotes another primary cover if needed
+	return uc.fileUseCase.Delete(userID, objectName)
+}
+
+// recordCover records ownership and the cover entry of a stored image, removing it on failure
+func (uc *MangaCoverUseCaseImpl) recordCover(userID, mangaID string, image *serviceinf.StoredImage, req *request.UploadCoverRequest) (*dto.MangaCoverResponse, error) {
+	objectName, err := uc.storageService.ObjectNameFromURL(image.URL)
+	if err != nil {
+		return nil, err
+	}
+	if err := uc.fileUseCase.RecordUpload(userID, mangaID, objectName, image.ContentType, image.Size); err != nil {
+		_ = uc.fileUseCase.Discard(objectName)
+		return nil, err
+	}
+
+	cover := &entities.MangaCover{
+		CoverID:    uuid.New().String(),
+		MangaID:    mangaID,
+		ImageURL:   uc.storageService.FileURL(objectName),
+		Volume:     req.Volume,
+		UploadedBy: &userID,
+		IsPrimary:  req.Primary,
+	}
+	if req.Locale != "" {
+		cover.Locale = &req.Locale
+	}
+	if err := uc.coverRepo.Create(cover); err != nil {
+		_ = uc.fileUseCase.Discard(objectName)
+		return nil, err
+	}
+
+	result := toMangaCoverResponse(cover)
+	result.Size = image.Size
+	result.OriginalSize = image.OriginalSize
+	return result, nil
+}
+
+// getCover retrieves a cover of the manga along with its object name
+func (uc *MangaCoverUseCaseImpl) getCover(mangaID, coverID string) (*entities.MangaCover, string, error) {
+	cover, err := uc.coverRepo.GetByID(coverID)
+	if err != nil || cover.MangaID != mangaID {
+		return nil, "", usecaseinf.ErrCoverNotFound
+	}
+	objectName, err := uc.storageService.ObjectNameFromURL(cover.ImageURL)
+	if err != nil {
+		return nil, "", err
+	}
+	return cover, objectName, nil
+}
+
+// toMangaCoverResponse converts a manga cover to its response representation
+func toMangaCoverResponse(cover *entities.MangaCover) *dto.MangaCoverResponse {
+	return &dto.MangaCoverResponse{
+		CoverID:    cover.CoverID,
+		URL:        cover.ImageURL,
+		Volume:     cover.Volume,
+		Locale:     cover.Locale,
+		UploadedBy: cover.UploadedBy,
+		IsPrimary:  cover.IsPrimary,
+		CreatedAt:  cover.CreatedAt,
+	}
+}
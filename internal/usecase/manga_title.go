@@ -0,0 +1,262 @@
+package usecase
+
+import (
+	"slices"
+	"strings"
+
+	"hotaku-api/internal/domain/dto"
+	"hotaku-api/internal/domain/entities"
+	"hotaku-api/internal/domain/request"
+	"hotaku-api/internal/repoinf"
+	"hotaku-api/internal/usecaseinf"
+	"hotaku-api/utils"
+
+	"github.com/google/uuid"
+)
+
+// MangaTitleUseCaseImpl implements the localized manga title and description use cases
+type MangaTitleUseCaseImpl struct {
+	userRepo       repoinf.UserRepository
+	mangaRepo      repoinf.MangaRepository
+	titleRepo      repoinf.MangaTitleRepository
+	suggestUseCase usecaseinf.SuggestUseCase
+}
+
+// NewMangaTitleUseCase creates a new instance of MangaTitleUseCaseImpl
+func NewMangaTitleUseCase(
+	userRepo repoinf.UserRepository,
+	mangaRepo repoinf.MangaRepository,
+	titleRepo repoinf.MangaTitleRepository,
+	suggestUseCase usecaseinf.SuggestUseCase,
+) usecaseinf.MangaTitleUseCase {
+	return &MangaTitleUseCaseImpl{
+		userRepo:       userRepo,
+		mangaRepo:      mangaRepo,
+		titleRepo:      titleRepo,
+		suggestUseCase: suggestUseCase,
+	}
+}
+
+// ListTitles returns all localized titles of a manga by locale, primary first
+func (uc *MangaTitleUseCaseImpl) ListTitles(mangaID string) ([]dto.MangaTitleResponse, error) {
+	if _, err := uc.mangaRepo.GetByID(mangaID); err != nil {
+		return nil, usecaseinf.ErrMangaNotFound
+	}
+
+	titles, err := uc.titleRepo.ListByManga(mangaID)
+	if err != nil {
+		return nil, err
+	}
+	return toMangaTitleResponses(titles), nil
+}
+
+// AddTitle adds a localized title to a manga, making it the one shown in its locale if requested or if it is the first
+func (uc *MangaTitleUseCaseImpl) AddTitle(userID, mangaID string, req *request.AddMangaTitleRequest) (*dto.MangaTitleResponse, error) {
+	if err := uc.requireAdmin(userID); err != nil {
+		return nil, err
+	}
+	locale, ok := utils.NormalizeLocale(req.Locale)
+	if !ok {
+		return nil, usecaseinf.ErrInvalidLocale
+	}
+	text := strings.TrimSpace(req.Title)
+
+	titles, err := uc.listManga(mangaID)
+	if err != nil {
+		return nil, err
+	}
+	for _, title := range titles {
+		if title.Locale == locale && strings.EqualFold(title.Title, text) {
+			return nil, usecaseinf.ErrMangaTitleExists
+		}
+	}
+
+	title := &entities.MangaTitle{
+		TitleID:   uuid.New().String(),
+		MangaID:   mangaID,
+		Locale:    locale,
+		Title:     text,
+		IsPrimary: req.Primary,
+	}
+	if err := uc.titleRepo.Create(title); err != nil {
+		return nil, err
+	}
+	uc.suggestUseCase.Invalidate()
+
+	return toMangaTitleResponse(title), nil
+}
+
+// DeleteTitle removes a localized title of a manga
+func (uc *MangaTitleUseCaseImpl) DeleteTitle(userID, mangaID, titleID string) error {
+	if err := uc.requireAdmin(userID); err != nil {
+		return err
+	}
+
+	titles, err := uc.listManga(mangaID)
+	if err != nil {
+		return err
+	}
+	if !slices.ContainsFunc(titles, func(title entities.MangaTitle) bool { return title.TitleID == titleID }) {
+		return usecaseinf.ErrMangaTitleNotFound
+	}
+
+	if err := uc.titleRepo.Delete(mangaID, titleID); err != nil {
+		return err
+	}
+	uc.suggestUseCase.Invalidate()
+	return nil
+}
+
+// ListDescriptions returns all localized descriptions of a manga
+func (uc *MangaTitleUseCaseImpl) ListDescriptions(mangaID string) ([]dto.MangaDescriptionResponse, error) {
+	if _, err := uc.mangaRepo.GetByID(mangaID); err != nil {
+		return nil, usecaseinf.ErrMangaNotFound
+	}
+
+	descriptions, err := uc.titleRepo.ListDescriptions(mangaID)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]dto.MangaDescriptionResponse, 0, len(descriptions))
+	for i := range descriptions {
+		result = append(result, *toMangaDescriptionResponse(&descriptions[i]))
+	}
+	return result, nil
+}
+
+// SetDescription creates or replaces the description of a manga in a locale
+func (uc *MangaTitleUseCaseImpl) SetDescription(userID, mangaID, locale string, req *request.SetMangaDescriptionRequest) (*dto.MangaDescriptionResponse, error) {
+	if err := uc.requireAdmin(userID); err != nil {
+		return nil, err
+	}
+	locale, ok := utils.NormalizeLocale(locale)
+	if !ok {
+		return nil, usecaseinf.ErrInvalidLocale
+	}
+	if _, err := uc.mangaRepo.GetByID(mangaID); err != nil {
+		return nil, usecaseinf.ErrMangaNotFound
+	}
+
+	description := &entities.MangaDescription{
+		MangaID:     mangaID,
+		Locale:      locale,
+		Description: strings.TrimSpace(req.Description),
+	}
+	if err := uc.titleRepo.SaveDescription(description); err != nil {
+		return nil, err
+	}
+	return toMangaDescriptionResponse(description), nil
+}
+
+// DeleteDescription removes the description of a manga in a locale
+func (uc *MangaTitleUseCaseImpl) DeleteDescription(userID, mangaID, locale string) error {
+	if err := uc.requireAdmin(userID); err != nil {
+		return err
+	}
+	locale, ok := utils.NormalizeLocale(locale)
+	if !ok {
+		return usecaseinf.ErrInvalidLocale
+	}
+	if _, err := uc.mangaRepo.GetByID(mangaID); err != nil {
+		return usecaseinf.ErrMangaNotFound
+	}
+
+	if err := uc.titleRepo.DeleteDescription(mangaID, locale); err != nil {
+		return usecaseinf.ErrMangaDescriptionNotFound
+	}
+	return nil
+}
+
+// requireAdmin fails unless the user is an admin
+func (uc *MangaTitleUseCaseImpl) requireAdmin(userID string) error {
+	user, err := uc.userRepo.GetByID(userID)
+	if err != nil {
+		return err
+	}
+	if !user.IsAdmin() {
+		return usecaseinf.ErrModeratorOnly
+	}
+	return nil
+}
+
+// listManga returns the titles of an existing manga
+func (uc *MangaTitleUseCaseImpl) listManga(mangaID string) ([]entities.MangaTitle, error) {
+	if _, err := uc.mangaRepo.GetByID(mangaID); err != nil {
+		return nil, usecaseinf.ErrMangaNotFound
+	}
+	return uc.titleRepo.ListByManga(mangaID)
+}
+
+// localizedTitle returns the title of a manga best matching the languages, most preferred first, along with
+// its locale. The default title, without a locale, is returned when no localized title matches
+func localizedTitle(manga *entities.Manga, languages []string) (string, *string) {
+	locales := make([]string, len(manga.Titles))
+	primary := make([]bool, len(manga.Titles))
+	for i, title := range manga.Titles {
+		locales[i] = title.Locale
+		primary[i] = title.IsPrimary
+	}
+	if i := bestLocale(languages, locales, primary); i >= 0 {
+		return manga.Titles[i].Title, &manga.Titles[i].Locale
+	}
+	return manga.Title, nil
+}
+
+// localizedDescription returns the description of a manga best matching the languages, most preferred first,
+// along with its locale. The default description, without a locale, is returned when no localized one matches
+func localizedDescription(manga *entities.Manga, languages []string) (*string, *string) {
+	locales := make([]string, len(manga.Descriptions))
+	for i, description := range manga.Descriptions {
+		locales[i] = description.Locale
+	}
+	if i := bestLocale(languages, locales, nil); i >= 0 {
+		return &manga.Descriptions[i].Description, &manga.Descriptions[i].Locale
+	}
+	return manga.Description, nil
+}
+
+// bestLocale returns the index of the locale best serving the most preferred language any of them serves,
+// primary ones winning ties, or -1 when none serves any of the languages
+func bestLocale(languages, locales []string, primary []bool) int {
+	for _, language := range languages {
+		best, bestScore := -1, 0
+		for i, locale := range locales {
+			score := utils.LocaleMatch(language, locale)
+			if score > bestScore || (score > 0 && score == bestScore && primary != nil && primary[i] && !primary[best]) {
+				best, bestScore = i, score
+			}
+		}
+		if best >= 0 {
+			return best
+		}
+	}
+	return -1
+}
+
+// toMangaTitleResponses converts manga titles to their response representation
+func toMangaTitleResponses(titles []entities.MangaTitle) []dto.MangaTitleResponse {
+	result := make([]dto.MangaTitleResponse, 0, len(titles))
+	for i := range titles {
+		result = append(result, *toMangaTitleResponse(&titles[i]))
+	}
+	return result
+}
+
+// toMangaTitleResponse converts a manga title to its response representation
+func toMangaTitleResponse(title *entities.MangaTitle) *dto.MangaTitleResponse {
+	return &dto.MangaTitleResponse{
+		TitleID:   title.TitleID,
+		Locale:    title.Locale,
+		Title:     title.Title,
+		IsPrimary: title.IsPrimary,
+	}
+}
+
+// toMangaDescriptionResponse converts a manga description to its response representation
+func toMangaDescriptionResponse(description *entities.MangaDescription) *dto.MangaDescriptionResponse {
+	return &dto.MangaDescriptionResponse{
+		Locale:      description.Locale,
+		Description: description.Description,
+		UpdatedAt:   description.UpdatedAt,
+	}
+}
@@ -0,0 +1,121 @@
+package usecase
+
+import (
+	"hotaku-api/internal/domain/dto"
+	"hotaku-api/internal/domain/entities"
+	"hotaku-api/internal/domain/request"
+	"hotaku-api/internal/repoinf"
+	"hotaku-api/internal/usecaseinf"
+)
+
+const (
+	// DefaultModerationPageSize is how many flags are listed per page unless the request asks otherwise
+	DefaultModerationPageSize = 20
+)
+
+// ModerationUseCaseImpl implements the moderation use cases
+type ModerationUseCaseImpl struct {
+	userRepo      repoinf.UserRepository
+	duplicateRepo repoinf.PageDuplicateRepository
+}
+
+// NewModerationUseCase creates a new instance of ModerationUseCaseImpl
+func NewModerationUseCase(userRepo repoinf.UserRepository, duplicateRepo repoinf.PageDuplicateRepository) usecaseinf.ModerationUseCase {
+	return &ModerationUseCaseImpl{
+		userRepo:      userRepo,
+		duplicateRepo: duplicateRepo,
+	}
+}
+
+// ListDuplicateFlags returns a page of duplicate page flags, pending ones unless another status is asked for
+func (uc *ModerationUseCaseImpl) ListDuplicateFlags(userID string, req *request.ListDuplicateFlagsRequest) (*dto.DuplicateFlagListResponse, error) {
+	if err := uc.ensureModerator(userID); err != nil {
+		return nil, err
+	}
+
+	status := req.Status
+	if status == "" {
+		status = entities.DuplicateStatusPending
+	}
+	page := max(req.Page, 1)
+	limit := req.Limit
+	if limit == 0 {
+		limit = DefaultModerationPageSize
+	}
+
+	flags, total, err := uc.duplicateRepo.List(status, req.MangaID, (page-1)*limit, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &dto.DuplicateFlagListResponse{
+		Items: make([]dto.DuplicateFlagResponse, 0, len(flags)),
+		Total: total,
+		Page:  page,
+		Limit: limit,
+	}
+	for i := range flags {
+		result.Items = append(result.Items, *toDuplicateFlagResponse(&flags[i]))
+	}
+	return result, nil
+}
+
+// ReviewDuplicateFlag confirms or dismisses a duplicate page flag
+func (uc *ModerationUseCaseImpl) ReviewDuplicateFlag(userID, flagID, status string) (*dto.DuplicateFlagResponse, error) {
+	if err := uc.ensureModerator(userID); err != nil {
+		return nil, err
+	}
+
+	if _, err := uc.duplicateRepo.GetByID(flagID); err != nil {
+		return nil, usecaseinf.ErrDuplicateFlagNotFound
+	}
+	if err := uc.duplicateRepo.UpdateStatus(flagID, status, userID); err != nil {
+		return nil, err
+	}
+
+	flag, err := uc.duplicateRepo.GetByID(flagID)
+	if err != nil {
+		return nil, err
+	}
+	return toDuplicateFlagResponse(flag), nil
+}
+
+// ensureModerator verifies that the user may review moderation queues
+func (uc *ModerationUseCaseImpl) ensureModerator(userID string) error {
+	user, err := uc.userRepo.GetByID(userID)
+	if err != nil {
+		return err
+	}
+	if !user.IsAdmin() {
+		return usecaseinf.ErrModeratorOnly
+	}
+	return nil
+}
+
+// toDuplicateFlagResponse converts a duplicate page flag to its response representation
+func toDuplicateFlagResponse(flag *entities.PageDuplicateFlag) *dto.DuplicateFlagResponse {
+	return &dto.DuplicateFlagResponse{
+		FlagID:        flag.FlagID,
+		MangaID:       flag.MangaID,
+		Page:          toDuplicatePageResponse(flag.Page),
+		DuplicatePage: toDuplicatePageResponse(flag.DuplicatePage),
+		Distance:      flag.Distance,
+		Status:        flag.Status,
+		ReviewedBy:    flag.ReviewedBy,
+		ReviewedAt:    flag.ReviewedAt,
+		CreatedAt:     flag.CreatedAt,
+	}
+}
+
+// toDuplicatePageResponse converts a flagged chapter page to its response representation
+func toDuplicatePageResponse(page *entities.ChapterPage) *dto.DuplicatePageResponse {
+	if page == nil {
+		return nil
+	}
+	return &dto.DuplicatePageResponse{
+		PageID:     page.PageID,
+		ChapterID:  page.ChapterID,
+		PageNumber: page.PageNumber,
+		URL:        page.ImageURL,
+	}
+}
@@ -0,0 +1,279 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"path"
+	"strings"
+	"sync"
+
+	"hotaku-api/internal/domain/dto"
+	"hotaku-api/internal/serviceinf"
+	"hotaku-api/internal/usecaseinf"
+	"hotaku-api/utils"
+)
+
+// Statuses of a page in a multi-page upload response
+const (
+	PageStatusUploaded = "uploaded"
+	PageStatusFailed   = "failed"
+)
+
+// batchPage is a page of an upload batch along with why it failed, if it did
+type batchPage struct {
+	upload uploadedPage
+	data   []byte
+	err    error
+}
+
+// pageUploadBatch stores the pages of a streamed upload while they arrive. Add is called from
+// the goroutine reading the request and queues each page for a fixed pool of workers, so
+// reading the request pauses while every worker is busy storing a page
+type pageUploadBatch struct {
+	uc        *ChapterPageUseCaseImpl
+	userID    string
+	mangaID   string
+	chapterID string
+	partial   bool
+
+	ctx       context.Context
+	cancel    context.CancelFunc
+	jobs      chan *batchPage
+	workers   sync.WaitGroup
+	closeJobs sync.Once
+
+	// pages holds the pages in upload order and hashes the first of them with each content hash.
+	// copies maps later pages with the same content to that first page, they share its object
+	pages  []*batchPage
+	hashes map[string]int
+	copies map[int]int
+
+	mu  sync.Mutex
+	err error
+}
+
+// newPageUploadBatch creates an empty batch for a chapter whose membership in the manga was checked
+// and starts its workers
+func newPageUploadBatch(ctx context.Context, uc *ChapterPageUseCaseImpl, userID, mangaID, chapterID string, partial bool) *pageUploadBatch {
+	ctx, cancel := context.WithCancel(ctx)
+	b := &pageUploadBatch{
+		uc:        uc,
+		userID:    userID,
+		mangaID:   mangaID,
+		chapterID: chapterID,
+		partial:   partial,
+		ctx:       ctx,
+		cancel:    cancel,
+		jobs:      make(chan *batchPage),
+		hashes:    make(map[string]int),
+		copies:    make(map[int]int),
+	}
+
+	for i := 0; i < max(uc.concurrency, 1); i++ {
+		b.workers.Add(1)
+		go b.work()
+	}
+	return b
+}
+
+// Add hashes a page and queues it for storage, blocking while every worker is busy.
+// It fails fast once the batch was cancelled, or once a page failed unless the batch is partial
+func (b *pageUploadBatch) Add(filename, contentType string, data []byte) error {
+	if err := b.checkAccepting(); err != nil {
+		return err
+	}
+
+	contentSHA256, perceptualHash, err := utils.HashImage(bytes.NewReader(data))
+	if err != nil {
+		return b.Reject(filename, fmt.Errorf("failed to read file %s: %w", filename, err))
+	}
+	page := &batchPage{
+		upload: uploadedPage{
+			filename:       filename,
+			contentType:    contentType,
+			size:           int64(len(data)),
+			originalSize:   int64(len(data)),
+			contentSHA256:  contentSHA256,
+			perceptualHash: perceptualHash,
+		},
+		data: data,
+	}
+	index := len(b.pages)
+	b.pages = append(b.pages, page)
+
+	// Repeats within the batch wait for the first copy rather than racing it to storage
+	if first, ok := b.hashes[contentSHA256]; ok {
+		b.copies[index] = first
+		page.data = nil
+		return nil
+	}
+	b.hashes[contentSHA256] = index
+
+	select {
+	case b.jobs <- page:
+		return nil
+	case <-b.ctx.Done():
+		return b.ctx.Err()
+	}
+}
+
+// Reject records a page that failed validation before reaching the batch. A partial batch
+// reports it as failed and goes on, otherwise err is returned
+func (b *pageUploadBatch) Reject(filename string, err error) error {
+	if !b.partial {
+		return err
+	}
+	if err := b.checkAccepting(); err != nil {
+		return err
+	}
+	b.pages = append(b.pages, &batchPage{upload: uploadedPage{filename: filename}, err: err})
+	return nil
+}
+
+// Commit waits for the queued pages and records the stored ones, appending them or inserting
+// them at position when it is positive. Unless the batch is partial, nothing is kept when any page failed
+func (b *pageUploadBatch) Commit(position int) (*dto.PageBatchUploadResponse, error) {
+	defer b.cancel()
+	b.finish()
+
+	// A failed page cancels a batch that isn't partial, so its error comes first. A client
+	// that went away gets nothing recorded, whatever the mode
+	if err := b.failure(); err != nil {
+		b.discard()
+		return nil, err
+	}
+	if err := b.ctx.Err(); err != nil {
+		b.discard()
+		return nil, err
+	}
+	if len(b.pages) == 0 {
+		return nil, fmt.Errorf("no pages uploaded")
+	}
+
+	var uploaded []uploadedPage
+	var indexes []int
+	for i, page := range b.pages {
+		if first, ok := b.copies[i]; ok {
+			original := b.pages[first]
+			page.err = original.err
+			page.upload.url = original.upload.url
+			page.upload.contentType = original.upload.contentType
+			page.upload.size = original.upload.size
+			page.upload.reused = true
+		}
+		if page.err == nil {
+			uploaded = append(uploaded, page.upload)
+			indexes = append(indexes, i)
+		}
+	}
+
+	result := &dto.PageBatchUploadResponse{
+		Pages: make([]dto.PageUploadStatus, len(b.pages)),
+	}
+	for i, page := range b.pages {
+		result.Pages[i] = dto.PageUploadStatus{Index: i, Filename: page.upload.filename, Status: PageStatusFailed}
+		if page.err != nil {
+			result.Pages[i].Error = page.err.Error()
+			result.Failed++
+		}
+	}
+	if len(uploaded) == 0 {
+		return result, nil
+	}
+
+	recorded, err := b.uc.recordPages(b.mangaID, b.chapterID, uploaded, position)
+	if err != nil {
+		return nil, err
+	}
+	for i, index := range indexes {
+		result.Pages[index].Status = PageStatusUploaded
+		result.Pages[index].Page = &recorded[i]
+		result.Uploaded++
+	}
+	return result, nil
+}
+
+// Abort cancels the queued pages and removes everything the batch stored
+func (b *pageUploadBatch) Abort() {
+	b.cancel()
+	b.finish()
+	b.discard()
+}
+
+// work stores queued pages until the queue is closed. Pages queued after the batch was
+// cancelled are failed without being stored
+func (b *pageUploadBatch) work() {
+	defer b.workers.Done()
+
+	for page := range b.jobs {
+		if err := b.ctx.Err(); err != nil {
+			page.err = err
+			continue
+		}
+
+		upload := &page.upload
+		err := b.uc.storePage(b.userID, b.mangaID, upload, make(map[string]string), func() (*serviceinf.StoredImage, error) {
+			ext := strings.ToLower(path.Ext(upload.filename))
+			return b.uc.storageService.UploadChapterPageFromReader(b.ctx, bytes.NewReader(page.data), int64(len(page.data)), upload.contentType, ext, b.mangaID, b.chapterID)
+		})
+		page.data = nil
+		if err != nil {
+			page.err = fmt.Errorf("failed to upload file %s: %w", upload.filename, err)
+			if !b.partial {
+				// The batch is lost anyway, stop storing pages that would be discarded
+				b.fail(page.err)
+				b.cancel()
+			}
+		}
+	}
+}
+
+// finish closes the queue and waits for the workers to drain it
+func (b *pageUploadBatch) finish() {
+	b.closeJobs.Do(func() {
+		close(b.jobs)
+	})
+	b.workers.Wait()
+}
+
+// discard removes the objects stored by the batch, copies and failed pages never stored one
+func (b *pageUploadBatch) discard() {
+	uploaded := make([]uploadedPage, 0, len(b.pages))
+	for i, page := range b.pages {
+		if _, ok := b.copies[i]; !ok && page.err == nil && page.upload.url != "" {
+			uploaded = append(uploaded, page.upload)
+		}
+	}
+	b.uc.discardUploads(uploaded)
+}
+
+// checkAccepting reports why no more pages may be added to the batch, if any
+func (b *pageUploadBatch) checkAccepting() error {
+	if err := b.failure(); err != nil {
+		return err
+	}
+	if err := b.ctx.Err(); err != nil {
+		return err
+	}
+	if b.uc.maxPages > 0 && len(b.pages) >= b.uc.maxPages {
+		return fmt.Errorf("%w: maximum allowed is %d", usecaseinf.ErrTooManyPages, b.uc.maxPages)
+	}
+	return nil
+}
+
+// fail records the first error of a batch that isn't partial
+func (b *pageUploadBatch) fail(err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.err == nil {
+		b.err = err
+	}
+}
+
+// failure returns the first error of the batch, if any
+func (b *pageUploadBatch) failure() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.err
+}
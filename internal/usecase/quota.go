@@ -0,0 +1,107 @@
+package usecase
+
+import (
+	"fmt"
+
+	"hotaku-api/internal/domain/dto"
+	"hotaku-api/internal/repoinf"
+	"hotaku-api/internal/usecaseinf"
+)
+
+// QuotaUseCaseImpl implements the storage quota use cases
+type QuotaUseCaseImpl struct {
+	groupRepo  repoinf.GroupRepository
+	objectRepo repoinf.StorageObjectRepository
+	userQuota  int64
+	groupQuota int64
+}
+
+// NewQuotaUseCase creates a new instance of QuotaUseCaseImpl. A quota of zero means unlimited
+func NewQuotaUseCase(groupRepo repoinf.GroupRepository, objectRepo repoinf.StorageObjectRepository, userQuota, groupQuota int64) usecaseinf.QuotaUseCase {
+	return &QuotaUseCaseImpl{
+		groupRepo:  groupRepo,
+		objectRepo: objectRepo,
+		userQuota:  userQuota,
+		groupQuota: groupQuota,
+	}
+}
+
+// CheckUpload verifies that storing size more bytes for the manga keeps the user and their group within quota
+func (uc *QuotaUseCaseImpl) CheckUpload(userID, mangaID string, size int64) error {
+	if uc.userQuota > 0 {
+		used, _, err := uc.objectRepo.UsageByUser(userID)
+		if err != nil {
+			return err
+		}
+		if used+size > uc.userQuota {
+			return fmt.Errorf("%w: user has %d of %d bytes left", usecaseinf.ErrQuotaExceeded, remaining(used, uc.userQuota), uc.userQuota)
+		}
+	}
+
+	if uc.groupQuota > 0 {
+		// Uploads are credited to the user's group on the manga, if any
+		groupID, err := uc.groupRepo.FindUserGroupForManga(userID, mangaID)
+		if err != nil {
+			return err
+		}
+		if groupID == "" {
+			return nil
+		}
+		used, _, err := uc.objectRepo.UsageByGroup(groupID)
+		if err != nil {
+			return err
+		}
+		if used+size > uc.groupQuota {
+			return fmt.Errorf("%w: group has %d of %d bytes left", usecaseinf.ErrQuotaExceeded, remaining(used, uc.groupQuota), uc.groupQuota)
+		}
+	}
+
+	return nil
+}
+
+// GetUsage returns the storage usage of the user and of each group they belong to
+func (uc *QuotaUseCaseImpl) GetUsage(userID string) (*dto.StorageUsageResponse, error) {
+	used, objects, err := uc.objectRepo.UsageByUser(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &dto.StorageUsageResponse{
+		User: dto.StorageUsage{
+			UsedBytes:  used,
+			QuotaBytes: uc.userQuota,
+			Objects:    objects,
+		},
+		Groups: []dto.GroupStorageUsage{},
+	}
+
+	groups, err := uc.groupRepo.ListByUser(userID)
+	if err != nil {
+		return nil, err
+	}
+	for _, group := range groups {
+		used, objects, err := uc.objectRepo.UsageByGroup(group.GroupID)
+		if err != nil {
+			return nil, err
+		}
+		result.Groups = append(result.Groups, dto.GroupStorageUsage{
+			GroupID:   group.GroupID,
+			GroupName: group.GroupName,
+			StorageUsage: dto.StorageUsage{
+				UsedBytes:  used,
+				QuotaBytes: uc.groupQuota,
+				Objects:    objects,
+			},
+		})
+	}
+
+	return result, nil
+}
+
+// remaining returns how many bytes are left under quota, never negative
+func remaining(used, quota int64) int64 {
+	if used >= quota {
+		return 0
+	}
+	return quota - used
+}
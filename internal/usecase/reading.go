@@ -0,0 +1,364 @@
+package usecase
+
+import (
+	"time"
+
+	"hotaku-api/internal/domain/dto"
+	"hotaku-api/internal/domain/entities"
+	"hotaku-api/internal/domain/request"
+	"hotaku-api/internal/repoinf"
+	"hotaku-api/internal/serviceinf"
+	"hotaku-api/internal/usecaseinf"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// DefaultContinueReadingPageSize is how many mangas the continue reading feed lists per page unless asked otherwise
+	DefaultContinueReadingPageSize = 20
+	// HistorySessionGap is how long a chapter stays open, going on reading it within this time moving its history
+	// entry rather than adding one
+	HistorySessionGap = 30 * time.Minute
+)
+
+// ReadingUseCaseImpl implements the reading progress use cases
+type ReadingUseCaseImpl struct {
+	userRepo    repoinf.UserRepository
+	mangaRepo   repoinf.MangaRepository
+	chapterRepo repoinf.ChapterRepository
+	pageRepo    repoinf.ChapterPageRepository
+	readingRepo repoinf.ReadingRepository
+	historyRepo repoinf.HistoryRepository
+	access      *mangaAccess
+}
+
+// NewReadingUseCase creates a new instance of ReadingUseCaseImpl
+func NewReadingUseCase(
+	userRepo repoinf.UserRepository,
+	mangaRepo repoinf.MangaRepository,
+	chapterRepo repoinf.ChapterRepository,
+	pageRepo repoinf.ChapterPageRepository,
+	readingRepo repoinf.ReadingRepository,
+	historyRepo repoinf.HistoryRepository,
+	groupRepo repoinf.GroupRepository,
+	imageURLService serviceinf.ImageURLService,
+) usecaseinf.ReadingUseCase {
+	return &ReadingUseCaseImpl{
+		userRepo:    userRepo,
+		mangaRepo:   mangaRepo,
+		chapterRepo: chapterRepo,
+		pageRepo:    pageRepo,
+		readingRepo: readingRepo,
+		historyRepo: historyRepo,
+		access:      newMangaAccess(userRepo, groupRepo, imageURLService),
+	}
+}
+
+// MarkRead records that the user read a chapter, now, and adds it to their history. Incognito reads leave no trace
+// at all: the read mark, which feeds the progress and continue reading, is skipped along with the history
+func (uc *ReadingUseCaseImpl) MarkRead(userID, chapterID string, incognito bool, languages []string) (*dto.MangaProgressResponse, error) {
+	chapter, err := uc.chapterRepo.GetByID(chapterID)
+	if err != nil {
+		return nil, usecaseinf.ErrChapterNotFound
+	}
+
+	if !incognito {
+		if err := uc.readingRepo.MarkRead(userID, []string{chapter.ChapterID}, time.Now()); err != nil {
+			return nil, err
+		}
+		if err := uc.recordHistory(userID, chapter, nil); err != nil {
+			return nil, err
+		}
+	}
+	return uc.GetProgress(userID, chapter.MangaID, languages)
+}
+
+// MarkUnread removes the reads of every version of a chapter by the user, since reading any of them counts
+func (uc *ReadingUseCaseImpl) MarkUnread(userID, chapterID string, languages []string) (*dto.MangaProgressResponse, error) {
+	chapter, err := uc.chapterRepo.GetByID(chapterID)
+	if err != nil {
+		return nil, usecaseinf.ErrChapterNotFound
+	}
+
+	chapters, err := uc.chapterRepo.ListByManga(chapter.MangaID)
+	if err != nil {
+		return nil, err
+	}
+	var chapterIDs []string
+	for _, version := range chapters {
+		if version.ChapterNumber == chapter.ChapterNumber {
+			chapterIDs = append(chapterIDs, version.ChapterID)
+		}
+	}
+
+	if err := uc.readingRepo.MarkUnread(userID, chapterIDs); err != nil {
+		return nil, err
+	}
+	return uc.GetProgress(userID, chapter.MangaID, languages)
+}
+
+// MarkReadUpTo records that the user read every version of the chapters of a manga numbered up to the given
+// chapter, keeping the read time of the chapters already read
+func (uc *ReadingUseCaseImpl) MarkReadUpTo(userID, mangaID string, req *request.MarkReadUpToRequest, languages []string) (*dto.MangaProgressResponse, error) {
+	if _, err := uc.mangaRepo.GetByID(mangaID); err != nil {
+		return nil, usecaseinf.ErrMangaNotFound
+	}
+
+	chapters, err := uc.chapterRepo.ListByManga(mangaID)
+	if err != nil {
+		return nil, err
+	}
+	reads, err := uc.chapterRepo.ListReadByManga(userID, mangaID)
+	if err != nil {
+		return nil, err
+	}
+	read := make(map[string]bool, len(reads))
+	for _, r := range reads {
+		read[r.ChapterID] = true
+	}
+
+	var chapterIDs []string
+	for _, chapter := range chapters {
+		if chapter.ChapterNumber <= *req.ChapterNumber && !read[chapter.ChapterID] {
+			chapterIDs = append(chapterIDs, chapter.ChapterID)
+		}
+	}
+
+	if err := uc.readingRepo.MarkRead(userID, chapterIDs, time.Now()); err != nil {
+		return nil, err
+	}
+	return uc.GetProgress(userID, mangaID, languages)
+}
+
+// SavePosition records the page of a chapter the user is on and adds the chapter to their history. Incognito reads
+// leave no trace at all: the position, which feeds the progress and continue reading, is skipped along with the history
+func (uc *ReadingUseCaseImpl) SavePosition(userID, chapterID string, req *request.SaveReadingPositionRequest, incognito bool, languages []string) (*dto.MangaProgressResponse, error) {
+	chapter, err := uc.chapterRepo.GetByID(chapterID)
+	if err != nil {
+		return nil, usecaseinf.ErrChapterNotFound
+	}
+
+	// Chapters uploaded before pages were recorded have no page count to check against
+	counts, err := uc.pageRepo.CountPagesByChapter([]string{chapter.ChapterID})
+	if err != nil {
+		return nil, err
+	}
+	if count := counts[chapter.ChapterID]; count > 0 && int64(req.PageNumber) > count {
+		return nil, usecaseinf.ErrInvalidPageNumber
+	}
+
+	if !incognito {
+		position := &entities.UserChapterPosition{
+			UserID:     userID,
+			ChapterID:  chapter.ChapterID,
+			PageNumber: req.PageNumber,
+		}
+		if err := uc.readingRepo.SavePosition(position); err != nil {
+			return nil, err
+		}
+		if err := uc.recordHistory(userID, chapter, &req.PageNumber); err != nil {
+			return nil, err
+		}
+	}
+	return uc.GetProgress(userID, chapter.MangaID, languages)
+}
+
+// GetProgress summarizes how much of a manga the user read and where to continue
+func (uc *ReadingUseCaseImpl) GetProgress(userID, mangaID string, languages []string) (*dto.MangaProgressResponse, error) {
+	if _, err := uc.mangaRepo.GetByID(mangaID); err != nil {
+		return nil, usecaseinf.ErrMangaNotFound
+	}
+	return uc.mangaProgress(userID, mangaID, languages)
+}
+
+// ContinueReading returns a page of the mangas the user read or opened a chapter of, the most recent first, leaving
+// out the mangas whose every chapter they read unless the request asks for them
+func (uc *ReadingUseCaseImpl) ContinueReading(userID string, req *request.ContinueReadingRequest, languages []string) (*dto.ContinueReadingResponse, error) {
+	page := max(req.Page, 1)
+	limit := req.Limit
+	if limit == 0 {
+		limit = DefaultContinueReadingPageSize
+	}
+
+	feed, err := uc.readingRepo.ListFeed(&entities.ReadingFeedQuery{
+		UserID:           userID,
+		IncludeCompleted: req.IncludeCompleted,
+		Offset:           (page - 1) * limit,
+		Limit:            limit,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result := &dto.ContinueReadingResponse{
+		Items: make([]dto.ContinueReadingItem, 0, len(feed.Items)),
+		Total: feed.Total,
+		Page:  page,
+		Limit: limit,
+	}
+	for _, activity := range feed.Items {
+		// A manga deleted in the meantime is left out of the page
+		manga, err := uc.mangaRepo.GetByID(activity.MangaID)
+		if err != nil {
+			continue
+		}
+		progress, err := uc.mangaProgress(userID, manga.MangaID, languages)
+		if err != nil {
+			return nil, err
+		}
+		mangaResponse, err := toMangaResponse(manga, languages, uc.access, userID)
+		if err != nil {
+			return nil, err
+		}
+		result.Items = append(result.Items, dto.ContinueReadingItem{
+			Manga:    *mangaResponse,
+			Progress: *progress,
+		})
+	}
+	return result, nil
+}
+
+// mangaProgress summarizes how much of a manga the user read and where to continue
+func (uc *ReadingUseCaseImpl) mangaProgress(userID, mangaID string, languages []string) (*dto.MangaProgressResponse, error) {
+	chapters, err := uc.chapterRepo.ListByManga(mangaID)
+	if err != nil {
+		return nil, err
+	}
+	reads, err := uc.chapterRepo.ListReadByManga(userID, mangaID)
+	if err != nil {
+		return nil, err
+	}
+	positions, err := uc.readingRepo.ListPositionsByManga(userID, mangaID)
+	if err != nil {
+		return nil, err
+	}
+	return readingProgress(mangaID, chapters, reads, positions, languages), nil
+}
+
+// recordHistory adds a chapter the user opened to their history, unless they paused it. Going on reading the
+// chapter last opened moves its entry, along with the page when known, rather than adding one
+func (uc *ReadingUseCaseImpl) recordHistory(userID string, chapter *entities.MangaChapter, pageNumber *int) error {
+	user, err := uc.userRepo.GetByID(userID)
+	if err != nil {
+		return err
+	}
+	if user.HistoryPaused {
+		return nil
+	}
+
+	now := time.Now()
+	if latest, err := uc.historyRepo.GetLatest(userID); err == nil && latest.ChapterID == chapter.ChapterID && now.Sub(latest.OpenedAt) < HistorySessionGap {
+		return uc.historyRepo.Touch(latest.EntryID, pageNumber, now)
+	}
+	return uc.historyRepo.Create(&entities.UserHistoryEntry{
+		EntryID:    uuid.New().String(),
+		UserID:     userID,
+		MangaID:    chapter.MangaID,
+		ChapterID:  chapter.ChapterID,
+		PageNumber: pageNumber,
+		OpenedAt:   now,
+	})
+}
+
+// readingProgress summarizes the reads of the chapters of a manga, expected by chapter number. The user continues
+// where they last were in a chapter they haven't read, unless they read a chapter since, in which case they
+// continue with the first unread chapter after it, or else the first unread chapter
+func readingProgress(mangaID string, chapters []entities.MangaChapter, reads []entities.UserReadChapter, positions []entities.UserChapterPosition, languages []string) *dto.MangaProgressResponse {
+	result := &dto.MangaProgressResponse{MangaID: mangaID}
+
+	byID := make(map[string]*entities.MangaChapter, len(chapters))
+	numbers := make(map[float64]bool)
+	var order []float64
+	for i := range chapters {
+		byID[chapters[i].ChapterID] = &chapters[i]
+		if _, ok := numbers[chapters[i].ChapterNumber]; !ok {
+			numbers[chapters[i].ChapterNumber] = false
+			order = append(order, chapters[i].ChapterNumber)
+		}
+	}
+	result.ChapterCount = len(order)
+
+	var lastRead *entities.MangaChapter
+	for i := range reads {
+		chapter, ok := byID[reads[i].ChapterID]
+		if !ok {
+			continue
+		}
+		if !numbers[chapter.ChapterNumber] {
+			numbers[chapter.ChapterNumber] = true
+			result.ReadCount++
+		}
+		if result.LastReadAt == nil || reads[i].ReadAt.After(*result.LastReadAt) {
+			result.LastReadAt = &reads[i].ReadAt
+			lastRead = chapter
+		}
+	}
+	result.Completed = result.ChapterCount > 0 && result.ReadCount == result.ChapterCount
+	if result.Completed {
+		return result
+	}
+
+	pages := make(map[string]int, len(positions))
+	var opened *entities.UserChapterPosition
+	for i := range positions {
+		chapter, ok := byID[positions[i].ChapterID]
+		if !ok {
+			continue
+		}
+		pages[chapter.ChapterID] = positions[i].PageNumber
+		if !numbers[chapter.ChapterNumber] && (opened == nil || positions[i].UpdatedAt.After(opened.UpdatedAt)) {
+			opened = &positions[i]
+		}
+	}
+	if opened != nil && (result.LastReadAt == nil || opened.UpdatedAt.After(*result.LastReadAt)) {
+		result.Continue = toContinueReadingChapter(byID[opened.ChapterID], opened.PageNumber)
+		return result
+	}
+
+	next := -1
+	for i, number := range order {
+		if numbers[number] {
+			continue
+		}
+		if lastRead == nil || number > lastRead.ChapterNumber {
+			next = i
+			break
+		}
+		if next < 0 {
+			next = i
+		}
+	}
+	if next < 0 {
+		return result
+	}
+
+	// A version the user already opened wins, or else the language they were reading in
+	var versions []entities.MangaChapter
+	for _, chapter := range chapters {
+		if chapter.ChapterNumber != order[next] {
+			continue
+		}
+		if page, ok := pages[chapter.ChapterID]; ok {
+			result.Continue = toContinueReadingChapter(&chapter, page)
+			return result
+		}
+		versions = append(versions, chapter)
+	}
+	if lastRead != nil && lastRead.Language != entities.ChapterLanguageUnknown {
+		languages = append([]string{lastRead.Language}, languages...)
+	}
+	best := bestChapterVersions(versions, languages, nil)
+	result.Continue = toContinueReadingChapter(&best[0], 1)
+	return result
+}
+
+// toContinueReadingChapter converts a chapter to where a user picks a manga back up
+func toContinueReadingChapter(chapter *entities.MangaChapter, pageNumber int) *dto.ContinueReadingChapter {
+	return &dto.ContinueReadingChapter{
+		ChapterID:     chapter.ChapterID,
+		ChapterNumber: chapter.ChapterNumber,
+		Title:         chapter.Title,
+		Language:      chapter.Language,
+		PageNumber:    pageNumber,
+	}
+}
@@ -0,0 +1,190 @@
+package usecase
+
+import (
+	"strings"
+
+	"hotaku-api/internal/domain/dto"
+	"hotaku-api/internal/domain/entities"
+	"hotaku-api/internal/domain/request"
+	"hotaku-api/internal/repoinf"
+	"hotaku-api/internal/serviceinf"
+	"hotaku-api/internal/usecaseinf"
+	"hotaku-api/utils"
+)
+
+const (
+	// DefaultSearchPageSize is how many mangas are listed per page unless the request asks otherwise
+	DefaultSearchPageSize = 20
+)
+
+// SearchUseCaseImpl implements the search use cases
+type SearchUseCaseImpl struct {
+	userRepo   repoinf.UserRepository
+	searchRepo repoinf.MangaSearchRepository
+	access     *mangaAccess
+	// searchIndex is the embedded index when it is the search backend, nil otherwise
+	searchIndex repoinf.MangaSearchIndex
+	indexPath   string
+}
+
+// NewSearchUseCase creates a new instance of SearchUseCaseImpl. searchIndex is nil unless the embedded index
+// is the search backend, in which case its snapshots are saved to indexPath
+func NewSearchUseCase(
+	userRepo repoinf.UserRepository,
+	groupRepo repoinf.GroupRepository,
+	searchRepo repoinf.MangaSearchRepository,
+	imageURLService serviceinf.ImageURLService,
+	searchIndex repoinf.MangaSearchIndex,
+	indexPath string,
+) usecaseinf.SearchUseCase {
+	return &SearchUseCaseImpl{
+		userRepo:    userRepo,
+		searchRepo:  searchRepo,
+		access:      newMangaAccess(userRepo, groupRepo, imageURLService),
+		searchIndex: searchIndex,
+		indexPath:   indexPath,
+	}
+}
+
+// SearchMangas returns a page of mangas matching a full-text query, best matches first unless sorted by recency.
+// Titles, localized titles included, and descriptions are matched unless the request limits the search to titles.
+// Mangas are titled in the languages best matching the preferred ones, and covers left out for a user who may not
+// see them
+func (uc *SearchUseCaseImpl) SearchMangas(userID string, req *request.SearchMangasRequest, languages []string) (*dto.MangaSearchResponse, error) {
+	mode := req.Mode
+	if mode == "" {
+		mode = entities.MangaSearchModeNatural
+	}
+	text := req.Query
+	if mode == entities.MangaSearchModeBoolean {
+		text = normalizeBooleanQuery(text)
+	}
+	terms := searchTerms(text, mode == entities.MangaSearchModeBoolean)
+	if len(terms) == 0 {
+		return nil, usecaseinf.ErrInvalidSearchQuery
+	}
+	if uc.searchIndex != nil {
+		// Highlight what the embedded index matched: words with typos and, while typing, the start of the last word
+		for i := range terms {
+			terms[i].typos = utils.TypoTolerance(terms[i].word)
+		}
+		if mode == entities.MangaSearchModeNatural && !strings.HasSuffix(text, " ") {
+			terms[len(terms)-1].prefix = true
+		}
+	}
+
+	sort := req.Sort
+	if sort == "" {
+		sort = entities.MangaSearchSortRelevance
+	}
+	page := max(req.Page, 1)
+	limit := req.Limit
+	if limit == 0 {
+		limit = DefaultSearchPageSize
+	}
+
+	query := &entities.MangaSearchQuery{
+		Text:            text,
+		Mode:            mode,
+		WithDescription: req.In != "title",
+		StatusID:        req.StatusID,
+		CategoryID:      req.CategoryID,
+		AuthorID:        req.AuthorID,
+		GroupID:         req.GroupID,
+		Sort:            sort,
+		Offset:          (page - 1) * limit,
+		Limit:           limit,
+	}
+	found, err := uc.searchRepo.Search(query)
+	if err != nil {
+		return nil, err
+	}
+
+	hits := found.Hits
+	result := &dto.MangaSearchResponse{
+		Items: make([]dto.MangaSearchHitResponse, 0, len(hits)),
+		Total: found.Total,
+		Page:  page,
+		Limit: limit,
+		Facets: dto.MangaSearchFacetsResponse{
+			Statuses:   toSearchFacetResponses(found.Facets.Statuses),
+			Categories: toSearchFacetResponses(found.Facets.Categories),
+			Authors:    toSearchFacetResponses(found.Facets.Authors),
+			Groups:     toSearchFacetResponses(found.Facets.Groups),
+		},
+	}
+	for i := range hits {
+		manga := &hits[i].Manga
+		mangaResponse, err := toMangaResponse(manga, languages, uc.access, userID)
+		if err != nil {
+			return nil, err
+		}
+		item := dto.MangaSearchHitResponse{
+			MangaResponse: *mangaResponse,
+			Score:         hits[i].Score,
+		}
+		item.Highlights.Title = highlightTerms(item.Title, terms)
+		if !containsTerm(item.Title, terms) {
+			// The manga was found by another of its titles, show which one
+			for _, title := range append([]string{manga.Title}, mangaTitles(manga)...) {
+				if title != item.Title && containsTerm(title, terms) {
+					altTitle := highlightTerms(title, terms)
+					item.Highlights.AltTitle = &altTitle
+					break
+				}
+			}
+		}
+		if query.WithDescription && item.Description != nil {
+			item.Highlights.Description = descriptionSnippet(*item.Description, terms)
+		}
+		result.Items = append(result.Items, item)
+	}
+	return result, nil
+}
+
+// RebuildIndex reindexes every manga in the embedded search index and saves a snapshot of it
+func (uc *SearchUseCaseImpl) RebuildIndex(userID string) (*dto.SearchIndexResponse, error) {
+	user, err := uc.userRepo.GetByID(userID)
+	if err != nil {
+		return nil, err
+	}
+	if !user.IsAdmin() {
+		return nil, usecaseinf.ErrModeratorOnly
+	}
+	if uc.searchIndex == nil {
+		return nil, usecaseinf.ErrSearchIndexDisabled
+	}
+
+	stats, err := uc.searchIndex.Rebuild()
+	if err != nil {
+		return nil, err
+	}
+	// The rebuilt index is already serving searches, a snapshot that can't be saved only slows the next start down
+	if uc.indexPath != "" {
+		_ = uc.searchIndex.Save(uc.indexPath)
+	}
+
+	return &dto.SearchIndexResponse{
+		Documents: stats.Documents,
+		Terms:     stats.Terms,
+		SyncedAt:  stats.SyncedAt,
+	}, nil
+}
+
+// mangaTitles returns the localized titles of a manga
+func mangaTitles(manga *entities.Manga) []string {
+	titles := make([]string, 0, len(manga.Titles))
+	for _, title := range manga.Titles {
+		titles = append(titles, title.Title)
+	}
+	return titles
+}
+
+// toSearchFacetResponses converts facet values to their response representation
+func toSearchFacetResponses(facets []entities.MangaSearchFacet) []dto.SearchFacetResponse {
+	result := make([]dto.SearchFacetResponse, 0, len(facets))
+	for _, facet := range facets {
+		result = append(result, dto.SearchFacetResponse{Value: facet.Value, Name: facet.Name, Count: facet.Count})
+	}
+	return result
+}
@@ -0,0 +1,189 @@
+package usecase
+
+import (
+	"html"
+	"strings"
+	"unicode"
+
+	"hotaku-api/utils"
+)
+
+const (
+	// snippetRadius is how many characters of the description are kept on each side of the first match
+	snippetRadius = 80
+	// booleanOperators are the operators that may prefix a word in a boolean search
+	booleanOperators = "+-~<>"
+)
+
+// searchTerm is a folded word of a search query. Prefix terms also match the words they start, and words
+// with up to typos mistakes match as well
+type searchTerm struct {
+	word   string
+	prefix bool
+	typos  int
+}
+
+// isWordRune reports whether r is part of a word the way the full-text parser splits them
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_'
+}
+
+// normalizeBooleanQuery rewrites a boolean query into one InnoDB accepts: a word keeps its first operator
+// only, lone operators are dropped, and unbalanced quotes or parentheses are removed
+func normalizeBooleanQuery(text string) string {
+	fields := strings.Fields(text)
+	kept := fields[:0]
+	for _, field := range fields {
+		word := strings.TrimLeft(field, booleanOperators)
+		if word != field {
+			word = field[:1] + word
+		}
+		if strings.HasSuffix(word, "*") {
+			word = strings.TrimRight(word, "*") + "*"
+		}
+		if strings.Trim(word, booleanOperators+"*") == "" {
+			continue
+		}
+		kept = append(kept, word)
+	}
+	result := strings.Join(kept, " ")
+
+	if strings.Count(result, `"`)%2 == 1 {
+		last := strings.LastIndex(result, `"`)
+		result = result[:last] + result[last+1:]
+	}
+	depth, balanced := 0, true
+	for _, r := range result {
+		switch r {
+		case '(':
+			depth++
+		case ')':
+			depth--
+		}
+		balanced = balanced && depth >= 0
+	}
+	if !balanced || depth != 0 {
+		result = strings.NewReplacer("(", " ", ")", " ").Replace(result)
+	}
+	return strings.TrimSpace(result)
+}
+
+// searchTerms returns the distinct words a query looks for, leaving out the excluded words of a boolean query
+func searchTerms(text string, boolean bool) []searchTerm {
+	var terms []searchTerm
+	seen := make(map[string]bool)
+	runes := []rune(utils.FoldText(text))
+	excluded, quoted := false, false
+
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		if !isWordRune(r) {
+			switch {
+			case boolean && r == '-' && (i == 0 || !isWordRune(runes[i-1])):
+				excluded = true
+			case boolean && r == '"':
+				quoted = !quoted
+			case unicode.IsSpace(r) && !quoted:
+				excluded = false
+			}
+			i++
+			continue
+		}
+
+		start := i
+		for i < len(runes) && isWordRune(runes[i]) {
+			i++
+		}
+		word := string(runes[start:i])
+		prefix := boolean && i < len(runes) && runes[i] == '*'
+		if excluded || seen[word] {
+			continue
+		}
+		seen[word] = true
+		terms = append(terms, searchTerm{word: word, prefix: prefix})
+	}
+	return terms
+}
+
+// matchesTerm reports whether a word is one of the terms, starts with a prefix term or is a term with typos
+func matchesTerm(word string, terms []searchTerm) bool {
+	word = utils.FoldText(word)
+	for _, term := range terms {
+		if word == term.word || (term.prefix && strings.HasPrefix(word, term.word)) {
+			return true
+		}
+		if term.typos > 0 && utils.EditDistance(word, term.word, term.typos) <= term.typos {
+			return true
+		}
+	}
+	return false
+}
+
+// containsTerm reports whether any word of text matches a term
+func containsTerm(text string, terms []searchTerm) bool {
+	for _, word := range strings.FieldsFunc(text, func(r rune) bool { return !isWordRune(r) }) {
+		if matchesTerm(word, terms) {
+			return true
+		}
+	}
+	return false
+}
+
+// highlightTerms HTML escapes text and wraps the words matching the terms in <mark> tags
+func highlightTerms(text string, terms []searchTerm) string {
+	var b strings.Builder
+	runes := []rune(text)
+	for i := 0; i < len(runes); {
+		start := i
+		word := isWordRune(runes[i])
+		for i < len(runes) && isWordRune(runes[i]) == word {
+			i++
+		}
+		segment := string(runes[start:i])
+		if word && matchesTerm(segment, terms) {
+			b.WriteString("<mark>" + html.EscapeString(segment) + "</mark>")
+		} else {
+			b.WriteString(html.EscapeString(segment))
+		}
+	}
+	return b.String()
+}
+
+// descriptionSnippet returns a highlighted excerpt of a description around its first matching word,
+// or nil when no word matches
+func descriptionSnippet(text string, terms []searchTerm) *string {
+	runes := []rune(text)
+	match := -1
+	for i := 0; i < len(runes) && match < 0; {
+		start := i
+		word := isWordRune(runes[i])
+		for i < len(runes) && isWordRune(runes[i]) == word {
+			i++
+		}
+		if word && matchesTerm(string(runes[start:i]), terms) {
+			match = start
+		}
+	}
+	if match < 0 {
+		return nil
+	}
+
+	// Widen the excerpt without cutting words in half
+	from := max(match-snippetRadius, 0)
+	for from > 0 && from < match && isWordRune(runes[from-1]) {
+		from++
+	}
+	to := min(match+snippetRadius, len(runes))
+	for to < len(runes) && to > match && isWordRune(runes[to]) {
+		to--
+	}
+
+	snippet := highlightTerms(strings.Join(strings.Fields(string(runes[from:to])), " "), terms)
+	if from > 0 {
+		snippet = "…" + snippet
+	}
+	if to < len(runes) {
+		snippet += "…"
+	}
+	return &snippet
+}
@@ -0,0 +1,267 @@
+package usecase
+
+import (
+	"path"
+	"strings"
+	"time"
+
+	"hotaku-api/internal/domain/dto"
+	"hotaku-api/internal/domain/entities"
+	"hotaku-api/internal/repoinf"
+	"hotaku-api/internal/serviceinf"
+	"hotaku-api/internal/usecaseinf"
+
+	"github.com/minio/minio-go/v7"
+)
+
+const (
+	// gcBatchSize is how many page objects are checked against the database per query
+	gcBatchSize = 500
+)
+
+// StorageGCUseCaseImpl implements the storage garbage collection use cases
+type StorageGCUseCaseImpl struct {
+	chapterRepo    repoinf.ChapterRepository
+	pageRepo       repoinf.ChapterPageRepository
+	coverRepo      repoinf.MangaCoverRepository
+	objectRepo     repoinf.StorageObjectRepository
+	watermarkRepo  repoinf.GroupWatermarkRepository
+	storageService serviceinf.StorageService
+}
+
+// NewStorageGCUseCase creates a new instance of StorageGCUseCaseImpl
+func NewStorageGCUseCase(
+	chapterRepo repoinf.ChapterRepository,
+	pageRepo repoinf.ChapterPageRepository,
+	coverRepo repoinf.MangaCoverRepository,
+	objectRepo repoinf.StorageObjectRepository,
+	watermarkRepo repoinf.GroupWatermarkRepository,
+	storageService serviceinf.StorageService,
+) usecaseinf.StorageGCUseCase {
+	return &StorageGCUseCaseImpl{
+		chapterRepo:    chapterRepo,
+		pageRepo:       pageRepo,
+		coverRepo:      coverRepo,
+		objectRepo:     objectRepo,
+		watermarkRepo:  watermarkRepo,
+		storageService: storageService,
+	}
+}
+
+// CollectGarbage finds objects older than gracePeriod that nothing references and deletes them unless dryRun is set
+func (uc *StorageGCUseCaseImpl) CollectGarbage(dryRun bool, gracePeriod time.Duration) (*dto.GarbageCollectionReport, error) {
+	// Anything newer may belong to an upload that hasn't been recorded yet
+	cutoff := time.Now().Add(-gracePeriod)
+
+	report := &dto.GarbageCollectionReport{
+		DryRun:  dryRun,
+		Orphans: []dto.OrphanObject{},
+		Failed:  []dto.FileErrorResponse{},
+	}
+
+	referenced, err := uc.referencedPageObjects()
+	if err != nil {
+		return nil, err
+	}
+	variants, err := uc.currentVariantPrefixes()
+	if err != nil {
+		return nil, err
+	}
+
+	var pages []minio.ObjectInfo
+	covers := make(map[string][]minio.ObjectInfo)
+
+	err = uc.storageService.WalkObjects("", func(obj minio.ObjectInfo) error {
+		report.Scanned++
+
+		parts := strings.Split(obj.Key, "/")
+		switch {
+		case parts[0] == "uploads":
+			// Staged uploads are only kept until their upload is finalized or expires
+			if obj.LastModified.Before(cutoff) {
+				report.Orphans = append(report.Orphans, toOrphanObject(obj))
+			}
+		case parts[0] == "manga" && len(parts) == 7 && parts[2] == "chapters" && parts[4] == "watermarked":
+			// Variants are named after the secret variant key rather than their page, so they are kept as long as
+			// their chapter is watermarked with the same version of the watermark
+			if obj.LastModified.Before(cutoff) && !isCurrentVariant(obj.Key, variants) {
+				report.Orphans = append(report.Orphans, toOrphanObject(obj))
+			}
+		case parts[0] == "manga" && len(parts) == 5 && parts[2] == "chapters":
+			if obj.LastModified.Before(cutoff) {
+				pages = append(pages, obj)
+			}
+			if len(pages) >= gcBatchSize {
+				if err := uc.collectPageOrphans(pages, referenced, report); err != nil {
+					return err
+				}
+				pages = pages[:0]
+			}
+		case parts[0] == "manga" && len(parts) == 3:
+			covers[parts[1]] = append(covers[parts[1]], obj)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := uc.collectPageOrphans(pages, referenced, report); err != nil {
+		return nil, err
+	}
+
+	for mangaID, objects := range covers {
+		if err := uc.collectCoverOrphans(mangaID, objects, cutoff, report); err != nil {
+			return nil, err
+		}
+	}
+
+	if dryRun {
+		return report, nil
+	}
+
+	// A page uploaded during the scan may have reused an object already reported, and a chapter may have been
+	// watermarked again with the same watermark, so references are read again
+	referenced, err = uc.referencedPageObjects()
+	if err != nil {
+		return nil, err
+	}
+	variants, err = uc.currentVariantPrefixes()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, orphan := range report.Orphans {
+		if referenced[orphan.ObjectName] || isCurrentVariant(orphan.ObjectName, variants) {
+			continue
+		}
+		if err := uc.storageService.DeleteFile(orphan.ObjectName); err != nil {
+			report.Failed = append(report.Failed, dto.FileErrorResponse{Filename: orphan.ObjectName, Error: err.Error()})
+			continue
+		}
+		_ = uc.objectRepo.Delete(orphan.ObjectName)
+		report.Deleted++
+		report.FreedBytes += orphan.Size
+	}
+
+	return report, nil
+}
+
+// referencedPageObjects returns the objects that at least one chapter page points to. Pages are compared by object
+// rather than URL so that objects stored under another base URL are still recognized
+func (uc *StorageGCUseCaseImpl) referencedPageObjects() (map[string]bool, error) {
+	referenced := make(map[string]bool)
+	afterID := ""
+	for {
+		pages, err := uc.pageRepo.ListImageURLsAfter(afterID, gcBatchSize)
+		if err != nil {
+			return nil, err
+		}
+		for _, page := range pages {
+			if objectName, err := uc.storageService.ObjectNameFromURL(page.ImageURL); err == nil {
+				referenced[objectName] = true
+			}
+		}
+		if len(pages) < gcBatchSize {
+			return referenced, nil
+		}
+		afterID = pages[len(pages)-1].PageID
+	}
+}
+
+// currentVariantPrefixes returns the storage prefixes holding the variants rendered with the current version of
+// the watermark of each watermarked chapter. A chapter whose watermark can't be read keeps all of its variants
+func (uc *StorageGCUseCaseImpl) currentVariantPrefixes() (map[string]bool, error) {
+	chapters, err := uc.chapterRepo.ListWatermarked()
+	if err != nil {
+		return nil, err
+	}
+
+	prefixes := make(map[string]bool, len(chapters))
+	watermarks := make(map[string]*entities.GroupWatermark)
+	for _, chapter := range chapters {
+		groupID := *chapter.WatermarkGroupID
+		watermark, ok := watermarks[groupID]
+		if !ok {
+			watermark, _ = uc.watermarkRepo.GetByGroupID(groupID)
+			watermarks[groupID] = watermark
+		}
+		if watermark == nil {
+			prefixes[watermarkPrefix(chapter.MangaID, chapter.ChapterID)] = true
+			continue
+		}
+		prefixes[variantPrefix(chapter.MangaID, chapter.ChapterID, watermark)] = true
+	}
+	return prefixes, nil
+}
+
+// collectPageOrphans adds the page objects that no chapter page points to to the report.
+// Chapters with no recorded pages predate page tracking, so their objects are left alone
+func (uc *StorageGCUseCaseImpl) collectPageOrphans(objects []minio.ObjectInfo, referenced map[string]bool, report *dto.GarbageCollectionReport) error {
+	if len(objects) == 0 {
+		return nil
+	}
+
+	chapterIDs := make([]string, 0, len(objects))
+	for _, obj := range objects {
+		chapterIDs = append(chapterIDs, strings.Split(obj.Key, "/")[3])
+	}
+
+	pageCounts, err := uc.pageRepo.CountPagesByChapter(chapterIDs)
+	if err != nil {
+		return err
+	}
+
+	for i, obj := range objects {
+		if referenced[obj.Key] {
+			continue
+		}
+		// A deleted chapter has no entry at all, a legacy one has zero recorded pages
+		if count, ok := pageCounts[chapterIDs[i]]; ok && count == 0 {
+			continue
+		}
+		report.Orphans = append(report.Orphans, toOrphanObject(obj))
+	}
+	return nil
+}
+
+// collectCoverOrphans adds the cover images of a manga that no cover entry points to to the report.
+// Mangas without cover entries predate cover tracking, so their images are left alone
+func (uc *StorageGCUseCaseImpl) collectCoverOrphans(mangaID string, objects []minio.ObjectInfo, cutoff time.Time, report *dto.GarbageCollectionReport) error {
+	covers, err := uc.coverRepo.ListByManga(mangaID)
+	if err != nil {
+		return err
+	}
+	if len(covers) == 0 {
+		return nil
+	}
+
+	referenced := make(map[string]bool, len(covers))
+	for _, cover := range covers {
+		if objectName, err := uc.storageService.ObjectNameFromURL(cover.ImageURL); err == nil {
+			referenced[objectName] = true
+		}
+	}
+
+	for _, obj := range objects {
+		if obj.LastModified.Before(cutoff) && !referenced[obj.Key] {
+			report.Orphans = append(report.Orphans, toOrphanObject(obj))
+		}
+	}
+	return nil
+}
+
+// isCurrentVariant reports whether an object is a watermarked variant rendered with the current version of its
+// chapter's watermark, given the prefixes returned by currentVariantPrefixes
+func isCurrentVariant(objectName string, prefixes map[string]bool) bool {
+	variantDir := path.Dir(objectName) + "/"
+	return prefixes[variantDir] || prefixes[path.Dir(path.Dir(objectName))+"/"]
+}
+
+// toOrphanObject converts an object listing entry to its report representation
+func toOrphanObject(obj minio.ObjectInfo) dto.OrphanObject {
+	return dto.OrphanObject{
+		ObjectName:   obj.Key,
+		Size:         obj.Size,
+		LastModified: obj.LastModified,
+	}
+}
@@ -0,0 +1,197 @@
+package usecase
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"hotaku-api/internal/domain/entities"
+	"hotaku-api/internal/repoinf"
+	"hotaku-api/internal/serviceinf"
+
+	"github.com/minio/minio-go/v7"
+)
+
+// gcTestBucketURL is the base URL page images are stored under in the storage GC tests
+const gcTestBucketURL = "http://storage/bucket/"
+
+// gcTestStorage holds objects in memory, implementing what the storage GC uses of the storage service
+type gcTestStorage struct {
+	serviceinf.StorageService
+	objects map[string]time.Time
+}
+
+func (s *gcTestStorage) WalkObjects(prefix string, fn func(minio.ObjectInfo) error) error {
+	keys := make([]string, 0, len(s.objects))
+	for key := range s.objects {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+	for _, key := range keys {
+		if err := fn(minio.ObjectInfo{Key: key, Size: 1, LastModified: s.objects[key]}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *gcTestStorage) ObjectNameFromURL(fileURL string) (string, error) {
+	if !strings.HasPrefix(fileURL, gcTestBucketURL) {
+		return "", fmt.Errorf("URL does not belong to the bucket")
+	}
+	return strings.TrimPrefix(fileURL, gcTestBucketURL), nil
+}
+
+func (s *gcTestStorage) DeleteFile(objectName string) error {
+	delete(s.objects, objectName)
+	return nil
+}
+
+// gcTestPages serves the recorded chapter pages
+type gcTestPages struct {
+	repoinf.ChapterPageRepository
+	pages []entities.ChapterPage
+}
+
+func (r *gcTestPages) ListImageURLsAfter(afterID string, limit int) ([]entities.ChapterPage, error) {
+	var pages []entities.ChapterPage
+	for _, page := range r.pages {
+		if page.PageID > afterID && len(pages) < limit {
+			pages = append(pages, page)
+		}
+	}
+	return pages, nil
+}
+
+func (r *gcTestPages) CountPagesByChapter(chapterIDs []string) (map[string]int64, error) {
+	counts := make(map[string]int64)
+	for _, page := range r.pages {
+		if slices.Contains(chapterIDs, page.ChapterID) {
+			counts[page.ChapterID]++
+		}
+	}
+	return counts, nil
+}
+
+// gcTestChapters serves the watermarked chapters
+type gcTestChapters struct {
+	repoinf.ChapterRepository
+	watermarked []entities.MangaChapter
+}
+
+func (r *gcTestChapters) ListWatermarked() ([]entities.MangaChapter, error) {
+	return r.watermarked, nil
+}
+
+// gcTestWatermarks serves the group watermarks
+type gcTestWatermarks struct {
+	repoinf.GroupWatermarkRepository
+	watermarks map[string]*entities.GroupWatermark
+}
+
+func (r *gcTestWatermarks) GetByGroupID(groupID string) (*entities.GroupWatermark, error) {
+	if watermark, ok := r.watermarks[groupID]; ok {
+		return watermark, nil
+	}
+	return nil, fmt.Errorf("group watermark not found")
+}
+
+// gcTestObjects ignores storage object records
+type gcTestObjects struct {
+	repoinf.StorageObjectRepository
+}
+
+func (r *gcTestObjects) Delete(objectName string) error {
+	return nil
+}
+
+func TestCollectGarbageKeepsCurrentWatermarkVariants(t *testing.T) {
+	const (
+		mangaID   = "m1"
+		chapterID = "c1"
+		groupID   = "0b7e8f4a-3c1d-4e2f-9a6b-5d4c3b2a1f0e"
+	)
+	watermark := &entities.GroupWatermark{GroupID: groupID, VariantKey: strings.Repeat("a", 32)}
+	stale := &entities.GroupWatermark{GroupID: groupID, VariantKey: strings.Repeat("b", 32)}
+
+	page := fmt.Sprintf("manga/%s/chapters/%s/page.jpg", mangaID, chapterID)
+	deletedPage := fmt.Sprintf("manga/%s/chapters/%s/deleted.jpg", mangaID, chapterID)
+	variant := variantObject(mangaID, chapterID, watermark, page)
+	staleVariant := variantObject(mangaID, chapterID, stale, page)
+	// Variants of a chapter whose watermark was turned off
+	unwatermarkedVariant := variantObject(mangaID, "c2", watermark, fmt.Sprintf("manga/%s/chapters/c2/page.jpg", mangaID))
+
+	old := time.Now().Add(-48 * time.Hour)
+	storage := &gcTestStorage{objects: map[string]time.Time{
+		page:                 old,
+		deletedPage:          old,
+		variant:              old,
+		staleVariant:         old,
+		unwatermarkedVariant: old,
+	}}
+	uc := NewStorageGCUseCase(
+		&gcTestChapters{watermarked: []entities.MangaChapter{
+			{ChapterID: chapterID, MangaID: mangaID, WatermarkGroupID: &watermark.GroupID},
+		}},
+		&gcTestPages{pages: []entities.ChapterPage{
+			{PageID: "p1", ChapterID: chapterID, ImageURL: gcTestBucketURL + page},
+		}},
+		nil,
+		&gcTestObjects{},
+		&gcTestWatermarks{watermarks: map[string]*entities.GroupWatermark{groupID: watermark}},
+		storage,
+	)
+
+	report, err := uc.CollectGarbage(false, 24*time.Hour)
+	if err != nil {
+		t.Fatalf("CollectGarbage() error = %v", err)
+	}
+
+	var orphans []string
+	for _, orphan := range report.Orphans {
+		orphans = append(orphans, orphan.ObjectName)
+	}
+	slices.Sort(orphans)
+	want := []string{deletedPage, staleVariant, unwatermarkedVariant}
+	slices.Sort(want)
+	if !slices.Equal(orphans, want) {
+		t.Fatalf("orphans = %v, want %v", orphans, want)
+	}
+	if report.Deleted != len(want) {
+		t.Fatalf("deleted %d objects, want %d", report.Deleted, len(want))
+	}
+	for _, kept := range []string{page, variant} {
+		if _, ok := storage.objects[kept]; !ok {
+			t.Errorf("%s was deleted", kept)
+		}
+	}
+}
+
+func TestCollectGarbageKeepsVariantsOfUnreadableWatermarks(t *testing.T) {
+	const mangaID, chapterID, groupID = "m1", "c1", "g1"
+	watermark := &entities.GroupWatermark{GroupID: groupID, VariantKey: strings.Repeat("a", 32)}
+	variant := variantObject(mangaID, chapterID, watermark, fmt.Sprintf("manga/%s/chapters/%s/page.jpg", mangaID, chapterID))
+
+	storage := &gcTestStorage{objects: map[string]time.Time{variant: time.Now().Add(-48 * time.Hour)}}
+	groupIDCopy := groupID
+	uc := NewStorageGCUseCase(
+		&gcTestChapters{watermarked: []entities.MangaChapter{
+			{ChapterID: chapterID, MangaID: mangaID, WatermarkGroupID: &groupIDCopy},
+		}},
+		&gcTestPages{},
+		nil,
+		&gcTestObjects{},
+		&gcTestWatermarks{},
+		storage,
+	)
+
+	report, err := uc.CollectGarbage(false, 24*time.Hour)
+	if err != nil {
+		t.Fatalf("CollectGarbage() error = %v", err)
+	}
+	if len(report.Orphans) != 0 {
+		t.Fatalf("orphans = %v, want none", report.Orphans)
+	}
+}
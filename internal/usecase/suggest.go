@@ -0,0 +1,340 @@
+package usecase
+
+import (
+	"container/list"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"hotaku-api/internal/domain/dto"
+	"hotaku-api/internal/domain/entities"
+	"hotaku-api/internal/domain/request"
+	"hotaku-api/internal/repoinf"
+	"hotaku-api/internal/usecaseinf"
+	"hotaku-api/utils"
+)
+
+const (
+	// DefaultSuggestionLimit is how many suggestions are returned unless the request asks otherwise
+	DefaultSuggestionLimit = 10
+	// suggestionCacheSize is how many distinct queries have their suggestions cached
+	suggestionCacheSize = 1024
+	// suggestionMaxAge is how long suggestions are kept while nothing they are built from changes,
+	// so new readers still move popularity
+	suggestionMaxAge = time.Hour
+)
+
+// suggestionKey is a word of a suggestion's name followed by the rest of the name, so typing any
+// word of a name finds it
+type suggestionKey struct {
+	key   string
+	entry int
+}
+
+// suggestionIndex is a prefix index over every suggestion. It is never modified once built, a rebuild
+// replaces it along with its cache
+type suggestionIndex struct {
+	// entries are sorted by popularity, so a lower index ranks higher
+	entries     []dto.SuggestionResponse
+	keys        []suggestionKey
+	fingerprint string
+	builtAt     time.Time
+	cache       *suggestionCache
+}
+
+// SuggestUseCaseImpl implements the search suggestion use cases
+type SuggestUseCaseImpl struct {
+	suggestionRepo repoinf.SuggestionRepository
+
+	mu    sync.RWMutex
+	index *suggestionIndex
+	// buildMu keeps a single rebuild running at a time
+	buildMu sync.Mutex
+	// pending records an invalidation not applied yet, refreshing whether a background rebuild is running
+	pending    atomic.Bool
+	refreshing atomic.Bool
+}
+
+// NewSuggestUseCase creates a new instance of SuggestUseCaseImpl. Suggestions are built on first use
+func NewSuggestUseCase(suggestionRepo repoinf.SuggestionRepository) usecaseinf.SuggestUseCase {
+	return &SuggestUseCaseImpl{
+		suggestionRepo: suggestionRepo,
+	}
+}
+
+// Suggest returns the mangas, authors, groups and categories with a word starting with the query, most popular first
+func (uc *SuggestUseCaseImpl) Suggest(req *request.SuggestRequest) ([]dto.SuggestionResponse, error) {
+	types, err := parseSuggestionTypes(req.Types)
+	if err != nil {
+		return nil, err
+	}
+	limit := req.Limit
+	if limit == 0 {
+		limit = DefaultSuggestionLimit
+	}
+	prefix := strings.Join(utils.Tokenize(req.Query), " ")
+	if prefix == "" {
+		return []dto.SuggestionResponse{}, nil
+	}
+
+	index, err := uc.currentIndex()
+	if err != nil {
+		return nil, err
+	}
+
+	cacheKey := prefix + "\x00" + req.Types + "\x00" + strconv.Itoa(limit)
+	if suggestions, ok := index.cache.get(cacheKey); ok {
+		return suggestions, nil
+	}
+	suggestions := index.lookup(prefix, types, limit)
+	index.cache.put(cacheKey, suggestions)
+	return suggestions, nil
+}
+
+// Invalidate drops the cached suggestions and rebuilds them in the background, for writes that change them.
+// Invalidations arriving during a rebuild are applied by another one right after it
+func (uc *SuggestUseCaseImpl) Invalidate() {
+	uc.pending.Store(true)
+	if !uc.refreshing.CompareAndSwap(false, true) {
+		return
+	}
+
+	go func() {
+		for uc.pending.Swap(false) {
+			_ = uc.rebuild()
+		}
+		uc.refreshing.Store(false)
+		// An invalidation may have come in after the last check, while still marked as refreshing
+		if uc.pending.Load() {
+			uc.Invalidate()
+		}
+	}()
+}
+
+// Refresh rebuilds the suggestions when what they are built from changed, or once they are an hour old
+func (uc *SuggestUseCaseImpl) Refresh() error {
+	uc.mu.RLock()
+	index := uc.index
+	uc.mu.RUnlock()
+
+	if index != nil && time.Since(index.builtAt) < suggestionMaxAge {
+		fingerprint, err := uc.suggestionRepo.Fingerprint()
+		if err != nil {
+			return err
+		}
+		if fingerprint == index.fingerprint {
+			return nil
+		}
+	}
+	return uc.rebuild()
+}
+
+// currentIndex returns the prefix index, building it the first time it is needed
+func (uc *SuggestUseCaseImpl) currentIndex() (*suggestionIndex, error) {
+	uc.mu.RLock()
+	index := uc.index
+	uc.mu.RUnlock()
+	if index != nil {
+		return index, nil
+	}
+
+	if err := uc.rebuild(); err != nil {
+		return nil, err
+	}
+	uc.mu.RLock()
+	defer uc.mu.RUnlock()
+	return uc.index, nil
+}
+
+// rebuild loads everything suggestions are built from and swaps in a new prefix index
+func (uc *SuggestUseCaseImpl) rebuild() error {
+	uc.buildMu.Lock()
+	defer uc.buildMu.Unlock()
+
+	// Fingerprinting first means a change made while loading triggers another rebuild later
+	fingerprint, err := uc.suggestionRepo.Fingerprint()
+	if err != nil {
+		return err
+	}
+	source, err := uc.suggestionRepo.Load()
+	if err != nil {
+		return err
+	}
+
+	index := buildSuggestionIndex(source)
+	index.fingerprint = fingerprint
+
+	uc.mu.Lock()
+	uc.index = index
+	uc.mu.Unlock()
+	return nil
+}
+
+// buildSuggestionIndex ranks the suggestions by popularity and indexes every word of their names. A manga is
+// as popular as its readers plus its favorites, an author, group or category as all of its mangas together
+func buildSuggestionIndex(source *entities.SuggestionSource) *suggestionIndex {
+	index := &suggestionIndex{
+		builtAt: time.Now(),
+		cache:   newSuggestionCache(suggestionCacheSize),
+	}
+
+	popularity := make(map[string]int64, len(source.Mangas))
+	for _, manga := range source.Mangas {
+		popularity[manga.MangaID] = manga.Favorites*entities.MangaFavoritePopularity + manga.Readers
+		index.entries = append(index.entries, dto.SuggestionResponse{
+			Type:       entities.SuggestionTypeManga,
+			ID:         manga.MangaID,
+			Name:       manga.Title,
+			Popularity: popularity[manga.MangaID],
+		})
+	}
+	for _, refs := range []struct {
+		suggestionType string
+		refs           []entities.SuggestionRef
+	}{
+		{entities.SuggestionTypeAuthor, source.Authors},
+		{entities.SuggestionTypeGroup, source.Groups},
+		{entities.SuggestionTypeCategory, source.Categories},
+	} {
+		for _, ref := range refs.refs {
+			suggestion := dto.SuggestionResponse{
+				Type:       refs.suggestionType,
+				ID:         ref.ID,
+				Name:       ref.Name,
+				MangaCount: len(ref.MangaIDs),
+			}
+			for _, mangaID := range ref.MangaIDs {
+				suggestion.Popularity += popularity[mangaID]
+			}
+			index.entries = append(index.entries, suggestion)
+		}
+	}
+
+	sort.SliceStable(index.entries, func(i, j int) bool {
+		a, b := index.entries[i], index.entries[j]
+		if a.Popularity != b.Popularity {
+			return a.Popularity > b.Popularity
+		}
+		return a.Name < b.Name
+	})
+
+	altTitles := make(map[string][]string)
+	for _, manga := range source.Mangas {
+		altTitles[manga.MangaID] = manga.AltTitles
+	}
+	for i, entry := range index.entries {
+		names := []string{entry.Name}
+		if entry.Type == entities.SuggestionTypeManga {
+			// A manga is also found by its localized titles, still suggested under its default one
+			names = append(names, altTitles[entry.ID]...)
+		}
+		for _, name := range names {
+			words := utils.Tokenize(name)
+			for w := range words {
+				index.keys = append(index.keys, suggestionKey{key: strings.Join(words[w:], " "), entry: i})
+			}
+		}
+	}
+	sort.Slice(index.keys, func(i, j int) bool {
+		return index.keys[i].key < index.keys[j].key
+	})
+	return index
+}
+
+// lookup returns the most popular suggestions of the given types with a key starting with prefix
+func (index *suggestionIndex) lookup(prefix string, types map[string]bool, limit int) []dto.SuggestionResponse {
+	var matches []int
+	seen := make(map[int]bool)
+	for i := sort.Search(len(index.keys), func(i int) bool { return index.keys[i].key >= prefix }); i < len(index.keys); i++ {
+		key := index.keys[i]
+		if !strings.HasPrefix(key.key, prefix) {
+			break
+		}
+		if seen[key.entry] || (types != nil && !types[index.entries[key.entry].Type]) {
+			continue
+		}
+		seen[key.entry] = true
+		matches = append(matches, key.entry)
+	}
+	sort.Ints(matches)
+
+	suggestions := make([]dto.SuggestionResponse, 0, min(len(matches), limit))
+	for _, entry := range matches[:min(len(matches), limit)] {
+		suggestions = append(suggestions, index.entries[entry])
+	}
+	return suggestions
+}
+
+// parseSuggestionTypes parses a comma separated list of suggestion types, nil meaning every type
+func parseSuggestionTypes(list string) (map[string]bool, error) {
+	if strings.TrimSpace(list) == "" {
+		return nil, nil
+	}
+	types := make(map[string]bool)
+	for _, suggestionType := range strings.Split(list, ",") {
+		switch suggestionType = strings.TrimSpace(suggestionType); suggestionType {
+		case entities.SuggestionTypeManga, entities.SuggestionTypeAuthor, entities.SuggestionTypeGroup, entities.SuggestionTypeCategory:
+			types[suggestionType] = true
+		default:
+			return nil, usecaseinf.ErrInvalidSuggestionType
+		}
+	}
+	return types, nil
+}
+
+// suggestionCache keeps the suggestions of the most recently asked queries
+type suggestionCache struct {
+	mu      sync.Mutex
+	size    int
+	order   *list.List
+	entries map[string]*list.Element
+}
+
+// cachedSuggestions is an entry of a suggestion cache
+type cachedSuggestions struct {
+	key         string
+	suggestions []dto.SuggestionResponse
+}
+
+// newSuggestionCache creates an empty cache holding up to size queries
+func newSuggestionCache(size int) *suggestionCache {
+	return &suggestionCache{
+		size:    size,
+		order:   list.New(),
+		entries: make(map[string]*list.Element),
+	}
+}
+
+// get returns the cached suggestions of a query, if any
+func (c *suggestionCache) get(key string) ([]dto.SuggestionResponse, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	element, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	c.order.MoveToFront(element)
+	return element.Value.(*cachedSuggestions).suggestions, true
+}
+
+// put caches the suggestions of a query, evicting the least recently asked query when full
+func (c *suggestionCache) put(key string, suggestions []dto.SuggestionResponse) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if element, ok := c.entries[key]; ok {
+		element.Value.(*cachedSuggestions).suggestions = suggestions
+		c.order.MoveToFront(element)
+		return
+	}
+	c.entries[key] = c.order.PushFront(&cachedSuggestions{key: key, suggestions: suggestions})
+	if c.order.Len() > c.size {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*cachedSuggestions).key)
+	}
+}
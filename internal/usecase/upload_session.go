@@ -0,0 +1,351 @@
+package usecase
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"strings"
+	"time"
+
+	"hotaku-api/internal/domain/dto"
+	"hotaku-api/internal/domain/entities"
+	"hotaku-api/internal/domain/request"
+	"hotaku-api/internal/repoinf"
+	"hotaku-api/internal/serviceinf"
+	"hotaku-api/internal/usecaseinf"
+
+	"github.com/google/uuid"
+	"github.com/minio/minio-go/v7"
+)
+
+const (
+	// UploadSessionExpiry is how long a resumable upload may stay incomplete
+	UploadSessionExpiry = 24 * time.Hour
+	// MinChunkSize is the smallest chunk accepted unless it is the last one (storage multipart minimum)
+	MinChunkSize = 5 * 1024 * 1024 // 5MB
+	// MaxChunkSize is the largest chunk accepted in a single request
+	MaxChunkSize = 64 * 1024 * 1024 // 64MB
+	// expiredSessionSweepBatch is how many expired sessions are discarded per query
+	expiredSessionSweepBatch = 100
+)
+
+// UploadSessionUseCaseImpl implements the resumable upload use cases
+type UploadSessionUseCaseImpl struct {
+	sessionRepo        repoinf.UploadSessionRepository
+	chapterRepo        repoinf.ChapterRepository
+	chapterPageUseCase usecaseinf.ChapterPageUseCase
+	storageService     serviceinf.StorageService
+}
+
+// NewUploadSessionUseCase creates a new instance of UploadSessionUseCaseImpl
+func NewUploadSessionUseCase(
+	sessionRepo repoinf.UploadSessionRepository,
+	chapterRepo repoinf.ChapterRepository,
+	chapterPageUseCase usecaseinf.ChapterPageUseCase,
+	storageService serviceinf.StorageService,
+) usecaseinf.UploadSessionUseCase {
+	return &UploadSessionUseCaseImpl{
+		sessionRepo:        sessionRepo,
+		chapterRepo:        chapterRepo,
+		chapterPageUseCase: chapterPageUseCase,
+		storageService:     storageService,
+	}
+}
+
+// Create starts a resumable upload session for a chapter page or archive
+func (uc *UploadSessionUseCaseImpl) Create(userID string, req *request.CreateUploadSessionRequest) (*dto.UploadSessionResponse, error) {
+	ext := strings.ToLower(path.Ext(req.Filename))
+	switch req.Kind {
+	case entities.UploadSessionKindPage:
+		if err := uc.storageService.ValidateImage(req.Filename, req.ContentType, req.Size); err != nil {
+			return nil, err
+		}
+	case entities.UploadSessionKindArchive:
+		if ext != ".cbz" && ext != ".zip" {
+			return nil, fmt.Errorf("invalid file type. Only archive files (cbz, zip) are allowed")
+		}
+		if req.Size > usecaseinf.MaxArchiveSize {
+			return nil, fmt.Errorf("file size %d exceeds maximum allowed size %d", req.Size, usecaseinf.MaxArchiveSize)
+		}
+	default:
+		return nil, fmt.Errorf("unsupported upload kind: %s", req.Kind)
+	}
+
+	chapter, err := uc.chapterRepo.GetByID(req.ChapterID)
+	if err != nil {
+		return nil, err
+	}
+	if chapter.MangaID != req.MangaID {
+		return nil, fmt.Errorf("chapter does not belong to manga")
+	}
+
+	objectName := uc.storageService.StagingObjectName(userID, ext)
+	storageUploadID, err := uc.storageService.StartMultipartUpload(objectName, req.ContentType)
+	if err != nil {
+		return nil, err
+	}
+
+	session := &entities.UploadSession{
+		SessionID:       uuid.New().String(),
+		UserID:          userID,
+		Kind:            req.Kind,
+		MangaID:         req.MangaID,
+		ChapterID:       req.ChapterID,
+		Filename:        req.Filename,
+		ContentType:     req.ContentType,
+		TotalSize:       req.Size,
+		ObjectName:      objectName,
+		StorageUploadID: storageUploadID,
+		ExpiresAt:       time.Now().Add(UploadSessionExpiry),
+	}
+	if err := uc.sessionRepo.Create(session); err != nil {
+		_ = uc.storageService.AbortMultipartUpload(objectName, storageUploadID)
+		return nil, err
+	}
+
+	return toUploadSessionResponse(session), nil
+}
+
+// Get returns the current state of an upload session
+func (uc *UploadSessionUseCaseImpl) Get(userID, sessionID string) (*dto.UploadSessionResponse, error) {
+	session, err := uc.getOwnedSession(userID, sessionID)
+	if err != nil {
+		return nil, err
+	}
+	return toUploadSessionResponse(session), nil
+}
+
+// AppendChunk stores the next chunk of an upload starting at the given offset
+func (uc *UploadSessionUseCaseImpl) AppendChunk(userID, sessionID string, offset int64, chunk io.Reader, size int64) (*dto.UploadSessionResponse, error) {
+	session, err := uc.getActiveSession(userID, sessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	if offset != session.UploadOffset {
+		return nil, usecaseinf.ErrUploadOffsetMismatch
+	}
+	if size <= 0 || size > MaxChunkSize {
+		return nil, fmt.Errorf("chunk size must be between 1 and %d bytes", MaxChunkSize)
+	}
+	if offset+size > session.TotalSize {
+		return nil, fmt.Errorf("chunk exceeds declared upload size")
+	}
+	if size < MinChunkSize && offset+size != session.TotalSize {
+		return nil, fmt.Errorf("only the last chunk may be smaller than %d bytes", MinChunkSize)
+	}
+
+	// Part numbers follow the recorded parts, so a retried chunk overwrites its failed attempt
+	parts, err := uc.sessionRepo.ListParts(session.SessionID)
+	if err != nil {
+		return nil, err
+	}
+	partNumber := len(parts) + 1
+
+	etag, err := uc.storageService.UploadPart(session.ObjectName, session.StorageUploadID, partNumber, io.LimitReader(chunk, size), size)
+	if err != nil {
+		return nil, err
+	}
+
+	part := &entities.UploadSessionPart{
+		SessionID:  session.SessionID,
+		PartNumber: partNumber,
+		ETag:       etag,
+		Size:       size,
+	}
+	if err := uc.sessionRepo.AppendPart(session.SessionID, offset, part); err != nil {
+		// Another request may have advanced the offset while this chunk was uploading
+		if current, getErr := uc.sessionRepo.GetByID(session.SessionID); getErr == nil && current.UploadOffset != offset {
+			return nil, usecaseinf.ErrUploadOffsetMismatch
+		}
+		return nil, err
+	}
+
+	session.UploadOffset = offset + size
+	return toUploadSessionResponse(session), nil
+}
+
+// Finalize assembles the uploaded chunks and hands the file to the chapter page pipeline
+func (uc *UploadSessionUseCaseImpl) Finalize(userID, sessionID string) (*dto.FinalizeSessionResponse, error) {
+	session, err := uc.getActiveSession(userID, sessionID)
+	if err != nil {
+		return nil, err
+	}
+	if !session.IsComplete() {
+		return nil, fmt.Errorf("upload incomplete: received %d of %d bytes", session.UploadOffset, session.TotalSize)
+	}
+
+	parts, err := uc.sessionRepo.ListParts(session.SessionID)
+	if err != nil {
+		return nil, err
+	}
+	completeParts := make([]minio.CompletePart, 0, len(parts))
+	for _, part := range parts {
+		completeParts = append(completeParts, minio.CompletePart{
+			PartNumber: part.PartNumber,
+			ETag:       part.ETag,
+		})
+	}
+
+	// Claim the session so concurrent finalize calls can't both register it. It is only marked completed once
+	// registered, a failure releasing it so the client can retry
+	if err := uc.sessionRepo.Claim(session.SessionID, FinalizeClaimLease); err != nil {
+		return nil, err
+	}
+	result, err := uc.register(session, completeParts)
+	if err != nil {
+		_ = uc.sessionRepo.Release(session.SessionID)
+		return nil, err
+	}
+	if err := uc.sessionRepo.MarkCompleted(session.SessionID); err != nil {
+		return nil, err
+	}
+
+	_ = uc.storageService.DeleteFile(session.ObjectName)
+	return result, nil
+}
+
+// SweepExpired discards the sessions that expired before being finalized, along with their stored chunks
+func (uc *UploadSessionUseCaseImpl) SweepExpired() (int, error) {
+	swept := 0
+	for {
+		sessions, err := uc.sessionRepo.ListExpired(time.Now(), expiredSessionSweepBatch)
+		if err != nil {
+			return swept, err
+		}
+		for i := range sessions {
+			// A finalize call that failed may have assembled the chunks already
+			_ = uc.storageService.AbortMultipartUpload(sessions[i].ObjectName, sessions[i].StorageUploadID)
+			_ = uc.storageService.DeleteFile(sessions[i].ObjectName)
+			if err := uc.sessionRepo.Delete(sessions[i].SessionID); err != nil {
+				return swept, err
+			}
+			swept++
+		}
+		if len(sessions) < expiredSessionSweepBatch {
+			return swept, nil
+		}
+	}
+}
+
+// register assembles the chunks of a session, unless a failed finalize call already did, and hands the file to
+// the chapter page pipeline. The assembled object is kept on failure so finalizing can be retried
+func (uc *UploadSessionUseCaseImpl) register(session *entities.UploadSession, parts []minio.CompletePart) (*dto.FinalizeSessionResponse, error) {
+	if _, err := uc.storageService.StatObject(session.ObjectName); err != nil {
+		if err := uc.storageService.CompleteMultipartUpload(session.ObjectName, session.StorageUploadID, parts); err != nil {
+			return nil, err
+		}
+	}
+
+	result := &dto.FinalizeSessionResponse{Kind: session.Kind}
+	switch session.Kind {
+	case entities.UploadSessionKindPage:
+		if err := verifyStagedImage(uc.storageService, session.ObjectName, session.ContentType, session.TotalSize); err != nil {
+			// Retrying can't fix the content, so the session is discarded
+			_ = uc.storageService.DeleteFile(session.ObjectName)
+			_ = uc.sessionRepo.Delete(session.SessionID)
+			return nil, err
+		}
+		page, err := uc.chapterPageUseCase.AppendPageFromObject(session.UserID, session.MangaID, session.ChapterID, session.ObjectName, session.Filename, session.ContentType, session.TotalSize)
+		if err != nil {
+			return nil, err
+		}
+		result.Page = page
+	case entities.UploadSessionKindArchive:
+		archive, err := uc.importStagedArchive(session)
+		if err != nil {
+			return nil, err
+		}
+		result.Archive = archive
+	default:
+		return nil, fmt.Errorf("unsupported upload kind: %s", session.Kind)
+	}
+	return result, nil
+}
+
+// Cancel aborts an upload session and discards its stored chunks
+func (uc *UploadSessionUseCaseImpl) Cancel(userID, sessionID string) error {
+	session, err := uc.getOwnedSession(userID, sessionID)
+	if err != nil {
+		return err
+	}
+	if session.CompletedAt != nil {
+		return fmt.Errorf("upload session already completed")
+	}
+
+	if err := uc.storageService.AbortMultipartUpload(session.ObjectName, session.StorageUploadID); err != nil {
+		return err
+	}
+	return uc.sessionRepo.Delete(session.SessionID)
+}
+
+// importStagedArchive spools an assembled archive to disk and imports its pages
+func (uc *UploadSessionUseCaseImpl) importStagedArchive(session *entities.UploadSession) (*dto.ArchiveImportResponse, error) {
+	obj, err := uc.storageService.GetObject(session.ObjectName)
+	if err != nil {
+		return nil, err
+	}
+	defer obj.Close()
+
+	// ZIP needs random access to its central directory, so work from a local copy
+	tmpFile, err := os.CreateTemp("", "hotaku-archive-*.zip")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create temporary file: %w", err)
+	}
+	defer func() {
+		tmpFile.Close()
+		os.Remove(tmpFile.Name())
+	}()
+
+	size, err := io.Copy(tmpFile, obj)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read uploaded archive: %w", err)
+	}
+
+	return uc.chapterPageUseCase.ImportArchive(session.UserID, session.MangaID, session.ChapterID, tmpFile, size)
+}
+
+// getOwnedSession retrieves an upload session belonging to the user
+func (uc *UploadSessionUseCaseImpl) getOwnedSession(userID, sessionID string) (*entities.UploadSession, error) {
+	session, err := uc.sessionRepo.GetByID(sessionID)
+	if err != nil {
+		return nil, err
+	}
+	if session.UserID != userID {
+		return nil, fmt.Errorf("upload session not found")
+	}
+	return session, nil
+}
+
+// getActiveSession retrieves an upload session that can still receive data, cleaning up expired ones
+func (uc *UploadSessionUseCaseImpl) getActiveSession(userID, sessionID string) (*entities.UploadSession, error) {
+	session, err := uc.getOwnedSession(userID, sessionID)
+	if err != nil {
+		return nil, err
+	}
+	if session.CompletedAt != nil {
+		return nil, fmt.Errorf("upload session already completed")
+	}
+	if session.IsExpired() {
+		_ = uc.storageService.AbortMultipartUpload(session.ObjectName, session.StorageUploadID)
+		_ = uc.sessionRepo.Delete(session.SessionID)
+		return nil, fmt.Errorf("upload session has expired")
+	}
+	return session, nil
+}
+
+// toUploadSessionResponse converts an upload session to its response representation
+func toUploadSessionResponse(session *entities.UploadSession) *dto.UploadSessionResponse {
+	return &dto.UploadSessionResponse{
+		SessionID:    session.SessionID,
+		Kind:         session.Kind,
+		Filename:     session.Filename,
+		Size:         session.TotalSize,
+		Offset:       session.UploadOffset,
+		MinChunkSize: MinChunkSize,
+		MaxChunkSize: MaxChunkSize,
+		ExpiresAt:    session.ExpiresAt,
+		CompletedAt:  session.CompletedAt,
+	}
+}
@@ -0,0 +1,392 @@
+package usecase
+
+import (
+	"slices"
+	"strings"
+
+	"hotaku-api/internal/domain/dto"
+	"hotaku-api/internal/domain/entities"
+	"hotaku-api/internal/domain/request"
+	"hotaku-api/internal/repoinf"
+	"hotaku-api/internal/serviceinf"
+	"hotaku-api/internal/usecaseinf"
+
+	"github.com/google/uuid"
+)
+
+// VolumeUseCaseImpl implements the manga volume use cases
+type VolumeUseCaseImpl struct {
+	userRepo    repoinf.UserRepository
+	mangaRepo   repoinf.MangaRepository
+	volumeRepo  repoinf.MangaVolumeRepository
+	chapterRepo repoinf.ChapterRepository
+	coverRepo   repoinf.MangaCoverRepository
+	access      *mangaAccess
+}
+
+// NewVolumeUseCase creates a new instance of VolumeUseCaseImpl
+func NewVolumeUseCase(
+	userRepo repoinf.UserRepository,
+	mangaRepo repoinf.MangaRepository,
+	volumeRepo repoinf.MangaVolumeRepository,
+	chapterRepo repoinf.ChapterRepository,
+	coverRepo repoinf.MangaCoverRepository,
+	groupRepo repoinf.GroupRepository,
+	imageURLService serviceinf.ImageURLService,
+) usecaseinf.VolumeUseCase {
+	return &VolumeUseCaseImpl{
+		userRepo:    userRepo,
+		mangaRepo:   mangaRepo,
+		volumeRepo:  volumeRepo,
+		chapterRepo: chapterRepo,
+		coverRepo:   coverRepo,
+		access:      newMangaAccess(userRepo, groupRepo, imageURLService),
+	}
+}
+
+// ListVolumes returns all volumes of a manga by volume number, each with how many chapters it holds and its cover
+// unless the user may not see it
+func (uc *VolumeUseCaseImpl) ListVolumes(userID, mangaID string) ([]dto.MangaVolumeResponse, error) {
+	manga, err := uc.mangaRepo.GetByID(mangaID)
+	if err != nil {
+		return nil, usecaseinf.ErrMangaNotFound
+	}
+	showCovers, err := uc.access.canView(userID, manga)
+	if err != nil {
+		return nil, err
+	}
+
+	volumes, err := uc.volumeRepo.ListByManga(mangaID)
+	if err != nil {
+		return nil, err
+	}
+	chapters, err := uc.chapterRepo.ListByManga(mangaID)
+	if err != nil {
+		return nil, err
+	}
+	covers, err := uc.coverRepo.ListByManga(mangaID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]dto.MangaVolumeResponse, 0, len(volumes))
+	for i := range volumes {
+		result = append(result, *uc.toVolumeResponse(manga, &volumes[i], chapters, covers, showCovers))
+	}
+	return result, nil
+}
+
+// CreateVolume adds a volume to a manga
+func (uc *VolumeUseCaseImpl) CreateVolume(userID, mangaID string, req *request.CreateVolumeRequest) (*dto.MangaVolumeResponse, error) {
+	if err := uc.requireAdmin(userID); err != nil {
+		return nil, err
+	}
+	if _, err := uc.mangaRepo.GetByID(mangaID); err != nil {
+		return nil, usecaseinf.ErrMangaNotFound
+	}
+	if err := uc.checkNumber(mangaID, "", *req.VolumeNumber); err != nil {
+		return nil, err
+	}
+	if req.CoverID != nil {
+		if err := uc.checkCover(mangaID, *req.CoverID); err != nil {
+			return nil, err
+		}
+	}
+
+	volume := &entities.MangaVolume{
+		VolumeID:     uuid.New().String(),
+		MangaID:      mangaID,
+		VolumeNumber: *req.VolumeNumber,
+		Title:        trimmedOrNil(req.Title),
+		CoverID:      req.CoverID,
+	}
+	if err := uc.volumeRepo.Create(volume); err != nil {
+		return nil, err
+	}
+	return uc.getVolumeResponse(mangaID, volume.VolumeID)
+}
+
+// UpdateVolume changes the number, title or cover of a volume, an empty title or cover clearing it
+func (uc *VolumeUseCaseImpl) UpdateVolume(userID, mangaID, volumeID string, req *request.UpdateVolumeRequest) (*dto.MangaVolumeResponse, error) {
+	if err := uc.requireAdmin(userID); err != nil {
+		return nil, err
+	}
+	volume, err := uc.getVolume(mangaID, volumeID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.VolumeNumber != nil && *req.VolumeNumber != volume.VolumeNumber {
+		if err := uc.checkNumber(mangaID, volumeID, *req.VolumeNumber); err != nil {
+			return nil, err
+		}
+		volume.VolumeNumber = *req.VolumeNumber
+	}
+	if req.Title != nil {
+		volume.Title = trimmedOrNil(req.Title)
+	}
+	if req.CoverID != nil {
+		volume.CoverID = nil
+		if *req.CoverID != "" {
+			if err := uc.checkCover(mangaID, *req.CoverID); err != nil {
+				return nil, err
+			}
+			volume.CoverID = req.CoverID
+		}
+	}
+
+	if err := uc.volumeRepo.Update(volume); err != nil {
+		return nil, err
+	}
+	return uc.getVolumeResponse(mangaID, volumeID)
+}
+
+// DeleteVolume removes a volume, its chapters staying in the manga without a volume
+func (uc *VolumeUseCaseImpl) DeleteVolume(userID, mangaID, volumeID string) error {
+	if err := uc.requireAdmin(userID); err != nil {
+		return err
+	}
+	if _, err := uc.getVolume(mangaID, volumeID); err != nil {
+		return err
+	}
+	return uc.volumeRepo.Delete(volumeID)
+}
+
+// SetChapters replaces the chapters of a volume with the chapters listed by ID and every version of the chapters
+// numbered within the range, moving them out of any other volume
+func (uc *VolumeUseCaseImpl) SetChapters(userID, mangaID, volumeID string, req *request.SetVolumeChaptersRequest) (*dto.MangaVolumeResponse, error) {
+	if err := uc.requireAdmin(userID); err != nil {
+		return nil, err
+	}
+	if _, err := uc.getVolume(mangaID, volumeID); err != nil {
+		return nil, err
+	}
+	if req.From != nil && req.To != nil && *req.From > *req.To {
+		return nil, usecaseinf.ErrInvalidVolumeChapters
+	}
+
+	chapters, err := uc.chapterRepo.ListByManga(mangaID)
+	if err != nil {
+		return nil, err
+	}
+
+	var chapterIDs []string
+	for _, id := range req.ChapterIDs {
+		if !slices.ContainsFunc(chapters, func(chapter entities.MangaChapter) bool { return chapter.ChapterID == id }) {
+			return nil, usecaseinf.ErrInvalidVolumeChapters
+		}
+		if !slices.Contains(chapterIDs, id) {
+			chapterIDs = append(chapterIDs, id)
+		}
+	}
+	if req.From != nil || req.To != nil {
+		for _, chapter := range chapters {
+			if (req.From != nil && chapter.ChapterNumber < *req.From) || (req.To != nil && chapter.ChapterNumber > *req.To) {
+				continue
+			}
+			if !slices.Contains(chapterIDs, chapter.ChapterID) {
+				chapterIDs = append(chapterIDs, chapter.ChapterID)
+			}
+		}
+	}
+
+	if err := uc.volumeRepo.SetChapters(volumeID, chapterIDs); err != nil {
+		return nil, err
+	}
+	return uc.getVolumeResponse(mangaID, volumeID)
+}
+
+// GetProgress summarizes how much of each volume of a manga a user read, by volume number, followed by the
+// chapters not in any volume when there are some
+func (uc *VolumeUseCaseImpl) GetProgress(userID, mangaID string) ([]dto.VolumeProgressResponse, error) {
+	if _, err := uc.mangaRepo.GetByID(mangaID); err != nil {
+		return nil, usecaseinf.ErrMangaNotFound
+	}
+
+	volumes, err := uc.volumeRepo.ListByManga(mangaID)
+	if err != nil {
+		return nil, err
+	}
+	chapters, err := uc.chapterRepo.ListByManga(mangaID)
+	if err != nil {
+		return nil, err
+	}
+	reads, err := uc.chapterRepo.ListReadByManga(userID, mangaID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]dto.VolumeProgressResponse, 0, len(volumes)+1)
+	for i := range volumes {
+		progress := volumeProgress(chapters, reads, &volumes[i].VolumeID)
+		progress.VolumeID = &volumes[i].VolumeID
+		progress.VolumeNumber = &volumes[i].VolumeNumber
+		result = append(result, *progress)
+	}
+	if progress := volumeProgress(chapters, reads, nil); progress.ChapterCount > 0 {
+		result = append(result, *progress)
+	}
+	return result, nil
+}
+
+// volumeProgress summarizes the reads of the chapters of a volume, or of the chapters not in any volume when
+// volumeID is nil. Chapters are expected by chapter number, so the next chapter is the earliest unread one
+func volumeProgress(chapters []entities.MangaChapter, reads []entities.UserReadChapter, volumeID *string) *dto.VolumeProgressResponse {
+	result := &dto.VolumeProgressResponse{}
+	numbers := make(map[float64]bool)
+	for i := range chapters {
+		chapter := &chapters[i]
+		if !sameVolume(chapter.VolumeID, volumeID) {
+			continue
+		}
+		if _, ok := numbers[chapter.ChapterNumber]; !ok {
+			numbers[chapter.ChapterNumber] = false
+			result.ChapterCount++
+		}
+
+		index := slices.IndexFunc(reads, func(read entities.UserReadChapter) bool { return read.ChapterID == chapter.ChapterID })
+		if index < 0 {
+			continue
+		}
+		if !numbers[chapter.ChapterNumber] {
+			numbers[chapter.ChapterNumber] = true
+			result.ReadCount++
+		}
+		if result.LastReadAt == nil || reads[index].ReadAt.After(*result.LastReadAt) {
+			result.LastReadAt = &reads[index].ReadAt
+		}
+	}
+
+	for i := range chapters {
+		chapter := &chapters[i]
+		if read, ok := numbers[chapter.ChapterNumber]; ok && !read && sameVolume(chapter.VolumeID, volumeID) {
+			result.NextChapterID = &chapter.ChapterID
+			break
+		}
+	}
+	result.Completed = result.ChapterCount > 0 && result.ReadCount == result.ChapterCount
+	return result
+}
+
+// sameVolume reports whether two volume IDs are the same, nil standing for no volume
+func sameVolume(a, b *string) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return *a == *b
+}
+
+// requireAdmin fails unless the user is an admin
+func (uc *VolumeUseCaseImpl) requireAdmin(userID string) error {
+	user, err := uc.userRepo.GetByID(userID)
+	if err != nil {
+		return err
+	}
+	if !user.IsAdmin() {
+		return usecaseinf.ErrModeratorOnly
+	}
+	return nil
+}
+
+// getVolume retrieves a volume of a manga
+func (uc *VolumeUseCaseImpl) getVolume(mangaID, volumeID string) (*entities.MangaVolume, error) {
+	volume, err := uc.volumeRepo.GetByID(volumeID)
+	if err != nil || volume.MangaID != mangaID {
+		return nil, usecaseinf.ErrVolumeNotFound
+	}
+	return volume, nil
+}
+
+// getVolumeResponse retrieves a volume of a manga in its response representation, for the admins editing it
+func (uc *VolumeUseCaseImpl) getVolumeResponse(mangaID, volumeID string) (*dto.MangaVolumeResponse, error) {
+	manga, err := uc.mangaRepo.GetByID(mangaID)
+	if err != nil {
+		return nil, usecaseinf.ErrMangaNotFound
+	}
+	volume, err := uc.getVolume(mangaID, volumeID)
+	if err != nil {
+		return nil, err
+	}
+	chapters, err := uc.chapterRepo.ListByManga(mangaID)
+	if err != nil {
+		return nil, err
+	}
+	covers, err := uc.coverRepo.ListByManga(mangaID)
+	if err != nil {
+		return nil, err
+	}
+	return uc.toVolumeResponse(manga, volume, chapters, covers, true), nil
+}
+
+// checkNumber fails if another volume of the manga than the given one already has the number
+func (uc *VolumeUseCaseImpl) checkNumber(mangaID, volumeID string, number int) error {
+	volumes, err := uc.volumeRepo.ListByManga(mangaID)
+	if err != nil {
+		return err
+	}
+	for _, volume := range volumes {
+		if volume.VolumeNumber == number && volume.VolumeID != volumeID {
+			return usecaseinf.ErrVolumeExists
+		}
+	}
+	return nil
+}
+
+// checkCover fails unless the cover belongs to the manga
+func (uc *VolumeUseCaseImpl) checkCover(mangaID, coverID string) error {
+	cover, err := uc.coverRepo.GetByID(coverID)
+	if err != nil || cover.MangaID != mangaID {
+		return usecaseinf.ErrCoverNotFound
+	}
+	return nil
+}
+
+// toVolumeResponse converts a volume to its response representation, counting its chapters once per chapter number
+// and falling back to the manga's cover uploaded for its number when it has no cover of its own. The cover is left
+// out unless showCover is set
+func (uc *VolumeUseCaseImpl) toVolumeResponse(manga *entities.Manga, volume *entities.MangaVolume, chapters []entities.MangaChapter, covers []entities.MangaCover, showCover bool) *dto.MangaVolumeResponse {
+	result := &dto.MangaVolumeResponse{
+		VolumeID:     volume.VolumeID,
+		MangaID:      volume.MangaID,
+		VolumeNumber: volume.VolumeNumber,
+		Title:        volume.Title,
+		CoverID:      volume.CoverID,
+		CreatedAt:    volume.CreatedAt,
+		UpdatedAt:    volume.UpdatedAt,
+	}
+
+	var numbers []float64
+	for _, chapter := range chapters {
+		if chapter.VolumeID != nil && *chapter.VolumeID == volume.VolumeID && !slices.Contains(numbers, chapter.ChapterNumber) {
+			numbers = append(numbers, chapter.ChapterNumber)
+		}
+	}
+	result.ChapterCount = len(numbers)
+
+	cover := volume.Cover
+	if cover == nil {
+		// Covers are listed primary first, so a primary cover for the volume's number wins
+		if i := slices.IndexFunc(covers, func(c entities.MangaCover) bool {
+			return c.Volume != nil && *c.Volume == volume.VolumeNumber
+		}); i >= 0 {
+			cover = &covers[i]
+		}
+	}
+	if cover != nil && showCover {
+		url := uc.access.imageURLService.Resolve(cover.ImageURL, manga.IsRestricted())
+		result.CoverURL = &url
+	}
+	return result
+}
+
+// trimmedOrNil returns the trimmed text, or nil when it is missing or blank
+func trimmedOrNil(text *string) *string {
+	if text == nil {
+		return nil
+	}
+	trimmed := strings.TrimSpace(*text)
+	if trimmed == "" {
+		return nil
+	}
+	return &trimmed
+}
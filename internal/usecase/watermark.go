@@ -0,0 +1,450 @@
+package usecase
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"image/png"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"path"
+	"time"
+
+	"hotaku-api/internal/domain/dto"
+	"hotaku-api/internal/domain/entities"
+	"hotaku-api/internal/domain/request"
+	"hotaku-api/internal/repoinf"
+	"hotaku-api/internal/serviceinf"
+	"hotaku-api/internal/usecaseinf"
+)
+
+const (
+	// MaxWatermarkOverlaySize caps the size of a PNG watermark overlay
+	MaxWatermarkOverlaySize = 1024 * 1024
+	// MaxWatermarkOverlayDimension caps the width and height of a PNG watermark overlay
+	MaxWatermarkOverlayDimension = 2000
+
+	DefaultWatermarkOpacity = 50
+	DefaultWatermarkScale   = 25
+)
+
+// WatermarkUseCaseImpl implements the group watermark use cases
+type WatermarkUseCaseImpl struct {
+	userRepo         repoinf.UserRepository
+	groupRepo        repoinf.GroupRepository
+	chapterRepo      repoinf.ChapterRepository
+	watermarkRepo    repoinf.GroupWatermarkRepository
+	storageService   serviceinf.StorageService
+	watermarkService serviceinf.WatermarkService
+	// privateBucket tells whether original pages are only reachable through the API
+	privateBucket bool
+}
+
+// NewWatermarkUseCase creates a new instance of WatermarkUseCaseImpl
+func NewWatermarkUseCase(
+	userRepo repoinf.UserRepository,
+	groupRepo repoinf.GroupRepository,
+	chapterRepo repoinf.ChapterRepository,
+	watermarkRepo repoinf.GroupWatermarkRepository,
+	storageService serviceinf.StorageService,
+	watermarkService serviceinf.WatermarkService,
+	privateBucket bool,
+) usecaseinf.WatermarkUseCase {
+	return &WatermarkUseCaseImpl{
+		userRepo:         userRepo,
+		groupRepo:        groupRepo,
+		chapterRepo:      chapterRepo,
+		watermarkRepo:    watermarkRepo,
+		storageService:   storageService,
+		watermarkService: watermarkService,
+		privateBucket:    privateBucket,
+	}
+}
+
+// GetGroupWatermark returns the watermark of a group
+func (uc *WatermarkUseCaseImpl) GetGroupWatermark(userID, groupID string) (*dto.GroupWatermarkResponse, error) {
+	if err := uc.CheckGroupAccess(userID, groupID); err != nil {
+		return nil, err
+	}
+
+	watermark, err := uc.watermarkRepo.GetByGroupID(groupID)
+	if err != nil {
+		return nil, usecaseinf.ErrWatermarkNotFound
+	}
+	return toGroupWatermarkResponse(watermark), nil
+}
+
+// SetGroupWatermark creates or replaces the watermark of a group. Settings left out of the
+// request keep their current value, and an image watermark keeps its overlay unless a new one is sent
+func (uc *WatermarkUseCaseImpl) SetGroupWatermark(userID, groupID string, req *request.SetGroupWatermarkRequest, overlay *multipart.FileHeader) (*dto.GroupWatermarkResponse, error) {
+	if err := uc.CheckGroupAccess(userID, groupID); err != nil {
+		return nil, err
+	}
+
+	watermark, err := uc.watermarkRepo.GetByGroupID(groupID)
+	if err != nil {
+		watermark = &entities.GroupWatermark{
+			GroupID:  groupID,
+			Position: entities.WatermarkPositionBottomRight,
+			Opacity:  DefaultWatermarkOpacity,
+			Scale:    DefaultWatermarkScale,
+		}
+	}
+	previousOverlay := watermark.ImageObject
+
+	watermark.Kind = req.Kind
+	if req.Position != "" {
+		watermark.Position = req.Position
+	}
+	if req.Opacity != nil {
+		watermark.Opacity = *req.Opacity
+	}
+	if req.Scale != nil {
+		watermark.Scale = *req.Scale
+	}
+
+	var storedOverlay string
+	switch req.Kind {
+	case entities.WatermarkKindText:
+		text := req.Text
+		watermark.Text = &text
+		watermark.ImageObject = nil
+	case entities.WatermarkKindImage:
+		watermark.Text = nil
+		if overlay != nil {
+			storedOverlay, err = uc.storeOverlay(groupID, overlay)
+			if err != nil {
+				return nil, err
+			}
+			watermark.ImageObject = &storedOverlay
+		}
+		if watermark.ImageObject == nil {
+			return nil, fmt.Errorf("%w: an image overlay is required", usecaseinf.ErrInvalidWatermark)
+		}
+	}
+
+	// A new variant key versions the cached variants, so a change never serves stale ones
+	watermark.VariantKey, err = newVariantKey()
+	if err != nil {
+		return nil, err
+	}
+	watermark.UpdatedAt = time.Now()
+	if err := uc.watermarkRepo.Save(watermark); err != nil {
+		if storedOverlay != "" {
+			_ = uc.storageService.DeleteFile(storedOverlay)
+		}
+		return nil, err
+	}
+
+	if previousOverlay != nil && (watermark.ImageObject == nil || *watermark.ImageObject != *previousOverlay) {
+		_ = uc.storageService.DeleteFile(*previousOverlay)
+	}
+	uc.purgeGroup(groupID)
+
+	return toGroupWatermarkResponse(watermark), nil
+}
+
+// DeleteGroupWatermark removes the watermark of a group and turns it off on the chapters using it
+func (uc *WatermarkUseCaseImpl) DeleteGroupWatermark(userID, groupID string) error {
+	if err := uc.CheckGroupAccess(userID, groupID); err != nil {
+		return err
+	}
+
+	watermark, err := uc.watermarkRepo.GetByGroupID(groupID)
+	if err != nil {
+		return usecaseinf.ErrWatermarkNotFound
+	}
+
+	chapters, err := uc.chapterRepo.ListByWatermarkGroup(groupID)
+	if err != nil {
+		return err
+	}
+	for _, chapter := range chapters {
+		if err := uc.chapterRepo.UpdateWatermarkGroup(chapter.ChapterID, nil); err != nil {
+			return err
+		}
+		_ = uc.PurgeChapter(chapter.MangaID, chapter.ChapterID)
+	}
+
+	if err := uc.watermarkRepo.Delete(groupID); err != nil {
+		return err
+	}
+	if watermark.ImageObject != nil {
+		_ = uc.storageService.DeleteFile(*watermark.ImageObject)
+	}
+	return nil
+}
+
+// CheckGroupAccess verifies that the user may manage the group's watermark
+func (uc *WatermarkUseCaseImpl) CheckGroupAccess(userID, groupID string) error {
+	user, err := uc.userRepo.GetByID(userID)
+	if err != nil {
+		return err
+	}
+	if user.IsAdmin() {
+		return nil
+	}
+
+	member, err := uc.groupRepo.IsMember(groupID, userID)
+	if err != nil {
+		return err
+	}
+	if !member {
+		return usecaseinf.ErrWatermarkForbidden
+	}
+	return nil
+}
+
+// CheckChapterAccess verifies that the user may watermark a chapter of the manga with the
+// group's watermark: the group must be credited on the manga and have a watermark configured.
+// Original pages of a public bucket can be read straight from storage, so watermarking needs a private one
+func (uc *WatermarkUseCaseImpl) CheckChapterAccess(userID, mangaID, groupID string) error {
+	if !uc.privateBucket {
+		return usecaseinf.ErrWatermarkNeedsPrivateBucket
+	}
+	if err := uc.CheckGroupAccess(userID, groupID); err != nil {
+		return err
+	}
+
+	credited, err := uc.groupRepo.IsCredited(groupID, mangaID)
+	if err != nil {
+		return err
+	}
+	if !credited {
+		return usecaseinf.ErrGroupNotCredited
+	}
+
+	if _, err := uc.watermarkRepo.GetByGroupID(groupID); err != nil {
+		return usecaseinf.ErrWatermarkNotFound
+	}
+	return nil
+}
+
+// RenderChapter renders the missing watermarked variants of a chapter's page objects, at most limit of them
+// when limit is positive, and returns how many were rendered. Variants are cached next to the pages under a
+// directory named after the group and the watermark's variant key, see variantObject
+func (uc *WatermarkUseCaseImpl) RenderChapter(mangaID, chapterID, groupID string, objects []string, limit int) (int, error) {
+	watermark, err := uc.watermarkRepo.GetByGroupID(groupID)
+	if err != nil {
+		return 0, usecaseinf.ErrWatermarkNotFound
+	}
+
+	cached, err := uc.cachedVariants(mangaID, chapterID, watermark)
+	if err != nil {
+		return 0, err
+	}
+
+	var spec *serviceinf.Watermark
+	rendered := 0
+	for _, objectName := range objects {
+		variant := variantObject(mangaID, chapterID, watermark, objectName)
+		if cached[variant] {
+			continue
+		}
+		if limit > 0 && rendered >= limit {
+			break
+		}
+
+		// The overlay is only loaded once a variant actually has to be rendered
+		if spec == nil {
+			if spec, err = uc.watermarkSpec(watermark); err != nil {
+				return rendered, err
+			}
+		}
+		if err := uc.renderVariant(objectName, variant, *spec); err != nil {
+			return rendered, err
+		}
+		cached[variant] = true
+		rendered++
+	}
+	return rendered, nil
+}
+
+// ChapterVariants returns the watermarked variants of a chapter's page objects without rendering any,
+// failing with ErrWatermarkPending while some are still missing
+func (uc *WatermarkUseCaseImpl) ChapterVariants(mangaID, chapterID, groupID string, objects []string) ([]string, error) {
+	watermark, err := uc.watermarkRepo.GetByGroupID(groupID)
+	if err != nil {
+		return nil, usecaseinf.ErrWatermarkNotFound
+	}
+
+	cached, err := uc.cachedVariants(mangaID, chapterID, watermark)
+	if err != nil {
+		return nil, err
+	}
+
+	variants := make([]string, 0, len(objects))
+	for _, objectName := range objects {
+		variant := variantObject(mangaID, chapterID, watermark, objectName)
+		if !cached[variant] {
+			return nil, usecaseinf.ErrWatermarkPending
+		}
+		variants = append(variants, variant)
+	}
+	return variants, nil
+}
+
+// cachedVariants returns the set of variants already rendered for the current version of the watermark
+func (uc *WatermarkUseCaseImpl) cachedVariants(mangaID, chapterID string, watermark *entities.GroupWatermark) (map[string]bool, error) {
+	existing, err := uc.storageService.ListFiles(variantPrefix(mangaID, chapterID, watermark))
+	if err != nil {
+		return nil, err
+	}
+	cached := make(map[string]bool, len(existing))
+	for _, objectName := range existing {
+		cached[objectName] = true
+	}
+	return cached, nil
+}
+
+// PurgeChapter deletes the cached watermarked variants of a chapter
+func (uc *WatermarkUseCaseImpl) PurgeChapter(mangaID, chapterID string) error {
+	variants, err := uc.storageService.ListFiles(watermarkPrefix(mangaID, chapterID))
+	if err != nil {
+		return err
+	}
+	for _, variant := range variants {
+		if err := uc.storageService.DeleteFile(variant); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// purgeGroup deletes the cached variants of every chapter watermarked by the group, best-effort
+// since the storage GC collects what is left behind
+func (uc *WatermarkUseCaseImpl) purgeGroup(groupID string) {
+	chapters, err := uc.chapterRepo.ListByWatermarkGroup(groupID)
+	if err != nil {
+		return
+	}
+	for _, chapter := range chapters {
+		_ = uc.PurgeChapter(chapter.MangaID, chapter.ChapterID)
+	}
+}
+
+// watermarkSpec converts a group watermark to what the watermark service draws, loading its overlay
+func (uc *WatermarkUseCaseImpl) watermarkSpec(watermark *entities.GroupWatermark) (*serviceinf.Watermark, error) {
+	spec := &serviceinf.Watermark{
+		Position: watermark.Position,
+		Opacity:  watermark.Opacity,
+		Scale:    watermark.Scale,
+	}
+	if watermark.Text != nil {
+		spec.Text = *watermark.Text
+	}
+	if watermark.Kind != entities.WatermarkKindImage || watermark.ImageObject == nil {
+		return spec, nil
+	}
+
+	obj, err := uc.storageService.GetObject(*watermark.ImageObject)
+	if err != nil {
+		return nil, err
+	}
+	defer obj.Close()
+
+	spec.Overlay, err = io.ReadAll(io.LimitReader(obj, MaxWatermarkOverlaySize))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read watermark overlay: %w", err)
+	}
+	return spec, nil
+}
+
+// renderVariant watermarks a page object and stores the result as the variant
+func (uc *WatermarkUseCaseImpl) renderVariant(objectName, variant string, spec serviceinf.Watermark) error {
+	obj, err := uc.storageService.GetObject(objectName)
+	if err != nil {
+		return err
+	}
+	defer obj.Close()
+
+	data, err := io.ReadAll(obj)
+	if err != nil {
+		return fmt.Errorf("failed to read page %s: %w", objectName, err)
+	}
+
+	watermarked, contentType, err := uc.watermarkService.Apply(data, spec)
+	if err != nil {
+		return fmt.Errorf("%w %s: %v", usecaseinf.ErrWatermarkFailed, path.Base(objectName), err)
+	}
+	return uc.storageService.PutObject(variant, watermarked, contentType)
+}
+
+// storeOverlay validates an uploaded PNG overlay and stores it for the group
+func (uc *WatermarkUseCaseImpl) storeOverlay(groupID string, overlay *multipart.FileHeader) (string, error) {
+	if overlay.Size > MaxWatermarkOverlaySize {
+		return "", fmt.Errorf("%w: overlay size %d exceeds maximum allowed size %d", usecaseinf.ErrInvalidWatermark, overlay.Size, MaxWatermarkOverlaySize)
+	}
+
+	file, err := overlay.Open()
+	if err != nil {
+		return "", fmt.Errorf("failed to open overlay: %w", err)
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(io.LimitReader(file, MaxWatermarkOverlaySize))
+	if err != nil {
+		return "", fmt.Errorf("failed to read overlay: %w", err)
+	}
+	if http.DetectContentType(data) != "image/png" {
+		return "", fmt.Errorf("%w: overlay must be a PNG image", usecaseinf.ErrInvalidWatermark)
+	}
+	config, err := png.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", usecaseinf.ErrInvalidWatermark, err)
+	}
+	if config.Width > MaxWatermarkOverlayDimension || config.Height > MaxWatermarkOverlayDimension {
+		return "", fmt.Errorf("%w: overlay dimensions %dx%d exceed %dx%d", usecaseinf.ErrInvalidWatermark,
+			config.Width, config.Height, MaxWatermarkOverlayDimension, MaxWatermarkOverlayDimension)
+	}
+
+	objectName := uc.storageService.WatermarkOverlayObjectName(groupID)
+	if err := uc.storageService.PutObject(objectName, data, "image/png"); err != nil {
+		return "", err
+	}
+	return objectName, nil
+}
+
+// watermarkPrefix returns the storage prefix holding the watermarked variants of a chapter
+func watermarkPrefix(mangaID, chapterID string) string {
+	return fmt.Sprintf("manga/%s/chapters/%s/watermarked/", mangaID, chapterID)
+}
+
+// variantPrefix returns the storage prefix holding the variants of a chapter rendered with the current
+// version of a group's watermark
+func variantPrefix(mangaID, chapterID string, watermark *entities.GroupWatermark) string {
+	return fmt.Sprintf("%s%s-%s/", watermarkPrefix(mangaID, chapterID), watermark.GroupID, watermark.VariantKey)
+}
+
+// variantObject returns the object a page is rendered to. Its name is derived from the secret variant key
+// rather than the page's, so a variant URL gives away neither the original page nor the other variants
+func variantObject(mangaID, chapterID string, watermark *entities.GroupWatermark, objectName string) string {
+	sum := sha256.Sum256([]byte(watermark.VariantKey + "/" + objectName))
+	return variantPrefix(mangaID, chapterID, watermark) + hex.EncodeToString(sum[:16]) + path.Ext(objectName)
+}
+
+// newVariantKey generates a random watermark variant key
+func newVariantKey() (string, error) {
+	key := make([]byte, 16)
+	if _, err := rand.Read(key); err != nil {
+		return "", fmt.Errorf("failed to generate watermark variant key: %w", err)
+	}
+	return hex.EncodeToString(key), nil
+}
+
+// toGroupWatermarkResponse converts a group watermark to its response representation
+func toGroupWatermarkResponse(watermark *entities.GroupWatermark) *dto.GroupWatermarkResponse {
+	return &dto.GroupWatermarkResponse{
+		GroupID:    watermark.GroupID,
+		Kind:       watermark.Kind,
+		Text:       watermark.Text,
+		HasOverlay: watermark.ImageObject != nil,
+		Position:   watermark.Position,
+		Opacity:    watermark.Opacity,
+		Scale:      watermark.Scale,
+		UpdatedAt:  watermark.UpdatedAt,
+	}
+}
@@ -0,0 +1,46 @@
+package usecaseinf
+
+import (
+	"errors"
+	"io"
+
+	"hotaku-api/internal/domain/dto"
+	"hotaku-api/internal/domain/request"
+)
+
+// ErrChapterNotFound is returned when a chapter does not exist
+var ErrChapterNotFound = errors.New("chapter not found")
+
+var (
+	// ErrInvalidChapterFilter is returned when a chapter list filter holds a malformed language or group
+	ErrInvalidChapterFilter = errors.New("invalid chapter filter")
+	// ErrChapterVersionExists is returned when the manga already has the chapter in the same language by the same group
+	ErrChapterVersionExists = errors.New("chapter already exists in this language for this group")
+	// ErrChapterForbidden is returned when the user may not credit a chapter to or away from a group
+	ErrChapterForbidden = errors.New("you do not have permission to manage this chapter")
+	// ErrChapterEmpty is returned when downloading a chapter that has no pages
+	ErrChapterEmpty = errors.New("chapter has no pages")
+)
+
+// ChapterUseCase defines the interface for chapter use cases
+type ChapterUseCase interface {
+	// ListChapters returns a page of the chapters of a manga, filtered by language and group. When only the best
+	// version of each chapter is asked for, languages earlier in the filter, or else in the preferred languages, win
+	ListChapters(mangaID string, req *request.ListChaptersRequest, languages []string) (*dto.ChapterListResponse, error)
+	// SetAttribution credits a chapter to a language and a scanlation group
+	SetAttribution(userID, chapterID string, req *request.SetChapterAttributionRequest) (*dto.ChapterResponse, error)
+	// GetManifest returns the pages of a chapter in reading order with the URLs to load them from, to a user who
+	// may see them. userID is empty when signed out
+	GetManifest(userID, chapterID string) (*dto.ChapterManifestResponse, error)
+	// SetWatermark turns the watermark of a chapter on with the given group's watermark, or off when groupID is nil
+	SetWatermark(userID, chapterID string, groupID *string) (*dto.ChapterWatermarkResponse, error)
+	// RenderWatermarks renders at most limit missing watermarked variants of the watermarked chapters
+	RenderWatermarks(limit int) (int, error)
+	// PrepareDownload resolves the pages and metadata of a chapter archive for a user who may see them
+	PrepareDownload(userID, chapterID string) (*dto.ChapterDownload, error)
+	// PrepareVolumeDownload resolves the pages and metadata of a volume archive, holding a single version of each of
+	// its chapters, the one in the most preferred of the languages
+	PrepareVolumeDownload(userID, volumeID string, languages []string) (*dto.ChapterDownload, error)
+	// WriteArchive streams a prepared chapter archive as a CBZ to the writer
+	WriteArchive(download *dto.ChapterDownload, w io.Writer) error
+}
@@ -0,0 +1,54 @@
+package usecaseinf
+
+import (
+	"context"
+	"errors"
+	"io"
+	"mime/multipart"
+
+	"hotaku-api/internal/domain/dto"
+)
+
+// MaxArchiveSize is the largest chapter archive accepted, whether uploaded at once or in chunks
+const MaxArchiveSize = 500 * 1024 * 1024 // 500MB
+
+// ErrTooManyPages is returned when an upload carries more pages than a single request may
+var ErrTooManyPages = errors.New("too many pages in a single upload")
+
+// ErrPageForbidden is returned when the user is neither an admin nor in a group credited on the manga
+var ErrPageForbidden = errors.New("you do not have permission to manage the pages of this chapter")
+
+// ChapterPageUseCase defines the interface for chapter page use cases
+type ChapterPageUseCase interface {
+	// BeginPageUpload starts a batch of chapter pages that are stored as they arrive until ctx is cancelled.
+	// A partial batch keeps the pages that were stored when others fail, otherwise nothing is kept
+	BeginPageUpload(ctx context.Context, userID, mangaID, chapterID string, partial bool) (PageUploadBatch, error)
+	// ReplacePage replaces the image of an existing chapter page
+	ReplacePage(userID, mangaID, chapterID string, pageNumber int, file *multipart.FileHeader) (*dto.PageUploadResponse, error)
+	// ReorderPages atomically renumbers all pages of a chapter to follow the given page ID order, for an admin or a
+	// member of a group credited on the manga
+	ReorderPages(userID, mangaID, chapterID string, pageIDs []string) ([]dto.ChapterPageResponse, error)
+	// ImportArchive extracts the images of a CBZ/ZIP archive and appends them as pages of a chapter
+	ImportArchive(userID, mangaID, chapterID string, archive io.ReaderAt, size int64) (*dto.ArchiveImportResponse, error)
+	// BackfillLegacyPages records the pages of the chapters uploaded before pages were recorded, which only exist in
+	// storage, returning how many chapters and pages were recorded
+	BackfillLegacyPages() (chapters int, pages int, err error)
+	// AppendPageFromObject stores a normalized copy of an already stored object as the next page of a chapter
+	AppendPageFromObject(userID, mangaID, chapterID, sourceObject, filename, contentType string, size int64) (*dto.PageUploadResponse, error)
+}
+
+// PageUploadBatch receives the pages of a streamed upload in reading order and stores them
+// with a pool of workers, so a page can be pushed to storage while the next one is still being received
+type PageUploadBatch interface {
+	// Add hashes a page and queues it for storage, blocking while every worker is busy.
+	// It fails fast once the batch was cancelled, or once a page failed unless the batch is partial
+	Add(filename, contentType string, data []byte) error
+	// Reject records a page that failed validation before reaching the batch. A partial batch
+	// reports it as failed and goes on, otherwise err is returned
+	Reject(filename string, err error) error
+	// Commit waits for the queued pages and records the stored ones, appending them or inserting
+	// them at position when it is positive
+	Commit(position int) (*dto.PageBatchUploadResponse, error)
+	// Abort cancels the queued pages and removes everything the batch stored
+	Abort()
+}
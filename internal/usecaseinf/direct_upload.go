@@ -0,0 +1,16 @@
+package usecaseinf
+
+import (
+	"hotaku-api/internal/domain/dto"
+	"hotaku-api/internal/domain/request"
+)
+
+// DirectUploadUseCase defines the interface for direct-to-storage upload use cases
+type DirectUploadUseCase interface {
+	// Presign registers an upload intent and returns a presigned URL the client uploads to
+	Presign(userID string, req *request.PresignUploadRequest) (*dto.PresignedUploadResponse, error)
+	// Finalize verifies an uploaded object and registers it as a cover or chapter page
+	Finalize(userID, uploadID string) (*dto.FinalizeUploadResponse, error)
+	// SweepExpired discards the uploads that expired before being finalized, returning how many there were
+	SweepExpired() (int, error)
+}
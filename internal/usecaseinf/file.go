@@ -0,0 +1,31 @@
+package usecaseinf
+
+import (
+	"errors"
+
+	"hotaku-api/internal/domain/dto"
+)
+
+var (
+	// ErrInvalidObjectName is returned when an object name is outside the manga namespace or malformed
+	ErrInvalidObjectName = errors.New("invalid object name")
+	// ErrFileNotFound is returned when a stored file does not exist
+	ErrFileNotFound = errors.New("file not found")
+	// ErrFileForbidden is returned when the user neither uploaded the file nor belongs to its group
+	ErrFileForbidden = errors.New("you do not have permission to manage this file")
+)
+
+// FileUseCase defines the interface for stored file ownership use cases
+type FileUseCase interface {
+	// RecordUpload records the uploader and group of a newly stored object
+	RecordUpload(userID, mangaID, objectName, contentType string, size int64) error
+	// Discard removes a stored object and its ownership record, used to roll back failed uploads
+	Discard(objectName string) error
+	// CheckAccess verifies that the user may manage a stored file
+	CheckAccess(userID, objectName string) error
+	// GetInfo returns information about a stored file the user may manage
+	GetInfo(userID, objectName string) (*dto.FileInfoResponse, error)
+	// Delete removes the references the user or the uploader added to a stored file the user may manage, and
+	// the file itself once nothing else references it
+	Delete(userID, objectName string) error
+}
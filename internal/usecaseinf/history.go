@@ -0,0 +1,26 @@
+package usecaseinf
+
+import (
+	"errors"
+
+	"hotaku-api/internal/domain/dto"
+	"hotaku-api/internal/domain/request"
+)
+
+// ErrHistoryEntryNotFound is returned when a history entry does not exist or belongs to another user
+var ErrHistoryEntryNotFound = errors.New("history entry not found")
+
+// HistoryUseCase defines the interface for reading history use cases. Entries are recorded by the reading
+// progress use cases as the user opens chapters
+type HistoryUseCase interface {
+	// ListHistory returns a page of the user's reading history, the latest opened first
+	ListHistory(userID string, req *request.ListHistoryRequest, languages []string) (*dto.HistoryResponse, error)
+	// ExportHistory returns the whole reading history of the user, the latest opened first
+	ExportHistory(userID string, languages []string) ([]dto.HistoryEntryResponse, error)
+	// DeleteEntry removes an entry from the user's reading history
+	DeleteEntry(userID, entryID string) error
+	// ClearHistory removes the user's reading history, or only its entries of a manga
+	ClearHistory(userID string, req *request.ClearHistoryRequest) (*dto.HistoryClearResponse, error)
+	// SetPaused changes whether the chapters the user opens are recorded
+	SetPaused(userID string, req *request.SetHistoryPauseRequest) (*dto.HistoryPauseResponse, error)
+}
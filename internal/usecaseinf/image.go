@@ -0,0 +1,12 @@
+package usecaseinf
+
+import "errors"
+
+// ErrImageAccessDenied is returned when an image of a restricted manga is requested without a valid signature
+var ErrImageAccessDenied = errors.New("image access denied")
+
+// ImageUseCase defines the interface for image serving use cases
+type ImageUseCase interface {
+	// CheckAccess verifies that an image may be served, requiring a valid signature for restricted mangas
+	CheckAccess(objectName, expires, signature string) error
+}
@@ -0,0 +1,46 @@
+package usecaseinf
+
+import (
+	"errors"
+
+	"hotaku-api/internal/domain/dto"
+	"hotaku-api/internal/domain/request"
+)
+
+var (
+	// ErrLibraryNotFound is returned when a user's library does not exist or isn't shared
+	ErrLibraryNotFound = errors.New("library not found")
+	// ErrFavoriteNotFound is returned when a manga is not in the user's library
+	ErrFavoriteNotFound = errors.New("manga is not in the library")
+	// ErrShelfNotFound is returned when a shelf does not exist or belongs to another user
+	ErrShelfNotFound = errors.New("shelf not found")
+	// ErrShelfExists is returned when the user already has a shelf with the same name
+	ErrShelfExists = errors.New("library already has a shelf with this name")
+	// ErrShelfBuiltin is returned when deleting one of the shelves every library starts with
+	ErrShelfBuiltin = errors.New("built-in shelves can't be deleted")
+	// ErrInvalidLibraryFilter is returned when a library shelf filter is malformed
+	ErrInvalidLibraryFilter = errors.New("invalid library filter")
+)
+
+// LibraryUseCase defines the interface for user library use cases
+type LibraryUseCase interface {
+	// GetLibrary returns a page of the user's library, titled in the best matching languages
+	GetLibrary(userID string, req *request.ListLibraryRequest, languages []string) (*dto.LibraryResponse, error)
+	// GetPublicLibrary returns a page of another user's library when they share it, viewerID being empty when
+	// signed out
+	GetPublicLibrary(viewerID, ownerID string, req *request.ListLibraryRequest, languages []string) (*dto.LibraryResponse, error)
+	// SaveFavorite adds a manga to the user's library, or moves it to another shelf if it already is in it
+	SaveFavorite(userID, mangaID string, req *request.SaveFavoriteRequest) (*dto.FavoriteResponse, error)
+	// RemoveFavorite removes a manga from the user's library
+	RemoveFavorite(userID, mangaID string) error
+	// ListShelves returns the shelves of the user's library
+	ListShelves(userID string) ([]dto.LibraryShelfResponse, error)
+	// CreateShelf adds a custom shelf to the user's library
+	CreateShelf(userID string, req *request.SaveShelfRequest) (*dto.LibraryShelfResponse, error)
+	// RenameShelf changes the name of a shelf of the user's library
+	RenameShelf(userID, shelfID string, req *request.SaveShelfRequest) (*dto.LibraryShelfResponse, error)
+	// DeleteShelf removes a custom shelf, its mangas staying in the library without a shelf
+	DeleteShelf(userID, shelfID string) error
+	// SetVisibility changes whether anyone may see the user's library
+	SetVisibility(userID string, req *request.SetLibraryVisibilityRequest) (*dto.LibraryVisibilityResponse, error)
+}
@@ -0,0 +1,83 @@
+package usecaseinf
+
+import (
+	"errors"
+	"mime/multipart"
+
+	"hotaku-api/internal/domain/dto"
+	"hotaku-api/internal/domain/request"
+)
+
+// ErrMangaNotFound is returned when a manga does not exist
+var ErrMangaNotFound = errors.New("manga not found")
+
+// ErrCoverNotFound is returned when a cover does not exist or belongs to another manga
+var ErrCoverNotFound = errors.New("cover not found")
+
+// ErrMangaRestricted is returned when the images of a restricted manga are requested by someone who may not see them
+var ErrMangaRestricted = errors.New("manga is restricted")
+
+// ErrRestrictedNeedsPrivateBucket is returned when a manga is restricted while the bucket is publicly readable
+var ErrRestrictedNeedsPrivateBucket = errors.New("restricted mangas require a private bucket")
+
+// ErrInvalidMangaFilter is returned when a manga listing filter is malformed or contradicts itself
+var ErrInvalidMangaFilter = errors.New("invalid manga filter")
+
+var (
+	// ErrMangaTitleNotFound is returned when a title does not exist or belongs to another manga
+	ErrMangaTitleNotFound = errors.New("manga title not found")
+	// ErrMangaTitleExists is returned when a manga already has the same title in the same locale
+	ErrMangaTitleExists = errors.New("manga already has this title in this locale")
+	// ErrMangaDescriptionNotFound is returned when a manga has no description in a locale
+	ErrMangaDescriptionNotFound = errors.New("manga description not found")
+	// ErrInvalidLocale is returned when a locale is not a valid BCP 47 language tag
+	ErrInvalidLocale = errors.New("invalid locale")
+)
+
+// MangaUseCase defines the interface for manga use cases
+type MangaUseCase interface {
+	// GetManga returns a manga with its primary cover and every localized title, its title and description
+	// being the ones best matching the languages, most preferred first. userID is empty when signed out
+	GetManga(userID, mangaID string, languages []string) (*dto.MangaResponse, error)
+	// ListMangas returns a page of mangas matching the filters of the request, titled in the best matching languages
+	ListMangas(userID string, req *request.ListMangasRequest, languages []string) (*dto.MangaListResponse, error)
+	// SetVisibility changes whether the images of a manga are served through signed URLs only
+	SetVisibility(userID, mangaID, visibility string) (*dto.MangaResponse, error)
+	// SetYear sets the year a manga was first published, nil clearing it
+	SetYear(userID, mangaID string, year *int) (*dto.MangaResponse, error)
+	// RefreshPopularity recomputes the popularity listings are sorted by
+	RefreshPopularity() error
+}
+
+// MangaTitleUseCase defines the interface for localized manga title and description use cases
+type MangaTitleUseCase interface {
+	// ListTitles returns all localized titles of a manga
+	ListTitles(mangaID string) ([]dto.MangaTitleResponse, error)
+	// AddTitle adds a localized title to a manga
+	AddTitle(userID, mangaID string, req *request.AddMangaTitleRequest) (*dto.MangaTitleResponse, error)
+	// DeleteTitle removes a localized title of a manga
+	DeleteTitle(userID, mangaID, titleID string) error
+	// ListDescriptions returns all localized descriptions of a manga
+	ListDescriptions(mangaID string) ([]dto.MangaDescriptionResponse, error)
+	// SetDescription creates or replaces the description of a manga in a locale
+	SetDescription(userID, mangaID, locale string, req *request.SetMangaDescriptionRequest) (*dto.MangaDescriptionResponse, error)
+	// DeleteDescription removes the description of a manga in a locale
+	DeleteDescription(userID, mangaID, locale string) error
+}
+
+// MangaCoverUseCase defines the interface for manga cover use cases
+type MangaCoverUseCase interface {
+	// ListCovers returns all covers of a manga, primary first, to a user who may see them
+	ListCovers(userID, mangaID string) ([]dto.MangaCoverResponse, error)
+	// UploadCover stores an image as a new cover of a manga
+	UploadCover(userID, mangaID string, file *multipart.FileHeader, req *request.UploadCoverRequest) (*dto.MangaCoverResponse, error)
+	// AddCoverFromObject stores a normalized copy of an already stored object as a new cover of a manga
+	AddCoverFromObject(userID, mangaID, sourceObject string) (*dto.MangaCoverResponse, error)
+	// SetPrimaryCover makes a cover the one shown for the manga
+	SetPrimaryCover(userID, mangaID, coverID string) (*dto.MangaCoverResponse, error)
+	// DeleteCover removes a cover and its image
+	DeleteCover(userID, mangaID, coverID string) error
+	// BackfillLegacyCovers records the covers of the mangas whose images were uploaded before covers were recorded,
+	// returning how many mangas and covers were recorded
+	BackfillLegacyCovers() (mangas int, covers int, err error)
+}
@@ -0,0 +1,22 @@
+package usecaseinf
+
+import (
+	"errors"
+
+	"hotaku-api/internal/domain/dto"
+	"hotaku-api/internal/domain/request"
+)
+
+// ErrModeratorOnly is returned when a user without moderation rights calls a moderation use case
+var ErrModeratorOnly = errors.New("moderator access required")
+
+// ErrDuplicateFlagNotFound is returned when a duplicate page flag does not exist
+var ErrDuplicateFlagNotFound = errors.New("duplicate flag not found")
+
+// ModerationUseCase defines the interface for moderation use cases
+type ModerationUseCase interface {
+	// ListDuplicateFlags returns a page of duplicate page flags
+	ListDuplicateFlags(userID string, req *request.ListDuplicateFlagsRequest) (*dto.DuplicateFlagListResponse, error)
+	// ReviewDuplicateFlag confirms or dismisses a duplicate page flag
+	ReviewDuplicateFlag(userID, flagID, status string) (*dto.DuplicateFlagResponse, error)
+}
@@ -0,0 +1,18 @@
+package usecaseinf
+
+import (
+	"errors"
+
+	"hotaku-api/internal/domain/dto"
+)
+
+// ErrQuotaExceeded is returned when an upload would take a user or group over its storage quota
+var ErrQuotaExceeded = errors.New("storage quota exceeded")
+
+// QuotaUseCase defines the interface for storage quota use cases
+type QuotaUseCase interface {
+	// CheckUpload verifies that storing size more bytes for the manga keeps the user and their group within quota
+	CheckUpload(userID, mangaID string, size int64) error
+	// GetUsage returns the storage usage of the user and of each group they belong to
+	GetUsage(userID string) (*dto.StorageUsageResponse, error)
+}
@@ -0,0 +1,29 @@
+package usecaseinf
+
+import (
+	"errors"
+
+	"hotaku-api/internal/domain/dto"
+	"hotaku-api/internal/domain/request"
+)
+
+// ErrInvalidPageNumber is returned when a reading position is past the last page of its chapter
+var ErrInvalidPageNumber = errors.New("invalid page number")
+
+// ReadingUseCase defines the interface for reading progress use cases. Progress picks the version of the next
+// chapter to read in the language of the last chapter read, or else the most preferred of the languages
+type ReadingUseCase interface {
+	// MarkRead records that the user read a chapter and adds it to their reading history, neither when incognito
+	MarkRead(userID, chapterID string, incognito bool, languages []string) (*dto.MangaProgressResponse, error)
+	// MarkUnread removes the reads of every version of a chapter by the user
+	MarkUnread(userID, chapterID string, languages []string) (*dto.MangaProgressResponse, error)
+	// MarkReadUpTo records that the user read every chapter of a manga numbered up to the given chapter
+	MarkReadUpTo(userID, mangaID string, req *request.MarkReadUpToRequest, languages []string) (*dto.MangaProgressResponse, error)
+	// SavePosition records the page of a chapter the user is on and adds it to their reading history, neither when
+	// incognito
+	SavePosition(userID, chapterID string, req *request.SaveReadingPositionRequest, incognito bool, languages []string) (*dto.MangaProgressResponse, error)
+	// GetProgress summarizes how much of a manga the user read and where to continue
+	GetProgress(userID, mangaID string, languages []string) (*dto.MangaProgressResponse, error)
+	// ContinueReading returns a page of the mangas the user has been reading, the most recently read first
+	ContinueReading(userID string, req *request.ContinueReadingRequest, languages []string) (*dto.ContinueReadingResponse, error)
+}
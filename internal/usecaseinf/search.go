@@ -0,0 +1,24 @@
+package usecaseinf
+
+import (
+	"errors"
+
+	"hotaku-api/internal/domain/dto"
+	"hotaku-api/internal/domain/request"
+)
+
+var (
+	// ErrInvalidSearchQuery is returned when a search query has no word to search for
+	ErrInvalidSearchQuery = errors.New("search query has no searchable words")
+	// ErrSearchIndexDisabled is returned when the embedded search index is used while another search backend is configured
+	ErrSearchIndexDisabled = errors.New("embedded search index is not enabled")
+)
+
+// SearchUseCase defines the interface for search use cases
+type SearchUseCase interface {
+	// SearchMangas returns a page of mangas matching a full-text query, best matches first unless sorted by recency,
+	// titled in the languages best matching the preferred ones
+	SearchMangas(userID string, req *request.SearchMangasRequest, languages []string) (*dto.MangaSearchResponse, error)
+	// RebuildIndex reindexes every manga in the embedded search index and saves a snapshot of it
+	RebuildIndex(userID string) (*dto.SearchIndexResponse, error)
+}
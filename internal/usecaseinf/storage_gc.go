@@ -0,0 +1,13 @@
+package usecaseinf
+
+import (
+	"time"
+
+	"hotaku-api/internal/domain/dto"
+)
+
+// StorageGCUseCase defines the interface for removing orphaned stored objects
+type StorageGCUseCase interface {
+	// CollectGarbage finds objects older than gracePeriod that nothing references and deletes them unless dryRun is set
+	CollectGarbage(dryRun bool, gracePeriod time.Duration) (*dto.GarbageCollectionReport, error)
+}
@@ -0,0 +1,21 @@
+package usecaseinf
+
+import (
+	"errors"
+
+	"hotaku-api/internal/domain/dto"
+	"hotaku-api/internal/domain/request"
+)
+
+// ErrInvalidSuggestionType is returned when suggestions are asked for a type that doesn't exist
+var ErrInvalidSuggestionType = errors.New("suggestion types must be manga, author, group or category")
+
+// SuggestUseCase defines the interface for search suggestion use cases
+type SuggestUseCase interface {
+	// Suggest returns the mangas, authors, groups and categories with a word starting with the query, most popular first
+	Suggest(req *request.SuggestRequest) ([]dto.SuggestionResponse, error)
+	// Invalidate drops the cached suggestions and rebuilds them in the background, for writes that change them
+	Invalidate()
+	// Refresh rebuilds the suggestions when what they are built from changed, or once they are an hour old
+	Refresh() error
+}
@@ -0,0 +1,28 @@
+package usecaseinf
+
+import (
+	"errors"
+	"io"
+
+	"hotaku-api/internal/domain/dto"
+	"hotaku-api/internal/domain/request"
+)
+
+// ErrUploadOffsetMismatch is returned when a chunk does not start at the current upload offset
+var ErrUploadOffsetMismatch = errors.New("upload offset mismatch")
+
+// UploadSessionUseCase defines the interface for resumable upload use cases
+type UploadSessionUseCase interface {
+	// Create starts a resumable upload session for a chapter page or archive
+	Create(userID string, req *request.CreateUploadSessionRequest) (*dto.UploadSessionResponse, error)
+	// Get returns the current state of an upload session
+	Get(userID, sessionID string) (*dto.UploadSessionResponse, error)
+	// AppendChunk stores the next chunk of an upload starting at the given offset
+	AppendChunk(userID, sessionID string, offset int64, chunk io.Reader, size int64) (*dto.UploadSessionResponse, error)
+	// Finalize assembles the uploaded chunks and hands the file to the chapter page pipeline
+	Finalize(userID, sessionID string) (*dto.FinalizeSessionResponse, error)
+	// Cancel aborts an upload session and discards its stored chunks
+	Cancel(userID, sessionID string) error
+	// SweepExpired discards the sessions that expired before being finalized, returning how many there were
+	SweepExpired() (int, error)
+}
@@ -0,0 +1,35 @@
+package usecaseinf
+
+import (
+	"errors"
+
+	"hotaku-api/internal/domain/dto"
+	"hotaku-api/internal/domain/request"
+)
+
+var (
+	// ErrVolumeNotFound is returned when a volume does not exist or belongs to another manga
+	ErrVolumeNotFound = errors.New("volume not found")
+	// ErrVolumeExists is returned when a manga already has a volume with the same number
+	ErrVolumeExists = errors.New("manga already has a volume with this number")
+	// ErrInvalidVolumeChapters is returned when the chapters given to a volume don't all belong to its manga
+	ErrInvalidVolumeChapters = errors.New("invalid volume chapters")
+	// ErrVolumeEmpty is returned when downloading a volume none of whose chapters has pages
+	ErrVolumeEmpty = errors.New("volume has no pages")
+)
+
+// VolumeUseCase defines the interface for manga volume use cases
+type VolumeUseCase interface {
+	// ListVolumes returns all volumes of a manga by volume number, covers left out for a user who may not see them
+	ListVolumes(userID, mangaID string) ([]dto.MangaVolumeResponse, error)
+	// CreateVolume adds a volume to a manga
+	CreateVolume(userID, mangaID string, req *request.CreateVolumeRequest) (*dto.MangaVolumeResponse, error)
+	// UpdateVolume changes the number, title or cover of a volume
+	UpdateVolume(userID, mangaID, volumeID string, req *request.UpdateVolumeRequest) (*dto.MangaVolumeResponse, error)
+	// DeleteVolume removes a volume, its chapters staying in the manga without a volume
+	DeleteVolume(userID, mangaID, volumeID string) error
+	// SetChapters replaces the chapters of a volume
+	SetChapters(userID, mangaID, volumeID string, req *request.SetVolumeChaptersRequest) (*dto.MangaVolumeResponse, error)
+	// GetProgress summarizes how much of each volume of a manga a user read
+	GetProgress(userID, mangaID string) ([]dto.VolumeProgressResponse, error)
+}
@@ -0,0 +1,48 @@
+package usecaseinf
+
+import (
+	"errors"
+	"mime/multipart"
+
+	"hotaku-api/internal/domain/dto"
+	"hotaku-api/internal/domain/request"
+)
+
+var (
+	// ErrWatermarkNotFound is returned when a group has no watermark configured
+	ErrWatermarkNotFound = errors.New("group watermark not found")
+	// ErrWatermarkForbidden is returned when the user neither belongs to the group nor is an admin
+	ErrWatermarkForbidden = errors.New("you do not have permission to manage this group's watermark")
+	// ErrInvalidWatermark is returned when a watermark overlay is missing or not a usable PNG image
+	ErrInvalidWatermark = errors.New("invalid watermark")
+	// ErrGroupNotCredited is returned when a chapter is watermarked by a group not credited on its manga
+	ErrGroupNotCredited = errors.New("group is not credited on this manga")
+	// ErrWatermarkFailed is returned when a page can't be watermarked, such as WebP pages
+	ErrWatermarkFailed = errors.New("failed to watermark page")
+	// ErrWatermarkPending is returned while the watermarked variants of a chapter are still being rendered
+	ErrWatermarkPending = errors.New("chapter pages are still being watermarked")
+	// ErrWatermarkNeedsPrivateBucket is returned when watermarking a chapter while original pages are publicly readable
+	ErrWatermarkNeedsPrivateBucket = errors.New("watermarking chapters requires a private bucket")
+)
+
+// WatermarkUseCase defines the interface for group watermark use cases
+type WatermarkUseCase interface {
+	// GetGroupWatermark returns the watermark of a group
+	GetGroupWatermark(userID, groupID string) (*dto.GroupWatermarkResponse, error)
+	// SetGroupWatermark creates or replaces the watermark of a group, overlay is only used by image watermarks
+	SetGroupWatermark(userID, groupID string, req *request.SetGroupWatermarkRequest, overlay *multipart.FileHeader) (*dto.GroupWatermarkResponse, error)
+	// DeleteGroupWatermark removes the watermark of a group and turns it off on the chapters using it
+	DeleteGroupWatermark(userID, groupID string) error
+	// CheckGroupAccess verifies that the user may manage the group's watermark
+	CheckGroupAccess(userID, groupID string) error
+	// CheckChapterAccess verifies that the user may watermark a chapter of the manga with the group's watermark
+	CheckChapterAccess(userID, mangaID, groupID string) error
+	// RenderChapter renders the missing watermarked variants of a chapter's page objects, at most limit of them
+	// when limit is positive, and returns how many were rendered
+	RenderChapter(mangaID, chapterID, groupID string, objects []string, limit int) (int, error)
+	// ChapterVariants returns the watermarked variants of a chapter's page objects, failing with ErrWatermarkPending
+	// while some are still missing
+	ChapterVariants(mangaID, chapterID, groupID string, objects []string) ([]string, error)
+	// PurgeChapter deletes the cached watermarked variants of a chapter
+	PurgeChapter(mangaID, chapterID string) error
+}
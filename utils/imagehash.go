@@ -0,0 +1,100 @@
+package utils
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"image"
+	_ "image/gif"  // register GIF decoding for HashImage
+	_ "image/jpeg" // register JPEG decoding for HashImage
+	_ "image/png"  // register PNG decoding for HashImage
+	"io"
+	"math/bits"
+)
+
+// MaxImagePixels caps how many pixels an image may have before it is decoded
+const MaxImagePixels = 50 * 1000 * 1000
+
+// HashImage returns the hex SHA-256 of the content and its perceptual hash. The perceptual
+// hash is nil when the format can't be decoded (WebP has no decoder in the standard library)
+// or the image has more than MaxImagePixels pixels
+func HashImage(r io.Reader) (string, *uint64, error) {
+	hasher := sha256.New()
+	tee := io.TeeReader(r, hasher)
+
+	// The header is read first and replayed to the decoder, so oversized images are never decoded
+	var header bytes.Buffer
+	var perceptual *uint64
+	config, _, err := image.DecodeConfig(io.TeeReader(tee, &header))
+	if err == nil && config.Width*config.Height <= MaxImagePixels {
+		if img, _, err := image.Decode(io.MultiReader(&header, tee)); err == nil {
+			hash := DifferenceHash(img)
+			perceptual = &hash
+		}
+	}
+
+	// The decoder may stop before the end, the checksum must cover everything
+	if _, err := io.Copy(io.Discard, tee); err != nil {
+		return "", nil, err
+	}
+
+	return hex.EncodeToString(hasher.Sum(nil)), perceptual, nil
+}
+
+// DifferenceHash computes a 64-bit dHash: the image is shrunk to 9x8 grayscale cells and
+// each bit records whether a cell is brighter than its right neighbour. Re-encoded or
+// resized copies of an image end up a small Hamming distance apart
+func DifferenceHash(img image.Image) uint64 {
+	const width, height = 9, 8
+	var cells [height][width]float64
+
+	bounds := img.Bounds()
+	for y := 0; y < height; y++ {
+		y0 := bounds.Min.Y + y*bounds.Dy()/height
+		y1 := bounds.Min.Y + (y+1)*bounds.Dy()/height
+		for x := 0; x < width; x++ {
+			x0 := bounds.Min.X + x*bounds.Dx()/width
+			x1 := bounds.Min.X + (x+1)*bounds.Dx()/width
+			cells[y][x] = averageLuma(img, x0, y0, x1, y1)
+		}
+	}
+
+	var hash uint64
+	for y := 0; y < height; y++ {
+		for x := 0; x < width-1; x++ {
+			hash <<= 1
+			if cells[y][x] > cells[y][x+1] {
+				hash |= 1
+			}
+		}
+	}
+	return hash
+}
+
+// HammingDistance returns the number of differing bits between two perceptual hashes
+func HammingDistance(a, b uint64) int {
+	return bits.OnesCount64(a ^ b)
+}
+
+// averageLuma returns the mean brightness of a rectangle, sampling at most 16x16 pixels
+func averageLuma(img image.Image, x0, y0, x1, y1 int) float64 {
+	if x1 <= x0 {
+		x1 = x0 + 1
+	}
+	if y1 <= y0 {
+		y1 = y0 + 1
+	}
+	stepX := max((x1-x0)/16, 1)
+	stepY := max((y1-y0)/16, 1)
+
+	var sum float64
+	var count int
+	for y := y0; y < y1; y += stepY {
+		for x := x0; x < x1; x += stepX {
+			r, g, b, _ := img.At(x, y).RGBA()
+			sum += 0.299*float64(r) + 0.587*float64(g) + 0.114*float64(b)
+			count++
+		}
+	}
+	return sum / float64(count)
+}
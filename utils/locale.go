@@ -0,0 +1,85 @@
+package utils
+
+import (
+	"strings"
+
+	"golang.org/x/text/language"
+)
+
+// maxPreferredLanguages caps how many languages of a request are considered
+const maxPreferredLanguages = 10
+
+// PreferredLanguages returns the languages a request asks for, most preferred first: the comma separated
+// lang parameter, then the Accept-Language header by decreasing quality. Malformed tags are skipped
+func PreferredLanguages(lang, acceptLanguage string) []string {
+	var languages []string
+	seen := make(map[string]bool)
+	add := func(tag language.Tag) {
+		if key := tag.String(); tag != language.Und && !seen[key] && len(languages) < maxPreferredLanguages {
+			seen[key] = true
+			languages = append(languages, key)
+		}
+	}
+
+	for _, value := range strings.Split(lang, ",") {
+		if tag, err := language.Parse(strings.TrimSpace(value)); err == nil {
+			add(tag)
+		}
+	}
+	// ParseAcceptLanguage already sorts the tags by quality and leaves out the ones with a quality of 0
+	if tags, _, err := language.ParseAcceptLanguage(acceptLanguage); err == nil {
+		for _, tag := range tags {
+			add(tag)
+		}
+	}
+	return languages
+}
+
+// NormalizeLocale returns the canonical form of a BCP 47 language tag, such as "en-US" for "en-us"
+func NormalizeLocale(locale string) (string, bool) {
+	tag, err := language.Parse(strings.TrimSpace(locale))
+	if err != nil || tag == language.Und {
+		return "", false
+	}
+	return tag.String(), true
+}
+
+// LocaleMatch scores how well content in a locale serves a reader asking for a language: 3 for the same tag,
+// 2 for the same language and script, 1 for the same language in another script or region, 0 otherwise.
+// The script of a tag without one is inferred, so "ja" is written in Japanese script and "ja-Latn" in romaji
+func LocaleMatch(preferred, locale string) int {
+	want, err := language.Parse(preferred)
+	if err != nil {
+		return 0
+	}
+	have, err := language.Parse(locale)
+	if err != nil {
+		return 0
+	}
+	if want == have {
+		return 3
+	}
+
+	wantBase, _ := want.Base()
+	haveBase, _ := have.Base()
+	if wantBase != haveBase {
+		return 0
+	}
+	wantScript, _ := want.Script()
+	haveScript, _ := have.Script()
+	if wantScript == haveScript {
+		return 2
+	}
+	return 1
+}
+
+// LocaleLanguage returns the base language subtag of a BCP 47 language tag, such as "zh" for "zh-Hant-TW", or ""
+// when the tag is malformed
+func LocaleLanguage(locale string) string {
+	tag, err := language.Parse(locale)
+	if err != nil {
+		return ""
+	}
+	base, _ := tag.Base()
+	return base.String()
+}
@@ -0,0 +1,52 @@
+package utils
+
+import "strings"
+
+// NaturalLess reports whether a sorts before b in natural order, comparing
+// runs of digits by numeric value so that "page2" sorts before "page10"
+func NaturalLess(a, b string) bool {
+	ra, rb := []rune(strings.ToLower(a)), []rune(strings.ToLower(b))
+	i, j := 0, 0
+
+	for i < len(ra) && j < len(rb) {
+		if isDigit(ra[i]) && isDigit(rb[j]) {
+			// Extract both digit runs
+			si := i
+			for i < len(ra) && isDigit(ra[i]) {
+				i++
+			}
+			sj := j
+			for j < len(rb) && isDigit(rb[j]) {
+				j++
+			}
+
+			// Compare numerically by ignoring leading zeros, then by length
+			na := strings.TrimLeft(string(ra[si:i]), "0")
+			nb := strings.TrimLeft(string(rb[sj:j]), "0")
+			if len(na) != len(nb) {
+				return len(na) < len(nb)
+			}
+			if na != nb {
+				return na < nb
+			}
+			// Equal values: fewer leading zeros sorts first
+			if i-si != j-sj {
+				return i-si < j-sj
+			}
+			continue
+		}
+
+		if ra[i] != rb[j] {
+			return ra[i] < rb[j]
+		}
+		i++
+		j++
+	}
+
+	return len(ra)-i < len(rb)-j
+}
+
+// isDigit reports whether r is an ASCII digit
+func isDigit(r rune) bool {
+	return r >= '0' && r <= '9'
+}